@@ -4,16 +4,22 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strings"
 	"text/tabwriter"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/nomagicln/open-bridge/pkg/cli"
 	"github.com/nomagicln/open-bridge/pkg/completion"
 	"github.com/nomagicln/open-bridge/pkg/config"
@@ -63,7 +69,11 @@ func init() {
 	specParser = spec.NewParser()
 
 	// Credential manager
-	credMgr, err = credential.NewManager()
+	var credOpts []credential.ManagerOption
+	credOpts, err = credentialOptions()
+	if err == nil {
+		credMgr, err = credential.NewManager(credOpts...)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: credential manager unavailable: %v\n", err)
 		// Continue without credential manager - auth will be limited
@@ -73,7 +83,7 @@ func init() {
 	mapper = semantic.NewMapper()
 
 	// Request builder
-	reqBuilder = request.NewBuilder(credMgr)
+	reqBuilder = request.NewBuilder(credMgr, request.WithUserAgent(request.UserAgent(version)))
 
 	// CLI handler
 	cliHandler = cli.NewHandler(specParser, mapper, reqBuilder, configMgr)
@@ -88,6 +98,16 @@ func init() {
 	completionHelper = completion.NewProvider(configMgr, specParser, mapper)
 }
 
+// credentialOptions returns the credential manager options selecting the
+// credential backend set in the global configuration, if any.
+func credentialOptions() ([]credential.ManagerOption, error) {
+	global, err := configMgr.GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	return credential.BackendOptions(global.Credential.Backend, global.Credential.Settings), nil
+}
+
 func main() {
 	if err := Execute(); err != nil {
 		// Don't print errors that have already been printed (e.g., formatted CLI errors)
@@ -110,6 +130,13 @@ func Execute() error {
 	}
 
 	// Normal 'ob' mode: use cobra CLI
+	rootCmd := newRootCmd()
+	rootCmd.SetArgs(resolveAppArgs(rootCmd, os.Args[1:]))
+	return rootCmd.Execute()
+}
+
+// newRootCmd creates the root 'ob' command with its subcommands.
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "ob",
 		Short: "OpenBridge - Universal API Runtime Engine",
@@ -118,7 +145,12 @@ to two distinct interfaces:
   - A semantic CLI for human users
   - An MCP server for AI agents
 
-One Spec, Dual Interface.`,
+One Spec, Dual Interface.
+
+Environment:
+  OB_APP      App to run when the first argument is not a command or app,
+              e.g. OB_APP=petstore ob pet get --petId 1
+  OB_PROFILE  Default profile for the app being run; --profile overrides it`,
 		Version:       fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -143,7 +175,7 @@ One Spec, Dual Interface.`,
 	// Add global flags
 	rootCmd.PersistentFlags().StringP("profile", "p", "", "Profile to use for the operation")
 	rootCmd.PersistentFlags().Bool("mcp", false, "Start in MCP server mode")
-	rootCmd.PersistentFlags().StringP("output", "o", "yaml", "Output format: json, yaml")
+	rootCmd.PersistentFlags().StringP("output", "o", "yaml", "Output format: json, jsonl, yaml")
 
 	// Add subcommands
 	rootCmd.AddCommand(
@@ -152,10 +184,70 @@ One Spec, Dual Interface.`,
 		newListCmd(),
 		newInfoCmd(),
 		newRunCmd(),
+		newConvertCmd(),
+		newDoctorCmd(),
+		newLintCmd(),
+		newCredentialCmd(),
+		newExportCmd(),
+		newImportCmd(),
+		newVersionCmd(),
 		newCompletionCmd(),
 	)
 
-	return rootCmd.Execute()
+	return rootCmd
+}
+
+// Environment variables that default the app and profile, for scripting
+// against one app without repeating its name.
+const (
+	envApp     = "OB_APP"
+	envProfile = "OB_PROFILE"
+)
+
+// resolveAppArgs rewrites the command-line arguments to run an app without the
+// run subcommand: an installed app named by the first argument is run, and
+// otherwise the OB_APP app, if set, is run with all of the arguments. Arguments
+// starting with a subcommand or a flag are returned unchanged.
+func resolveAppArgs(root *cobra.Command, args []string) []string {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isSubcommand(root, args[0]) {
+		return args
+	}
+	if configMgr.AppExists(args[0]) {
+		return append([]string{"run"}, args...)
+	}
+	if app := os.Getenv(envApp); app != "" {
+		return append([]string{"run", app}, args...)
+	}
+	return args
+}
+
+// isSubcommand reports whether name is a subcommand of root, including the
+// help and shell completion commands cobra adds when executing.
+func isSubcommand(root *cobra.Command, name string) bool {
+	switch name {
+	case "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEnvProfile makes the profile named by OB_PROFILE, if set, the app's
+// default profile.
+func applyEnvProfile(appConfig *config.AppConfig) error {
+	name := os.Getenv(envProfile)
+	if name == "" {
+		return nil
+	}
+	if _, ok := appConfig.GetProfile(name); !ok {
+		return fmt.Errorf("profile '%s' from %s not found in app '%s'", name, envProfile, appConfig.Name)
+	}
+	appConfig.DefaultProfile = name
+	return nil
 }
 
 // prepareInteractiveInstall handles the interactive TUI installation flow.
@@ -200,6 +292,12 @@ func mergeExistingConfig(opts config.InstallOptions, existing *config.AppConfig)
 	if opts.Description == "" {
 		opts.Description = existing.Description
 	}
+	if opts.VerbMappings == nil {
+		opts.VerbMappings = existing.VerbMappings
+	}
+	if !opts.OperationIDVerbs {
+		opts.OperationIDVerbs = existing.OperationIDVerbs
+	}
 	if profile, ok := existing.Profiles[existing.DefaultProfile]; ok {
 		if opts.BaseURL == "" {
 			opts.BaseURL = profile.BaseURL
@@ -252,6 +350,10 @@ func createCredentialFromParams(authType string, params map[string]string) *cred
 		if user != "" || pass != "" {
 			return credential.NewBasicCredential(user, pass)
 		}
+	case string(credential.CredentialTypeOAuth2ClientCredentials):
+		if clientID := params["client_id"]; clientID != "" {
+			return credential.NewOAuth2ClientCredentials(clientID, params["client_secret"], params["token_url"], strings.Fields(strings.ReplaceAll(params["scopes"], ",", " ")))
+		}
 	default:
 		// "none" or unknown auth type - no credential needed
 	}
@@ -282,25 +384,60 @@ func updateAPIKeyName(appName string, params map[string]string) {
 	}
 }
 
-// printInstallResult prints the installation result.
-func printInstallResult(appName string, result *config.InstallResult) {
-	fmt.Printf("✓ Successfully installed app '%s'\n", result.AppName)
-	fmt.Printf("  Config: %s\n", result.ConfigPath)
+// printInstallPlan prints what an installation would do.
+func printInstallPlan(out io.Writer, plan *config.InstallPlan) {
+	_, _ = fmt.Fprintf(out, "Install plan for '%s' (no changes written)\n", plan.AppName)
+	if plan.SpecInfo != nil {
+		_, _ = fmt.Fprintf(out, "  API: %s (v%s)\n", plan.SpecInfo.Title, plan.SpecInfo.Version)
+		_, _ = fmt.Fprintf(out, "  Operations: %d\n", plan.SpecInfo.Operations)
+	}
+	_, _ = fmt.Fprintf(out, "  Config: %s\n", plan.ConfigPath)
+	for _, name := range slices.Sorted(maps.Keys(plan.Config.Profiles)) {
+		profile := plan.Config.Profiles[name]
+		authType := profile.Auth.Type
+		if authType == "" {
+			authType = "none"
+		}
+		_, _ = fmt.Fprintf(out, "  Profile: %s (base URL: %s, auth: %s)\n", name, profile.BaseURL, authType)
+	}
+	if plan.ShimPath != "" {
+		_, _ = fmt.Fprintf(out, "  Shim: %s\n", plan.ShimPath)
+	}
+
+	if len(plan.Warnings) > 0 {
+		_, _ = fmt.Fprintln(out, "\nWarnings:")
+		for _, warning := range plan.Warnings {
+			_, _ = fmt.Fprintf(out, "  - %s\n", warning)
+		}
+	}
+
+	_, _ = fmt.Fprintln(out, "\n✓ Spec is valid; run without --check to install")
+}
+
+// printInstallResult prints the installation result. Without a shim, it
+// suggests a shell alias for the app instead.
+func printInstallResult(out io.Writer, appName string, result *config.InstallResult) {
+	_, _ = fmt.Fprintf(out, "✓ Successfully installed app '%s'\n", result.AppName)
+	_, _ = fmt.Fprintf(out, "  Config: %s\n", result.ConfigPath)
 	if result.ShimPath != "" {
-		fmt.Printf("  Shim: %s\n", result.ShimPath)
+		_, _ = fmt.Fprintf(out, "  Shim: %s\n", result.ShimPath)
 	}
 	if result.SpecInfo != nil {
-		fmt.Printf("  API: %s (v%s)\n", result.SpecInfo.Title, result.SpecInfo.Version)
-		fmt.Printf("  Operations: %d\n", result.SpecInfo.Operations)
+		_, _ = fmt.Fprintf(out, "  API: %s (v%s)\n", result.SpecInfo.Title, result.SpecInfo.Version)
+		_, _ = fmt.Fprintf(out, "  Operations: %d\n", result.SpecInfo.Operations)
 	}
 
-	fmt.Printf("\nUsage:\n")
+	_, _ = fmt.Fprintf(out, "\nUsage:\n")
 	if result.ShimPath != "" {
-		fmt.Printf("  %s <verb> <resource> [flags]\n", appName)
+		_, _ = fmt.Fprintf(out, "  %s <verb> <resource> [flags]\n", appName)
 	} else {
-		fmt.Printf("  ob run %s <verb> <resource> [flags]\n", appName)
+		_, _ = fmt.Fprintf(out, "  ob run %s <verb> <resource> [flags]\n", appName)
+	}
+	_, _ = fmt.Fprintf(out, "  ob run %s --help\n", appName)
+
+	if result.ShimPath == "" {
+		_, _ = fmt.Fprintf(out, "\n%s\n", config.GetAliasInstructions(appName, config.DetectShell()))
 	}
-	fmt.Printf("  ob run %s --help\n", appName)
 }
 
 // installCmdFlags holds the flag values for the install command.
@@ -312,20 +449,29 @@ type installCmdFlags struct {
 	createShim  bool
 	force       bool
 	interactive bool
+	check       bool
+	verbMap     map[string]string
+	opIDVerbs   bool
 }
 
 // runInstallCmd executes the install command logic.
 func runInstallCmd(appName string, flags *installCmdFlags) error {
+	if err := semantic.ValidateMethodVerbs(flags.verbMap); err != nil {
+		return fmt.Errorf("invalid verb mappings: %w", err)
+	}
+
 	opts := config.InstallOptions{
-		SpecSource:  flags.specSource,
-		BaseURL:     flags.baseURL,
-		Description: flags.description,
-		AuthType:    flags.authType,
-		CreateShim:  flags.createShim,
-		Force:       flags.force,
-		Interactive: flags.interactive,
-		Reader:      os.Stdin,
-		Writer:      os.Stdout,
+		SpecSource:       flags.specSource,
+		BaseURL:          flags.baseURL,
+		Description:      flags.description,
+		AuthType:         flags.authType,
+		CreateShim:       flags.createShim,
+		Force:            flags.force,
+		Interactive:      flags.interactive,
+		Reader:           os.Stdin,
+		Writer:           os.Stdout,
+		VerbMappings:     flags.verbMap,
+		OperationIDVerbs: flags.opIDVerbs,
 	}
 
 	if flags.interactive {
@@ -338,13 +484,22 @@ func runInstallCmd(appName string, flags *installCmdFlags) error {
 		return fmt.Errorf("--spec flag is required (or use -i for interactive mode)")
 	}
 
+	if flags.check {
+		plan, err := configMgr.PlanInstall(appName, opts)
+		if err != nil {
+			return fmt.Errorf("install check failed: %w", err)
+		}
+		printInstallPlan(os.Stdout, plan)
+		return nil
+	}
+
 	result, err := configMgr.InstallApp(appName, opts)
 	if err != nil {
 		return fmt.Errorf("installation failed: %w", err)
 	}
 
 	storeInstallCredentials(appName, opts)
-	printInstallResult(appName, result)
+	printInstallResult(os.Stdout, appName, result)
 
 	return nil
 }
@@ -358,6 +513,8 @@ func newInstallCmd() *cobra.Command {
 		Short: "Install an API as a CLI application",
 		Long: `Install an OpenAPI specification as a CLI application.
 The specification can be provided as a local file path or a remote URL.
+Use --spec - to read the specification from stdin; a copy is stored with the
+app's configuration so later commands do not need stdin again.
 
 After installation, you can call API operations using either:
   - Automatic parameter classification (recommended):
@@ -368,20 +525,27 @@ After installation, you can call API operations using either:
 Example:
   ob install myapi --spec ./openapi.yaml
   ob install petstore --spec https://petstore.swagger.io/v2/swagger.json
-  ob install myapi -i  # Interactive mode`,
+  generate-spec | ob install myapi --spec -  # Read the spec from stdin
+  ob install myapi -i  # Interactive mode
+  ob install myapi --spec ./openapi.yaml --check  # Validate without installing
+  ob install myapi --spec ./openapi.yaml --verb-map PATCH=update  # Name PATCH commands "update"
+  ob install myapi --spec ./openapi.yaml --operation-id-verbs  # listCustomers becomes "customers list"`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			return runInstallCmd(args[0], flags)
 		},
 	}
 
-	cmd.Flags().StringVarP(&flags.specSource, "spec", "s", "", "Path or URL to the OpenAPI specification")
+	cmd.Flags().StringVarP(&flags.specSource, "spec", "s", "", "Path, URL or git+<repo>//<path>[@ref] source of the OpenAPI specification, or - for stdin")
 	cmd.Flags().StringVar(&flags.baseURL, "base-url", "", "Base URL for API requests (overrides spec)")
 	cmd.Flags().StringVar(&flags.description, "description", "", "Description of the application")
-	cmd.Flags().StringVar(&flags.authType, "auth", "", "Authentication type: none, bearer, api_key, basic")
+	cmd.Flags().StringVar(&flags.authType, "auth", "", "Authentication type: none, bearer, api_key, basic, oauth2_client_credentials")
 	cmd.Flags().BoolVar(&flags.createShim, "shim", true, "Create command shortcut (shim)")
 	cmd.Flags().BoolVarP(&flags.force, "force", "f", false, "Overwrite existing app configuration")
 	cmd.Flags().BoolVarP(&flags.interactive, "interactive", "i", false, "Interactive installation mode")
+	cmd.Flags().BoolVar(&flags.check, "check", false, "Validate the spec and print the install plan without writing anything")
+	cmd.Flags().StringToStringVar(&flags.verbMap, "verb-map", nil, "Override the verb for an HTTP method, e.g. PATCH=update (keys: GET, LIST, POST, PUT, PATCH, DELETE, HEAD, OPTIONS)")
+	cmd.Flags().BoolVar(&flags.opIDVerbs, "operation-id-verbs", false, "Name commands after operationId prefixes, e.g. list for listCustomers")
 
 	return cmd
 }
@@ -389,6 +553,7 @@ Example:
 // newUninstallCmd creates the uninstall subcommand
 func newUninstallCmd() *cobra.Command {
 	var removeShim bool
+	var opts uninstallAllOptions
 
 	cmd := &cobra.Command{
 		Use:   "uninstall <app-name>",
@@ -396,20 +561,45 @@ func newUninstallCmd() *cobra.Command {
 		Long: `Uninstall a previously installed API application.
 This removes the application configuration and any created shims.
 
+Use --all to remove every installed application. You will be asked to
+confirm unless --yes is given; failures are reported after all apps
+have been processed.
+
 Example:
-  ob uninstall myapi`,
-		Args:              cobra.ExactArgs(1),
+  ob uninstall myapi
+  ob uninstall --all --yes --purge-credentials`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		ValidArgsFunction: completeAppNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				opts.removeShim = removeShim
+				return uninstallAllApps(cmd.InOrStdin(), cmd.OutOrStdout(), opts)
+			}
 			return uninstallApp(args[0], removeShim)
 		},
 	}
 
 	cmd.Flags().BoolVar(&removeShim, "remove-shim", true, "Remove the command shortcut (shim)")
+	cmd.Flags().BoolVar(&opts.all, "all", false, "Uninstall every installed application")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Skip the confirmation prompt for --all")
+	cmd.Flags().BoolVar(&opts.purgeCredentials, "purge-credentials", false, "Also delete stored credentials for removed apps")
 
 	return cmd
 }
 
+// uninstallAllOptions holds options for uninstalling every app.
+type uninstallAllOptions struct {
+	all              bool
+	yes              bool
+	removeShim       bool
+	purgeCredentials bool
+}
+
 // uninstallApp uninstalls an app by name.
 func uninstallApp(appName string, removeShim bool) error {
 	if !configMgr.AppExists(appName) {
@@ -426,6 +616,70 @@ func uninstallApp(appName string, removeShim bool) error {
 	return nil
 }
 
+// uninstallAllApps removes every installed app after confirmation.
+// It continues past individual failures and reports them in the summary.
+func uninstallAllApps(in io.Reader, out io.Writer, opts uninstallAllOptions) error {
+	apps, err := configMgr.ListApps()
+	if err != nil {
+		return fmt.Errorf("failed to list apps: %w", err)
+	}
+	if len(apps) == 0 {
+		_, _ = fmt.Fprintln(out, "No apps are currently installed.")
+		return nil
+	}
+
+	if !opts.yes && !confirmUninstallAll(in, out, apps) {
+		_, _ = fmt.Fprintln(out, "Aborted.")
+		return nil
+	}
+
+	var removed []string
+	failures := make(map[string]error)
+	for _, appName := range apps {
+		if err := configMgr.UninstallApp(appName, opts.removeShim); err != nil {
+			failures[appName] = err
+			continue
+		}
+		if opts.purgeCredentials && credMgr != nil {
+			if err := credMgr.DeleteAllCredentials(appName); err != nil {
+				failures[appName] = fmt.Errorf("app removed, but failed to delete credentials: %w", err)
+				continue
+			}
+		}
+		removed = append(removed, appName)
+	}
+
+	_, _ = fmt.Fprintf(out, "✓ Uninstalled %d of %d app(s)\n", len(removed), len(apps))
+	for _, appName := range removed {
+		_, _ = fmt.Fprintf(out, "  - %s\n", appName)
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "✗ Failed to uninstall %d app(s):\n", len(failures))
+	for _, appName := range apps {
+		if err, ok := failures[appName]; ok {
+			_, _ = fmt.Fprintf(out, "  - %s: %v\n", appName, err)
+		}
+	}
+	return &cli.PrintedError{Err: fmt.Errorf("failed to uninstall %d app(s)", len(failures))}
+}
+
+// confirmUninstallAll lists the apps to be removed and asks the user to confirm.
+func confirmUninstallAll(in io.Reader, out io.Writer, apps []string) bool {
+	_, _ = fmt.Fprintf(out, "This will uninstall %d app(s):\n", len(apps))
+	for _, appName := range apps {
+		_, _ = fmt.Fprintf(out, "  - %s\n", appName)
+	}
+	_, _ = fmt.Fprint(out, "Continue? [y/N]: ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // showAppNotFoundError displays helpful error when app is not found.
 func showAppNotFoundError(appName string) {
 	apps, _ := configMgr.ListApps()
@@ -556,9 +810,12 @@ func displayApps(apps []string, showDetails bool) {
 // newInfoCmd creates the info subcommand to show app configuration
 func newInfoCmd() *cobra.Command {
 	var outputFormat string
+	var curlExamples bool
+	var diffProfiles bool
+	var webhooks bool
 
 	cmd := &cobra.Command{
-		Use:   "info <app-name>",
+		Use:   "info <app-name> [--diff-profiles <profile> <profile>]",
 		Short: "Show configuration for an installed application",
 		Long: `Show detailed configuration for an installed API application.
 
@@ -571,15 +828,35 @@ This command displays all configuration settings including:
 Example:
   ob info petstore
   ob info petstore -o yaml
-  ob info petstore -o json`,
-		Args:              cobra.ExactArgs(1),
+  ob info petstore -o json
+  ob info petstore --curl-examples
+  ob info petstore --webhooks
+  ob info petstore --diff-profiles prod staging`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if diffProfiles {
+				return cobra.ExactArgs(3)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		ValidArgsFunction: completeAppNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if diffProfiles {
+				return showProfileDiff(args[0], args[1], args[2], outputFormat)
+			}
+			if curlExamples {
+				return showCurlExamples(args[0])
+			}
+			if webhooks {
+				return showWebhooks(cmd.OutOrStdout(), args[0], outputFormat)
+			}
 			return showAppInfo(args[0], outputFormat)
 		},
 	}
 
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().BoolVar(&curlExamples, "curl-examples", false, "Print an example curl command for each operation")
+	cmd.Flags().BoolVar(&diffProfiles, "diff-profiles", false, "Compare the settings of two profiles")
+	cmd.Flags().BoolVar(&webhooks, "webhooks", false, "List the webhooks and callbacks the API sends, with example payloads")
 
 	return cmd
 }
@@ -598,6 +875,177 @@ func showAppInfo(appName, outputFormat string) error {
 	return printAppConfig(appConfig, outputFormat)
 }
 
+// showProfileDiff prints the settings that differ between two profiles of an app.
+func showProfileDiff(appName, leftName, rightName, outputFormat string) error {
+	if !configMgr.AppExists(appName) {
+		return fmt.Errorf("app '%s' not found", appName)
+	}
+
+	appConfig, err := configMgr.GetAppConfig(appName)
+	if err != nil {
+		return fmt.Errorf("failed to get app config: %w", err)
+	}
+
+	left, ok := appConfig.Profiles[leftName]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found", leftName)
+	}
+	right, ok := appConfig.Profiles[rightName]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found", rightName)
+	}
+
+	diffs := config.DiffProfiles(&left, &right)
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal profile diff to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(diffs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal profile diff to YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if len(diffs) == 0 {
+		fmt.Printf("Profiles '%s' and '%s' have the same settings\n", leftName, rightName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "SETTING\t%s\t%s\n", leftName, rightName)
+	for _, d := range diffs {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", d.Setting, valueOrNone(d.Left), valueOrNone(d.Right))
+	}
+	return w.Flush()
+}
+
+// showCurlExamples prints an example curl command for every operation of an app.
+func showCurlExamples(appName string) error {
+	if !configMgr.AppExists(appName) {
+		return fmt.Errorf("app '%s' not found", appName)
+	}
+
+	appConfig, err := loadAppConfig(appName)
+	if err != nil {
+		return err
+	}
+
+	examples, err := cliHandler.CurlExamples(appName, appConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(examples)
+	return nil
+}
+
+// webhookEntry is a webhook or callback listed by ob info --webhooks, with an
+// example payload derived from its schema for local testing.
+type webhookEntry struct {
+	spec.EventInfo
+	Example any `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+// showWebhooks lists the webhooks and callbacks declared in an app's spec.
+func showWebhooks(out io.Writer, appName, outputFormat string) error {
+	if !configMgr.AppExists(appName) {
+		return fmt.Errorf("app '%s' not found", appName)
+	}
+
+	appConfig, err := loadAppConfig(appName)
+	if err != nil {
+		return err
+	}
+
+	specDoc, err := specParser.LoadSpec(appConfig.SpecSource)
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+
+	entries := []webhookEntry{}
+	for _, event := range spec.GetEvents(specDoc) {
+		entry := webhookEntry{EventInfo: event}
+		if event.Schema != nil {
+			entry.Example = cli.ExampleValueForSchema("payload", event.Schema)
+		}
+		entries = append(entries, entry)
+	}
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhooks to JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := json.Marshal(entries)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhooks to YAML: %w", err)
+		}
+		var generic any
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("failed to marshal webhooks to YAML: %w", err)
+		}
+		data, err = yaml.Marshal(generic)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhooks to YAML: %w", err)
+		}
+		_, _ = fmt.Fprint(out, string(data))
+	default:
+		return printWebhooks(out, entries)
+	}
+	return nil
+}
+
+// printWebhooks prints webhooks and callbacks as human-readable text.
+func printWebhooks(out io.Writer, entries []webhookEntry) error {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "No webhooks or callbacks defined")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Webhooks and callbacks (%d)\n", len(entries))
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(out, "\n  %s %s %s\n", entry.Kind, entry.Name, entry.Method)
+		if entry.Expression != "" {
+			_, _ = fmt.Fprintf(out, "    URL:     %s\n", entry.Expression)
+		}
+		if entry.Summary != "" {
+			_, _ = fmt.Fprintf(out, "    Summary: %s\n", entry.Summary)
+		}
+		if entry.Schema == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "    Payload: %s %s\n", entry.ContentType, schemaLabel(entry.Schema))
+		example, err := json.Marshal(entry.Example)
+		if err != nil {
+			return fmt.Errorf("failed to marshal example payload: %w", err)
+		}
+		_, _ = fmt.Fprintf(out, "    Example: %s\n", example)
+	}
+	return nil
+}
+
+// schemaLabel names a payload schema by its component name, or else its type.
+func schemaLabel(schemaRef *openapi3.SchemaRef) string {
+	if name, ok := strings.CutPrefix(schemaRef.Ref, "#/components/schemas/"); ok {
+		return name
+	}
+	if schemaRef.Value != nil && schemaRef.Value.Type != nil {
+		return strings.Join(schemaRef.Value.Type.Slice(), "|")
+	}
+	return "any"
+}
+
 // printAppConfig prints the app configuration in the specified format.
 func printAppConfig(cfg *config.AppConfig, format string) error {
 	switch format {
@@ -683,6 +1131,17 @@ func printProfileDetails(p *config.Profile, indent string) {
 	if p.Timeout.Duration > 0 {
 		fmt.Printf("%sTimeout:     %s\n", indent, p.Timeout.String())
 	}
+	if p.ConnectTimeout.Duration > 0 {
+		fmt.Printf("%sConnect Timeout: %s\n", indent, p.ConnectTimeout.String())
+	}
+	if p.RequestDeadline.Duration > 0 {
+		fmt.Printf("%sRequest Deadline: %s\n", indent, p.RequestDeadline.String())
+	}
+	if !p.FollowsRedirects() {
+		fmt.Printf("%sRedirects:   not followed\n", indent)
+	} else if p.MaxRedirects > 0 {
+		fmt.Printf("%sRedirects:   up to %d\n", indent, p.MaxRedirects)
+	}
 }
 
 // printProfileAuth prints authentication configuration.
@@ -723,8 +1182,8 @@ func printProfileHeaders(p *config.Profile, indent string) {
 	fmt.Printf("%sHeaders:\n", indent)
 	for k, v := range p.Headers {
 		displayValue := v
-		if isSensitiveHeader(k) {
-			displayValue = maskValue(v)
+		if config.IsSensitiveHeader(k) {
+			displayValue = cli.MaskValue(v)
 		}
 		fmt.Printf("%s  %s: %s\n", indent, k, displayValue)
 	}
@@ -763,72 +1222,728 @@ func valueOrNone(value string) string {
 	return value
 }
 
-// isSensitiveHeader checks if a header name is sensitive.
-func isSensitiveHeader(name string) bool {
-	sensitive := []string{"authorization", "x-api-key", "api-key", "token", "secret", "password"}
-	nameLower := strings.ToLower(name)
-	for _, s := range sensitive {
-		if strings.Contains(nameLower, s) {
-			return true
-		}
+// newDoctorCmd creates the doctor command.
+func newDoctorCmd() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "doctor [app-name]",
+		Short: "Check installed applications for configuration problems",
+		Long: `Check the configuration of installed API applications for inconsistencies,
+such as a default profile that does not exist, an auth type without a stored
+credential, or TLS files that are missing.
+
+Without an app name, every installed application is checked. The command fails
+if any error is found; warnings are reported but do not fail it.
+
+With -o json or -o yaml, the result of every app and the overall pass/fail are
+printed as a document for CI.
+
+Example:
+  ob doctor
+  ob doctor petstore
+  ob doctor -o json`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeAppNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.OutOrStdout(), args, outputFormat)
+		},
 	}
-	return false
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, yaml")
+
+	return cmd
 }
 
-// maskValue masks a sensitive value, showing only first and last 2 chars.
-func maskValue(value string) string {
-	if len(value) <= 4 {
-		return "****"
+// newCredentialCmd creates the credential command.
+func newCredentialCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credential",
+		Short: "Manage stored credentials",
 	}
-	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
+	cmd.AddCommand(newCredentialMigrateCmd())
+	return cmd
 }
 
-// newRunCmd creates the run subcommand for running app commands
-func newRunCmd() *cobra.Command {
+// newCredentialMigrateCmd creates the credential migrate command.
+func newCredentialMigrateCmd() *cobra.Command {
+	var (
+		to       string
+		settings map[string]string
+		purge    bool
+	)
+
 	cmd := &cobra.Command{
-		Use:   "run <app-name> [args...]",
-		Short: "Run commands for an installed API application",
-		Long: `Run commands for an installed API application.
+		Use:   "migrate --to <backend>",
+		Short: "Move stored credentials to another backend",
+		Long: `Copy the credentials of every installed application from the current
+credential backend to another one: keyring for the system keyring, file for
+the encrypted file keyring, or the name of a backend plugin.
+
+The file keyring is encrypted with the passphrase in OB_CREDENTIAL_PASSPHRASE,
+or with OB_CREDENTIAL_NEW_PASSPHRASE when it is the destination. Migrating the
+file keyring to its own directory without --purge changes its passphrase.
+Each credential is reported as it is migrated; failures do not stop the others.
+With --purge, every credential copied is deleted from the current backend.
+Set credential.backend in config.yaml afterwards to use the new backend.
 
 Example:
-  ob run petstore list pets
-  ob run petstore create pet --name "Fluffy" --status available
-  ob run petstore --mcp  # Start MCP server`,
-		Args:               cobra.MinimumNArgs(1),
-		ValidArgsFunction:  completeRunArgs,
-		DisableFlagParsing: true, // Pass all flags to the app handler
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCommand(args)
+  ob credential migrate --to file --setting dir=~/.config/openbridge/keyring
+  OB_CREDENTIAL_NEW_PASSPHRASE=... ob credential migrate --to file
+  ob credential migrate --to vault --setting addr=https://vault.internal --purge`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if credMgr == nil {
+				return fmt.Errorf("credential manager unavailable")
+			}
+			dest, err := credential.NewManager(credential.MigrationBackendOptions(to, settings)...)
+			if err != nil {
+				return fmt.Errorf("failed to open the %s credential backend: %w", to, err)
+			}
+			return migrateCredentials(cmd.OutOrStdout(), credMgr, dest, purge)
 		},
 	}
 
+	cmd.Flags().StringVar(&to, "to", "", "Backend to move credentials to: keyring, file, or a plugin name")
+	cmd.Flags().StringToStringVar(&settings, "setting", nil, "Setting of the new backend, e.g. dir=/path (repeatable)")
+	cmd.Flags().BoolVar(&purge, "purge", false, "Delete each migrated credential from the current backend")
+	_ = cmd.MarkFlagRequired("to")
+
 	return cmd
 }
 
-// runCommand executes the run command logic.
-func runCommand(args []string) error {
-	appName := args[0]
-
-	if !validateAppExists(appName) {
-		return fmt.Errorf("app not found: %s", appName)
+// migrateCredentials copies the credentials of every installed app from src
+// to dest and reports each one. It refuses to migrate to the store src
+// already uses, except to re-encrypt a file keyring.
+func migrateCredentials(out io.Writer, src, dest *credential.Manager, purge bool) error {
+	if err := src.CheckMigration(dest, purge); err != nil {
+		return fmt.Errorf("cannot migrate credentials from %s to itself: %w", src.Backend(), err)
 	}
 
-	appConfig, err := loadAppConfig(appName)
+	apps, err := configMgr.ListApps()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to list apps: %w", err)
 	}
 
-	return executeRunMode(args, appConfig)
-}
-
-// validateAppExists checks if the app exists and shows helpful error if not.
-func validateAppExists(appName string) bool {
-	if configMgr.AppExists(appName) {
-		return true
+	results := src.Migrate(dest, apps, purge)
+	if len(results) == 0 {
+		_, _ = fmt.Fprintf(out, "No credentials are stored in %s.\n", src.Backend())
+		return nil
 	}
 
-	apps, _ := configMgr.ListApps()
-	if len(apps) > 0 {
+	failed := 0
+	for _, result := range results {
+		name := result.AppName
+		if result.ProfileName != "" {
+			name += "/" + result.ProfileName
+		}
+		if result.Err != nil {
+			failed++
+			_, _ = fmt.Fprintf(out, "✗ %s: %v\n", name, result.Err)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "✓ %s\n", name)
+	}
+
+	_, _ = fmt.Fprintf(out, "\nMigrated %d of %d credential(s) from %s to %s\n", len(results)-failed, len(results), src.Backend(), dest.Backend())
+	if failed > 0 {
+		return &cli.PrintedError{Err: fmt.Errorf("failed to migrate %d credential(s)", failed)}
+	}
+	_, _ = fmt.Fprintf(out, "Set credential.backend in %s to use the new backend.\n", configMgr.GlobalConfigPath())
+	return nil
+}
+
+// newExportCmd creates the export command.
+func newExportCmd() *cobra.Command {
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "export <app-name>",
+		Short: "Export an installed application to a portable bundle",
+		Long: `Export the configuration of an installed application, with all of its
+profiles, to a bundle that can be installed elsewhere with ob import.
+
+The bundle records which profiles have a stored credential, with its type,
+key name, and OAuth2 token URL and scopes, but never the secret itself:
+credentials are supplied again when the bundle is imported. Headers and query parameters that look like
+credentials are left out as well.
+
+Example:
+  ob export myapi --out myapi-bundle.yaml`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeAppNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportApp(cmd.OutOrStdout(), credMgr, args[0], outPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&outPath, "out", "", "File to write the bundle to (default: stdout)")
+
+	return cmd
+}
+
+// exportApp writes the bundle of an app to outPath, or to out if outPath is empty.
+func exportApp(out io.Writer, creds *credential.Manager, appName, outPath string) error {
+	credentials, err := storedCredentials(creds, appName)
+	if err != nil {
+		return err
+	}
+
+	data, err := configMgr.ExportBundle(appName, credentials)
+	if err != nil {
+		return err
+	}
+
+	if outPath == "" {
+		_, err = out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "✓ Exported app '%s' to %s\n", appName, outPath)
+	if len(credentials) > 0 {
+		_, _ = fmt.Fprintf(out, "  %d credential reference(s) included; secrets are not exported.\n", len(credentials))
+	}
+	return nil
+}
+
+// storedCredentials returns a bundle reference to the credential stored for
+// each profile of an app that has one, without its secret.
+func storedCredentials(creds *credential.Manager, appName string) (map[string]config.BundleCredential, error) {
+	refs := make(map[string]config.BundleCredential)
+	if creds == nil {
+		return refs, nil
+	}
+
+	profiles, err := creds.ListCredentials(appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+	for _, profileName := range profiles {
+		cred, err := creds.GetCredential(appName, profileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credential for profile '%s': %w", profileName, err)
+		}
+		refs[profileName] = config.BundleCredential{
+			Type:     string(cred.Type),
+			TokenURL: cred.TokenURL,
+			Scopes:   strings.Join(cred.Scopes, " "),
+		}
+	}
+	return refs, nil
+}
+
+// newImportCmd creates the import command.
+func newImportCmd() *cobra.Command {
+	var opts importBundleOptions
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle-file>",
+		Short: "Install an application from a bundle written by ob export",
+		Long: `Install an application from a bundle written by ob export.
+
+Bundles carry no secrets. For each profile the bundle records a credential
+for, the secret is taken from --secret or prompted for; use --no-prompt to
+skip the prompts and store the missing credentials later. A --secret value
+for basic auth is given as username:password, and for OAuth2 client
+credentials as client_id:client_secret.
+
+Example:
+  ob import myapi-bundle.yaml
+  ob import myapi-bundle.yaml --name myapi-staging --secret default=$TOKEN --no-prompt`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read bundle: %w", err)
+			}
+			return importBundle(cmd.InOrStdin(), cmd.OutOrStdout(), credMgr, data, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.name, "name", "", "Install the app under this name instead of the bundled one")
+	cmd.Flags().BoolVar(&opts.overwrite, "overwrite", false, "Replace an installed app of the same name")
+	cmd.Flags().StringArrayVar(&opts.secrets, "secret", nil, "Secret for a profile's credential as profile=value (repeatable)")
+	cmd.Flags().BoolVar(&opts.noPrompt, "no-prompt", false, "Do not prompt for secrets missing from --secret")
+
+	return cmd
+}
+
+// importBundleOptions holds options for importing a bundle.
+type importBundleOptions struct {
+	name      string
+	overwrite bool
+	secrets   []string
+	noPrompt  bool
+}
+
+// importBundle installs the app of a bundle and stores a credential for each
+// profile the bundle records one for. Secrets come from opts.secrets or are
+// prompted for on in; a credential that cannot be stored is reported, but does
+// not fail the import.
+func importBundle(in io.Reader, out io.Writer, creds *credential.Manager, data []byte, opts importBundleOptions) error {
+	secrets, err := parseBundleSecrets(opts.secrets)
+	if err != nil {
+		return err
+	}
+
+	bundle, err := config.ParseBundle(data)
+	if err != nil {
+		return err
+	}
+	appConfig, err := configMgr.ImportBundle(bundle, opts.name, opts.overwrite)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "✓ Imported app '%s' with %d profile(s)\n", appConfig.Name, len(appConfig.Profiles))
+
+	if len(bundle.Credentials) == 0 {
+		return nil
+	}
+	if creds == nil {
+		_, _ = fmt.Fprintln(out, "Warning: credential manager unavailable; no credentials were stored.")
+		return nil
+	}
+
+	prompter := credential.NewSecurePrompterWithIO(in, out)
+	var missing []string
+	for _, ref := range bundle.Credentials {
+		cred, err := bundleCredential(prompter, ref, secrets, opts.noPrompt)
+		if err == nil && cred != nil {
+			err = creds.StoreCredential(appConfig.Name, ref.Profile, cred)
+		}
+		switch {
+		case err != nil:
+			_, _ = fmt.Fprintf(out, "✗ %s credential for profile '%s': %v\n", ref.Type, ref.Profile, err)
+			missing = append(missing, ref.Profile)
+		case cred == nil:
+			missing = append(missing, ref.Profile)
+		default:
+			_, _ = fmt.Fprintf(out, "✓ Stored %s credential for profile '%s'\n", ref.Type, ref.Profile)
+		}
+	}
+
+	if len(missing) > 0 {
+		_, _ = fmt.Fprintf(out, "No credential stored for profile(s): %s\n", strings.Join(missing, ", "))
+		_, _ = fmt.Fprintln(out, "Run ob import again with --overwrite and --secret <profile>=<value> to add them.")
+	}
+	return nil
+}
+
+// bundleCredential returns the credential to store for a bundled credential
+// reference: from its --secret value, or prompted for unless noPrompt is
+// set. It returns nil if there is no value and prompting is disabled.
+// OAuth2 client credentials keep the token URL and scopes of the reference.
+func bundleCredential(prompter *credential.SecurePrompter, ref config.BundleCredential, secrets map[string]string, noPrompt bool) (*credential.Credential, error) {
+	isOAuth2 := ref.Type == string(credential.CredentialTypeOAuth2ClientCredentials)
+	secret, ok := secrets[ref.Profile]
+	if !ok {
+		if noPrompt {
+			return nil, nil
+		}
+		if isOAuth2 {
+			return prompter.PromptOAuth2ClientCredentials(ref.TokenURL, strings.Fields(ref.Scopes))
+		}
+		return prompter.PromptCredential(ref.Type)
+	}
+
+	params := map[string]string{"token": secret}
+	switch ref.Type {
+	case string(credential.CredentialTypeBasic):
+		username, password, found := strings.Cut(secret, ":")
+		if !found {
+			return nil, fmt.Errorf("expected the secret as username:password")
+		}
+		params = map[string]string{"username": username, "password": password}
+	case string(credential.CredentialTypeOAuth2ClientCredentials):
+		clientID, clientSecret, found := strings.Cut(secret, ":")
+		if !found {
+			return nil, fmt.Errorf("expected the secret as client_id:client_secret")
+		}
+		params = map[string]string{"client_id": clientID, "client_secret": clientSecret, "token_url": ref.TokenURL, "scopes": ref.Scopes}
+	}
+
+	cred := createCredentialFromParams(ref.Type, params)
+	if cred == nil {
+		return nil, fmt.Errorf("unsupported credential type")
+	}
+	return cred, nil
+}
+
+// parseBundleSecrets parses --secret values of the form profile=value.
+func parseBundleSecrets(values []string) (map[string]string, error) {
+	secrets := make(map[string]string, len(values))
+	for _, value := range values {
+		profileName, secret, ok := strings.Cut(value, "=")
+		if !ok || profileName == "" || secret == "" {
+			return nil, fmt.Errorf("invalid --secret value: expected profile=value")
+		}
+		secrets[profileName] = secret
+	}
+	return secrets, nil
+}
+
+// Doctor check statuses.
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorReport is the structured output of ob doctor.
+type doctorReport struct {
+	// Passed is false if any app has a failing check.
+	Passed bool              `json:"passed" yaml:"passed"`
+	Apps   []doctorAppResult `json:"apps" yaml:"apps"`
+}
+
+// doctorAppResult is the outcome of checking one app. Its status is the worst
+// status of its checks, or pass if none reported a problem.
+type doctorAppResult struct {
+	App    string        `json:"app" yaml:"app"`
+	Status string        `json:"status" yaml:"status"`
+	Checks []doctorCheck `json:"checks" yaml:"checks"`
+}
+
+// doctorCheck is a configuration check that found a problem: fail for an
+// error, warn for a warning.
+type doctorCheck struct {
+	Check   string `json:"check" yaml:"check"`
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Status  string `json:"status" yaml:"status"`
+	Detail  string `json:"detail" yaml:"detail"`
+}
+
+// runDoctor validates the given apps, or all installed apps, and prints the
+// issues found in the given output format.
+func runDoctor(out io.Writer, appNames []string, outputFormat string) error {
+	if len(appNames) == 0 {
+		apps, err := configMgr.ListApps()
+		if err != nil {
+			return fmt.Errorf("failed to list apps: %w", err)
+		}
+		appNames = apps
+	}
+
+	report := doctorReport{Passed: true, Apps: make([]doctorAppResult, 0, len(appNames))}
+	failed := 0
+	for _, appName := range appNames {
+		issues := configMgr.ValidateConfig(appName, doctorValidateOptions()...)
+		result := newDoctorAppResult(appName, issues)
+		if result.Status == doctorFail {
+			report.Passed = false
+			failed++
+		}
+		report.Apps = append(report.Apps, result)
+
+		if outputFormat != "text" {
+			continue
+		}
+		if len(issues) == 0 {
+			_, _ = fmt.Fprintf(out, "✓ %s: no issues found\n", appName)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "✗ %s:\n", appName)
+		for _, issue := range issues {
+			_, _ = fmt.Fprintf(out, "    %s: %s\n", issue.Severity, issue)
+		}
+	}
+
+	switch outputFormat {
+	case "text":
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal doctor report to JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal doctor report to YAML: %w", err)
+		}
+		_, _ = fmt.Fprint(out, string(data))
+	default:
+		return fmt.Errorf("unsupported output format: %s (valid formats: text, json, yaml)", outputFormat)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d app(s) have configuration errors", failed)
+	}
+	return nil
+}
+
+// newDoctorAppResult converts the configuration issues of an app to its doctor result.
+func newDoctorAppResult(appName string, issues []config.ConfigIssue) doctorAppResult {
+	result := doctorAppResult{App: appName, Status: doctorPass, Checks: make([]doctorCheck, 0, len(issues))}
+	for _, issue := range issues {
+		status := doctorWarn
+		if issue.Severity == config.IssueError {
+			status = doctorFail
+		}
+		if status == doctorFail || result.Status == doctorPass {
+			result.Status = status
+		}
+
+		check := issue.Field
+		if check == "" {
+			check = "config"
+		}
+		result.Checks = append(result.Checks, doctorCheck{Check: check, Profile: issue.Profile, Status: status, Detail: issue.Message})
+	}
+	return result
+}
+
+// doctorValidateOptions returns the validation options for ob doctor, checking
+// stored credentials when the keyring is available.
+func doctorValidateOptions() []config.ValidateOption {
+	if credMgr == nil {
+		return nil
+	}
+	return []config.ValidateOption{config.WithCredentialLookup(credMgr.HasCredential)}
+}
+
+// newLintCmd creates the lint command.
+func newLintCmd() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "lint <spec>",
+		Short: "Check a specification for validity and usability problems",
+		Long: `Check an OpenAPI specification for problems that make the generated commands
+harder to use, such as operations without an operationId, summary, tags, or
+success response, and operation IDs that break the naming style of the rest
+of the spec. Validation errors and warnings are reported as well.
+
+Each finding has a severity (error, warning, info) and a category. The command
+fails if any error is found; warnings and suggestions do not fail it.
+
+Example:
+  ob lint ./openapi.yaml
+  ob lint https://petstore3.swagger.io/api/v3/openapi.json -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLint(cmd.OutOrStdout(), args[0], outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, yaml")
+
+	return cmd
+}
+
+// runLint lints a spec and prints its findings in the given output format.
+func runLint(out io.Writer, specSource, outputFormat string) error {
+	doc, err := specParser.LoadSpec(specSource)
+	if err != nil {
+		return fmt.Errorf("failed to load spec: %w", err)
+	}
+
+	findings := specParser.LintSpec(doc)
+	if findings == nil {
+		findings = []spec.LintFinding{}
+	}
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal lint findings to JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(findings)
+		if err != nil {
+			return fmt.Errorf("failed to marshal lint findings to YAML: %w", err)
+		}
+		_, _ = fmt.Fprint(out, string(data))
+	case "text":
+		printLintFindings(out, findings)
+	default:
+		return fmt.Errorf("unsupported output format: %s (valid formats: text, json, yaml)", outputFormat)
+	}
+
+	if spec.HasLintErrors(findings) {
+		return fmt.Errorf("spec has errors")
+	}
+	return nil
+}
+
+// printLintFindings prints lint findings one per line, followed by a count per severity.
+func printLintFindings(out io.Writer, findings []spec.LintFinding) {
+	if len(findings) == 0 {
+		_, _ = fmt.Fprintln(out, "✓ no issues found")
+		return
+	}
+
+	counts := make(map[spec.LintSeverity]int)
+	for _, finding := range findings {
+		_, _ = fmt.Fprintf(out, "  %s: %s\n", finding.Severity, finding)
+		counts[finding.Severity]++
+	}
+	_, _ = fmt.Fprintf(out, "\n%d error(s), %d warning(s), %d suggestion(s)\n",
+		counts[spec.LintError], counts[spec.LintWarning], counts[spec.LintInfo])
+}
+
+// convertCmdFlags holds the flags for the convert command.
+type convertCmdFlags struct {
+	target     string
+	format     string
+	outputFile string
+}
+
+// newConvertCmd creates the convert subcommand for converting Swagger 2.0 specs
+func newConvertCmd() *cobra.Command {
+	flags := &convertCmdFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "convert <spec-file>",
+		Short: "Convert a Swagger 2.0 specification to OpenAPI 3.x",
+		Long: `Convert a Swagger 2.0 specification to OpenAPI 3.0 without installing it.
+
+Constructs that cannot be represented exactly in OpenAPI 3.0 are converted as
+closely as possible and reported as warnings on stderr.
+
+Example:
+  ob convert swagger.json -o openapi3
+  ob convert swagger.yaml -o openapi3 --format yaml --file openapi.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConvertCmd(args[0], flags)
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.target, "output", "o", "openapi3", "Target specification version: openapi3")
+	cmd.Flags().StringVar(&flags.format, "format", "json", "Document format: json, yaml")
+	cmd.Flags().StringVarP(&flags.outputFile, "file", "f", "", "Write the converted document to a file (default: stdout)")
+
+	return cmd
+}
+
+// runConvertCmd reads a spec file, converts it and writes the result.
+func runConvertCmd(specFile string, flags *convertCmdFlags) error {
+	data, err := os.ReadFile(specFile)
+	if err != nil {
+		return fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	if flags.outputFile == "" {
+		return convertSpec(data, flags.target, flags.format, os.Stdout, os.Stderr)
+	}
+
+	// Convert before touching the output file so that a failed conversion
+	// leaves an existing file intact.
+	var out bytes.Buffer
+	if err := convertSpec(data, flags.target, flags.format, &out, os.Stderr); err != nil {
+		return err
+	}
+	if err := os.WriteFile(flags.outputFile, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// convertSpec converts a Swagger 2.0 document to the target version and writes it
+// in the requested format. Conversion warnings are written to warnOut.
+func convertSpec(data []byte, target, format string, out, warnOut io.Writer) error {
+	if target != "openapi3" {
+		return fmt.Errorf("unsupported conversion target: %s (valid targets: openapi3)", target)
+	}
+
+	result, err := spec.ConvertSwagger2(context.Background(), data)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range result.Warnings {
+		_, _ = fmt.Fprintf(warnOut, "Warning: %s\n", warning)
+	}
+
+	encoded, err := encodeSpecDocument(result.Spec, format)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(encoded)
+	return err
+}
+
+// encodeSpecDocument serializes a spec document as JSON or YAML.
+func encodeSpecDocument(doc any, format string) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal spec to JSON: %w", err)
+	}
+
+	switch format {
+	case "json":
+		return append(jsonData, '\n'), nil
+	case "yaml":
+		// Round-trip through a generic value so YAML keys match the JSON field names.
+		var generic any
+		if err := json.Unmarshal(jsonData, &generic); err != nil {
+			return nil, fmt.Errorf("failed to decode spec: %w", err)
+		}
+		data, err := yaml.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal spec to YAML: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (valid formats: json, yaml)", format)
+	}
+}
+
+// newRunCmd creates the run subcommand for running app commands
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <app-name> [args...]",
+		Short: "Run commands for an installed API application",
+		Long: `Run commands for an installed API application.
+
+Example:
+  ob run petstore list pets
+  ob run petstore create pet --name "Fluffy" --status available
+  ob run petstore list pets --base-url http://localhost:8080  # Send one call elsewhere
+  ob run petstore create pet --name Foo --dry-run  # Print the request without sending it
+  ob run petstore --mcp  # Start MCP server
+  ob run petstore --mcp --transport sse --metrics  # Also serve Prometheus metrics at /metrics`,
+		Args:               cobra.MinimumNArgs(1),
+		ValidArgsFunction:  completeRunArgs,
+		DisableFlagParsing: true, // Pass all flags to the app handler
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCommand(args)
+		},
+	}
+
+	return cmd
+}
+
+// runCommand executes the run command logic.
+func runCommand(args []string) error {
+	appName := args[0]
+
+	if !validateAppExists(appName) {
+		return fmt.Errorf("app not found: %s", appName)
+	}
+
+	appConfig, err := loadAppConfig(appName)
+	if err != nil {
+		return err
+	}
+
+	return executeRunMode(args, appConfig)
+}
+
+// validateAppExists checks if the app exists and shows helpful error if not.
+func validateAppExists(appName string) bool {
+	if configMgr.AppExists(appName) {
+		return true
+	}
+
+	apps, _ := configMgr.ListApps()
+	if len(apps) > 0 {
 		fmt.Fprintf(os.Stderr, "App '%s' not found.\n\nInstalled apps:\n", appName)
 		for _, app := range apps {
 			fmt.Fprintf(os.Stderr, "  %s\n", app)
@@ -839,15 +1954,30 @@ func validateAppExists(appName string) bool {
 	return false
 }
 
-// loadAppConfig loads the app configuration.
+// loadAppConfig loads the app configuration with the global defaults applied.
+// Configuration errors are printed to stderr as warnings.
 func loadAppConfig(appName string) (*config.AppConfig, error) {
-	appConfig, err := configMgr.GetAppConfig(appName)
+	appConfig, err := configMgr.GetEffectiveAppConfig(appName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load app config: %w", err)
 	}
+	warnConfigIssues(os.Stderr, appName, config.ValidateAppConfig(appConfig))
+	if err := applyEnvProfile(appConfig); err != nil {
+		return nil, err
+	}
 	return appConfig, nil
 }
 
+// warnConfigIssues prints the error-level configuration issues of an app as
+// warnings, pointing to ob doctor for details.
+func warnConfigIssues(w io.Writer, appName string, issues []config.ConfigIssue) {
+	for _, issue := range issues {
+		if issue.Severity == config.IssueError {
+			_, _ = fmt.Fprintf(w, "Warning: %s (run 'ob doctor %s' for details)\n", issue, appName)
+		}
+	}
+}
+
 // executeRunMode executes the appropriate run mode (CLI or MCP).
 func executeRunMode(args []string, appConfig *config.AppConfig) error {
 	isMCPMode := slices.Contains(args[1:], "--mcp")
@@ -864,6 +1994,7 @@ type mcpServerOptions struct {
 	profileName string
 	transport   string
 	port        string
+	metrics     bool
 }
 
 // parseMCPServerArgs parses MCP server arguments.
@@ -877,6 +2008,9 @@ func parseMCPServerArgs(args []string, defaultProfile string) mcpServerOptions {
 		opts.profileName = parseArgValue(arg, args, i, "--profile", "-p", opts.profileName)
 		opts.transport = parseArgValue(arg, args, i, "--transport", "", opts.transport)
 		opts.port = parseArgValue(arg, args, i, "--port", "", opts.port)
+		if arg == "--metrics" {
+			opts.metrics = true
+		}
 	}
 
 	if opts.profileName == "" {
@@ -913,6 +2047,12 @@ func startMCPServer(appConfig *config.AppConfig, args []string) error {
 	}
 
 	factory := mcp.NewServerFactory(appConfig.Name, version)
+	if opts.metrics {
+		if opts.transport != "sse" {
+			return fmt.Errorf("--metrics requires the sse transport, got '%s'", opts.transport)
+		}
+		factory.Metrics = mcp.NewMetrics()
+	}
 	server := factory.CreateServer()
 
 	// Check if progressive disclosure mode is enabled
@@ -931,10 +2071,14 @@ func startMCPServer(appConfig *config.AppConfig, args []string) error {
 			_ = progressiveHandler.Close()
 		}()
 
+		progressiveHandler.SetMapper(mapper)
+		progressiveHandler.SetAppConfig(appConfig, opts.profileName)
+		if factory.Metrics != nil {
+			progressiveHandler.SetMetrics(factory.Metrics)
+		}
 		if err := progressiveHandler.SetSpec(specDoc, &profile.SafetyConfig); err != nil {
 			return fmt.Errorf("failed to set spec: %w", err)
 		}
-		progressiveHandler.SetAppConfig(appConfig, opts.profileName)
 		progressiveHandler.Register(server)
 
 		fmt.Fprintf(os.Stderr, "Starting MCP server (progressive mode) for app '%s' (profile: %s) via %s...\n", appConfig.Name, opts.profileName, opts.transport)
@@ -942,6 +2086,9 @@ func startMCPServer(appConfig *config.AppConfig, args []string) error {
 		// Use standard Handler
 		mcpHandler.SetSpec(specDoc)
 		mcpHandler.SetAppConfig(appConfig, opts.profileName)
+		if factory.Metrics != nil {
+			mcpHandler.SetMetrics(factory.Metrics)
+		}
 		mcpHandler.Register(server, &profile.SafetyConfig)
 
 		fmt.Fprintf(os.Stderr, "Starting MCP server for app '%s' (profile: %s) via %s...\n", appConfig.Name, opts.profileName, opts.transport)
@@ -983,9 +2130,34 @@ func completeRunArgs(cmd *cobra.Command, args []string, toComplete string) ([]st
 		return completeVerbs(appName, args[1], toComplete)
 	}
 
+	// The run handler reads --profile among the flags after the verb.
+	if prev := args[len(args)-1]; len(args) > 3 && (prev == "--profile" || prev == "-p") {
+		return completeProfiles(appName, toComplete)
+	}
+
+	if prev := args[len(args)-1]; len(args) > 3 && strings.HasPrefix(prev, "-") && !strings.HasPrefix(toComplete, "-") {
+		if values := completionHelper.CompleteFlagValuesContext(commandContext(cmd), appName, args[1], args[2], prev); len(values) > 0 {
+			return values, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+
 	return completeFlags(appName, args[1], args[2], toComplete)
 }
 
+// commandContext returns the command's context, or a background context if none was set.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// completeProfiles completes profile names for an app.
+func completeProfiles(appName, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles := completionHelper.CompleteProfiles(appName, toComplete)
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
 // completeResources completes resource names.
 func completeResources(appName, toComplete string) ([]string, cobra.ShellCompDirective) {
 	resources := completionHelper.CompleteResources(appName, toComplete)
@@ -1055,3 +2227,72 @@ Fish:
 
 	return cmd
 }
+
+// versionInfo is the build metadata printed by ob version.
+type versionInfo struct {
+	Version       string `json:"version" yaml:"version"`
+	Commit        string `json:"commit" yaml:"commit"`
+	Date          string `json:"date" yaml:"date"`
+	GoVersion     string `json:"go_version" yaml:"go_version"`
+	ParserVersion string `json:"parser_version" yaml:"parser_version"`
+}
+
+// newVersionCmd creates the version command.
+func newVersionCmd() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version and build information",
+		Long: `Print the version of ob with the commit and date it was built from, the Go
+version it was built with, and the spec parser version used for the
+persistent spec cache.
+
+Example:
+  ob version
+  ob version -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runVersion(cmd.OutOrStdout(), outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, yaml")
+
+	return cmd
+}
+
+// runVersion prints the build information in the given output format.
+func runVersion(out io.Writer, outputFormat string) error {
+	info := versionInfo{
+		Version:       version,
+		Commit:        commit,
+		Date:          date,
+		GoVersion:     runtime.Version(),
+		ParserVersion: config.ParserVersion,
+	}
+
+	switch outputFormat {
+	case "text":
+		_, _ = fmt.Fprintf(out, "ob %s\n", info.Version)
+		_, _ = fmt.Fprintf(out, "  Commit:         %s\n", info.Commit)
+		_, _ = fmt.Fprintf(out, "  Built:          %s\n", info.Date)
+		_, _ = fmt.Fprintf(out, "  Go version:     %s\n", info.GoVersion)
+		_, _ = fmt.Fprintf(out, "  Parser version: %s\n", info.ParserVersion)
+	case "json":
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal version info to JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	case "yaml":
+		data, err := yaml.Marshal(info)
+		if err != nil {
+			return fmt.Errorf("failed to marshal version info to YAML: %w", err)
+		}
+		_, _ = fmt.Fprint(out, string(data))
+	default:
+		return fmt.Errorf("unsupported output format: %s (valid formats: text, json, yaml)", outputFormat)
+	}
+	return nil
+}
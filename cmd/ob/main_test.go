@@ -1,15 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/nomagicln/open-bridge/internal/testutil"
+	"github.com/nomagicln/open-bridge/pkg/cli"
+	"github.com/nomagicln/open-bridge/pkg/completion"
 	"github.com/nomagicln/open-bridge/pkg/config"
 	"github.com/nomagicln/open-bridge/pkg/credential"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestCreateCredentialFromParams(t *testing.T) {
@@ -262,6 +274,17 @@ func TestParseMCPServerArgs(t *testing.T) {
 				port:        "9090",
 			},
 		},
+		{
+			name:           "with metrics flag",
+			args:           []string{"--transport", "sse", "--metrics"},
+			defaultProfile: "default",
+			expected: mcpServerOptions{
+				profileName: "default",
+				transport:   "sse",
+				port:        "8080",
+				metrics:     true,
+			},
+		},
 		{
 			name:           "mixed syntax",
 			args:           []string{"--profile", "prod", "--transport=tcp", "-p", "staging"},
@@ -496,3 +519,603 @@ paths: {}
 	// Should still be "X-API-Key"
 	assert.Equal(t, "X-API-Key", profile.Auth.KeyName)
 }
+
+func TestNewConvertCmd(t *testing.T) {
+	cmd := newConvertCmd()
+	testCmdWithSingleArg(t, cmd, "convert <spec-file>", "Convert a Swagger 2.0 specification to OpenAPI 3.x")
+	assert.Equal(t, "openapi3", cmd.Flag("output").DefValue)
+}
+
+func TestConvertSpec(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "internal", "integration", "testdata", "swagger20.json"))
+	require.NoError(t, err)
+
+	t.Run("yaml output", func(t *testing.T) {
+		var out, warnings bytes.Buffer
+		require.NoError(t, convertSpec(data, "openapi3", "yaml", &out, &warnings))
+
+		var doc map[string]any
+		require.NoError(t, yaml.Unmarshal(out.Bytes(), &doc))
+		assert.Contains(t, doc["openapi"], "3.")
+		components, ok := doc["components"].(map[string]any)
+		require.True(t, ok, "expected components in output")
+		assert.Contains(t, components, "schemas")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		var out, warnings bytes.Buffer
+		require.NoError(t, convertSpec(data, "openapi3", "json", &out, &warnings))
+		assert.Contains(t, out.String(), `"openapi": "3.`)
+	})
+
+	t.Run("unsupported target", func(t *testing.T) {
+		var out, warnings bytes.Buffer
+		assert.Error(t, convertSpec(data, "swagger2", "json", &out, &warnings))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		var out, warnings bytes.Buffer
+		assert.Error(t, convertSpec(data, "openapi3", "toml", &out, &warnings))
+	})
+}
+
+func TestRunConvertCmd_OutputFile(t *testing.T) {
+	specFile := filepath.Join("..", "..", "internal", "integration", "testdata", "swagger20.json")
+	outputFile := filepath.Join(t.TempDir(), "openapi.json")
+	require.NoError(t, os.WriteFile(outputFile, []byte("previous"), 0644))
+
+	err := runConvertCmd(specFile, &convertCmdFlags{target: "openapi3", format: "toml", outputFile: outputFile})
+	require.Error(t, err)
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(data), "a failed conversion must not touch the output file")
+
+	require.NoError(t, runConvertCmd(specFile, &convertCmdFlags{target: "openapi3", format: "json", outputFile: outputFile}))
+	data, err = os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"openapi": "3.`)
+}
+
+// useTestConfigManager installs the named apps into a temporary config manager
+// and swaps it in as the global for the duration of the test.
+func useTestConfigManager(t *testing.T, apps ...string) *config.Manager {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	mgr, err := config.NewManager(config.WithConfigDir(tmpDir))
+	require.NoError(t, err)
+
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte("openapi: \"3.0.0\"\ninfo:\n  title: Test API\n  version: \"1.0.0\"\npaths: {}\n"), 0644))
+
+	for _, app := range apps {
+		_, err := mgr.InstallApp(app, config.InstallOptions{
+			SpecSource: specPath,
+			BaseURL:    "https://api.example.com",
+		})
+		require.NoError(t, err)
+	}
+
+	// The CLI handler caches command trees and sessions under its config
+	// manager's directory, so it is replaced too
+	original, originalHandler := configMgr, cliHandler
+	configMgr = mgr
+	cliHandler = cli.NewHandler(spec.NewParser(), semantic.NewMapper(), reqBuilder, mgr)
+	t.Cleanup(func() { configMgr, cliHandler = original, originalHandler })
+	return mgr
+}
+
+func TestUninstallAllApps(t *testing.T) {
+	mgr := useTestConfigManager(t, "bulk-one", "bulk-two", "bulk-three")
+
+	cmd := newUninstallCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--all", "--yes", "--remove-shim=false"})
+	require.NoError(t, cmd.Execute())
+
+	apps, err := mgr.ListApps()
+	require.NoError(t, err)
+	assert.Empty(t, apps)
+	assert.Contains(t, out.String(), "Uninstalled 3 of 3 app(s)")
+	assert.Contains(t, out.String(), "bulk-two")
+}
+
+func TestUninstallAllApps_RequiresConfirmation(t *testing.T) {
+	mgr := useTestConfigManager(t, "bulk-one", "bulk-two")
+
+	var out bytes.Buffer
+	err := uninstallAllApps(strings.NewReader("n\n"), &out, uninstallAllOptions{all: true})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "This will uninstall 2 app(s)")
+	assert.Contains(t, out.String(), "Aborted.")
+
+	apps, err := mgr.ListApps()
+	require.NoError(t, err)
+	assert.Len(t, apps, 2)
+
+	out.Reset()
+	require.NoError(t, uninstallAllApps(strings.NewReader("yes\n"), &out, uninstallAllOptions{all: true}))
+	apps, err = mgr.ListApps()
+	require.NoError(t, err)
+	assert.Empty(t, apps)
+}
+
+func TestNewUninstallCmd_AllRejectsAppName(t *testing.T) {
+	cmd := newUninstallCmd()
+	require.NoError(t, cmd.Flags().Set("all", "true"))
+	assert.NoError(t, cmd.Args(cmd, []string{}))
+	assert.Error(t, cmd.Args(cmd, []string{"testapp"}))
+}
+
+func TestNewInfoCmd_DiffProfilesArgs(t *testing.T) {
+	cmd := newInfoCmd()
+	assert.NoError(t, cmd.Args(cmd, []string{"testapp"}))
+	assert.Error(t, cmd.Args(cmd, []string{"testapp", "prod", "staging"}))
+
+	require.NoError(t, cmd.Flags().Set("diff-profiles", "true"))
+	assert.NoError(t, cmd.Args(cmd, []string{"testapp", "prod", "staging"}))
+	assert.Error(t, cmd.Args(cmd, []string{"testapp", "prod"}))
+}
+
+func TestShowProfileDiff_MissingProfile(t *testing.T) {
+	useTestConfigManager(t, "diffapp")
+
+	err := showProfileDiff("diffapp", "default", "staging", "text")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "profile 'staging' not found")
+}
+
+func TestRunDoctor(t *testing.T) {
+	mgr := useTestConfigManager(t, "healthy", "broken")
+
+	appConfig, err := mgr.GetAppConfig("broken")
+	require.NoError(t, err)
+	appConfig.DefaultProfile = "missing"
+	require.NoError(t, mgr.SaveAppConfig(appConfig))
+
+	var out bytes.Buffer
+	err = runDoctor(&out, nil, "text")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 app(s) have configuration errors")
+	assert.Contains(t, out.String(), "✓ healthy: no issues found")
+	assert.Contains(t, out.String(), "error: default_profile: default profile 'missing' does not exist")
+
+	out.Reset()
+	require.NoError(t, runDoctor(&out, []string{"healthy"}, "text"))
+	assert.NotContains(t, out.String(), "broken")
+}
+
+func TestRunDoctor_JSON(t *testing.T) {
+	mgr := useTestConfigManager(t, "healthy", "broken")
+
+	appConfig, err := mgr.GetAppConfig("broken")
+	require.NoError(t, err)
+	appConfig.DefaultProfile = "missing"
+	require.NoError(t, mgr.SaveAppConfig(appConfig))
+
+	var out bytes.Buffer
+	err = runDoctor(&out, []string{"broken", "healthy"}, "json")
+	require.EqualError(t, err, "1 app(s) have configuration errors")
+
+	var report doctorReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report), "stdout: %s", out.String())
+	assert.False(t, report.Passed)
+	require.Len(t, report.Apps, 2)
+
+	assert.Equal(t, "broken", report.Apps[0].App)
+	assert.Equal(t, "fail", report.Apps[0].Status)
+	assert.Contains(t, report.Apps[0].Checks, doctorCheck{
+		Check:  "default_profile",
+		Status: "fail",
+		Detail: "default profile 'missing' does not exist",
+	})
+
+	assert.Equal(t, doctorAppResult{App: "healthy", Status: "pass", Checks: []doctorCheck{}}, report.Apps[1])
+
+	out.Reset()
+	require.NoError(t, runDoctor(&out, []string{"healthy"}, "json"))
+	assert.JSONEq(t, `{"passed":true,"apps":[{"app":"healthy","status":"pass","checks":[]}]}`, out.String())
+}
+
+func TestRunLint(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(`openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+`), 0644))
+
+	var out bytes.Buffer
+	require.NoError(t, runLint(&out, specPath, "json"))
+
+	var findings []spec.LintFinding
+	require.NoError(t, json.Unmarshal(out.Bytes(), &findings))
+	assert.Equal(t, []spec.LintFinding{
+		{Severity: spec.LintWarning, Category: "documentation", Location: "GET /users", Message: "operation has no summary or description"},
+		{Severity: spec.LintWarning, Category: "tags", Location: "GET /users", Message: "operation has no tags"},
+	}, findings)
+
+	out.Reset()
+	require.NoError(t, runLint(&out, specPath, "text"))
+	assert.Contains(t, out.String(), "  warning: [tags] GET /users: operation has no tags")
+	assert.Contains(t, out.String(), "0 error(s), 2 warning(s), 0 suggestion(s)")
+
+	err := runLint(&out, specPath, "xml")
+	assert.ErrorContains(t, err, "unsupported output format: xml")
+}
+
+func TestRunInstallCmd_Check(t *testing.T) {
+	mgr := useTestConfigManager(t)
+
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte("openapi: \"3.0.0\"\ninfo:\n  title: Test API\n  version: \"1.0.0\"\npaths: {}\n"), 0644))
+
+	err := runInstallCmd("checked-app", &installCmdFlags{
+		specSource: specPath,
+		baseURL:    "https://api.example.com",
+		createShim: true,
+		check:      true,
+	})
+	require.NoError(t, err)
+	assert.False(t, mgr.AppExists("checked-app"))
+
+	err = runInstallCmd("checked-app", &installCmdFlags{
+		specSource: filepath.Join(t.TempDir(), "missing.yaml"),
+		check:      true,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "install check failed")
+}
+
+func TestRunInstallCmd_InvalidVerbMappings(t *testing.T) {
+	mgr := useTestConfigManager(t)
+
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte("openapi: \"3.0.0\"\ninfo:\n  title: Test API\n  version: \"1.0.0\"\npaths: {}\n"), 0644))
+
+	err := runInstallCmd("verbs-app", &installCmdFlags{
+		specSource: specPath,
+		baseURL:    "https://api.example.com",
+		verbMap:    map[string]string{"TRACE": "trace"},
+	})
+	assert.ErrorContains(t, err, "invalid verb mappings")
+	assert.False(t, mgr.AppExists("verbs-app"))
+}
+
+func TestPrintInstallResult_AliasSuggestion(t *testing.T) {
+	t.Setenv("SHELL", "/bin/bash")
+
+	var out bytes.Buffer
+	printInstallResult(&out, "petstore", &config.InstallResult{AppName: "petstore", ConfigPath: "/tmp/petstore.yaml"})
+	assert.Contains(t, out.String(), "  ob run petstore <verb> <resource> [flags]\n")
+	assert.Contains(t, out.String(), "  alias petstore='ob run petstore'\n")
+
+	t.Setenv("SHELL", "/usr/bin/fish")
+	out.Reset()
+	printInstallResult(&out, "petstore", &config.InstallResult{AppName: "petstore", ConfigPath: "/tmp/petstore.yaml"})
+	assert.Contains(t, out.String(), "  alias petstore 'ob run petstore'\n")
+
+	out.Reset()
+	printInstallResult(&out, "petstore", &config.InstallResult{AppName: "petstore", ConfigPath: "/tmp/petstore.yaml", ShimPath: "/home/user/.local/bin/petstore"})
+	assert.Contains(t, out.String(), "  Shim: /home/user/.local/bin/petstore\n")
+	assert.Contains(t, out.String(), "  petstore <verb> <resource> [flags]\n")
+	assert.NotContains(t, out.String(), "alias")
+}
+
+func TestResolveAppArgs(t *testing.T) {
+	useTestConfigManager(t, "petstore", "other")
+	root := newRootCmd()
+
+	tests := []struct {
+		name   string
+		envApp string
+		args   []string
+		want   []string
+	}{
+		{"env app", "petstore", []string{"pet", "get", "--petId", "1"}, []string{"run", "petstore", "pet", "get", "--petId", "1"}},
+		{"explicit app overrides env", "petstore", []string{"other", "pet", "get"}, []string{"run", "other", "pet", "get"}},
+		{"explicit run overrides env", "petstore", []string{"run", "other", "pet", "get"}, []string{"run", "other", "pet", "get"}},
+		{"subcommand", "petstore", []string{"list"}, []string{"list"}},
+		{"help command", "petstore", []string{"help"}, []string{"help"}},
+		{"flag", "petstore", []string{"--version"}, []string{"--version"}},
+		{"no env app", "", []string{"pet", "get"}, []string{"pet", "get"}},
+		{"no args", "petstore", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envApp, tt.envApp)
+			assert.Equal(t, tt.want, resolveAppArgs(root, tt.args))
+		})
+	}
+}
+
+func TestApplyEnvProfile(t *testing.T) {
+	appConfig := &config.AppConfig{
+		Name:           "petstore",
+		DefaultProfile: "default",
+		Profiles:       map[string]config.Profile{"default": {Name: "default"}, "staging": {Name: "staging"}},
+	}
+
+	t.Setenv(envProfile, "")
+	require.NoError(t, applyEnvProfile(appConfig))
+	assert.Equal(t, "default", appConfig.DefaultProfile)
+
+	t.Setenv(envProfile, "staging")
+	require.NoError(t, applyEnvProfile(appConfig))
+	assert.Equal(t, "staging", appConfig.DefaultProfile)
+
+	t.Setenv(envProfile, "missing")
+	assert.EqualError(t, applyEnvProfile(appConfig), "profile 'missing' from OB_PROFILE not found in app 'petstore'")
+}
+
+func TestRunCommand_ProfileFlagOverridesEnvProfile(t *testing.T) {
+	mgr := useTestConfigManager(t)
+
+	var hits []string
+	serverURL := func(name string) string {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			hits = append(hits, name)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+		}))
+		t.Cleanup(server.Close)
+		return server.URL
+	}
+
+	specPath := filepath.Join(t.TempDir(), "petstore.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(testutil.PetstoreOpenAPISpec), 0644))
+	_, err := mgr.InstallApp("petstore", config.InstallOptions{SpecSource: specPath, BaseURL: serverURL("default")})
+	require.NoError(t, err)
+	appConfig, err := mgr.GetAppConfig("petstore")
+	require.NoError(t, err)
+	appConfig.AddProfile(config.NewProfile("staging", serverURL("staging")))
+	appConfig.AddProfile(config.NewProfile("prod", serverURL("prod")))
+	require.NoError(t, mgr.SaveAppConfig(appConfig))
+
+	var out bytes.Buffer
+	cliHandler.SetOutput(&out)
+
+	t.Setenv(envProfile, "staging")
+	require.NoError(t, runCommand([]string{"petstore", "pet", "get", "--petId", "1", "--json"}))
+	require.NoError(t, runCommand([]string{"petstore", "pet", "get", "--petId", "1", "--profile", "prod", "--json"}))
+	require.NoError(t, runCommand([]string{"petstore", "pet", "get", "--petId", "1", "-p", "default", "--json"}))
+	assert.Equal(t, []string{"staging", "prod", "default"}, hits)
+
+	err = runCommand([]string{"petstore", "pet", "get", "--petId", "1", "--profile", "missing"})
+	assert.EqualError(t, err, "profile 'missing' not found in app 'petstore'")
+}
+
+func TestCompleteRunArgs_Profile(t *testing.T) {
+	mgr := useTestConfigManager(t, "petstore")
+	appConfig, err := mgr.GetAppConfig("petstore")
+	require.NoError(t, err)
+	appConfig.AddProfile(config.NewProfile("prod", "https://prod.example.com"))
+	require.NoError(t, mgr.SaveAppConfig(appConfig))
+
+	original := completionHelper
+	completionHelper = completion.NewProvider(mgr, specParser, mapper)
+	t.Cleanup(func() { completionHelper = original })
+
+	cmd := newRunCmd()
+	values, _ := completeRunArgs(cmd, []string{"petstore", "pet", "get", "--profile"}, "")
+	assert.ElementsMatch(t, []string{"default\tdefault", "prod"}, values)
+	values, _ = completeRunArgs(cmd, []string{"petstore", "pet", "get", "--petId", "1", "-p"}, "p")
+	assert.Equal(t, []string{"prod"}, values)
+
+	values, _ = completeRunArgs(cmd, []string{"petstore", "--profile"}, "")
+	assert.Empty(t, values, "the run handler does not read --profile before the resource and verb")
+}
+
+func TestShowWebhooks(t *testing.T) {
+	mgr := useTestConfigManager(t)
+
+	specPath := filepath.Join(t.TempDir(), "events.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(`openapi: 3.1.0
+info:
+  title: Pet Events
+  version: 1.0.0
+paths: {}
+webhooks:
+  newPet:
+    post:
+      summary: A pet was added to the store
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          example: doggie
+`), 0644))
+	_, err := mgr.InstallApp("events", config.InstallOptions{SpecSource: specPath, BaseURL: "https://api.example.com"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, showWebhooks(&out, "events", "text"))
+	assert.Contains(t, out.String(), "Webhooks and callbacks (1)")
+	assert.Contains(t, out.String(), "  webhook newPet POST\n")
+	assert.Contains(t, out.String(), "    Payload: application/json Pet\n")
+	assert.Contains(t, out.String(), `    Example: {"name":"doggie"}`)
+
+	out.Reset()
+	require.NoError(t, showWebhooks(&out, "events", "json"))
+	var entries []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entries), "stdout: %s", out.String())
+	require.Len(t, entries, 1)
+	assert.Equal(t, "webhook", entries[0]["kind"])
+	assert.Equal(t, "newPet", entries[0]["name"])
+	assert.Equal(t, map[string]any{"name": "doggie"}, entries[0]["example"])
+
+	err = showWebhooks(&out, "missing", "text")
+	assert.EqualError(t, err, "app 'missing' not found")
+}
+
+func TestMigrateCredentials(t *testing.T) {
+	useTestConfigManager(t, "petstore", "other")
+
+	newFileManager := func() *credential.Manager {
+		mgr, err := credential.NewManager(credential.BackendOptions("file", map[string]string{"dir": t.TempDir()})...)
+		require.NoError(t, err)
+		return mgr
+	}
+	src, dest := newFileManager(), newFileManager()
+	require.NoError(t, src.StoreCredential("petstore", "default", credential.NewBearerCredential("token")))
+	require.NoError(t, src.StoreCredential("other", "prod", credential.NewAPIKeyCredential("key")))
+
+	var out bytes.Buffer
+	require.NoError(t, migrateCredentials(&out, src, dest, true))
+	assert.Contains(t, out.String(), "✓ petstore/default\n")
+	assert.Contains(t, out.String(), "✓ other/prod\n")
+	assert.Contains(t, out.String(), "Migrated 2 of 2 credential(s) from file to file")
+
+	cred, err := dest.GetCredential("petstore", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "token", cred.Token)
+	assert.False(t, src.HasCredential("other", "prod"))
+
+	out.Reset()
+	require.NoError(t, migrateCredentials(&out, src, dest, false))
+	assert.Equal(t, "No credentials are stored in file.\n", out.String())
+
+	err = migrateCredentials(&out, dest, dest, true)
+	require.ErrorIs(t, err, credential.ErrSameStore)
+	assert.True(t, dest.HasCredential("petstore", "default"))
+}
+
+func TestExportImportBundle(t *testing.T) {
+	mgr := useTestConfigManager(t, "petstore")
+	appConfig, err := mgr.GetAppConfig("petstore")
+	require.NoError(t, err)
+	prod := config.NewProfile("prod", "https://prod.example.com")
+	prod.Auth = config.AuthConfig{Type: "basic"}
+	appConfig.AddProfile(prod)
+	require.NoError(t, mgr.SaveAppConfig(appConfig))
+
+	creds, err := credential.NewManager(credential.BackendOptions("file", map[string]string{"dir": t.TempDir()})...)
+	require.NoError(t, err)
+	require.NoError(t, creds.StoreCredential("petstore", "default", credential.NewBearerCredential("bearer-secret")))
+	require.NoError(t, creds.StoreCredential("petstore", "prod", credential.NewBasicCredential("admin", "basic-secret")))
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.yaml")
+	var out bytes.Buffer
+	require.NoError(t, exportApp(&out, creds, "petstore", bundlePath))
+	assert.Contains(t, out.String(), "2 credential reference(s) included")
+
+	data, err := os.ReadFile(bundlePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "bearer-secret")
+	assert.NotContains(t, string(data), "basic-secret")
+	assert.Contains(t, string(data), "type: bearer")
+	assert.Contains(t, string(data), "type: basic")
+
+	out.Reset()
+	in := strings.NewReader("new-bearer-token\n")
+	opts := importBundleOptions{name: "petstore-copy", secrets: []string{"prod=admin:s3:cret"}}
+	require.NoError(t, importBundle(in, &out, creds, data, opts))
+	assert.Contains(t, out.String(), "✓ Imported app 'petstore-copy' with 2 profile(s)")
+	assert.Contains(t, out.String(), "Enter bearer token: ")
+
+	cred, err := creds.GetCredential("petstore-copy", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "new-bearer-token", cred.Token)
+	cred, err = creds.GetCredential("petstore-copy", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", cred.Username)
+	assert.Equal(t, "s3:cret", cred.Password)
+
+	out.Reset()
+	opts = importBundleOptions{name: "petstore-bare", noPrompt: true}
+	require.NoError(t, importBundle(strings.NewReader(""), &out, creds, data, opts))
+	assert.Contains(t, out.String(), "No credential stored for profile(s): default, prod")
+	assert.False(t, creds.HasCredential("petstore-bare", "default"))
+
+	err = importBundle(strings.NewReader(""), &out, creds, data, importBundleOptions{name: "petstore-copy"})
+	assert.EqualError(t, err, "app 'petstore-copy' is already installed")
+}
+
+func TestExportImportBundle_OAuth2ClientCredentials(t *testing.T) {
+	mgr := useTestConfigManager(t, "petstore")
+	appConfig, err := mgr.GetAppConfig("petstore")
+	require.NoError(t, err)
+	profile := appConfig.Profiles["default"]
+	profile.Auth = config.AuthConfig{Type: "oauth2_client_credentials"}
+	appConfig.Profiles["default"] = profile
+	require.NoError(t, mgr.SaveAppConfig(appConfig))
+
+	creds, err := credential.NewManager(credential.BackendOptions("file", map[string]string{"dir": t.TempDir()})...)
+	require.NoError(t, err)
+	require.NoError(t, creds.StoreCredential("petstore", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "client-secret", "https://auth.example.com/token", []string{"read", "write"})))
+
+	var out bytes.Buffer
+	bundlePath := filepath.Join(t.TempDir(), "bundle.yaml")
+	require.NoError(t, exportApp(&out, creds, "petstore", bundlePath))
+	data, err := os.ReadFile(bundlePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "client-secret")
+
+	opts := importBundleOptions{name: "petstore-secret", secrets: []string{"default=new-client:new-secret"}}
+	require.NoError(t, importBundle(strings.NewReader(""), &out, creds, data, opts))
+	cred, err := creds.GetCredential("petstore-secret", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "new-client", cred.ClientID)
+	assert.Equal(t, "new-secret", cred.ClientSecret)
+	assert.Equal(t, "https://auth.example.com/token", cred.TokenURL)
+	assert.Equal(t, []string{"read", "write"}, cred.Scopes)
+
+	out.Reset()
+	in := strings.NewReader("prompted-client\nprompted-secret\n")
+	require.NoError(t, importBundle(in, &out, creds, data, importBundleOptions{name: "petstore-prompted"}))
+	assert.Contains(t, out.String(), "Client ID: ")
+	cred, err = creds.GetCredential("petstore-prompted", "default")
+	require.NoError(t, err)
+	assert.Equal(t, "prompted-client", cred.ClientID)
+	assert.Equal(t, "prompted-secret", cred.ClientSecret)
+	assert.Equal(t, "https://auth.example.com/token", cred.TokenURL)
+}
+
+func TestRunVersion_JSON(t *testing.T) {
+	originalVersion, originalCommit, originalDate := version, commit, date
+	version, commit, date = "1.2.3", "abc1234", "2026-01-02T03:04:05Z"
+	t.Cleanup(func() { version, commit, date = originalVersion, originalCommit, originalDate })
+
+	cmd := newVersionCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"-o", "json"})
+	require.NoError(t, cmd.Execute())
+
+	var info map[string]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info), "stdout: %s", out.String())
+	assert.Equal(t, map[string]string{
+		"version":        "1.2.3",
+		"commit":         "abc1234",
+		"date":           "2026-01-02T03:04:05Z",
+		"go_version":     runtime.Version(),
+		"parser_version": config.ParserVersion,
+	}, info)
+
+	out.Reset()
+	require.NoError(t, runVersion(&out, "text"))
+	assert.Contains(t, out.String(), "ob 1.2.3\n")
+	assert.Contains(t, out.String(), "Commit:         abc1234")
+
+	assert.Error(t, runVersion(&out, "xml"))
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 	"gopkg.in/yaml.v3"
 )
 
@@ -517,6 +521,59 @@ func BuildNestedBody(flatBodyParams map[string]any) (map[string]any, error) {
 	return result, nil
 }
 
+// ResolveParamFileReferences replaces "@path" values of string-typed parameters and
+// body fields with the verbatim contents of the referenced file. Unlike the whole-body
+// "--body @file.json" form, the file is never parsed as JSON or YAML, which makes it
+// suitable for values such as PEM-encoded keys.
+func ResolveParamFileReferences(params map[string]any, opSpec *openapi3.Operation) error {
+	for name, value := range params {
+		if name == "body" {
+			continue
+		}
+
+		ref, ok := value.(string)
+		if !ok || !strings.HasPrefix(ref, "@") || !isStringParam(opSpec, name) {
+			continue
+		}
+
+		content, err := readParamFile(name, ref)
+		if err != nil {
+			return err
+		}
+		params[name] = content
+	}
+	return nil
+}
+
+// isStringParam reports whether a parameter or body field is declared with a string schema.
+func isStringParam(opSpec *openapi3.Operation, name string) bool {
+	for _, location := range []string{"path", "query", "header", "cookie"} {
+		if param, ok := getParamFromSpec(opSpec, name, location); ok {
+			return param.Schema != nil && isStringSchema(param.Schema.Value)
+		}
+	}
+	return isStringSchema(getBodyParamSchema(opSpec, name))
+}
+
+// isStringSchema reports whether a schema is of type string.
+func isStringSchema(schema *openapi3.Schema) bool {
+	return schema != nil && schema.Type != nil && schema.Type.Is("string")
+}
+
+// readParamFile reads the file referenced by an "@path" value as a raw string.
+func readParamFile(name, ref string) (string, error) {
+	absPath, err := resolveFilePath(ref[1:])
+	if err != nil {
+		return "", fmt.Errorf("failed to load --%s from %s: %w", name, ref[1:], err)
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load --%s from %s: %w", name, ref[1:], err)
+	}
+	return string(data), nil
+}
+
 // parseValueWithContext is an internal helper for parsing values in context of a parameter's schema.
 // Supports type-aware parsing for proper JSON serialization.
 // If schema is nil, performs generic parsing.
@@ -719,18 +776,24 @@ func getBodyParamSchema(opSpec *openapi3.Operation, paramName string) *openapi3.
 
 // convertToSchemaType converts a value to match the schema type
 func convertToSchemaType(schemaValue *openapi3.Schema, parsed any) any {
-	if schemaValue.Type == nil {
+	if parsed == nil && request.AllowsNull(schemaValue) {
+		return nil
+	}
+
+	// A union such as ["string", "integer"] accepts the value as parsed
+	types := request.SchemaTypes(schemaValue)
+	if len(types) != 1 {
 		return parsed
 	}
 
-	switch {
-	case schemaValue.Type.Is("string"):
+	switch types[0] {
+	case "string":
 		return convertToString(parsed)
-	case schemaValue.Type.Is("integer"):
+	case "integer":
 		return convertToInteger(parsed)
-	case schemaValue.Type.Is("number"):
+	case "number":
 		return convertToNumber(parsed)
-	case schemaValue.Type.Is("boolean"):
+	case "boolean":
 		return convertToBoolean(parsed)
 	default:
 		return parsed
@@ -921,3 +984,117 @@ func setNestedValue(obj map[string]any, path string, value any) error {
 	current[finalKey] = value
 	return nil
 }
+
+// SplitPositionalArgs separates leading positional arguments from flag arguments.
+// Positional arguments are the tokens before the first one that starts with "-".
+//
+// Example:
+//
+//	["1", "--verbose"] -> (["1"], ["--verbose"])
+func SplitPositionalArgs(args []string) (positional []string, rest []string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+// AssignPositionalPathParams maps positional arguments to the operation's path
+// parameters in declaration order, so "pet get 1" is equivalent to "pet get --petId 1".
+// When an operation has several path parameters, values are assigned in the order
+// the parameters are declared in the spec; any parameter may instead be given as a flag.
+// Supplying more positional values than path parameters, or giving the same
+// parameter both positionally and as a flag, is an error. opParams must include
+// the path item's parameters, see spec.OperationParameters.
+func AssignPositionalPathParams(params map[string]any, opParams openapi3.Parameters, positional []string) error {
+	if len(positional) == 0 {
+		return nil
+	}
+
+	var pathParams []string
+	for _, paramRef := range opParams {
+		if paramRef != nil && paramRef.Value != nil && paramRef.Value.In == "path" {
+			pathParams = append(pathParams, paramRef.Value.Name)
+		}
+	}
+
+	if len(positional) > len(pathParams) {
+		return fmt.Errorf("too many positional arguments: got %d, operation has %d path parameter(s)", len(positional), len(pathParams))
+	}
+
+	for i, value := range positional {
+		name := pathParams[i]
+		if _, exists := params[name]; exists {
+			return fmt.Errorf("path parameter '%s' given both positionally and as --%s", name, name)
+		}
+		params[name] = value
+	}
+
+	return nil
+}
+
+// ApplyProfileDefaultParams fills in the profile's default parameter values
+// for parameters the operation declares and the command did not set, so a
+// profile default of limit=100 applies only to operations with a limit
+// parameter and is overridden by --limit.
+func ApplyProfileDefaultParams(params map[string]any, opSpec *openapi3.Operation, profile *config.Profile) {
+	if profile == nil || len(profile.DefaultParams) == 0 {
+		return
+	}
+	for _, paramRef := range opSpec.Parameters {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		name := paramRef.Value.Name
+		value, ok := profile.DefaultParams[name]
+		if !ok {
+			continue
+		}
+		if _, exists := params[name]; !exists {
+			params[name] = value
+		}
+	}
+}
+
+// DeprecatedParams returns the sorted names of the given parameters that the
+// operation marks as deprecated. Aliases must already be resolved.
+func DeprecatedParams(params map[string]any, opSpec *openapi3.Operation) []string {
+	var deprecated []string
+	for _, paramRef := range opSpec.Parameters {
+		if paramRef == nil || paramRef.Value == nil || !paramRef.Value.Deprecated {
+			continue
+		}
+		if _, ok := params[paramRef.Value.Name]; ok && !slices.Contains(deprecated, paramRef.Value.Name) {
+			deprecated = append(deprecated, paramRef.Value.Name)
+		}
+	}
+	slices.Sort(deprecated)
+	return deprecated
+}
+
+// ResolveParamAliases renames parameters given by an x-ob-alias name to their
+// canonical names, so "--issue 5" is equivalent to "--issue_number 5". Giving a
+// parameter both by its canonical name and an alias is an error. opParams
+// should include the path item's parameters, as returned by
+// spec.OperationParameters.
+func ResolveParamAliases(params map[string]any, opParams openapi3.Parameters) error {
+	for _, paramRef := range opParams {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		name := paramRef.Value.Name
+		for _, alias := range spec.ParameterAliases(paramRef.Value) {
+			value, ok := params[alias]
+			if !ok {
+				continue
+			}
+			if _, exists := params[name]; exists {
+				return fmt.Errorf("parameter '%s' given both as --%s and --%s", name, name, alias)
+			}
+			params[name] = value
+			delete(params, alias)
+		}
+	}
+	return nil
+}
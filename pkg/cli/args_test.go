@@ -1,9 +1,21 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 )
 
 // TestSplitArgs_NoDelimiter tests splitting args without '--' delimiter
@@ -448,3 +460,658 @@ func TestParseRequestParams_EmptyArgs(t *testing.T) {
 		t.Errorf("expected empty params")
 	}
 }
+
+// TestParseRequestParams_NullableAndUnionBody tests OpenAPI 3.1 nullable and union body fields
+func TestParseRequestParams_NullableAndUnionBody(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("nickname", &openapi3.Schema{Type: &openapi3.Types{"string", "null"}}).
+		WithProperty("age", &openapi3.Schema{Type: &openapi3.Types{"integer", "null"}}).
+		WithProperty("code", &openapi3.Schema{Type: &openapi3.Types{"string", "null"}}).
+		WithProperty("tag", &openapi3.Schema{Type: &openapi3.Types{"string", "integer"}})
+	opSpec := &openapi3.Operation{
+		RequestBody: &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(schema)},
+	}
+
+	args := []string{"--body:nickname=null", "--body:age=3", "--body:code=42", "--body:tag=7"}
+	result, err := ParseRequestParams(args, opSpec, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{"nickname": nil, "age": int64(3), "code": "42", "tag": int64(7)}
+	if !reflect.DeepEqual(result.Body, want) {
+		t.Errorf("expected body %v, got %v", want, result.Body)
+	}
+}
+
+// paramFileOperation returns an operation with string, integer and body parameters.
+func paramFileOperation() *openapi3.Operation {
+	stringSchema := openapi3.NewStringSchema().NewRef()
+	return &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			{Value: &openapi3.Parameter{Name: "X-Client-Cert", In: "header", Schema: stringSchema}},
+			{Value: &openapi3.Parameter{Name: "limit", In: "query", Schema: openapi3.NewIntegerSchema().NewRef()}},
+		},
+		RequestBody: &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(
+			openapi3.NewObjectSchema().WithProperty("public_key", openapi3.NewStringSchema()),
+		)},
+	}
+}
+
+// TestResolveParamFileReferences_ReachesRequest tests that @file values are loaded and sent
+func TestResolveParamFileReferences_ReachesRequest(t *testing.T) {
+	dir := t.TempDir()
+	pemPath := filepath.Join(dir, "pub.pem")
+	pem := "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE\n-----END PUBLIC KEY-----\n"
+	if err := os.WriteFile(pemPath, []byte(pem), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	certPath := filepath.Join(dir, "cert.txt")
+	if err := os.WriteFile(certPath, []byte("cert-data"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	opSpec := paramFileOperation()
+	params := map[string]any{
+		"public_key":    "@" + pemPath,
+		"X-Client-Cert": "@" + certPath,
+		"limit":         "10",
+	}
+
+	if err := ResolveParamFileReferences(params, opSpec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["public_key"] != pem {
+		t.Errorf("expected public_key to be the file contents, got %q", params["public_key"])
+	}
+
+	req, err := request.NewBuilder(nil).BuildRequest(
+		"POST", "/keys", "https://api.example.com", params, opSpec.Parameters, opSpec.RequestBody.Value,
+	)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+
+	if got := req.Header.Get("X-Client-Cert"); got != "cert-data" {
+		t.Errorf("expected header from file, got %q", got)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body["public_key"] != pem {
+		t.Errorf("expected body public_key from file, got %q", body["public_key"])
+	}
+}
+
+// TestResolveParamFileReferences_MissingFile tests the error for a missing file
+func TestResolveParamFileReferences_MissingFile(t *testing.T) {
+	params := map[string]any{"public_key": "@/nonexistent/pub.pem"}
+
+	err := ResolveParamFileReferences(params, paramFileOperation())
+	if err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if !strings.Contains(err.Error(), "--public_key") || !strings.Contains(err.Error(), "file not found") {
+		t.Errorf("expected error to name the parameter and missing file, got: %v", err)
+	}
+}
+
+// TestResolveParamFileReferences_SkipsNonStringAndBody tests that other values are untouched
+func TestResolveParamFileReferences_SkipsNonStringAndBody(t *testing.T) {
+	params := map[string]any{
+		"limit":   "@/nonexistent/limit",
+		"body":    "@/nonexistent/body.json",
+		"unknown": "@/nonexistent/other",
+	}
+
+	if err := ResolveParamFileReferences(params, paramFileOperation()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["limit"] != "@/nonexistent/limit" || params["body"] != "@/nonexistent/body.json" {
+		t.Errorf("expected non-string and body values to be untouched, got %v", params)
+	}
+}
+
+func TestSplitPositionalArgs(t *testing.T) {
+	positional, rest := SplitPositionalArgs([]string{"1", "--json"})
+	if len(positional) != 1 || positional[0] != "1" {
+		t.Errorf("expected positional [1], got %v", positional)
+	}
+	if len(rest) != 1 || rest[0] != "--json" {
+		t.Errorf("expected rest [--json], got %v", rest)
+	}
+
+	positional, rest = SplitPositionalArgs([]string{"--petId", "1"})
+	if len(positional) != 0 || len(rest) != 2 {
+		t.Errorf("expected no positional args, got %v / %v", positional, rest)
+	}
+}
+
+func TestAssignPositionalPathParams(t *testing.T) {
+	opSpec := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			{Value: &openapi3.Parameter{Name: "petId", In: "path", Required: true}},
+			{Value: &openapi3.Parameter{Name: "verbose", In: "query"}},
+		},
+	}
+
+	t.Run("single path param", func(t *testing.T) {
+		params := map[string]any{}
+		if err := AssignPositionalPathParams(params, opSpec.Parameters, []string{"1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if params["petId"] != "1" {
+			t.Errorf("expected petId=1, got %v", params["petId"])
+		}
+	})
+
+	t.Run("too many positional args", func(t *testing.T) {
+		err := AssignPositionalPathParams(map[string]any{}, opSpec.Parameters, []string{"1", "2"})
+		if err == nil || !strings.Contains(err.Error(), "too many positional arguments") {
+			t.Errorf("expected too many positional arguments error, got %v", err)
+		}
+	})
+
+	t.Run("conflicts with flag", func(t *testing.T) {
+		err := AssignPositionalPathParams(map[string]any{"petId": "2"}, opSpec.Parameters, []string{"1"})
+		if err == nil || !strings.Contains(err.Error(), "both positionally and as --petId") {
+			t.Errorf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("multiple path params in declaration order", func(t *testing.T) {
+		multi := &openapi3.Operation{
+			Parameters: openapi3.Parameters{
+				{Value: &openapi3.Parameter{Name: "ownerId", In: "path", Required: true}},
+				{Value: &openapi3.Parameter{Name: "petId", In: "path", Required: true}},
+			},
+		}
+		params := map[string]any{}
+		if err := AssignPositionalPathParams(params, multi.Parameters, []string{"7", "1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if params["ownerId"] != "7" || params["petId"] != "1" {
+			t.Errorf("expected ownerId=7 petId=1, got %v", params)
+		}
+	})
+}
+
+func TestExecuteCommand_PositionalPathParam(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	if err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "1", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if gotPath != "/pet/1" {
+		t.Errorf("expected request to /pet/1, got %s", gotPath)
+	}
+	if !strings.Contains(out.String(), "doggie") {
+		t.Errorf("expected response output, got: %s", out.String())
+	}
+}
+
+func TestExecuteCommand_PositionalPathItemParam(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":5,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpecFile("complex", complexSpecPath))
+
+	// petId is declared on the /pets/{petId} path item, not on the operation
+	if err := handler.ExecuteCommand("complex", appConfig, []string{"pets", "get", "5"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if gotPath != "/pets/5" {
+		t.Errorf("expected request to /pets/5, got %s", gotPath)
+	}
+
+	if err := handler.ExecuteCommand("complex", appConfig, []string{"pets", "get", "--petId", "6"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if gotPath != "/pets/6" {
+		t.Errorf("expected request to /pets/6, got %s", gotPath)
+	}
+}
+
+func TestExecuteCommand_ValidatesPathItemParams(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":5,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpecFile("complex", complexSpecPath))
+
+	// petId is a required integer declared on the /pets/{petId} path item
+	if err := handler.ExecuteCommand("complex", appConfig, []string{"pets", "get"}); err == nil {
+		t.Error("expected an error for the missing petId")
+	}
+	if err := handler.ExecuteCommand("complex", appConfig, []string{"pets", "get", "--petId", "abc"}); err == nil {
+		t.Error("expected an error for a non-integer petId")
+	}
+	if requests != 0 {
+		t.Errorf("expected no request to be sent, got %d", requests)
+	}
+}
+
+const issuesAliasSpec = `openapi: "3.0.0"
+info:
+  title: Issues API
+  version: "1.0.0"
+paths:
+  /issues/{issue_number}:
+    get:
+      operationId: getIssue
+      parameters:
+        - name: issue_number
+          in: path
+          required: true
+          x-ob-alias: issue
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`
+
+func TestExecuteCommand_ParamAlias(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number":5}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	appConfig.SpecSource = filepath.Join(t.TempDir(), "issues.yaml")
+	if err := os.WriteFile(appConfig.SpecSource, []byte(issuesAliasSpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"issues", "get", "--issue", "5", "--json"},
+		{"issues", "get", "--issue_number", "5", "--json"},
+	} {
+		gotPath = ""
+		if err := handler.ExecuteCommand("issues", appConfig, args); err != nil {
+			t.Fatalf("ExecuteCommand(%v) failed: %v", args, err)
+		}
+		if gotPath != "/issues/5" {
+			t.Errorf("ExecuteCommand(%v): expected request to /issues/5, got %s", args, gotPath)
+		}
+	}
+}
+
+const deprecatedParamSpec = `openapi: "3.0.0"
+info:
+  title: Search API
+  version: "1.0.0"
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - name: q
+          in: query
+          schema:
+            type: string
+        - name: sort_by
+          in: query
+          deprecated: true
+          x-ob-alias: sort
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`
+
+func TestExecuteCommand_DeprecatedParam(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	handler.SetErrorOutput(&errOut)
+	appConfig.SpecSource = filepath.Join(t.TempDir(), "search.yaml")
+	if err := os.WriteFile(appConfig.SpecSource, []byte(deprecatedParamSpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	if err := handler.ExecuteCommand("search", appConfig, []string{"items", "list", "--q", "x", "--sort", "name", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if !strings.Contains(gotQuery, "sort_by=name") {
+		t.Errorf("expected deprecated parameter to be sent, got query %q", gotQuery)
+	}
+	if n := strings.Count(errOut.String(), "Warning: parameter --sort_by is deprecated"); n != 1 {
+		t.Errorf("expected one deprecation warning, got %d in %q", n, errOut.String())
+	}
+
+	errOut.Reset()
+	if err := handler.ExecuteCommand("search", appConfig, []string{"items", "list", "--q", "x", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("expected no warning without the deprecated parameter, got %q", errOut.String())
+	}
+}
+
+func TestResolveParamAliases(t *testing.T) {
+	opSpec := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			{Value: &openapi3.Parameter{
+				Name:       "issue_number",
+				In:         "path",
+				Extensions: map[string]any{"x-ob-alias": []any{"issue", "n"}},
+			}},
+		},
+	}
+
+	params := map[string]any{"n": "5"}
+	if err := ResolveParamAliases(params, opSpec.Parameters); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["issue_number"] != "5" || params["n"] != nil {
+		t.Errorf("expected alias to be renamed to issue_number, got %v", params)
+	}
+
+	params = map[string]any{"issue": "5", "issue_number": "6"}
+	if err := ResolveParamAliases(params, opSpec.Parameters); err == nil {
+		t.Error("expected error when both alias and canonical name are given")
+	}
+	pathItem := &openapi3.PathItem{Parameters: opSpec.Parameters, Get: &openapi3.Operation{}}
+	params = map[string]any{"issue": "5"}
+	if err := ResolveParamAliases(params, spec.OperationParameters(pathItem, pathItem.Get)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["issue_number"] != "5" {
+		t.Errorf("expected the path item parameter's alias to be renamed, got %v", params)
+	}
+}
+
+const nestedBodySpec = `openapi: "3.0.0"
+info:
+  title: Users API
+  version: "1.0.0"
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                address:
+                  type: object
+                  properties:
+                    city:
+                      type: string
+                    zip:
+                      type: integer
+      responses:
+        "201":
+          description: Created
+`
+
+func TestExecuteCommand_DottedBodyFlags(t *testing.T) {
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	appConfig.SpecSource = filepath.Join(t.TempDir(), "users.yaml")
+	if err := os.WriteFile(appConfig.SpecSource, []byte(nestedBodySpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	if err := handler.ExecuteCommand("users", appConfig, []string{"users", "create", "--help", "--flatten"}); err != nil {
+		t.Fatalf("help failed: %v", err)
+	}
+	for _, path := range []string{"address.city", "address.zip"} {
+		if !strings.Contains(out.String(), path) {
+			t.Errorf("expected flattened help to list %s, got:\n%s", path, out.String())
+		}
+	}
+
+	args := []string{"users", "create", "--name", "alice", "--address.city", "Paris", "--address.zip", "75001", "--json"}
+	if err := handler.ExecuteCommand("users", appConfig, args); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	expected := map[string]any{"name": "alice", "address": map[string]any{"city": "Paris", "zip": float64(75001)}}
+	if !reflect.DeepEqual(gotBody, expected) {
+		t.Errorf("expected body %v, got %v", expected, gotBody)
+	}
+}
+
+func TestExecuteCommand_NoValidateBody(t *testing.T) {
+	var gotBody map[string]any
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	handler.SetErrorOutput(&errOut)
+	appConfig.SpecSource = filepath.Join(t.TempDir(), "users.yaml")
+	if err := os.WriteFile(appConfig.SpecSource, []byte(nestedBodySpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	// The body is missing the required name and has a non-integer zip.
+	args := []string{"users", "create", "--address.city", "Paris", "--address.zip", "unknown", "--json"}
+	if err := handler.ExecuteCommand("users", appConfig, args); err == nil {
+		t.Fatal("expected validation error without --no-validate-body")
+	}
+	if requests != 0 {
+		t.Fatalf("expected no request to be sent, got %d", requests)
+	}
+
+	if err := handler.ExecuteCommand("users", appConfig, append(args, "--no-validate-body")); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected one request to be sent, got %d", requests)
+	}
+	if _, ok := gotBody["no-validate-body"]; ok {
+		t.Errorf("expected --no-validate-body not to be sent in the body, got %v", gotBody)
+	}
+	if _, ok := gotBody["name"]; ok {
+		t.Errorf("expected body to be sent without name, got %v", gotBody)
+	}
+	address, _ := gotBody["address"].(map[string]any)
+	if address["zip"] != "unknown" {
+		t.Errorf("expected zip to be sent as given, got %v", gotBody)
+	}
+}
+
+const enumParamSpec = `openapi: "3.0.0"
+info:
+  title: Pets API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, pending, sold]
+      responses:
+        "200":
+          description: OK
+`
+
+func TestExecuteCommand_EnumCaseInsensitive(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	handler.SetErrorOutput(&errOut)
+	appConfig.SpecSource = filepath.Join(t.TempDir(), "pets.yaml")
+	if err := os.WriteFile(appConfig.SpecSource, []byte(enumParamSpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	args := []string{"pets", "list", "--status", "Available", "--json"}
+	if err := handler.ExecuteCommand("pets", appConfig, args); err == nil {
+		t.Fatal("expected strict enum validation to reject Available")
+	}
+
+	if err := handler.ExecuteCommand("pets", appConfig, append(args, "--enum-case-insensitive")); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if gotQuery != "status=available" {
+		t.Errorf("expected enum value to be normalized, got query %q", gotQuery)
+	}
+
+	gotQuery = ""
+	profile := appConfig.Profiles["default"]
+	profile.EnumCaseInsensitive = true
+	appConfig.Profiles["default"] = profile
+	if err := handler.ExecuteCommand("pets", appConfig, []string{"pets", "list", "--status", "SOLD", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand with profile setting failed: %v", err)
+	}
+	if gotQuery != "status=sold" {
+		t.Errorf("expected enum value to be normalized by the profile setting, got query %q", gotQuery)
+	}
+}
+
+func TestExecuteCommand_OperationFilter(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	handler.SetErrorOutput(io.Discard)
+	handler.SetOperationFilter(func(_, method, _ string, _ *openapi3.Operation) bool {
+		return method == http.MethodGet
+	})
+
+	specDoc, err := handler.loadAndCacheSpec("petstore", appConfig)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	var walk func(res *semantic.Resource)
+	count := 0
+	walk = func(res *semantic.Resource) {
+		for _, op := range res.Operations {
+			count++
+			if op.Method != http.MethodGet {
+				t.Errorf("expected only GET commands, got %s %s", op.Method, op.Path)
+			}
+		}
+		for _, sub := range res.SubResources {
+			walk(sub)
+		}
+	}
+	for _, res := range handler.commandTree(appConfig, specDoc).RootResources {
+		walk(res)
+	}
+	if count == 0 {
+		t.Fatal("expected GET commands to remain")
+	}
+
+	if err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--json"}); err == nil {
+		t.Error("expected filtered command to be rejected")
+	}
+	if err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Errorf("expected a single GET request, got %v", methods)
+	}
+}
+
+func TestExecuteCommand_ProfileDefaultParams(t *testing.T) {
+	var gotURLs, gotBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotURLs = append(gotURLs, r.URL.String())
+		gotBodies = append(gotBodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	profile := appConfig.Profiles["default"]
+	profile.DefaultParams = map[string]string{"petId": "7", "limit": "100"}
+	appConfig.Profiles["default"] = profile
+
+	commands := [][]string{
+		{"pet", "get", "--json"},
+		{"pet", "get", "--petId", "1", "--json"},
+		{"pet", "get", "3", "--json"},
+		{"pet", "create", "--name", "doggie", "--json"},
+	}
+	for _, args := range commands {
+		if err := handler.ExecuteCommand("petstore", appConfig, args); err != nil {
+			t.Fatalf("ExecuteCommand(%v) failed: %v", args, err)
+		}
+	}
+
+	wantURLs := []string{"/pet/7", "/pet/1", "/pet/3", "/pet"}
+	if !reflect.DeepEqual(gotURLs, wantURLs) {
+		t.Errorf("expected requests %v, got %v", wantURLs, gotURLs)
+	}
+	if strings.Contains(gotBodies[3], "petId") || strings.Contains(gotBodies[3], "limit") {
+		t.Errorf("expected defaults for undeclared parameters to be ignored, got body %s", gotBodies[3])
+	}
+}
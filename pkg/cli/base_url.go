@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// applyBaseURL overrides the profile's base URL with --base-url for this
+// request, e.g. to send one call to a local instance of the API. The profile
+// then no longer matches the spec's servers, so path and operation servers
+// are ignored as well.
+func applyBaseURL(params map[string]any, profile *config.Profile) error {
+	value, ok := params["base-url"]
+	if !ok {
+		return nil
+	}
+	baseURL := fmt.Sprint(value)
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid --base-url value %q: must be an http or https URL", baseURL)
+	}
+	profile.BaseURL = baseURL
+	return nil
+}
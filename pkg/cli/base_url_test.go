@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBaseURL(t *testing.T) {
+	profile := &config.Profile{BaseURL: "https://api.example.com"}
+
+	require.NoError(t, applyBaseURL(map[string]any{}, profile))
+	assert.Equal(t, "https://api.example.com", profile.BaseURL)
+
+	require.NoError(t, applyBaseURL(map[string]any{"base-url": "http://localhost:8080/v2"}, profile))
+	assert.Equal(t, "http://localhost:8080/v2", profile.BaseURL)
+
+	for _, value := range []any{"localhost:8080", "ftp://example.com", "http://", true} {
+		err := applyBaseURL(map[string]any{"base-url": value}, profile)
+		assert.ErrorContains(t, err, "must be an http or https URL", "value %v", value)
+	}
+}
+
+func TestExecuteCommand_BaseURLOverride(t *testing.T) {
+	var configuredHits atomic.Int32
+	configured := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		configuredHits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"configured"}`))
+	}))
+	defer configured.Close()
+
+	var localPath string
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		localPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"local"}`))
+	}))
+	defer local.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, configured.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--base-url", local.URL, "--output", "json"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "local")
+	assert.Equal(t, "/pet/1", localPath)
+	assert.Zero(t, configuredHits.Load())
+	assert.Equal(t, configured.URL, appConfig.Profiles["default"].BaseURL, "the stored profile is unchanged")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--output", "json"}))
+	assert.Contains(t, out.String(), "configured")
+	assert.Equal(t, int32(1), configuredHits.Load())
+
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--base-url", "localhost:8080"})
+	assert.EqualError(t, err, `invalid --base-url value "localhost:8080": must be an http or https URL`)
+}
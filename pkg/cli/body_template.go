@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/request"
+)
+
+// bodyTemplateData is the data a --body-template is rendered with.
+type bodyTemplateData struct {
+	// Env holds the environment variables.
+	Env map[string]string
+	// Args holds the command's flag values.
+	Args map[string]any
+}
+
+// ApplyBodyTemplate renders the --body-template flag, given inline or as @file,
+// and uses the result as the request body. The template is a Go text/template
+// over .Env and .Args; referencing a missing key is an error. The env and arg
+// functions look up a value with an optional default, e.g. {{env "REGION" "eu-west-1"}}.
+// When the operation takes a JSON body, the rendered body must be valid JSON.
+func ApplyBodyTemplate(params map[string]any, opSpec *openapi3.Operation) error {
+	value, ok := params["body-template"]
+	if !ok {
+		return nil
+	}
+	delete(params, "body-template")
+
+	if _, hasBody := params["body"]; hasBody {
+		return fmt.Errorf("--body and --body-template cannot be used together")
+	}
+	if _, hasYAML := params[request.BodyYAMLParam]; hasYAML {
+		return fmt.Errorf("--body-yaml and --body-template cannot be used together")
+	}
+
+	text := fmt.Sprint(value)
+	if strings.HasPrefix(text, "@") {
+		content, err := readParamFile("body-template", text)
+		if err != nil {
+			return err
+		}
+		text = content
+	}
+
+	data := bodyTemplateData{Env: environMap(), Args: maps.Clone(params)}
+	body, err := renderBodyTemplate(text, data)
+	if err != nil {
+		return err
+	}
+
+	if expectsJSONBody(opSpec) && !json.Valid([]byte(body)) {
+		return fmt.Errorf("rendered --body-template is not valid JSON:\n%s", body)
+	}
+	params["body"] = body
+	return nil
+}
+
+// renderBodyTemplate executes a body template with the given data.
+func renderBodyTemplate(text string, data bodyTemplateData) (string, error) {
+	funcs := template.FuncMap{
+		"env": func(name string, defaults ...string) (string, error) {
+			if value, ok := data.Env[name]; ok {
+				return value, nil
+			}
+			if len(defaults) > 0 {
+				return defaults[0], nil
+			}
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		},
+		"arg": func(name string, defaults ...any) (any, error) {
+			if value, ok := data.Args[name]; ok {
+				return value, nil
+			}
+			if len(defaults) > 0 {
+				return defaults[0], nil
+			}
+			return nil, fmt.Errorf("flag --%s is not set", name)
+		},
+	}
+
+	tmpl, err := template.New("body-template").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse --body-template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render --body-template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// environMap returns the environment variables as a map.
+func environMap() map[string]string {
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// expectsJSONBody reports whether an operation's request body is JSON, or
+// declares no media types at all.
+func expectsJSONBody(opSpec *openapi3.Operation) bool {
+	if opSpec == nil || opSpec.RequestBody == nil || opSpec.RequestBody.Value == nil {
+		return true
+	}
+	content := opSpec.RequestBody.Value.Content
+	if len(content) == 0 {
+		return true
+	}
+	for mediaType := range content {
+		if strings.Contains(mediaType, "json") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_BodyTemplate(t *testing.T) {
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	t.Setenv("OB_TEST_PET_STATUS", "pending")
+
+	tmplPath := filepath.Join(t.TempDir(), "pet.json.tmpl")
+	tmpl := `{"name": "{{ .Args.name }}", "status": "{{ .Env.OB_TEST_PET_STATUS }}", "tag": "{{ arg "tag" "none" }}"}`
+	require.NoError(t, os.WriteFile(tmplPath, []byte(tmpl), 0644))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--body-template", "@" + tmplPath, "--json"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"doggie","status":"pending","tag":"none"}`, string(gotBody))
+}
+
+func TestExecuteCommand_BodyTemplateInline(t *testing.T) {
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--body-template", `{"name":"{{ .Args.name }}"}`, "--json"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"doggie"}`, string(gotBody))
+}
+
+func TestApplyBodyTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]any
+		wantErr string
+	}{
+		{
+			name:    "missing arg",
+			params:  map[string]any{"body-template": `{"name": "{{ .Args.name }}"}`},
+			wantErr: `map has no entry for key "name"`,
+		},
+		{
+			name:    "missing env without default",
+			params:  map[string]any{"body-template": `{"region": "{{ env "OB_TEST_UNSET_VARIABLE" }}"}`},
+			wantErr: "environment variable OB_TEST_UNSET_VARIABLE is not set",
+		},
+		{
+			name:    "invalid JSON",
+			params:  map[string]any{"body-template": `{"name": {{ .Args.name }}}`, "name": "doggie"},
+			wantErr: "not valid JSON",
+		},
+		{
+			name:    "combined with body",
+			params:  map[string]any{"body-template": `{}`, "body": `{}`},
+			wantErr: "cannot be used together",
+		},
+		{
+			name:    "combined with body-yaml",
+			params:  map[string]any{"body-template": `{}`, "body-yaml": "name: doggie"},
+			wantErr: "--body-yaml and --body-template cannot be used together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ApplyBodyTemplate(tt.params, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestApplyBodyTemplate_Defaults(t *testing.T) {
+	params := map[string]any{"body-template": `{"region": "{{ env "OB_TEST_UNSET_VARIABLE" "eu-west-1" }}"}`}
+	require.NoError(t, ApplyBodyTemplate(params, nil))
+	assert.Equal(t, `{"region": "eu-west-1"}`, params["body"])
+	assert.NotContains(t, params, "body-template")
+}
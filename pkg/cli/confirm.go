@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"golang.org/x/term"
+)
+
+// SetInput sets the reader confirmation prompts are read from. Defaults to
+// os.Stdin. A reader other than a file is treated as an interactive user.
+func (h *Handler) SetInput(r io.Reader) {
+	h.stdin = r
+}
+
+// confirmOperation asks the user to confirm an operation marked with
+// x-ob-confirm by typing the resource name, unless --yes is given. Without
+// --yes it refuses to run when input is not interactive, so that scripts
+// cannot run the operation by accident.
+func (h *Handler) confirmOperation(resource string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any) error {
+	message, required := semantic.ConfirmMessage(opSpec)
+	if !required || flagEnabled(params, "yes") {
+		return nil
+	}
+
+	if !isInteractive(h.stdin) {
+		return fmt.Errorf("%s %s requires confirmation: re-run with --yes to run it non-interactively", op.Method, op.Path)
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("%s %s is marked as requiring confirmation.", op.Method, op.Path)
+	}
+	_, _ = fmt.Fprintln(h.stderr, message)
+	_, _ = fmt.Fprintf(h.stderr, "Type '%s' to continue: ", resource)
+
+	answer, _ := bufio.NewReader(h.stdin).ReadString('\n')
+	if strings.TrimSpace(answer) != resource {
+		return fmt.Errorf("aborted: confirmation did not match '%s'", resource)
+	}
+	return nil
+}
+
+// isInteractive reports whether prompts can be answered from r: a file must be
+// a terminal, while any other reader is assumed to be answered.
+func isInteractive(r io.Reader) bool {
+	if f, ok := r.(*os.File); ok {
+		return term.IsTerminal(int(f.Fd()))
+	}
+	return r != nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const confirmSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    delete:
+      operationId: deletePet
+      x-ob-confirm: This permanently deletes the pet.
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "204":
+          description: Deleted
+`
+
+func TestExecuteCommand_ConfirmPrompt(t *testing.T) {
+	server, requests := newStatusSequenceServer(t)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("confirm", confirmSpec), withErrorOutput(&errOut))
+
+	handler.SetInput(strings.NewReader("no\n"))
+	err := handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1"})
+	assert.EqualError(t, err, "aborted: confirmation did not match 'pets'")
+	assert.Contains(t, errOut.String(), "This permanently deletes the pet.\n")
+	assert.Contains(t, errOut.String(), "Type 'pets' to continue: ")
+	assert.Equal(t, int32(0), requests.Load())
+
+	handler.SetInput(strings.NewReader("pets\n"))
+	require.NoError(t, handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1"}))
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestExecuteCommand_ConfirmYes(t *testing.T) {
+	server, requests := newStatusSequenceServer(t)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("confirm", confirmSpec), withErrorOutput(&errOut))
+
+	// A file that is not a terminal stands in for piped input.
+	stdin, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer func() { _ = stdin.Close() }()
+	handler.SetInput(stdin)
+
+	err = handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1"})
+	assert.EqualError(t, err, "DELETE /pets/{petId} requires confirmation: re-run with --yes to run it non-interactively")
+	assert.Equal(t, int32(0), requests.Load())
+
+	require.NoError(t, handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1", "--yes"}))
+	assert.Equal(t, int32(1), requests.Load())
+	assert.NotContains(t, errOut.String(), "Type 'pets'")
+}
+
+func TestExecuteCommand_InvalidBooleanFlag(t *testing.T) {
+	server, requests := newStatusSequenceServer(t)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("confirm", confirmSpec), withErrorOutput(&errOut))
+	handler.SetInput(strings.NewReader("no\n"))
+
+	assert.EqualError(t, handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1", "--yes=no"}), `invalid --yes value "no": must be true or false`)
+	assert.EqualError(t, handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1", "--dry-run=nope"}), `invalid --dry-run value "nope": must be true or false`)
+	assert.EqualError(t, handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1", "--yes", "--reveal=off"}), `invalid --reveal value "off": must be true or false`)
+	assert.Equal(t, int32(0), requests.Load())
+
+	require.NoError(t, handler.ExecuteCommand("confirm", appConfig, []string{"pets", "delete", "--petId", "1", "--yes=true", "--dry-run=false"}))
+	assert.Equal(t, int32(1), requests.Load(), "--dry-run=false sends the request")
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+// dryRunRequest is what --dry-run prints: the request that would be sent,
+// with sensitive header and query values masked.
+type dryRunRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    any               `json:"body,omitempty"`
+}
+
+// MaskValue masks a sensitive value, showing only first and last 2 chars.
+func MaskValue(value string) string {
+	if len(value) <= 4 {
+		return "****"
+	}
+	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
+}
+
+// printDryRun builds the request for --dry-run, credentials included, and
+// prints it instead of sending it. JSON and YAML output print the request as
+// {"method": ..., "url": ..., "headers": {...}, "body": ...}; other formats
+// print the request line, "Name: value" header lines and the body. No OAuth2
+// access token is requested; request.OAuth2TokenPlaceholder stands in for it.
+func (h *Handler) printDryRun(appName string, op *semantic.Operation, opSpec *openapi3.Operation, cleanParams, params map[string]any, profile *config.Profile, opts ...request.BuildOption) error {
+	req, err := h.buildRequest(appName, op, opSpec, cleanParams, profile, append(opts, request.WithoutTokenRequest())...)
+	if err != nil {
+		return err
+	}
+
+	planned, err := newDryRunRequest(req, &profile.Auth)
+	if err != nil {
+		return err
+	}
+
+	switch determineOutputFormat(params) {
+	case "json", "yaml":
+		data, err := json.Marshal(planned)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		return h.formatAndPrintOutput(data, params)
+	}
+
+	_, _ = fmt.Fprintf(h.stdout, "%s %s\n", planned.Method, planned.URL)
+	writeHeaderLines(h.stdout, planned.Headers)
+	if planned.Body != nil {
+		body := fmt.Sprint(planned.Body)
+		if raw, ok := planned.Body.(json.RawMessage); ok {
+			var indented bytes.Buffer
+			if json.Indent(&indented, raw, "", "  ") == nil {
+				body = indented.String()
+			}
+		}
+		_, _ = fmt.Fprintf(h.stdout, "\n%s\n", body)
+	}
+	return nil
+}
+
+// newDryRunRequest captures req for printing. Sensitive headers, and the API
+// key when auth sends it as a query parameter, are masked with MaskValue.
+func newDryRunRequest(req *http.Request, auth *config.AuthConfig) (*dryRunRequest, error) {
+	u := *req.URL
+	if auth.Type == "api_key" && auth.Location == "query" && auth.KeyName != "" {
+		query := u.Query()
+		if value := query.Get(auth.KeyName); value != "" {
+			query.Set(auth.KeyName, MaskValue(value))
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	planned := &dryRunRequest{
+		Method:  req.Method,
+		URL:     u.String(),
+		Headers: make(map[string]string, len(req.Header)),
+	}
+	for name, values := range req.Header {
+		value := strings.Join(values, ", ")
+		if config.IsSensitiveHeader(name) || (auth.Type == "api_key" && strings.EqualFold(name, auth.KeyName)) {
+			value = MaskValue(value)
+		}
+		planned.Headers[name] = value
+	}
+
+	if req.Body == nil {
+		return planned, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) == 0 {
+		return planned, nil
+	}
+	if json.Valid(body) {
+		planned.Body = json.RawMessage(body)
+	} else {
+		planned.Body = string(body)
+	}
+	return planned, nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskValue(t *testing.T) {
+	assert.Equal(t, "****", MaskValue("abcd"))
+	assert.Equal(t, "Be***********en", MaskValue("Bearer s3-token"))
+}
+
+func TestExecuteCommand_DryRun(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withCredential("bearer", credential.NewBearerCredential("s3cret-token")))
+	args := []string{"pet", "create", "--name", "Foo", "--dry-run"}
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, args))
+	assert.Zero(t, requests, "--dry-run must not send the request")
+	assert.Contains(t, out.String(), "POST "+server.URL+"/pet\n")
+	assert.Contains(t, out.String(), "Authorization: Be***************en\n")
+	assert.Contains(t, out.String(), "Content-Type: application/json\n")
+	assert.Contains(t, out.String(), `"name": "Foo"`)
+	assert.NotContains(t, out.String(), "s3cret-token")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, append(args, "--output", "json")))
+	var planned dryRunRequest
+	require.NoError(t, json.Unmarshal(out.Bytes(), &planned), "stdout: %s", out.String())
+	assert.Equal(t, http.MethodPost, planned.Method)
+	assert.Equal(t, server.URL+"/pet", planned.URL)
+	assert.Equal(t, "Be***************en", planned.Headers["Authorization"])
+	assert.Equal(t, map[string]any{"name": "Foo"}, planned.Body)
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, append(args, "--output", "yaml")))
+	assert.Contains(t, out.String(), "method: POST")
+	assert.Zero(t, requests)
+}
+
+func TestExecuteCommand_DryRunSkipsTokenRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withCredential("oauth2_client_credentials",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", server.URL+"/token", nil)))
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--dry-run"}))
+	assert.Zero(t, requests, "--dry-run must not request an access token")
+	assert.Contains(t, out.String(), "Authorization: ")
+}
+
+func TestNewDryRunRequest_MasksQueryAPIKey(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/pets?api_key=abcdef123&limit=5", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Trace", "visible")
+
+	planned, err := newDryRunRequest(req, &config.AuthConfig{Type: "api_key", Location: "query", KeyName: "api_key"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/pets?api_key=ab%2A%2A%2A%2A%2A23&limit=5", planned.URL)
+	assert.Equal(t, "visible", planned.Headers["X-Trace"])
+	assert.Nil(t, planned.Body)
+}
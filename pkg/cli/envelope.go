@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Error types reported in the error envelope.
+const (
+	ErrorTypeValidation = "validation_error"
+	ErrorTypeNetwork    = "network_error"
+	ErrorTypeHTTP       = "http_error"
+	ErrorTypeRequest    = "request_error"
+)
+
+// HTTPError is returned when the API responds with an error status code.
+type HTTPError struct {
+	Response *http.Response
+	Body     []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", e.Response.StatusCode, http.StatusText(e.Response.StatusCode))
+}
+
+// ErrorEnvelope is the machine-readable error written to stdout when JSON output is requested.
+type ErrorEnvelope struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a single failure in an ErrorEnvelope.
+type ErrorDetail struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Status  int    `json:"status,omitempty"`
+	Body    any    `json:"body,omitempty"`
+}
+
+// NewErrorEnvelope builds an error envelope for err, classifying it by type.
+// HTTP errors carry the response status and body; JSON bodies are embedded as-is.
+// A spent request deadline is a request error even when it wraps the HTTP or
+// network error of the last attempt.
+func NewErrorEnvelope(err error) ErrorEnvelope {
+	var httpErr *HTTPError
+	if !errors.Is(err, ErrRequestDeadline) && errors.As(err, &httpErr) {
+		return ErrorEnvelope{Error: ErrorDetail{
+			Type:    ErrorTypeHTTP,
+			Message: httpErr.Error(),
+			Status:  httpErr.Response.StatusCode,
+			Body:    envelopeBody(httpErr.Body),
+		}}
+	}
+
+	return ErrorEnvelope{Error: ErrorDetail{
+		Type:    classifyError(err),
+		Message: err.Error(),
+	}}
+}
+
+// classifyError returns the envelope error type for a non-HTTP error.
+func classifyError(err error) string {
+	if errors.Is(err, ErrRequestDeadline) {
+		return ErrorTypeRequest
+	}
+	var urlErr *url.Error
+	var opErr *net.OpError
+	if errors.As(err, &urlErr) || errors.As(err, &opErr) {
+		return ErrorTypeNetwork
+	}
+	return ErrorTypeRequest
+}
+
+// envelopeBody converts a response body into a value suitable for the envelope.
+func envelopeBody(body []byte) any {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	return string(body)
+}
+
+// printErrorEnvelope writes the envelope to stdout and returns a PrintedError.
+func (h *Handler) printErrorEnvelope(envelope ErrorEnvelope, underlying error) error {
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode error: %w", err)
+	}
+	_, _ = fmt.Fprintln(h.stdout, string(data))
+	return &PrintedError{Err: underlying}
+}
+
+// reportRequestError reports a failed API request in the requested output format.
+func (h *Handler) reportRequestError(err error, outputFormat string) error {
+	if outputFormat == "json" {
+		return h.printErrorEnvelope(NewErrorEnvelope(err), err)
+	}
+
+	var httpErr *HTTPError
+	if !errors.Is(err, ErrRequestDeadline) && errors.As(err, &httpErr) {
+		return h.printAndWrapError(h.errorFormatter.FormatHTTPError(httpErr.Response, httpErr.Body), err)
+	}
+	return h.printAndWrapError(h.errorFormatter.FormatError(err), err)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_JSONErrorEnvelope_Validation(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, "http://127.0.0.1:0", &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--output", "json"})
+	require.Error(t, err)
+	assert.True(t, IsPrintedError(err))
+
+	var envelope ErrorEnvelope
+	require.NoError(t, json.Unmarshal(out.Bytes(), &envelope), "stdout: %s", out.String())
+	assert.Equal(t, ErrorTypeValidation, envelope.Error.Type)
+	assert.Contains(t, envelope.Error.Message, "petId")
+	assert.Zero(t, envelope.Error.Status)
+}
+
+func TestExecuteCommand_JSONErrorEnvelope_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Pet not found"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "42", "--json"})
+	require.Error(t, err)
+
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+
+	var raw map[string]map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &raw), "stdout: %s", out.String())
+	detail := raw["error"]
+	assert.Equal(t, ErrorTypeHTTP, detail["type"])
+	assert.Equal(t, "HTTP 404 Not Found", detail["message"])
+	assert.InDelta(t, 404, detail["status"], 0)
+	assert.Equal(t, map[string]any{"message": "Pet not found"}, detail["body"])
+
+	out.Reset()
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "42", "-o", "json"})
+	require.Error(t, err)
+	var envelope ErrorEnvelope
+	require.NoError(t, json.Unmarshal(out.Bytes(), &envelope), "stdout: %s", out.String())
+	assert.Equal(t, ErrorTypeHTTP, envelope.Error.Type)
+	assert.Equal(t, http.StatusNotFound, envelope.Error.Status)
+}
+
+func TestNewErrorEnvelope_NetworkError(t *testing.T) {
+	_, err := http.Get("http://127.0.0.1:1")
+	require.Error(t, err)
+
+	envelope := NewErrorEnvelope(err)
+	assert.Equal(t, ErrorTypeNetwork, envelope.Error.Type)
+	assert.Zero(t, envelope.Error.Status)
+}
+
+func TestNewErrorEnvelope_NonJSONBody(t *testing.T) {
+	err := &HTTPError{Response: &http.Response{StatusCode: http.StatusBadGateway}, Body: []byte("<html>bad gateway</html>")}
+
+	data, marshalErr := json.Marshal(NewErrorEnvelope(err))
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `{"error":{"type":"http_error","message":"HTTP 502 Bad Gateway","status":502,"body":"<html>bad gateway</html>"}}`, string(data))
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 )
 
 // PrintedError wraps an error that has already been printed to stderr.
@@ -302,12 +303,17 @@ func writeResponseBody(sb *strings.Builder, body []byte) {
 }
 
 // FormatHTTPError formats HTTP error responses with status codes and details.
+// RFC 7807 problem details bodies are shown field by field.
 func (f *ErrorFormatter) FormatHTTPError(resp *http.Response, body []byte) string {
 	var sb strings.Builder
 
 	fmt.Fprintf(&sb, "Error: HTTP %d %s\n\n", resp.StatusCode, resp.Status)
 	writeStatusExplanation(&sb, resp.StatusCode)
-	writeResponseBody(&sb, body)
+	if problem, ok := parseProblemDetails(resp, body); ok {
+		writeProblemDetails(&sb, problem)
+	} else {
+		writeResponseBody(&sb, body)
+	}
 
 	return sb.String()
 }
@@ -383,7 +389,7 @@ func writeOutputFlags(sb *strings.Builder) {
 	sb.WriteString("Output Flags:\n")
 	sb.WriteString("  --json       Output in JSON format\n")
 	sb.WriteString("  --yaml       Output in YAML format (default)\n")
-	sb.WriteString("  --output     Output format: json, yaml (default: yaml)\n\n")
+	sb.WriteString("  --output     Output format: json, jsonl, yaml, headers, raw, go-template=<template> (default: yaml)\n\n")
 }
 
 // FormatUsageHelpWithBody formats usage help for a command including request body parameters.
@@ -477,27 +483,28 @@ func (f *ErrorFormatter) formatBodyProperty(name string, schemaRef *openapi3.Sch
 
 	fmt.Fprintf(&sb, "  --%s", name)
 
+	var schema *openapi3.Schema
+	if schemaRef != nil {
+		schema = schemaRef.Value
+	}
+
 	// Add type information
-	if schemaRef != nil && schemaRef.Value != nil {
-		schema := schemaRef.Value
-		if schema.Type != nil {
-			fmt.Fprintf(&sb, " <%s>", schema.Type.Slice()[0])
-		}
+	if label := schemaTypeLabel(schema); label != "" {
+		fmt.Fprintf(&sb, " <%s>", label)
+	}
 
+	if required {
+		sb.WriteString(" (required)")
+	}
+
+	if schema != nil {
 		// Add description
 		if schema.Description != "" {
 			fmt.Fprintf(&sb, "\n      %s", schema.Description)
 		}
 
 		// Add enum values if present
-		if len(schema.Enum) > 0 {
-			sb.WriteString("\n      Allowed values: ")
-			var values []string
-			for _, v := range schema.Enum {
-				values = append(values, fmt.Sprintf("%v", v))
-			}
-			sb.WriteString(strings.Join(values, ", "))
-		}
+		writeEnumValues(&sb, schema.Enum)
 
 		// Add example if present
 		if schema.Example != nil {
@@ -505,10 +512,6 @@ func (f *ErrorFormatter) formatBodyProperty(name string, schemaRef *openapi3.Sch
 		}
 	}
 
-	if required {
-		sb.WriteString(" (required)")
-	}
-
 	sb.WriteString("\n")
 	return sb.String()
 }
@@ -540,13 +543,13 @@ func (f *ErrorFormatter) formatBasicExample(sb *strings.Builder, appName, resour
 	for _, paramRef := range opParams {
 		param := paramRef.Value
 		if param.Required {
-			exampleValue := f.getExampleValue(param)
+			exampleValue := exampleFlagValue(ExampleValueForParameter(param))
 			fmt.Fprintf(sb, " --%s %s", param.Name, exampleValue)
 		}
 	}
 	for _, propName := range requiredBodyProps {
 		if propSchema, ok := bodyProps[propName]; ok {
-			exampleValue := f.getExampleValueForSchema(propName, propSchema)
+			exampleValue := exampleFlagValue(ExampleValueForSchema(propName, propSchema))
 			fmt.Fprintf(sb, " --%s %s", propName, exampleValue)
 		}
 	}
@@ -559,14 +562,14 @@ func (f *ErrorFormatter) formatPrefixedExample(sb *strings.Builder, appName, res
 	for _, paramRef := range opParams {
 		param := paramRef.Value
 		if param.Required {
-			exampleValue := f.getExampleValue(param)
+			exampleValue := exampleFlagValue(ExampleValueForParameter(param))
 			prefix := f.getPrefixForParam(param)
 			fmt.Fprintf(sb, " --%s:%s %s", prefix, param.Name, exampleValue)
 		}
 	}
 	for _, propName := range requiredBodyProps {
 		if propSchema, ok := bodyProps[propName]; ok {
-			exampleValue := f.getExampleValueForSchema(propName, propSchema)
+			exampleValue := exampleFlagValue(ExampleValueForSchema(propName, propSchema))
 			fmt.Fprintf(sb, " --body:%s %s", propName, exampleValue)
 		}
 	}
@@ -583,13 +586,13 @@ func (f *ErrorFormatter) formatFullExample(sb *strings.Builder, appName, resourc
 	for _, paramRef := range opParams {
 		param := paramRef.Value
 		if param.Required {
-			exampleValue := f.getExampleValue(param)
+			exampleValue := exampleFlagValue(ExampleValueForParameter(param))
 			fmt.Fprintf(sb, " --%s %s", param.Name, exampleValue)
 		}
 	}
 	for _, propName := range requiredBodyProps {
 		if propSchema, ok := bodyProps[propName]; ok {
-			exampleValue := f.getExampleValueForSchema(propName, propSchema)
+			exampleValue := exampleFlagValue(ExampleValueForSchema(propName, propSchema))
 			fmt.Fprintf(sb, " --%s %s", propName, exampleValue)
 		}
 	}
@@ -598,13 +601,13 @@ func (f *ErrorFormatter) formatFullExample(sb *strings.Builder, appName, resourc
 	for _, paramRef := range opParams {
 		param := paramRef.Value
 		if !param.Required {
-			exampleValue := f.getExampleValue(param)
+			exampleValue := exampleFlagValue(ExampleValueForParameter(param))
 			fmt.Fprintf(sb, "\n  # --%s %s (optional)", param.Name, exampleValue)
 		}
 	}
 	for _, propName := range optionalBodyProps {
 		if propSchema, ok := bodyProps[propName]; ok {
-			exampleValue := f.getExampleValueForSchema(propName, propSchema)
+			exampleValue := exampleFlagValue(ExampleValueForSchema(propName, propSchema))
 			fmt.Fprintf(sb, "\n  # --body:%s %s (optional)", propName, exampleValue)
 		}
 	}
@@ -634,118 +637,89 @@ func (f *ErrorFormatter) getPrefixForParam(param *openapi3.Parameter) string {
 	}
 }
 
-// getExampleValueForSchema returns an example value for a schema property.
-func (f *ErrorFormatter) getExampleValueForSchema(name string, schemaRef *openapi3.SchemaRef) string {
-	if schemaRef == nil || schemaRef.Value == nil {
-		return "\"value\""
-	}
-
-	schema := schemaRef.Value
-
-	// Check for example in schema
-	if schema.Example != nil {
-		return fmt.Sprintf("\"%v\"", schema.Example)
-	}
-
-	// Use enum value if available
-	if len(schema.Enum) > 0 {
-		return fmt.Sprintf("\"%v\"", schema.Enum[0])
-	}
-
-	// Generate example based on type
-	if schema.Type != nil {
-		switch {
-		case schema.Type.Is("string"):
-			return fmt.Sprintf("\"%s-value\"", name)
-		case schema.Type.Is("integer"):
-			return "123"
-		case schema.Type.Is("number"):
-			return "123.45"
-		case schema.Type.Is("boolean"):
-			return "true"
-		case schema.Type.Is("array"):
-			return "\"value1,value2\""
-		case schema.Type.Is("object"):
-			return "\"{}\""
-		}
-	}
-
-	return "\"value\""
-}
-
 // formatParameter formats a single parameter for help output.
 func (f *ErrorFormatter) formatParameter(param *openapi3.Parameter) string {
 	var sb strings.Builder
 
 	fmt.Fprintf(&sb, "  --%s", param.Name)
+	for _, alias := range spec.ParameterAliases(param) {
+		fmt.Fprintf(&sb, ", --%s", alias)
+	}
 
 	// Add type information
-	if param.Schema != nil && param.Schema.Value != nil {
-		schema := param.Schema.Value
-		if schema.Type != nil {
-			fmt.Fprintf(&sb, " <%s>", schema.Type.Slice()[0])
-		}
+	schema := parameterSchema(param)
+	if label := schemaTypeLabel(schema); label != "" {
+		fmt.Fprintf(&sb, " <%s>", label)
+	}
+
+	if param.Required {
+		sb.WriteString(" (required)")
+	}
+	if param.Deprecated {
+		sb.WriteString(" (deprecated)")
 	}
 
-	// Add description
-	if param.Description != "" {
-		fmt.Fprintf(&sb, "\n      %s", param.Description)
+	// Add description, falling back to the schema's
+	description := param.Description
+	if description == "" && schema != nil {
+		description = schema.Description
+	}
+	if description != "" {
+		fmt.Fprintf(&sb, "\n      %s", description)
 	}
 
 	// Add location
 	fmt.Fprintf(&sb, " (in: %s)", param.In)
 
 	// Add enum values if present
-	if param.Schema != nil && param.Schema.Value != nil && len(param.Schema.Value.Enum) > 0 {
-		sb.WriteString("\n      Allowed values: ")
-		var values []string
-		for _, v := range param.Schema.Value.Enum {
-			values = append(values, fmt.Sprintf("%v", v))
-		}
-		sb.WriteString(strings.Join(values, ", "))
+	if schema != nil {
+		writeEnumValues(&sb, schema.Enum)
 	}
 
 	sb.WriteString("\n")
 	return sb.String()
 }
 
-// getExampleValue returns an example value for a parameter.
-func (f *ErrorFormatter) getExampleValue(param *openapi3.Parameter) string {
-	// Check for example in parameter
-	if param.Example != nil {
-		return fmt.Sprintf("%v", param.Example)
+// parameterSchema returns the schema of a parameter, taken from its content
+// when it is declared with content instead of schema.
+func parameterSchema(param *openapi3.Parameter) *openapi3.Schema {
+	if param.Schema != nil {
+		return param.Schema.Value
 	}
-
-	// Check for example in schema
-	if param.Schema != nil && param.Schema.Value != nil {
-		schema := param.Schema.Value
-		if schema.Example != nil {
-			return fmt.Sprintf("%v", schema.Example)
-		}
-
-		// Use enum value if available
-		if len(schema.Enum) > 0 {
-			return fmt.Sprintf("%v", schema.Enum[0])
+	for _, mediaType := range param.Content {
+		if mediaType != nil && mediaType.Schema != nil {
+			return mediaType.Schema.Value
 		}
+	}
+	return nil
+}
 
-		// Generate example based on type
-		if schema.Type != nil {
-			switch {
-			case schema.Type.Is("string"):
-				return fmt.Sprintf("\"%s-value\"", param.Name)
-			case schema.Type.Is("integer"):
-				return "123"
-			case schema.Type.Is("number"):
-				return "123.45"
-			case schema.Type.Is("boolean"):
-				return "true"
-			case schema.Type.Is("array"):
-				return "\"value1,value2\""
-			}
+// schemaTypeLabel describes the type of a flag's schema, such as integer or
+// array[string].
+func schemaTypeLabel(schema *openapi3.Schema) string {
+	if schema == nil || schema.Type == nil || len(schema.Type.Slice()) == 0 {
+		return ""
+	}
+	typ := schema.Type.Slice()[0]
+	if typ == openapi3.TypeArray && schema.Items != nil {
+		if item := schemaTypeLabel(schema.Items.Value); item != "" {
+			return typ + "[" + item + "]"
 		}
 	}
+	return typ
+}
 
-	return "\"value\""
+// writeEnumValues writes the allowed values of an enum, if any.
+func writeEnumValues(sb *strings.Builder, enum []any) {
+	if len(enum) == 0 {
+		return
+	}
+	values := make([]string, len(enum))
+	for i, v := range enum {
+		values[i] = fmt.Sprintf("%v", v)
+	}
+	sb.WriteString("\n      Allowed values: ")
+	sb.WriteString(strings.Join(values, ", "))
 }
 
 // SuggestSimilarApps suggests similar app names for typos.
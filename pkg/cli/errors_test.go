@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"bytes"
 	"errors"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
@@ -194,6 +197,78 @@ func TestErrorFormatter_FormatUsageHelp(t *testing.T) {
 	}
 }
 
+const describedPetSpec = `openapi: "3.0.0"
+info:
+  title: Petstore
+  version: "1.0.0"
+paths:
+  /pet/{petId}:
+    get:
+      operationId: getPetById
+      summary: Find pet by ID
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          description: ID of pet to return
+          schema:
+            type: integer
+            format: int64
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+        - name: status
+          in: query
+          schema:
+            type: string
+            description: Status of the pet
+            enum: [available, sold]
+      responses:
+        "200":
+          description: successful operation
+`
+
+func TestExecuteCommand_HelpDescribesFlags(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, "http://127.0.0.1:0", &out)
+	appConfig.SpecSource = filepath.Join(t.TempDir(), "petstore.yaml")
+	if err := os.WriteFile(appConfig.SpecSource, []byte(describedPetSpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	if err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--help"}); err != nil {
+		t.Fatalf("help failed: %v", err)
+	}
+
+	expectedContent := []string{
+		"--petId <integer> (required)\n      ID of pet to return (in: path)",
+		"--tags <array[string]> (in: query)",
+		"--status <string>\n      Status of the pet (in: query)\n      Allowed values: available, sold",
+	}
+	for _, expected := range expectedContent {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected help to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestErrorFormatter_FormatBodyProperty(t *testing.T) {
+	formatter := NewErrorFormatter()
+	schema := openapi3.NewStringSchema()
+	schema.Description = "Name of the pet"
+	schema.Enum = []any{"rex", "fido"}
+
+	result := formatter.formatBodyProperty("name", schema.NewRef(), true)
+
+	expected := "  --name <string> (required)\n      Name of the pet\n      Allowed values: rex, fido\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
 func TestErrorFormatter_SuggestSimilarApps(t *testing.T) {
 	formatter := NewErrorFormatter()
 
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/codegen"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
+)
+
+// maxExampleDepth bounds recursion when deriving examples from nested or circular schemas.
+const maxExampleDepth = 5
+
+// ExampleValueForSchema derives a representative value for a schema.
+// It prefers the schema example, then the default, then the first enum value,
+// and finally falls back to a placeholder based on the schema type.
+func ExampleValueForSchema(name string, schemaRef *openapi3.SchemaRef) any {
+	return exampleValueForSchema(name, schemaRef, 0)
+}
+
+// exampleValueForSchema is the depth-tracking implementation of ExampleValueForSchema.
+func exampleValueForSchema(name string, schemaRef *openapi3.SchemaRef, depth int) any {
+	if schemaRef == nil || schemaRef.Value == nil {
+		return name + "-value"
+	}
+
+	schema := schemaRef.Value
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if schema.Type == nil {
+		return name + "-value"
+	}
+
+	switch {
+	case schema.Type.Is("integer"):
+		return 123
+	case schema.Type.Is("number"):
+		return 123.45
+	case schema.Type.Is("boolean"):
+		return true
+	case schema.Type.Is("array"):
+		if depth >= maxExampleDepth {
+			return []any{}
+		}
+		return []any{exampleValueForSchema(name, schema.Items, depth+1)}
+	case schema.Type.Is("object"):
+		if depth >= maxExampleDepth {
+			return map[string]any{}
+		}
+		return exampleObject(schema, depth+1)
+	default:
+		return name + "-value"
+	}
+}
+
+// exampleObject builds an example object containing every declared property.
+func exampleObject(schema *openapi3.Schema, depth int) map[string]any {
+	obj := make(map[string]any, len(schema.Properties))
+	for propName, propRef := range schema.Properties {
+		obj[propName] = exampleValueForSchema(propName, propRef, depth)
+	}
+	return obj
+}
+
+// ExampleValueForParameter derives a representative value for a parameter.
+// A parameter-level example takes precedence over the schema.
+func ExampleValueForParameter(param *openapi3.Parameter) any {
+	if param.Example != nil {
+		return param.Example
+	}
+	return ExampleValueForSchema(param.Name, param.Schema)
+}
+
+// exampleFlagValue renders an example value as a command-line flag value.
+// Strings are quoted, arrays are joined with commas and objects are written
+// as JSON.
+func exampleFlagValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+		return strconv.Quote(strings.Join(items, ","))
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return `"{}"`
+		}
+		return strconv.Quote(string(data))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ExampleParams builds a parameter map for an operation using example values.
+// Path and required parameters are always included; optional parameters are
+// included only when the spec provides an explicit example or default.
+// Properties of a JSON object request body are added as top-level body fields.
+// Parameters declared on the path item apply alongside the operation's own.
+func ExampleParams(pathItem *openapi3.PathItem, opSpec *openapi3.Operation) map[string]any {
+	params := make(map[string]any)
+
+	for _, paramRef := range spec.OperationParameters(pathItem, opSpec) {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		param := paramRef.Value
+		if param.In == "path" || param.Required || hasExplicitExample(param) {
+			params[param.Name] = ExampleValueForParameter(param)
+		}
+	}
+
+	addExampleBody(params, getOperationRequestBody(opSpec))
+
+	return params
+}
+
+// hasExplicitExample reports whether a parameter declares an example or default value.
+func hasExplicitExample(param *openapi3.Parameter) bool {
+	if param.Example != nil {
+		return true
+	}
+	if param.Schema == nil || param.Schema.Value == nil {
+		return false
+	}
+	return param.Schema.Value.Example != nil || param.Schema.Value.Default != nil
+}
+
+// addExampleBody adds example body fields for the JSON request body schema.
+func addExampleBody(params map[string]any, requestBody *openapi3.RequestBody) {
+	if requestBody == nil {
+		return
+	}
+
+	mediaType := requestBody.Content.Get("application/json")
+	if mediaType == nil || mediaType.Schema == nil || mediaType.Schema.Value == nil {
+		return
+	}
+
+	value := ExampleValueForSchema("body", mediaType.Schema)
+	if obj, ok := value.(map[string]any); ok {
+		maps.Copy(params, obj)
+		return
+	}
+	params["body"] = value
+}
+
+// CurlExamples renders an example curl command for every operation of an app.
+// Values are derived from the spec's examples and defaults, and secrets are always masked.
+func (h *Handler) CurlExamples(appName string, appConfig *config.AppConfig) (string, error) {
+	specDoc, err := h.loadSpec(appName, appConfig)
+	if err != nil {
+		return "", err
+	}
+
+	generator, err := codegen.NewGenerator(codegen.FormatCurl, codegen.Options{MaskSecrets: true})
+	if err != nil {
+		return "", err
+	}
+
+	ex := &curlExampleWriter{
+		handler:   h,
+		appName:   appName,
+		specDoc:   specDoc,
+		profile:   h.getProfile(appConfig),
+		generator: generator,
+	}
+
+	tree := h.commandTree(appConfig, specDoc)
+	for _, name := range sortedKeys(tree.RootResources) {
+		if err := ex.writeResource(name, tree.RootResources[name]); err != nil {
+			return "", err
+		}
+	}
+
+	return ex.sb.String(), nil
+}
+
+// curlExampleWriter accumulates curl examples for the resources of a spec.
+type curlExampleWriter struct {
+	handler   *Handler
+	appName   string
+	specDoc   *openapi3.T
+	profile   *config.Profile
+	generator codegen.Generator
+	sb        strings.Builder
+}
+
+// writeResource writes examples for a resource's operations and its sub-resources.
+func (w *curlExampleWriter) writeResource(name string, res *semantic.Resource) error {
+	for _, verb := range sortedKeys(res.Operations) {
+		if err := w.writeOperation(name, verb, res.Operations[verb]); err != nil {
+			return err
+		}
+	}
+
+	for _, subName := range sortedKeys(res.SubResources) {
+		if err := w.writeResource(subName, res.SubResources[subName]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeOperation writes a single commented curl example for an operation.
+func (w *curlExampleWriter) writeOperation(resName, verb string, op *semantic.Operation) error {
+	opSpec, err := w.handler.getOperationFromSpec(w.specDoc, op)
+	if err != nil {
+		return err
+	}
+
+	// Examples are printed, not sent: no token request, and no request id
+	// that would change on every run
+	params := ExampleParams(w.specDoc.Paths.Find(op.Path), opSpec)
+	req, err := w.handler.buildRequest(w.appName, op, opSpec, params, w.profile, request.WithoutTokenRequest(), request.WithoutRequestID())
+	if err != nil {
+		return fmt.Errorf("failed to build example for %s %s: %w", resName, verb, err)
+	}
+
+	code, err := w.generator.Generate(req)
+	if err != nil {
+		return fmt.Errorf("failed to generate example for %s %s: %w", resName, verb, err)
+	}
+
+	fmt.Fprintf(&w.sb, "# %s %s %s", w.appName, resName, verb)
+	if op.Summary != "" {
+		fmt.Fprintf(&w.sb, " - %s", op.Summary)
+	}
+	w.sb.WriteString("\n")
+	w.sb.WriteString(strings.TrimRight(code, "\n"))
+	w.sb.WriteString("\n\n")
+
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
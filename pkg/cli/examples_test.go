@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/internal/testutil"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
+)
+
+func TestExampleValueForSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   *openapi3.Schema
+		expected any
+	}{
+		{"example wins", &openapi3.Schema{Type: &openapi3.Types{"string"}, Example: "doggie", Default: "x"}, "doggie"},
+		{"default", &openapi3.Schema{Type: &openapi3.Types{"integer"}, Default: 10}, 10},
+		{"enum", &openapi3.Schema{Type: &openapi3.Types{"string"}, Enum: []any{"available", "sold"}}, "available"},
+		{"string placeholder", &openapi3.Schema{Type: &openapi3.Types{"string"}}, "field-value"},
+		{"integer placeholder", &openapi3.Schema{Type: &openapi3.Types{"integer"}}, 123},
+		{"boolean placeholder", &openapi3.Schema{Type: &openapi3.Types{"boolean"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExampleValueForSchema("field", openapi3.NewSchemaRef("", tt.schema))
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExampleValueForSchema_CircularSchema(t *testing.T) {
+	node := &openapi3.Schema{Type: &openapi3.Types{"object"}, Properties: openapi3.Schemas{}}
+	node.Properties["child"] = openapi3.NewSchemaRef("", node)
+
+	got := ExampleValueForSchema("node", openapi3.NewSchemaRef("", node))
+	if _, ok := got.(map[string]any); !ok {
+		t.Fatalf("expected object example, got %T", got)
+	}
+}
+
+func TestExampleFlagValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   *openapi3.Schema
+		expected string
+	}{
+		{"string", &openapi3.Schema{Type: &openapi3.Types{"string"}}, `"field-value"`},
+		{"default", &openapi3.Schema{Type: &openapi3.Types{"integer"}, Default: 10}, "10"},
+		{"array", &openapi3.Schema{Type: &openapi3.Types{"array"}, Items: openapi3.NewStringSchema().NewRef()}, `"field-value"`},
+		{"object", &openapi3.Schema{Type: &openapi3.Types{"object"}, Properties: openapi3.Schemas{"id": openapi3.NewIntegerSchema().NewRef()}}, `"{\"id\":123}"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exampleFlagValue(ExampleValueForSchema("field", openapi3.NewSchemaRef("", tt.schema)))
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCurlExamples_CreateOperation(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "petstore.yaml")
+	if err := os.WriteFile(specPath, []byte(testutil.PetstoreOpenAPISpec), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	handler := NewHandler(spec.NewParser(), semantic.NewMapper(), request.NewBuilder(nil), nil)
+	appConfig := &config.AppConfig{
+		Name:           "petstore",
+		SpecSource:     specPath,
+		DefaultProfile: "default",
+		Profiles: map[string]config.Profile{
+			"default": {Name: "default", BaseURL: "https://api.example.com"},
+		},
+	}
+
+	out, err := handler.CurlExamples("petstore", appConfig)
+	if err != nil {
+		t.Fatalf("CurlExamples failed: %v", err)
+	}
+
+	createExample := extractExample(out, "# petstore pet create")
+	if createExample == "" {
+		t.Fatalf("expected a create example, got:\n%s", out)
+	}
+
+	for _, want := range []string{
+		"curl -X POST 'https://api.example.com/pet'",
+		"-d '",
+		`"name":"name-value"`,
+		`"status":"available"`,
+		`"id":123`,
+	} {
+		if !strings.Contains(createExample, want) {
+			t.Errorf("expected create example to contain %q, got:\n%s", want, createExample)
+		}
+	}
+
+	getExample := extractExample(out, "# petstore pet get")
+	if !strings.Contains(getExample, "curl -X GET 'https://api.example.com/pet/123'") {
+		t.Errorf("expected get example with path parameter, got:\n%s", getExample)
+	}
+}
+
+func TestCurlExamples_PathItemParamsAndPrintedAuth(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpecFile("complex", complexSpecPath),
+		withCredential("oauth2_client_credentials",
+			credential.NewOAuth2ClientCredentials("ob-client", "s3cret", server.URL+"/token", nil)))
+
+	examples, err := handler.CurlExamples("complex", appConfig)
+	if err != nil {
+		t.Fatalf("CurlExamples failed: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no token request, got %d request(s)", requests)
+	}
+	if strings.Contains(examples, request.HeaderRequestID) {
+		t.Errorf("expected no %s header in examples, got:\n%s", request.HeaderRequestID, examples)
+	}
+
+	// petId is declared on the /pets/{petId} path item, not on the operation
+	deleteExample := extractExample(examples, "# complex pets delete")
+	if !strings.Contains(deleteExample, "curl -X DELETE '"+server.URL+"/pets/123'") {
+		t.Errorf("expected delete example with path parameter, got:\n%s", deleteExample)
+	}
+}
+
+// extractExample returns the example block starting with the given header.
+func extractExample(output, header string) string {
+	_, after, ok := strings.Cut(output, header)
+	if !ok {
+		return ""
+	}
+	block, _, _ := strings.Cut(after, "\n\n")
+	return block
+}
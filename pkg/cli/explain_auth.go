@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+)
+
+// authReport is the --explain-auth output: the selected profile and how its
+// requests are authenticated.
+type authReport struct {
+	App     string `json:"app"`
+	Profile string `json:"profile"`
+	request.AuthExplanation
+}
+
+// explainAuth prints which profile and credential requests use, the auth scheme,
+// and where the credential is injected, without revealing it or sending a request.
+func (h *Handler) explainAuth(appName string, appConfig *config.AppConfig, args []string) error {
+	flags := h.parseCLIFlags(args)
+	profile, err := h.selectProfile(appConfig, flags)
+	if err != nil {
+		return err
+	}
+	report := authReport{
+		App:             appName,
+		Profile:         profile.Name,
+		AuthExplanation: h.reqBuilder.ExplainAuth(appName, profile.Name, &profile.Auth),
+	}
+
+	if determineOutputFormat(flags) == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal auth explanation: %w", err)
+		}
+		_, _ = fmt.Fprintln(h.stdout, string(data))
+		return nil
+	}
+
+	credential := "not found"
+	if report.CredentialFound {
+		credential = fmt.Sprintf("found (%s)", report.CredentialType)
+	}
+
+	_, _ = fmt.Fprintf(h.stdout, "App:         %s\n", report.App)
+	_, _ = fmt.Fprintf(h.stdout, "Profile:     %s\n", report.Profile)
+	_, _ = fmt.Fprintf(h.stdout, "Scheme:      %s\n", report.Scheme)
+	_, _ = fmt.Fprintf(h.stdout, "Injected as: %s\n", report.Injection)
+	_, _ = fmt.Fprintf(h.stdout, "Credential:  %s\n", credential)
+	if report.Problem != "" {
+		_, _ = fmt.Fprintf(h.stdout, "Problem:     %s\n", report.Problem)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nomagicln/open-bridge/pkg/credential"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_ExplainAuth_StoredCredential(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withCredential("bearer", credential.NewBearerCredential("s3cret-token")))
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"--explain-auth"}))
+	assert.Zero(t, requests, "--explain-auth must not send a request")
+	assert.Contains(t, out.String(), "Profile:     default")
+	assert.Contains(t, out.String(), "Scheme:      bearer")
+	assert.Contains(t, out.String(), "Injected as: header Authorization: Bearer <token>")
+	assert.Contains(t, out.String(), "Credential:  found (bearer)")
+	assert.NotContains(t, out.String(), "Problem")
+	assert.NotContains(t, out.String(), "s3cret-token")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--explain-auth", "--json"}))
+	var report authReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report), "stdout: %s", out.String())
+	assert.True(t, report.CredentialFound)
+	assert.Equal(t, "petstore", report.App)
+}
+
+func TestExecuteCommand_ExplainAuth_MissingCredential(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, "http://127.0.0.1:0", &out, withCredential("bearer", nil))
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"--explain-auth"}))
+	assert.Contains(t, out.String(), "Credential:  not found")
+	assert.Contains(t, out.String(), "Problem:     no credential is stored for app 'petstore' profile 'default'")
+}
+
+func TestExecuteCommand_SendsStoredCredential(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withCredential("bearer", credential.NewBearerCredential("s3cret-token")))
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1"}))
+	assert.Equal(t, "Bearer s3cret-token", gotAuth)
+}
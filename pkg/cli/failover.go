@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/spec"
+)
+
+// DefaultServerCacheTTL is how long the server picked by --auto-failover is
+// reused before the servers are probed again.
+const DefaultServerCacheTTL = 5 * time.Minute
+
+// failoverProbeTimeout bounds each server probe of --auto-failover.
+const failoverProbeTimeout = 2 * time.Second
+
+// ServerCache persists the server picked by --auto-failover for each app
+// profile, so that later commands skip the probes until the entry expires.
+type ServerCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// serverCacheEntry is a server picked for an app profile.
+type serverCacheEntry struct {
+	URL       string    `json:"url"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// NewServerCache creates a server cache backed by the file at path.
+func NewServerCache(path string, ttl time.Duration) *ServerCache {
+	return &ServerCache{path: path, ttl: ttl, now: time.Now}
+}
+
+// DefaultServerCachePath returns the server cache file in the user's cache
+// directory dir. OPENBRIDGE_SERVER_CACHE_FILE overrides the location.
+func DefaultServerCachePath(dir string) string {
+	if path := os.Getenv("OPENBRIDGE_SERVER_CACHE_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(dir, "servers.json")
+}
+
+// Get returns the server cached for key, if it has not expired.
+func (c *ServerCache) Get(key string) (string, bool) {
+	entries := c.load()
+	entry, ok := entries[key]
+	if !ok || c.now().Sub(entry.CheckedAt) > c.ttl {
+		return "", false
+	}
+	return entry.URL, true
+}
+
+// Set caches serverURL for key.
+func (c *ServerCache) Set(key, serverURL string) error {
+	entries := c.load()
+	entries[key] = serverCacheEntry{URL: serverURL, CheckedAt: c.now()}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode server cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create server cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write server cache: %w", err)
+	}
+	return nil
+}
+
+// load returns the cached entries. A missing or unreadable cache is empty.
+func (c *ServerCache) load() map[string]serverCacheEntry {
+	entries := map[string]serverCacheEntry{}
+	data, err := os.ReadFile(c.path)
+	if err != nil || json.Unmarshal(data, &entries) != nil {
+		return map[string]serverCacheEntry{}
+	}
+	return entries
+}
+
+// SetServerCache sets the cache used by --auto-failover.
+func (h *Handler) SetServerCache(cache *ServerCache) {
+	h.servers = cache
+}
+
+// applyAutoFailover points the profile at the first responsive server with
+// --auto-failover. The candidates are the profile's base URL followed by the
+// spec's root servers. The pick is cached for the cache's TTL; a --base-url
+// given for the request is used as is.
+func (h *Handler) applyAutoFailover(appName string, params map[string]any, profile *config.Profile) error {
+	if !flagEnabled(params, "auto-failover") {
+		return nil
+	}
+	if _, ok := params["base-url"]; ok {
+		return nil
+	}
+
+	candidates := []string{}
+	if profile.BaseURL != "" {
+		candidates = append(candidates, profile.BaseURL)
+	}
+	if specDoc, ok := h.specParser.GetCachedSpec(appName); ok {
+		for _, serverURL := range spec.RootServerURLs(specDoc, profile.BaseURL) {
+			if !slices.Contains(candidates, serverURL) {
+				candidates = append(candidates, serverURL)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	key := appName + "/" + profile.Name
+	if cached, ok := h.servers.Get(key); ok && slices.Contains(candidates, cached) {
+		profile.BaseURL = cached
+		return nil
+	}
+
+	client, err := h.reqBuilder.HTTPClient(appName, profile)
+	if err != nil {
+		return err
+	}
+
+	for _, serverURL := range candidates {
+		if !probeServer(client, serverURL) {
+			_, _ = fmt.Fprintf(h.stderr, "Warning: server %s did not respond, trying the next one\n", serverURL)
+			continue
+		}
+		if serverURL != profile.BaseURL {
+			_, _ = fmt.Fprintf(h.stderr, "Using server %s\n", serverURL)
+		}
+		profile.BaseURL = serverURL
+		if err := h.servers.Set(key, serverURL); err != nil {
+			_, _ = fmt.Fprintf(h.stderr, "Warning: %v\n", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("auto-failover: no server responded: %s", strings.Join(candidates, ", "))
+}
+
+// probeServer reports whether a server answers a HEAD request, or an OPTIONS
+// request if it does not support HEAD, with a status below 500.
+func probeServer(client *http.Client, serverURL string) bool {
+	status, err := probeStatus(client, http.MethodHead, serverURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = probeStatus(client, http.MethodOptions, serverURL)
+	}
+	return err == nil && status < http.StatusInternalServerError
+}
+
+// probeStatus sends a request without a body to serverURL and returns the
+// response status.
+func probeStatus(client *http.Client, method, serverURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), failoverProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, serverURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failoverSpec returns a spec listing the given servers, in order.
+func failoverSpec(servers ...string) string {
+	specYAML := "openapi: 3.0.3\ninfo:\n  title: Failover\n  version: 1.0.0\nservers:\n"
+	for _, serverURL := range servers {
+		specYAML += fmt.Sprintf("  - url: %s\n", serverURL)
+	}
+	return specYAML + "paths:\n  /pets:\n    get:\n      operationId: listPets\n      responses:\n        \"200\":\n          description: OK\n"
+}
+
+// newDownServerURL returns the URL of a server that no longer accepts connections.
+func newDownServerURL() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL
+}
+
+func TestExecuteCommand_AutoFailover(t *testing.T) {
+	down := newDownServerURL()
+	var probes atomic.Int32
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			probes.Add(1)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"server":"up","path":%q}`, r.URL.Path)
+	}))
+	t.Cleanup(up.Close)
+
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, down, &out, withSpec("failover", failoverSpec(down, up.URL)), withErrorOutput(&errOut))
+	handler.SetServerCache(NewServerCache(filepath.Join(t.TempDir(), "servers.json"), time.Minute))
+
+	require.NoError(t, handler.ExecuteCommand("failover", appConfig, []string{"pets", "list", "--auto-failover", "--output", "json"}))
+	assert.JSONEq(t, `{"server":"up","path":"/pets"}`, out.String())
+	assert.Contains(t, errOut.String(), "server "+down+" did not respond")
+	assert.Contains(t, errOut.String(), "Using server "+up.URL)
+	assert.Equal(t, int32(1), probes.Load())
+
+	// The pick is cached, so the next command skips the probes.
+	out.Reset()
+	errOut.Reset()
+	require.NoError(t, handler.ExecuteCommand("failover", appConfig, []string{"pets", "list", "--auto-failover", "--output", "json"}))
+	assert.JSONEq(t, `{"server":"up","path":"/pets"}`, out.String())
+	assert.Empty(t, errOut.String())
+	assert.Equal(t, int32(1), probes.Load())
+
+	// Without the flag, the profile's base URL is used as is.
+	err := handler.ExecuteCommand("failover", appConfig, []string{"pets", "list", "--output", "json"})
+	assert.Error(t, err)
+}
+
+func TestExecuteCommand_AutoFailover_NoServerResponds(t *testing.T) {
+	down := newDownServerURL()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(unhealthy.Close)
+
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, down, &out, withSpec("failover", failoverSpec(down, unhealthy.URL)), withErrorOutput(&errOut))
+	handler.SetServerCache(NewServerCache(filepath.Join(t.TempDir(), "servers.json"), time.Minute))
+
+	err := handler.ExecuteCommand("failover", appConfig, []string{"pets", "list", "--auto-failover"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "auto-failover: no server responded")
+}
+
+func TestProbeServer_FallsBackToOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	assert.True(t, probeServer(server.Client(), server.URL))
+	assert.False(t, probeServer(server.Client(), newDownServerURL()))
+}
+
+func TestServerCache_Expires(t *testing.T) {
+	cache := NewServerCache(filepath.Join(t.TempDir(), "servers.json"), time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	require.NoError(t, cache.Set("app/default", "https://b.example.com"))
+	got, ok := cache.Get("app/default")
+	assert.True(t, ok)
+	assert.Equal(t, "https://b.example.com", got)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.Get("app/default")
+	assert.False(t, ok)
+}
@@ -5,12 +5,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/nomagicln/open-bridge/pkg/codegen"
@@ -26,9 +31,17 @@ type Handler struct {
 	specParser     *spec.Parser
 	mapper         *semantic.Mapper
 	reqBuilder     *request.Builder
-	httpClient     *http.Client
 	errorFormatter *ErrorFormatter
 	configMgr      *config.Manager
+	transformers   []ResponseTransformer
+	stdin          io.Reader
+	stdout         io.Writer
+	stderr         io.Writer
+	session        *SessionStore
+	servers        *ServerCache
+	sleep          func(time.Duration)
+	jitter         func(time.Duration) time.Duration
+	filter         semantic.OperationFilter
 }
 
 // NewHandler creates a new CLI handler.
@@ -42,12 +55,67 @@ func NewHandler(
 		specParser:     specParser,
 		mapper:         mapper,
 		reqBuilder:     reqBuilder,
-		httpClient:     &http.Client{},
 		errorFormatter: NewErrorFormatter(),
 		configMgr:      configMgr,
+		stdin:          os.Stdin,
+		stdout:         os.Stdout,
+		stderr:         os.Stderr,
+		session:        NewSessionStore(DefaultSessionPath(userCacheDir(configMgr)), DefaultSessionTTL),
+		servers:        NewServerCache(DefaultServerCachePath(userCacheDir(configMgr)), DefaultServerCacheTTL),
+		sleep:          time.Sleep,
+		jitter:         retryJitter,
 	}
 }
 
+// userCacheDir returns the directory for the session and server cache files:
+// the cache directory in the user's OpenBridge configuration directory, which
+// other users cannot write to, unlike the shared temporary directory.
+func userCacheDir(configMgr *config.Manager) string {
+	if configMgr != nil {
+		return filepath.Join(configMgr.ConfigDir(), "cache")
+	}
+	if dir, err := config.GetConfigDir(); err == nil {
+		return filepath.Join(dir, "cache")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "openbridge")
+	}
+	return "."
+}
+
+// SetOperationFilter restricts the operations exposed as commands to those the
+// filter accepts. Rejected operations are left out of help and cannot be run.
+func (h *Handler) SetOperationFilter(filter semantic.OperationFilter) {
+	h.filter = filter
+}
+
+// SetOutput sets the writer used for command output. Defaults to os.Stdout.
+func (h *Handler) SetOutput(w io.Writer) {
+	h.stdout = w
+}
+
+// commandTree builds the command tree for an app's spec with the app's mapper
+// settings, reusing the tree cached alongside the app's parsed spec when neither
+// the spec nor the mapper changed. Operations rejected by the handler's
+// operation filter are removed.
+func (h *Handler) commandTree(appConfig *config.AppConfig, specDoc *openapi3.T) *semantic.CommandTree {
+	mapper, cachePath := h.mapper, ""
+	if appConfig != nil {
+		mapper = mapper.ForApp(appConfig.VerbMappings, appConfig.OperationIDVerbs)
+		if h.configMgr != nil && appConfig.Name != "" {
+			cachePath = h.configMgr.CommandTreeCachePath(appConfig.Name)
+		}
+	}
+	tree := mapper.BuildCommandTreeCached(specDoc, cachePath)
+	semantic.FilterCommandTree(tree, specDoc, h.filter)
+	return tree
+}
+
+// SetErrorOutput sets the writer used for errors and diagnostics. Defaults to os.Stderr.
+func (h *Handler) SetErrorOutput(w io.Writer) {
+	h.stderr = w
+}
+
 // handleHelpCommands handles help-related command patterns.
 // Returns true if help was handled, false otherwise.
 func (h *Handler) handleHelpCommands(appName string, appConfig *config.AppConfig, args []string) (bool, error) {
@@ -66,7 +134,7 @@ func (h *Handler) handleHelpCommands(appName string, appConfig *config.AppConfig
 	// Check for help flag in remaining args
 	for _, arg := range args[2:] {
 		if arg == "--help" || arg == "-h" {
-			return true, h.showCommandHelp(appName, args[0], args[1], appConfig)
+			return true, h.showCommandHelp(appName, args[0], args[1], appConfig, slices.Contains(args[2:], "--flatten"))
 		}
 	}
 
@@ -105,6 +173,10 @@ func getOperationSpec(pathItem *openapi3.PathItem, method string) *openapi3.Oper
 		return pathItem.Patch
 	case "DELETE":
 		return pathItem.Delete
+	case "HEAD":
+		return pathItem.Head
+	case "OPTIONS":
+		return pathItem.Options
 	default:
 		return nil
 	}
@@ -113,24 +185,39 @@ func getOperationSpec(pathItem *openapi3.PathItem, method string) *openapi3.Oper
 // printAndWrapError prints a formatted error message to stderr and returns a PrintedError
 // to prevent double printing when the error bubbles up to main().
 func (h *Handler) printAndWrapError(message string, underlying error) error {
-	fmt.Fprintln(os.Stderr, message)
+	_, _ = fmt.Fprintln(h.stderr, message)
 	return &PrintedError{Err: underlying}
 }
 
 // executeAPIRequest builds and executes the API request.
-// buildRequest builds an HTTP request from operation details.
-func (h *Handler) buildRequest(op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile) (*http.Request, error) {
+// buildRequest builds an HTTP request from operation details. The request goes
+// to the operation's or path's own server when the spec declares one, and
+// parameters declared on the path item apply alongside the operation's own.
+func (h *Handler) buildRequest(appName string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile, opts ...request.BuildOption) (*http.Request, error) {
+	baseURL := profile.BaseURL
+	opParams := opSpec.Parameters
+	if specDoc, ok := h.specParser.GetCachedSpec(appName); ok {
+		baseURL = spec.OperationBaseURL(specDoc, op.Path, opSpec, baseURL)
+		opParams = spec.OperationParameters(specDoc.Paths.Find(op.Path), opSpec)
+	}
+
 	var requestBody *openapi3.RequestBody
-	if opSpec.RequestBody != nil && opSpec.RequestBody.Value != nil {
+	if opSpec.RequestBody != nil {
 		requestBody = opSpec.RequestBody.Value
 	}
 
-	req, err := h.reqBuilder.BuildRequest(op.Method, op.Path, profile.BaseURL, params, opSpec.Parameters, requestBody)
+	req, err := h.reqBuilder.BuildRequest(op.Method, op.Path, baseURL, params, opParams, requestBody, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build request: %w", err)
 	}
 
-	if err := h.reqBuilder.InjectAuth(req, "", profile.Name, &profile.Auth); err != nil {
+	// Profile headers override defaults such as User-Agent and X-Request-ID,
+	// but not the credentials injected after them
+	for key, value := range profile.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if err := h.reqBuilder.InjectAuth(req, appName, profile, opts...); err != nil {
 		return nil, fmt.Errorf("failed to inject auth: %w", err)
 	}
 
@@ -145,26 +232,86 @@ func (h *Handler) readResponse(resp *http.Response) ([]byte, error) {
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, h.printAndWrapError(h.errorFormatter.FormatHTTPError(resp, body), nil)
+		return nil, &HTTPError{Response: resp, Body: body}
 	}
 
 	return body, nil
 }
 
 // executeAPIRequest executes an API request and returns the response body.
-func (h *Handler) executeAPIRequest(_ string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile) ([]byte, error) {
-	req, err := h.buildRequest(op, opSpec, params, profile)
+// Errors are returned unprinted so the caller can report them in the requested output format.
+// Responses with a retryable status and network errors are retried according
+// to the retry policy; the request is rebuilt for each attempt so the body can be sent again. With a
+// deadline, an attempt in flight when it passes is cancelled, and no retry is
+// made whose backoff would end past it.
+// The outcome of the final attempt is recorded in stats.
+func (h *Handler) executeAPIRequest(appName string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile, retry retryPolicy, stats *requestStats, opts ...request.BuildOption) ([]byte, *http.Response, error) {
+	start := time.Now()
+	defer func() { stats.duration = time.Since(start) }()
+
+	ctx, cancel := retry.withDeadline(context.Background())
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		stats.attempts = attempt + 1
+		body, resp, err := h.sendAPIRequest(ctx, appName, op, opSpec, params, profile, opts...)
+		stats.status, stats.bytes = 0, len(body)
+		if resp != nil {
+			stats.status = resp.StatusCode
+		}
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return body, resp, retry.deadlineError(stats.attempts, err)
+		}
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			stats.bytes = len(httpErr.Body)
+		}
+		delay, ok := h.retryDelay(retry, op.Method, err, attempt)
+		if !ok {
+			return body, resp, err
+		}
+		if retry.outlasts(start, delay) {
+			return body, resp, retry.deadlineError(stats.attempts, err)
+		}
+		h.sleep(delay)
+	}
+}
+
+// sendAPIRequest builds and sends a single API request, returning the response
+// body and the response, whose body is closed. The response is nil if none was
+// received.
+func (h *Handler) sendAPIRequest(ctx context.Context, appName string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile, opts ...request.BuildOption) ([]byte, *http.Response, error) {
+	req, err := h.buildRequest(appName, op, opSpec, params, profile, opts...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	req = req.WithContext(ctx)
 
-	resp, err := h.httpClient.Do(req)
+	client, err := h.reqBuilder.HTTPClient(appName, profile)
 	if err != nil {
-		return nil, h.printAndWrapError(h.errorFormatter.FormatError(err), err)
+		return nil, nil, err
+	}
+
+	return h.sendRequest(client, req)
+}
+
+// sendRequest applies the request interceptors, sends req with client, and
+// reads the response. The response is nil if none was received; its body is
+// closed.
+func (h *Handler) sendRequest(client *http.Client, req *http.Request) ([]byte, *http.Response, error) {
+	if err := h.reqBuilder.ApplyInterceptors(req); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	return h.readResponse(resp)
+	body, err := h.readResponse(resp)
+	return body, resp, err
 }
 
 // createCodeGenerator creates a code generator with the specified format and options.
@@ -189,8 +336,9 @@ func writeCodeOutput(code, outputFile string) error {
 }
 
 // generateCode generates code for the API request and outputs it to stdout or a file.
-func (h *Handler) generateCode(_ string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile, format string, outputFile string) error {
-	req, err := h.buildRequest(op, opSpec, params, profile)
+// No OAuth2 access token is requested for it; a placeholder stands in.
+func (h *Handler) generateCode(appName string, op *semantic.Operation, opSpec *openapi3.Operation, params map[string]any, profile *config.Profile, format string, outputFile string, opts ...request.BuildOption) error {
+	req, err := h.buildRequest(appName, op, opSpec, params, profile, append(opts, request.WithoutTokenRequest())...)
 	if err != nil {
 		return err
 	}
@@ -209,8 +357,8 @@ func (h *Handler) generateCode(_ string, op *semantic.Operation, opSpec *openapi
 }
 
 // resolveOperation finds the resource and operation for the given command.
-func (h *Handler) resolveOperation(specDoc *openapi3.T, resource, verb string) (*semantic.Resource, *semantic.Operation, error) {
-	tree := h.mapper.BuildCommandTree(specDoc)
+func (h *Handler) resolveOperation(specDoc *openapi3.T, appConfig *config.AppConfig, resource, verb string) (*semantic.Resource, *semantic.Operation, error) {
+	tree := h.commandTree(appConfig, specDoc)
 	res := h.findResource(tree, resource)
 	if res == nil {
 		return nil, nil, h.showUnknownResourceError(resource, tree)
@@ -240,7 +388,7 @@ func (h *Handler) resolveOperationSpec(appName string, appConfig *config.AppConf
 		return nil, nil, nil, err
 	}
 
-	_, op, err := h.resolveOperation(specDoc, resource, verb)
+	_, op, err := h.resolveOperation(specDoc, appConfig, resource, verb)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -285,77 +433,304 @@ func (h *Handler) parseAndMergeParams(flagArgs []string, opSpec *openapi3.Operat
 	return h.mergeRequestParams(cliParams, requestParams), nil
 }
 
-// extractCLIFlags extracts CLI-only flags from parameters.
-func extractCLIFlags(params map[string]any) (string, string, map[string]any) {
-	generateFormat := ""
-	generateOutput := ""
+// cliFlagNames are the flags handled by ob itself rather than sent with the
+// request.
+var cliFlagNames = []string{
+	"generate", "generate-output", "output", "json", "yaml", "unwrap", "capture",
+	"retry", "retry-on", "retry-unsafe", "no-retry", "deadline", "timeout-retry-budget",
+	"flatten", "stats", "suggest", "no-validate-body", "enum-case-insensitive", "prefer",
+	"files", "field", "content-type", "all", "format-template", "show-headers", "header-out",
+	"reveal", "timeout-connect", "yes", "follow-redirects", "max-redirects", "base-url",
+	"auto-failover", "interactive-body", "dry-run", "profile",
+}
 
-	if val, ok := params["generate"]; ok && val != nil {
-		if str, ok := val.(string); ok {
-			generateFormat = str
+// operationHasName reports whether an operation has a parameter (opParams,
+// including those of its path item) or a JSON body property called name, which
+// then takes the value of a --name flag over any CLI flag of the same name.
+func operationHasName(opSpec *openapi3.Operation, opParams openapi3.Parameters, name string) bool {
+	for _, paramRef := range opParams {
+		if paramRef != nil && paramRef.Value != nil && paramRef.Value.Name == name {
+			return true
 		}
 	}
-	if val, ok := params["generate-output"]; ok && val != nil {
-		if str, ok := val.(string); ok {
-			generateOutput = str
+	return opSpec != nil && getBodyParamSchema(opSpec, name) != nil
+}
+
+// cliFlags returns the CLI flags given in params, leaving out those named
+// like a parameter or body property of the operation.
+func cliFlags(params map[string]any, opSpec *openapi3.Operation, opParams openapi3.Parameters) map[string]any {
+	flags := make(map[string]any)
+	for _, name := range cliFlagNames {
+		if value, ok := params[name]; ok && !operationHasName(opSpec, opParams, name) {
+			flags[name] = value
 		}
 	}
+	return flags
+}
+
+// extractCLIFlags splits CLI-only flags from the request parameters. It
+// returns the --generate format and output file, the CLI flags, and the
+// parameters to send. A flag named like a parameter or body property of the
+// operation is sent as that parameter.
+func extractCLIFlags(params map[string]any, opSpec *openapi3.Operation, opParams openapi3.Parameters) (string, string, map[string]any, map[string]any) {
+	flags := cliFlags(params, opSpec, opParams)
+	generateFormat, _ := flags["generate"].(string)
+	generateOutput, _ := flags["generate-output"].(string)
 
-	// Remove CLI flags from params map
 	cleanParams := make(map[string]any, len(params))
 	for k, v := range params {
-		switch k {
-		case "generate", "generate-output", "output", "json", "yaml":
-			continue
-		default:
+		if _, ok := flags[k]; !ok {
 			cleanParams[k] = v
 		}
 	}
-	return generateFormat, generateOutput, cleanParams
+	return generateFormat, generateOutput, flags, cleanParams
+}
+
+// requestBuildOptions returns the request builder options selected by CLI flags
+// and the profile. --no-validate-body sends the body as given instead of checking
+// it against the schema, and --enum-case-insensitive (or the profile's
+// enum_case_insensitive setting) accepts enum values in any case. --prefer (or
+// the profile's prefer setting) sets the Prefer header on write operations, and
+// --content-type picks the request body encoding among those the operation offers.
+func requestBuildOptions(params map[string]any, profile *config.Profile) []request.BuildOption {
+	var opts []request.BuildOption
+	if prefer := preferValue(params, profile); prefer != "" {
+		opts = append(opts, request.WithPrefer(prefer))
+	}
+	if contentType, ok := params["content-type"].(string); ok && contentType != "" {
+		opts = append(opts, request.WithContentType(contentType))
+	}
+	if flagEnabled(params, "no-validate-body") {
+		opts = append(opts, request.WithoutBodyValidation())
+	}
+	if flagEnabled(params, "enum-case-insensitive") || (profile != nil && profile.EnumCaseInsensitive) {
+		opts = append(opts, request.WithEnumCaseInsensitive())
+	}
+	return opts
+}
+
+// booleanCLIFlags are the CLI flags that are either given bare or set to a
+// boolean value.
+var booleanCLIFlags = []string{
+	"no-retry", "no-validate-body", "enum-case-insensitive", "all", "reveal", "yes",
+	"auto-failover", "interactive-body", "dry-run",
+}
+
+// validateBooleanFlags checks that every boolean CLI flag given has a value
+// strconv.ParseBool accepts.
+func validateBooleanFlags(flags map[string]any) error {
+	for _, name := range booleanCLIFlags {
+		value, ok := flags[name]
+		if !ok {
+			continue
+		}
+		if _, err := strconv.ParseBool(fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("invalid --%s value %q: must be true or false", name, fmt.Sprint(value))
+		}
+	}
+	return nil
+}
+
+// flagEnabled reports whether a boolean CLI flag was given and not set to false.
+func flagEnabled(params map[string]any, name string) bool {
+	value, ok := params[name]
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(fmt.Sprint(value))
+	return err == nil && enabled
 }
 
 // ExecuteCommand parses and executes a CLI command.
 func (h *Handler) ExecuteCommand(appName string, appConfig *config.AppConfig, args []string) error {
+	if slices.Contains(args, "--explain-auth") {
+		return h.explainAuth(appName, appConfig, args)
+	}
+
 	if handled, err := h.handleHelpCommands(appName, appConfig, args); handled {
 		return err
 	}
 
 	resource, verb, flagArgs := args[0], args[1], args[2:]
 
-	_, opSpec, op, err := h.resolveOperationSpec(appName, appConfig, resource, verb)
+	pathItem, opSpec, op, err := h.resolveOperationSpec(appName, appConfig, resource, verb)
 	if err != nil {
 		return err
 	}
 
+	positional, flagArgs := SplitPositionalArgs(flagArgs)
+
 	params, err := h.parseAndMergeParams(flagArgs, opSpec)
 	if err != nil {
 		return err
 	}
 
-	generateFormat, generateOutput, cleanParams := extractCLIFlags(params)
-	profile := h.getProfile(appConfig)
+	opParams := spec.OperationParameters(pathItem, opSpec)
+	if err := ResolveParamAliases(params, opParams); err != nil {
+		return err
+	}
+	h.warnDeprecatedParams(params, opSpec)
+
+	if err := AssignPositionalPathParams(params, opParams, positional); err != nil {
+		return err
+	}
+
+	profile, err := h.selectProfile(appConfig, cliFlags(params, opSpec, opParams))
+	if err != nil {
+		return err
+	}
+	ApplyProfileDefaultParams(params, opSpec, profile)
+	flags := cliFlags(params, opSpec, opParams)
+	if err := validateBooleanFlags(flags); err != nil {
+		return err
+	}
+	if err := applyConnectTimeout(flags, profile); err != nil {
+		return err
+	}
+	if err := applyRedirectPolicy(flags, profile); err != nil {
+		return err
+	}
+	if err := applyBaseURL(flags, profile); err != nil {
+		return err
+	}
+	if err := h.applyAutoFailover(appName, flags, profile); err != nil {
+		return err
+	}
+
+	if err := h.resolveSessionReferences(params); err != nil {
+		return err
+	}
+
+	if err := ResolveParamFileReferences(params, opSpec); err != nil {
+		return err
+	}
+
+	if err := ApplyBodyTemplate(params, opSpec); err != nil {
+		return err
+	}
+
+	if err := h.promptMissingBodyFields(opSpec, opParams, params); err != nil {
+		return err
+	}
+
+	queryOpts, err := rawQueryBuildOptions(params, flagArgs, opSpec)
+	if err != nil {
+		return err
+	}
+
+	generateFormat, generateOutput, flags, cleanParams := extractCLIFlags(params, opSpec, opParams)
+	outputFormat := determineOutputFormat(flags)
+	buildOpts := requestBuildOptions(flags, profile)
+	multipartOpts, err := multipartBuildOptions(flags, flagArgs)
+	if err != nil {
+		return err
+	}
+	buildOpts = append(buildOpts, multipartOpts...)
+	buildOpts = append(buildOpts, queryOpts...)
 
 	// Handle code generation or API request execution
 	if generateFormat != "" {
-		return h.generateCode(appName, op, opSpec, cleanParams, profile, generateFormat, generateOutput)
+		return h.generateCode(appName, op, opSpec, cleanParams, profile, generateFormat, generateOutput, buildOpts...)
 	}
 
 	// API request path: validate parameters
 	requestBody := getOperationRequestBody(opSpec)
-	if err := h.reqBuilder.ValidateParams(cleanParams, opSpec.Parameters, requestBody); err != nil {
-		return h.showParameterValidationError(err, appName, resource, verb, opSpec.Parameters)
+	if err := h.reqBuilder.ValidateParams(cleanParams, opParams, requestBody, buildOpts...); err != nil {
+		if outputFormat == "json" {
+			return h.printErrorEnvelope(ErrorEnvelope{Error: ErrorDetail{Type: ErrorTypeValidation, Message: err.Error()}}, err)
+		}
+		return h.showParameterValidationError(err, appName, resource, verb, opParams)
+	}
+
+	if flagEnabled(flags, "dry-run") {
+		return h.printDryRun(appName, op, opSpec, cleanParams, flags, profile, buildOpts...)
+	}
+
+	if err := h.confirmOperation(resource, op, opSpec, flags); err != nil {
+		return err
+	}
+
+	retry, err := resolveRetryPolicy(profile, flags)
+	if err != nil {
+		return err
+	}
+
+	if flagEnabled(flags, "all") {
+		return h.executeAllPages(appName, op, opSpec, cleanParams, flags, profile, retry, buildOpts...)
 	}
 
-	body, err := h.executeAPIRequest(appName, op, opSpec, cleanParams, profile)
+	stats := &requestStats{}
+	body, resp, err := h.executeAPIRequest(appName, op, opSpec, cleanParams, profile, retry, stats, buildOpts...)
+	if outputFormat == rawOutputFormat {
+		if printed, rawErr := h.printRawOutput(body, err); printed {
+			h.printStats(stats, flags)
+			return rawErr
+		}
+	}
 	if err != nil {
+		reportErr := h.reportRequestError(err, outputFormat)
+		h.printStats(stats, flags)
+		return reportErr
+	}
+
+	if printed, err := h.printHeaderOutput(resp, flags); printed {
+		h.printStats(stats, flags)
+		return err
+	}
+
+	if printed, err := h.printRedirect(resp, flags, profile); printed {
+		h.printStats(stats, flags)
 		return err
 	}
 
-	return h.formatAndPrintOutput(body, cleanParams)
+	if op.Method == http.MethodHead {
+		err := h.printHeadResponse(resp, flags)
+		h.printStats(stats, flags)
+		return err
+	}
+
+	if err := h.captureResponseValues(body, flags); err != nil {
+		return err
+	}
+
+	rawBody := body
+	body, err = TransformResponse(body, h.responseTransformers(flags))
+	if err != nil {
+		return err
+	}
+
+	if headersRequested(flags) {
+		if body, err = h.includeResponseHeaders(resp, body, flags); err != nil {
+			return err
+		}
+	}
+
+	if h.printMinimalSuccess(op, stats.status, body, flags, profile) {
+		h.printStats(stats, flags)
+		return nil
+	}
+
+	if err := h.formatAndPrintOutput(body, flags); err != nil {
+		return err
+	}
+	h.printLinkSuggestions(appName, appConfig, opSpec, op, stats.status, flags, cleanParams, rawBody)
+	h.printStats(stats, flags)
+	return nil
+}
+
+// warnDeprecatedParams prints one warning to stderr for each deprecated parameter
+// that was given. The parameters are still sent.
+func (h *Handler) warnDeprecatedParams(params map[string]any, opSpec *openapi3.Operation) {
+	for _, name := range DeprecatedParams(params, opSpec) {
+		_, _ = fmt.Fprintf(h.stderr, "Warning: parameter --%s is deprecated\n", name)
+	}
 }
 
 // determineOutputFormat extracts the output format from parameters.
 func determineOutputFormat(params map[string]any) string {
+	if text, ok := outputTemplate(params); ok {
+		return goTemplateFormat + text
+	}
 	if f, ok := params["output"].(string); ok {
 		return f
 	}
@@ -374,7 +749,7 @@ func (h *Handler) formatAndPrintOutput(body []byte, params map[string]any) error
 	if err != nil {
 		return fmt.Errorf("failed to format output: %w", err)
 	}
-	fmt.Println(output)
+	_, _ = fmt.Fprintln(h.stdout, output)
 	return nil
 }
 
@@ -412,6 +787,12 @@ func (h *Handler) findResourceRecursive(parent *semantic.Resource, name string)
 	return nil
 }
 
+// shortCLIFlags maps the short forms of CLI flags to their long forms.
+var shortCLIFlags = map[string]string{
+	"-p": "--profile",
+	"-o": "--output",
+}
+
 // parseCLIFlags parses CLI-only flags (like --generate, --output, etc.)
 // These are flags not related to API parameters.
 func (h *Handler) parseCLIFlags(args []string) map[string]any {
@@ -419,6 +800,9 @@ func (h *Handler) parseCLIFlags(args []string) map[string]any {
 
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
+		if long, ok := shortCLIFlags[arg]; ok {
+			arg = long
+		}
 		if !strings.HasPrefix(arg, "--") {
 			continue
 		}
@@ -492,8 +876,30 @@ func (h *Handler) getProfile(appConfig *config.AppConfig) *config.Profile {
 	return &config.Profile{Name: "default"}
 }
 
+// selectProfile returns the profile named by the --profile (or -p) flag, or
+// else the app's default profile.
+func (h *Handler) selectProfile(appConfig *config.AppConfig, flags map[string]any) (*config.Profile, error) {
+	value, ok := flags["profile"]
+	if !ok {
+		return h.getProfile(appConfig), nil
+	}
+	name, ok := value.(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("--profile requires a profile name")
+	}
+	profile, ok := appConfig.GetProfile(name)
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' not found in app '%s'", name, appConfig.Name)
+	}
+	return profile, nil
+}
+
 // FormatOutput formats the response body according to the specified format.
 func (h *Handler) FormatOutput(body []byte, format string) (string, error) {
+	if text, ok := strings.CutPrefix(format, goTemplateFormat); ok {
+		return renderOutputTemplate(body, text)
+	}
+
 	switch format {
 	case "json":
 		var data any
@@ -506,6 +912,13 @@ func (h *Handler) FormatOutput(body []byte, format string) (string, error) {
 		}
 		return string(formatted), nil
 
+	case "jsonl":
+		var buf bytes.Buffer
+		if err := WriteJSONLines(&buf, body); err != nil {
+			return string(body), nil
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+
 	case "yaml":
 		var data any
 		if err := json.Unmarshal(body, &data); err != nil {
@@ -547,7 +960,7 @@ func (h *Handler) showResourceOrVerbHelp(appName, arg string, appConfig *config.
 		return err
 	}
 
-	tree := h.mapper.BuildCommandTree(specDoc)
+	tree := h.commandTree(appConfig, specDoc)
 
 	// Check if arg is a resource
 	res := h.findResource(tree, arg)
@@ -567,7 +980,7 @@ func (h *Handler) showResourceHelp(appName, resourceName string, appConfig *conf
 		return err
 	}
 
-	tree := h.mapper.BuildCommandTree(specDoc)
+	tree := h.commandTree(appConfig, specDoc)
 
 	// Find resource
 	res := h.findResource(tree, resourceName)
@@ -652,13 +1065,13 @@ func (h *Handler) showAppHelp(appName string, appConfig *config.AppConfig) error
 		h.specParser.CacheSpec(appName, specDoc)
 	}
 
-	tree := h.mapper.BuildCommandTree(specDoc)
+	tree := h.commandTree(appConfig, specDoc)
 
-	h.writeResourcesSection(&sb, tree)
+	h.writeCommandsSection(&sb, tree, specDoc)
 	h.writeGlobalFlagsSection(&sb)
 	h.writeExamplesSection(&sb, appName, tree)
 
-	fmt.Print(sb.String())
+	_, _ = fmt.Fprint(h.stdout, sb.String())
 	return nil
 }
 
@@ -666,16 +1079,26 @@ func (h *Handler) writeHelpWhenSpecLoadFails(sb *strings.Builder, appName string
 	sb.WriteString("(Unable to load API specification)\n\n")
 	sb.WriteString("Examples:\n")
 	fmt.Fprintf(sb, "  %s <resource> <verb> [flags]\n", appName)
-	fmt.Print(sb.String())
+	_, _ = fmt.Fprint(h.stdout, sb.String())
 }
 
-func (h *Handler) writeResourcesSection(sb *strings.Builder, tree *semantic.CommandTree) {
-	sb.WriteString("Available Resources:\n")
-	for resourceName, res := range tree.RootResources {
-		fmt.Fprintf(sb, "  %s\n", resourceName)
-		// List sub-resources
-		for subName := range res.SubResources {
-			fmt.Fprintf(sb, "    %s\n", subName)
+// writeCommandsSection lists the commands grouped by OpenAPI tag, or by
+// resource for untagged operations, with each tag's description.
+func (h *Handler) writeCommandsSection(sb *strings.Builder, tree *semantic.CommandTree, specDoc *openapi3.T) {
+	sb.WriteString("Available Commands:\n")
+	for _, group := range semantic.GroupCommandsByTag(tree, specDoc) {
+		sb.WriteString("\n")
+		sb.WriteString(group.Name)
+		if group.Description != "" {
+			fmt.Fprintf(sb, " - %s", group.Description)
+		}
+		sb.WriteString("\n")
+		for _, cmd := range group.Commands {
+			fmt.Fprintf(sb, "  %s %s", cmd.Resource, cmd.Operation.Name)
+			if cmd.Operation.Summary != "" {
+				fmt.Fprintf(sb, " - %s", cmd.Operation.Summary)
+			}
+			sb.WriteString("\n")
 		}
 	}
 
@@ -688,8 +1111,42 @@ func (h *Handler) writeGlobalFlagsSection(sb *strings.Builder) {
 	sb.WriteString("  --help, -h       Show help\n")
 	sb.WriteString("  --json           Output in JSON format\n")
 	sb.WriteString("  --yaml           Output in YAML format (default)\n")
-	sb.WriteString("  --output, -o     Output format: json, yaml (default: yaml)\n")
+	sb.WriteString("  --output, -o     Output format: json, jsonl, yaml, headers, raw, go-template=<template> (default: yaml)\n")
+	sb.WriteString("  --format-template <template>  Render the response, or each element of an array response, through a Go template\n")
 	sb.WriteString("  --profile, -p    Profile to use\n")
+	sb.WriteString("  --unwrap <field> Display only a nested response field (e.g. data)\n")
+	sb.WriteString("  --capture <name>=.<field>  Save a response field for later use as {{name}}\n")
+	sb.WriteString("  --retry <n>      Retry up to n times, overriding the profile retry policy\n")
+	sb.WriteString("  --retry-on <codes>  Status codes to retry, e.g. 429,503\n")
+	sb.WriteString("  --retry-unsafe   Also retry non-idempotent requests such as POST\n")
+	sb.WriteString("  --no-retry       Do not retry this request, whatever the retry policy\n")
+	sb.WriteString("  --deadline <duration>  Give up on the request and its retries after this long, e.g. 30s\n")
+	sb.WriteString("  --timeout-retry-budget <duration>  Alias for --deadline\n")
+	sb.WriteString("  --all            Follow Link rel=\"next\" headers and print the items of every page\n")
+	sb.WriteString("  --suggest        Print follow-up commands from the response's OpenAPI links\n")
+	sb.WriteString("  --explain-auth   Show how requests are authenticated, without sending one\n")
+	sb.WriteString("  --dry-run        Print the request, with credentials masked, instead of sending it\n")
+	sb.WriteString("  --stats          Print the response status, time, and size to stderr\n")
+	sb.WriteString("  --base-url <url>  Send this request to another base URL, e.g. http://localhost:8080\n")
+	sb.WriteString("  --auto-failover  Send the request to the first responsive server of the profile and spec\n")
+	sb.WriteString("  --timeout-connect <duration>  Give up connecting after this long, e.g. 2s\n")
+	sb.WriteString("  --follow-redirects=false  Print a redirect's status and Location instead of following it\n")
+	sb.WriteString("  --max-redirects <n>  Follow at most n redirects\n")
+	sb.WriteString("  --yes            Run operations marked x-ob-confirm without prompting\n")
+	sb.WriteString("  --show-headers [names]  Include all or the listed response headers in the output\n")
+	sb.WriteString("  --header-out <name>  Print only the value of one response header\n")
+	sb.WriteString("  --reveal         Show sensitive response headers such as Set-Cookie unmasked\n")
+	sb.WriteString("  --body-yaml <yaml|@file>  Send a YAML document, converted to JSON, as the request body\n")
+	sb.WriteString("  --interactive-body  Prompt on a terminal for required body fields that were not given\n")
+	sb.WriteString("  --body-template @file  Render a Go template over .Env and .Args as the request body\n")
+	sb.WriteString("  --prefer <value> Send a Prefer header on writes, e.g. return=minimal\n")
+	sb.WriteString("  --content-type <type>  Encode the body as this offered type, e.g. application/x-www-form-urlencoded\n")
+	sb.WriteString("  --files <dir>    Send every file in a directory as multipart/form-data parts\n")
+	sb.WriteString("  --field <name>=<value>  Add a multipart form field (repeatable)\n")
+	sb.WriteString("  --query <name>=<value>  Add a raw query parameter, skipping validation (repeatable)\n")
+	sb.WriteString("  --no-validate-body  Send the request body as given, skipping body validation\n")
+	sb.WriteString("  --enum-case-insensitive  Accept enum values in any case, sending the spec's casing\n")
+	sb.WriteString("  --flatten        With --help, list nested body fields as dotted flags (e.g. --address.city)\n")
 	sb.WriteString("  --generate, -g   Generate code instead of sending request\n")
 	sb.WriteString("                   Formats: curl, nodejs, go, python\n")
 	sb.WriteString("  --generate-output, -O  Save generated code to file (default: stdout)\n\n")
@@ -748,7 +1205,8 @@ func (h *Handler) writeResourceExamples(sb *strings.Builder, appName string, tre
 }
 
 // showCommandHelp displays help for a specific command.
-func (h *Handler) showCommandHelp(appName, resource, verb string, appConfig *config.AppConfig) error {
+// With flatten, nested body objects are listed as dotted leaf flags.
+func (h *Handler) showCommandHelp(appName, resource, verb string, appConfig *config.AppConfig, flatten bool) error {
 	// Load spec
 	specDoc, ok := h.specParser.GetCachedSpec(appName)
 	if !ok {
@@ -761,7 +1219,7 @@ func (h *Handler) showCommandHelp(appName, resource, verb string, appConfig *con
 	}
 
 	// Build command tree and find operation
-	tree := h.mapper.BuildCommandTree(specDoc)
+	tree := h.commandTree(appConfig, specDoc)
 	res := h.findResource(tree, resource)
 	if res == nil {
 		return fmt.Errorf("unknown resource: %s", resource)
@@ -781,12 +1239,36 @@ func (h *Handler) showCommandHelp(appName, resource, verb string, appConfig *con
 	if opSpec.RequestBody != nil && opSpec.RequestBody.Value != nil {
 		requestBody = opSpec.RequestBody.Value
 	}
+	if flatten && requestBody != nil {
+		requestBody = flattenedRequestBody(requestBody)
+	}
 
-	help := h.errorFormatter.FormatUsageHelpWithBody(appName, resource, verb, opSpec, opSpec.Parameters, requestBody)
-	fmt.Print(help)
+	help := h.errorFormatter.FormatUsageHelpWithBody(appName, resource, verb, opSpec, spec.OperationParameters(specDoc.Paths.Find(op.Path), opSpec), requestBody)
+	_, _ = fmt.Fprint(h.stdout, help)
 	return nil
 }
 
+// flattenedRequestBody returns a copy of requestBody whose JSON schema lists nested
+// fields as dotted properties such as address.city.
+func flattenedRequestBody(requestBody *openapi3.RequestBody) *openapi3.RequestBody {
+	media := requestBody.Content.Get("application/json")
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return requestBody
+	}
+
+	schema := openapi3.NewObjectSchema()
+	for _, field := range request.FlattenBodySchema(media.Schema.Value) {
+		schema.Properties[field.Path] = field.Schema
+		if field.Required {
+			schema.Required = append(schema.Required, field.Path)
+		}
+	}
+
+	flattened := *requestBody
+	flattened.Content = openapi3.NewContentWithJSONSchema(schema)
+	return &flattened
+}
+
 // getOperationFromSpec retrieves the operation spec from the spec document.
 func (h *Handler) getOperationFromSpec(specDoc *openapi3.T, op *semantic.Operation) (*openapi3.Operation, error) {
 	pathItem := specDoc.Paths.Find(op.Path)
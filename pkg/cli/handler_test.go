@@ -0,0 +1,416 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/keyring"
+	"github.com/nomagicln/open-bridge/internal/testutil"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// complexSpecPath is the integration test spec with path item parameters,
+// response headers and operations spread over several resources.
+var complexSpecPath = filepath.Join("..", "..", "internal", "integration", "testdata", "complex_openapi3.json")
+
+// testHandlerOption customizes the handler and app config returned by
+// newPetstoreHandler.
+type testHandlerOption func(t *testing.T, handler *Handler, appConfig *config.AppConfig)
+
+// withSpec installs the app under name with the given spec document instead
+// of the petstore spec.
+func withSpec(name, document string) testHandlerOption {
+	return func(t *testing.T, _ *Handler, appConfig *config.AppConfig) {
+		appConfig.Name = name
+		appConfig.SpecSource = filepath.Join(t.TempDir(), name+".yaml")
+		require.NoError(t, os.WriteFile(appConfig.SpecSource, []byte(document), 0644))
+	}
+}
+
+// withSpecFile installs the app under name with an existing spec file.
+func withSpecFile(name, path string) testHandlerOption {
+	return func(_ *testing.T, _ *Handler, appConfig *config.AppConfig) {
+		appConfig.Name = name
+		appConfig.SpecSource = path
+	}
+}
+
+// withErrorOutput sends what the handler writes to stderr to w.
+func withErrorOutput(w io.Writer) testHandlerOption {
+	return func(_ *testing.T, handler *Handler, _ *config.AppConfig) {
+		handler.SetErrorOutput(w)
+	}
+}
+
+// withCredential sets the default profile's auth type and stores cred, if
+// not nil, in a file keyring used by the request builder.
+func withCredential(authType string, cred *credential.Credential) testHandlerOption {
+	return func(t *testing.T, handler *Handler, appConfig *config.AppConfig) {
+		credMgr, err := credential.NewManager(
+			credential.WithAllowedBackends(keyring.FileBackend),
+			credential.WithFileBackend(t.TempDir(), keyring.FixedStringPrompt("test-password")),
+		)
+		require.NoError(t, err)
+		if cred != nil {
+			require.NoError(t, credMgr.StoreCredential(appConfig.Name, "default", cred))
+		}
+		handler.reqBuilder = request.NewBuilder(credMgr)
+
+		profile := appConfig.Profiles["default"]
+		profile.Auth.Type = authType
+		appConfig.Profiles["default"] = profile
+	}
+}
+
+// withRetryDelays records the delays between retries in delays instead of
+// sleeping, and turns off jitter so that they are predictable.
+func withRetryDelays(delays *[]time.Duration) testHandlerOption {
+	return func(_ *testing.T, handler *Handler, _ *config.AppConfig) {
+		handler.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+		handler.jitter = func(d time.Duration) time.Duration { return d }
+	}
+}
+
+// newPetstoreHandler returns a handler writing to out and an app config
+// targeting baseURL, for the petstore spec unless an option installs another.
+func newPetstoreHandler(t *testing.T, baseURL string, out *bytes.Buffer, opts ...testHandlerOption) (*Handler, *config.AppConfig) {
+	t.Helper()
+
+	handler := NewHandler(spec.NewParser(), semantic.NewMapper(), request.NewBuilder(nil), nil)
+	handler.SetOutput(out)
+
+	appConfig := &config.AppConfig{
+		Name:           "petstore",
+		DefaultProfile: "default",
+		Profiles: map[string]config.Profile{
+			"default": {Name: "default", BaseURL: baseURL},
+		},
+	}
+	withSpec("petstore", testutil.PetstoreOpenAPISpec)(t, handler, appConfig)
+	for _, opt := range opts {
+		opt(t, handler, appConfig)
+	}
+	return handler, appConfig
+}
+
+// newStatusSequenceServer responds with the given statuses in order, then 200.
+func newStatusSequenceServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := int(requests.Add(1))
+		w.Header().Set("Content-Type", "application/json")
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+		}
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestExecuteCommand_GenerateSkipsTokenRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withCredential("oauth2_client_credentials",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", server.URL+"/token", nil)))
+
+	outputFile := filepath.Join(t.TempDir(), "request.sh")
+	args := []string{"pet", "get", "--petId", "1", "--generate", "curl", "--generate-output", outputFile}
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, args))
+	assert.Zero(t, requests, "--generate must not request an access token")
+
+	code, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), request.OAuth2TokenPlaceholder)
+}
+
+// newCreatedServer returns a server that answers every request with 201
+// Created and body, and a pointer to the last request body it received.
+func newCreatedServer(t *testing.T, body string) (*httptest.Server, *[]byte) {
+	t.Helper()
+
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &gotBody
+}
+
+// newRecordingServer returns a server that answers every request with its name.
+func newRecordingServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"server":%q,"path":%q}`, name, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteCommand_OperationServerOverride(t *testing.T) {
+	root := newRecordingServer(t, "root")
+	reports := newRecordingServer(t, "reports")
+
+	specYAML := fmt.Sprintf(`openapi: 3.0.3
+info:
+  title: Gateways
+  version: 1.0.0
+servers:
+  - url: %s
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+  /reports:
+    get:
+      operationId: listReports
+      servers:
+        - url: %s/v2
+      responses:
+        "200":
+          description: OK
+`, root.URL, reports.URL)
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, root.URL, &out, withSpec("gateways", specYAML))
+
+	require.NoError(t, handler.ExecuteCommand("gateways", appConfig, []string{"reports", "list", "--output", "json"}))
+	assert.JSONEq(t, `{"server":"reports","path":"/v2/reports"}`, out.String())
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("gateways", appConfig, []string{"pets", "list", "--output", "json"}))
+	assert.JSONEq(t, `{"server":"root","path":"/pets"}`, out.String())
+}
+
+const taggedSpec = `openapi: 3.0.0
+info: {title: Shop, version: "1.0"}
+tags:
+  - name: catalog
+    description: Browse the products for sale
+  - name: checkout
+    description: Carts and orders
+paths:
+  /products:
+    get:
+      operationId: listProducts
+      summary: List products
+      tags: [catalog]
+      responses: {"200": {description: OK}}
+  /orders:
+    post:
+      operationId: createOrder
+      summary: Place an order
+      tags: [checkout]
+      responses: {"201": {description: Created}}
+  /carts:
+    get:
+      operationId: listCarts
+      tags: [checkout]
+      responses: {"200": {description: OK}}
+  /health:
+    get:
+      operationId: getHealth
+      responses: {"200": {description: OK}}
+`
+
+func TestExecuteCommand_AppHelpGroupsByTag(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, "http://127.0.0.1:0", &out, withSpec("shop", taggedSpec))
+
+	require.NoError(t, handler.ExecuteCommand("shop", appConfig, []string{"--help"}))
+
+	assert.Contains(t, out.String(), "Available Commands:\n"+
+		"\ncatalog - Browse the products for sale\n"+
+		"  products list - List products\n"+
+		"\ncheckout - Carts and orders\n"+
+		"  carts list\n"+
+		"  orders create - Place an order\n"+
+		"\nhealth\n"+
+		"  health get\n")
+}
+
+func TestExecuteCommand_OperationParamsNamedLikeCLIFlags(t *testing.T) {
+	var gotQuery string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		gotBody = nil
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	specYAML := `openapi: 3.0.3
+info: {title: Audit, version: 1.0.0}
+paths:
+  /events:
+    get:
+      operationId: listEvents
+      parameters:
+        - {name: all, in: query, schema: {type: boolean}}
+        - {name: stats, in: query, schema: {type: string}}
+      responses: {"200": {description: OK}}
+    post:
+      operationId: createEvent
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                field: {type: string}
+                prefer: {type: string}
+      responses: {"201": {description: Created}}
+`
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("audit", specYAML))
+
+	args := []string{"events", "list", "--all", "true", "--stats", "daily", "--output", "json"}
+	require.NoError(t, handler.ExecuteCommand("audit", appConfig, args))
+	assert.Equal(t, "all=true&stats=daily", gotQuery, "--all and --stats are sent as query parameters")
+	assert.JSONEq(t, `{"ok":true}`, out.String())
+
+	out.Reset()
+	args = []string{"events", "create", "--field", "status", "--prefer", "fast", "--output", "json"}
+	require.NoError(t, handler.ExecuteCommand("audit", appConfig, args))
+	assert.Equal(t, map[string]any{"field": "status", "prefer": "fast"}, gotBody, "--field and --prefer are sent as body properties")
+}
+
+func TestExecuteCommand_DefaultRequestHeaders(t *testing.T) {
+	var gotHeaders []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = append(gotHeaders, r.Header.Clone())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	args := []string{"pet", "get", "--petId", "1", "--json"}
+	for range 2 {
+		if err := handler.ExecuteCommand("petstore", appConfig, args); err != nil {
+			t.Fatalf("ExecuteCommand failed: %v", err)
+		}
+	}
+
+	if ua := gotHeaders[0].Get("User-Agent"); ua != "OpenBridge/dev" {
+		t.Errorf("expected default User-Agent, got %q", ua)
+	}
+	first, second := gotHeaders[0].Get("X-Request-ID"), gotHeaders[1].Get("X-Request-ID")
+	if len(first) != 36 || first == second {
+		t.Errorf("expected a distinct generated request id per request, got %q and %q", first, second)
+	}
+
+	profile := appConfig.Profiles["default"]
+	profile.Headers = map[string]string{"User-Agent": "my-agent/2.0", "X-Request-ID": "req-123"}
+	appConfig.Profiles["default"] = profile
+	if err := handler.ExecuteCommand("petstore", appConfig, args); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if ua := gotHeaders[2].Get("User-Agent"); ua != "my-agent/2.0" {
+		t.Errorf("expected profile User-Agent to win, got %q", ua)
+	}
+	if id := gotHeaders[2].Get("X-Request-ID"); id != "req-123" {
+		t.Errorf("expected profile request id to win, got %q", id)
+	}
+}
+
+func TestExecuteCommand_ProfileHeadersDoNotOverrideAuth(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withCredential("bearer", credential.NewBearerCredential("s3cret-token")))
+	profile := appConfig.Profiles["default"]
+	profile.Headers = map[string]string{"Authorization": "Bearer stale-token"}
+	appConfig.Profiles["default"] = profile
+
+	if err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand failed: %v", err)
+	}
+	if gotAuth != "Bearer s3cret-token" {
+		t.Errorf("expected the stored credential to win over the profile header, got %q", gotAuth)
+	}
+}
+
+func TestExecuteCommand_OperationIDVerbs(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.Method + " " + r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("customers", `openapi: 3.0.3
+info:
+  title: Customers
+  version: 1.0.0
+paths:
+  /customers/search:
+    post:
+      operationId: listCustomers
+      responses:
+        "200":
+          description: Customers
+`))
+
+	require.NoError(t, handler.ExecuteCommand("customers", appConfig, []string{"customers-search", "search", "--json"}))
+	assert.Equal(t, "POST /customers/search", gotPath)
+
+	appConfig.OperationIDVerbs = true
+	gotPath = ""
+	require.NoError(t, handler.ExecuteCommand("customers", appConfig, []string{"customers-search", "list", "--json"}))
+	assert.Equal(t, "POST /customers/search", gotPath, "operation_id_verbs names the command after listCustomers")
+}
+
+func TestWriteGlobalFlagsSection_MatchesOperationHelp(t *testing.T) {
+	var global, operation strings.Builder
+	(&Handler{}).writeGlobalFlagsSection(&global)
+	writeOutputFlags(&operation)
+
+	const formats = "json, jsonl, yaml, headers, raw, go-template=<template>"
+	assert.Contains(t, global.String(), formats)
+	assert.Contains(t, operation.String(), formats)
+	assert.Contains(t, global.String(), "--timeout-retry-budget <duration>")
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// headResponse is what a HEAD request prints: the response has no body, so
+// its status and headers are the result.
+type headResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+}
+
+// printHeadResponse prints the status and headers of a HEAD response in the
+// requested output format, as selected and masked by responseHeaders.
+func (h *Handler) printHeadResponse(resp *http.Response, params map[string]any) error {
+	result := headResponse{Status: resp.StatusCode, Headers: responseHeaders(resp, params)}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode response headers: %w", err)
+	}
+	return h.formatAndPrintOutput(data, params)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const headFilesSpec = `openapi: "3.0.0"
+info:
+  title: Files
+  version: "1.0.0"
+paths:
+  /files/{id}:
+    head:
+      operationId: checkFile
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: The file exists
+    options:
+      operationId: fileOptions
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "204":
+          description: Allowed methods
+`
+
+func TestExecuteCommand_HeadAndOptions(t *testing.T) {
+	var gotMethods []string
+	var gotBodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethods = append(gotMethods, r.Method)
+		gotBodies = append(gotBodies, string(body))
+		w.Header().Set("X-File-Size", "1024")
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("files", headFilesSpec))
+
+	require.NoError(t, handler.ExecuteCommand("files", appConfig, []string{"files", "check", "report.pdf", "--json"}))
+
+	var result headResponse
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result), "stdout: %s", out.String())
+	assert.Equal(t, http.StatusOK, result.Status)
+	assert.Equal(t, "1024", result.Headers["X-File-Size"])
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("files", appConfig, []string{"files", "check", "report.pdf"}))
+	assert.Contains(t, out.String(), "X-File-Size: \"1024\"")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("files", appConfig, []string{"files", "options", "report.pdf", "--body", "ignored"}))
+
+	assert.Equal(t, []string{http.MethodHead, http.MethodHead, http.MethodOptions}, gotMethods)
+	assert.Equal(t, []string{"", "", ""}, gotBodies, "no body is sent")
+}
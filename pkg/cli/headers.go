@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// headersOutputFormat is the --output value that prints the response headers
+// instead of the body.
+const headersOutputFormat = "headers"
+
+// maskedHeaderValue replaces the value of a sensitive response header.
+const maskedHeaderValue = "[REDACTED]"
+
+// responseHeaders returns the headers of a response, repeated headers joined
+// with ", ". --show-headers with a comma-separated list of names selects those
+// headers only. Sensitive values are masked unless --reveal is given.
+func responseHeaders(resp *http.Response, params map[string]any) map[string]string {
+	selected := selectedHeaders(params)
+	reveal := flagEnabled(params, "reveal")
+
+	headers := make(map[string]string, len(resp.Header))
+	for name, values := range resp.Header {
+		if len(selected) > 0 && !slices.Contains(selected, name) {
+			continue
+		}
+		value := strings.Join(values, ", ")
+		if !reveal && config.IsSensitiveHeader(name) {
+			value = maskedHeaderValue
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+// headersRequested reports whether --show-headers was given, either bare or
+// with a list of header names, and not set to false.
+func headersRequested(params map[string]any) bool {
+	value, ok := params["show-headers"]
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(fmt.Sprint(value))
+	return err != nil || enabled
+}
+
+// selectedHeaders returns the canonical names of the headers listed with
+// --show-headers, or nil when all headers are shown.
+func selectedHeaders(params map[string]any) []string {
+	value, ok := params["show-headers"].(string)
+	if !ok {
+		return nil
+	}
+	if _, err := strconv.ParseBool(value); err == nil {
+		return nil
+	}
+
+	var names []string
+	for name := range strings.SplitSeq(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+	}
+	return names
+}
+
+// printHeaderOutput prints the response headers when they replace the body:
+// --header-out <name> prints the value of one header, for use in scripts, and
+// --output headers prints every header. It reports whether it printed them.
+func (h *Handler) printHeaderOutput(resp *http.Response, params map[string]any) (bool, error) {
+	if name, ok := params["header-out"].(string); ok {
+		name = http.CanonicalHeaderKey(name)
+		if _, found := resp.Header[name]; !found {
+			return true, fmt.Errorf("response has no %s header", name)
+		}
+		value := strings.Join(resp.Header.Values(name), ", ")
+		if !flagEnabled(params, "reveal") && config.IsSensitiveHeader(name) {
+			value = maskedHeaderValue
+		}
+		_, _ = fmt.Fprintln(h.stdout, value)
+		return true, nil
+	}
+
+	if determineOutputFormat(params) == headersOutputFormat {
+		writeHeaderLines(h.stdout, responseHeaders(resp, params))
+		return true, nil
+	}
+	return false, nil
+}
+
+// writeHeaderLines writes headers as "Name: value" lines, sorted by name.
+func writeHeaderLines(w io.Writer, headers map[string]string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", name, headers[name])
+	}
+}
+
+// includeResponseHeaders adds the response headers to the output for
+// --show-headers. JSON and YAML output wrap the body as
+// {"status": ..., "headers": {...}, "body": ...}; other formats print the
+// headers as "Name: value" lines and a blank line before the body.
+func (h *Handler) includeResponseHeaders(resp *http.Response, body []byte, params map[string]any) ([]byte, error) {
+	headers := responseHeaders(resp, params)
+
+	switch determineOutputFormat(params) {
+	case "json", "jsonl", "yaml":
+	default:
+		writeHeaderLines(h.stdout, headers)
+		_, _ = fmt.Fprintln(h.stdout)
+		return body, nil
+	}
+
+	var decoded any = string(body)
+	if len(body) == 0 {
+		decoded = nil
+	} else if json.Valid(body) {
+		decoded = json.RawMessage(body)
+	}
+
+	wrapped, err := json.Marshal(map[string]any{
+		"status":  resp.StatusCode,
+		"headers": headers,
+		"body":    decoded,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response headers: %w", err)
+	}
+	return wrapped, nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHeadersServer returns a server whose list pets response carries paging
+// and session headers.
+func newHeadersServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "42")
+		w.Header().Set("X-Rate-Limit-Remaining", "99")
+		w.Header().Set("Set-Cookie", "session=abc123")
+		_, _ = w.Write([]byte(`[{"id":1,"name":"doggie"}]`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteCommand_HeaderOut(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, newHeadersServer(t).URL, &out, withSpecFile("complex", complexSpecPath))
+
+	require.NoError(t, handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--header-out", "x-total-count"}))
+	assert.Equal(t, "42\n", out.String())
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--header-out", "Set-Cookie"}))
+	assert.Equal(t, "[REDACTED]\n", out.String())
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--header-out", "Set-Cookie", "--reveal"}))
+	assert.Equal(t, "session=abc123\n", out.String())
+
+	err := handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--header-out", "ETag"})
+	assert.EqualError(t, err, "response has no Etag header")
+}
+
+func TestExecuteCommand_ShowHeaders(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, newHeadersServer(t).URL, &out, withSpecFile("complex", complexSpecPath))
+
+	require.NoError(t, handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--show-headers", "X-Total-Count,x-rate-limit-remaining", "--output", "json"}))
+	var result struct {
+		Status  int               `json:"status"`
+		Headers map[string]string `json:"headers"`
+		Body    []map[string]any  `json:"body"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result), "stdout: %s", out.String())
+	assert.Equal(t, http.StatusOK, result.Status)
+	assert.Equal(t, map[string]string{"X-Total-Count": "42", "X-Rate-Limit-Remaining": "99"}, result.Headers)
+	assert.Equal(t, "doggie", result.Body[0]["name"])
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--output", "headers"}))
+	assert.Contains(t, out.String(), "Set-Cookie: [REDACTED]\n")
+	assert.Contains(t, out.String(), "X-Total-Count: 42\n")
+	assert.NotContains(t, out.String(), "doggie")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("complex", appConfig, []string{"pets", "list", "--show-headers", "--output", "go-template={{.name}}"}))
+	assert.Contains(t, out.String(), "X-Total-Count: 42\n")
+	assert.Contains(t, out.String(), "Set-Cookie: [REDACTED]\n")
+	assert.Contains(t, out.String(), "\n\ndoggie\n")
+}
+
+func TestExecuteCommand_HeaderOutLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", "/pet/10")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--photoUrls", `["a.png"]`, "--header-out", "Location"})
+	require.NoError(t, err)
+	assert.Equal(t, "/pet/10\n", out.String())
+}
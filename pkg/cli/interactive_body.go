@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"golang.org/x/term"
+)
+
+// promptMissingBodyFields asks for each required request body property that
+// was not given, with --interactive-body and an interactive input. Each
+// prompt shows the property's type hint; answers for writeOnly, password
+// and secret-looking properties are not echoed on a terminal. Without an
+// interactive input nothing is asked, and validation reports the missing
+// properties as usual. An empty answer leaves the property missing.
+func (h *Handler) promptMissingBodyFields(opSpec *openapi3.Operation, opParams openapi3.Parameters, params map[string]any) error {
+	if !flagEnabled(params, "interactive-body") || operationHasName(opSpec, opParams, "interactive-body") || !isInteractive(h.stdin) {
+		return nil
+	}
+	if _, ok := params["body"]; ok {
+		return nil
+	}
+	if _, ok := params[request.BodyYAMLParam]; ok {
+		return nil
+	}
+
+	schema := jsonBodySchema(opSpec)
+	if schema == nil {
+		return nil
+	}
+
+	reader := bufio.NewReader(h.stdin)
+	for _, name := range missingRequiredBodyFields(schema, params) {
+		var prop *openapi3.Schema
+		if ref := schema.Properties[name]; ref != nil {
+			prop = ref.Value
+		}
+
+		_, _ = fmt.Fprintf(h.stderr, "%s (%s): ", name, bodyFieldHint(prop))
+		answer, err := h.readBodyFieldAnswer(reader, isSensitiveBodyField(name, prop))
+		if errors.Is(err, io.EOF) && answer == "" {
+			_, _ = fmt.Fprintln(h.stderr)
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read body field '%s': %w", name, err)
+		}
+		if answer == "" {
+			continue
+		}
+
+		parsed, err := ParseValue(answer)
+		if err != nil {
+			return fmt.Errorf("invalid value for body field '%s': %w", name, err)
+		}
+		params[name] = convertBodyParamToSchemaType(opSpec, name, parsed)
+	}
+	return nil
+}
+
+// readBodyFieldAnswer reads one answer. A masked answer typed on a terminal
+// is not echoed.
+func (h *Handler) readBodyFieldAnswer(reader *bufio.Reader, masked bool) (string, error) {
+	if f, ok := h.stdin.(*os.File); ok && masked && term.IsTerminal(int(f.Fd())) {
+		answer, err := term.ReadPassword(int(f.Fd()))
+		_, _ = fmt.Fprintln(h.stderr)
+		return strings.TrimSpace(string(answer)), err
+	}
+
+	answer, err := reader.ReadString('\n')
+	return strings.TrimSpace(answer), err
+}
+
+// jsonBodySchema returns the schema of an operation's JSON request body.
+func jsonBodySchema(opSpec *openapi3.Operation) *openapi3.Schema {
+	if opSpec == nil || opSpec.RequestBody == nil || opSpec.RequestBody.Value == nil {
+		return nil
+	}
+	mediaType := opSpec.RequestBody.Value.Content.Get("application/json")
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+	return mediaType.Schema.Value
+}
+
+// missingRequiredBodyFields returns the required properties of a body schema
+// that are not in params, in the schema's order. A dotted flag such as
+// --address.city sets its top-level property.
+func missingRequiredBodyFields(schema *openapi3.Schema, params map[string]any) []string {
+	var missing []string
+	for _, name := range schema.Required {
+		if _, ok := params[name]; ok {
+			continue
+		}
+		given := false
+		for key := range params {
+			if strings.HasPrefix(key, name+".") {
+				given = true
+				break
+			}
+		}
+		if !given {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// bodyFieldHint describes the value expected for a body property, e.g.
+// "string, one of: available, pending, sold". Arrays and objects are
+// entered as JSON.
+func bodyFieldHint(prop *openapi3.Schema) string {
+	if prop == nil {
+		return "any"
+	}
+
+	types := request.SchemaTypes(prop)
+	hint := strings.Join(types, " or ")
+	if hint == "" {
+		hint = "any"
+	}
+	if prop.Format != "" {
+		hint += ", format: " + prop.Format
+	}
+	if len(prop.Enum) > 0 {
+		values := make([]string, len(prop.Enum))
+		for i, value := range prop.Enum {
+			values[i] = fmt.Sprint(value)
+		}
+		hint += ", one of: " + strings.Join(values, ", ")
+	}
+	if len(types) == 1 && (types[0] == "array" || types[0] == "object") {
+		hint += ", as JSON"
+	}
+	return hint
+}
+
+// isSensitiveBodyField reports whether the answer for a body property should
+// be masked: the property is writeOnly, has the password format, or its name
+// looks like it carries a secret.
+func isSensitiveBodyField(name string, prop *openapi3.Schema) bool {
+	if prop != nil && (prop.WriteOnly || prop.Format == "password") {
+		return true
+	}
+	return config.IsSensitiveHeader(name)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const interactiveBodySpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      operationId: createUser
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [username, password, role, age, tags]
+              properties:
+                username:
+                  type: string
+                password:
+                  type: string
+                  writeOnly: true
+                role:
+                  type: string
+                  enum: [admin, member]
+                age:
+                  type: integer
+                tags:
+                  type: array
+                  items:
+                    type: string
+      responses:
+        "201":
+          description: Created
+`
+
+func TestExecuteCommand_InteractiveBody(t *testing.T) {
+	server, gotBody := newCreatedServer(t, `{"id":1}`)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("users", interactiveBodySpec), withErrorOutput(&errOut))
+	handler.SetInput(strings.NewReader("s3cret\nadmin\n42\n[\"ops\"]\n"))
+
+	require.NoError(t, handler.ExecuteCommand("users", appConfig, []string{"users", "create", "--username", "alice", "--interactive-body"}))
+	assert.JSONEq(t, `{"username":"alice","password":"s3cret","role":"admin","age":42,"tags":["ops"]}`, string(*gotBody))
+
+	prompts := errOut.String()
+	assert.NotContains(t, prompts, "username (")
+	assert.Contains(t, prompts, "password (string): ")
+	assert.Contains(t, prompts, "role (string, one of: admin, member): ")
+	assert.Contains(t, prompts, "age (integer): ")
+	assert.Contains(t, prompts, "tags (array, as JSON): ")
+}
+
+func TestExecuteCommand_InteractiveBody_EndOfInput(t *testing.T) {
+	server, _ := newCreatedServer(t, `{"id":1}`)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("users", interactiveBodySpec), withErrorOutput(&errOut))
+	handler.SetInput(strings.NewReader("s3cret\n"))
+
+	err := handler.ExecuteCommand("users", appConfig, []string{"users", "create", "--username", "alice", "--interactive-body"})
+	assert.ErrorContains(t, err, "required body parameter 'role' is missing")
+}
+
+func TestExecuteCommand_InteractiveBody_NotInteractive(t *testing.T) {
+	server, gotBody := newCreatedServer(t, `{"id":1}`)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("users", interactiveBodySpec), withErrorOutput(&errOut))
+
+	input, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = input.Close() })
+	handler.SetInput(input)
+
+	err = handler.ExecuteCommand("users", appConfig, []string{"users", "create", "--username", "alice", "--interactive-body"})
+	assert.ErrorContains(t, err, "required body parameter 'password' is missing")
+	assert.NotContains(t, errOut.String(), "password (")
+	assert.Nil(t, *gotBody)
+}
+
+func TestExecuteCommand_InteractiveBody_RequiresFlag(t *testing.T) {
+	server, _ := newCreatedServer(t, `{"id":1}`)
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("users", interactiveBodySpec), withErrorOutput(&errOut))
+	handler.SetInput(strings.NewReader("s3cret\n"))
+
+	require.Error(t, handler.ExecuteCommand("users", appConfig, []string{"users", "create", "--username", "alice"}))
+	assert.NotContains(t, errOut.String(), "password (")
+}
+
+func TestIsSensitiveBodyField(t *testing.T) {
+	assert.True(t, isSensitiveBodyField("secret", &openapi3.Schema{WriteOnly: true}))
+	assert.True(t, isSensitiveBodyField("pin", &openapi3.Schema{Format: "password"}))
+	assert.True(t, isSensitiveBodyField("apiToken", nil))
+	assert.False(t, isSensitiveBodyField("username", openapi3.NewStringSchema()))
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteJSONLines writes a JSON response as JSON lines.
+// Each element of a top-level array, or of a top-level "data" array as used by
+// list envelopes, is written on its own line. Any other JSON value is written
+// as a single line. Bodies that are not JSON are written unchanged.
+func WriteJSONLines(w io.Writer, body []byte) error {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		_, err := fmt.Fprintln(w, string(body))
+		return err
+	}
+
+	for _, item := range jsonLineItems(data) {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON line: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, string(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonLineItems returns the values that should each be emitted as a line.
+func jsonLineItems(data any) []any {
+	switch v := data.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		if items, ok := v["data"].([]any); ok {
+			return items
+		}
+	}
+	return []any{data}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONLines_StripeList(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSONLines(&buf, []byte(stripeListResponse)))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2, "expected one line per customer")
+
+	for i, want := range []string{"cus_1", "cus_2"} {
+		var customer map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &customer))
+		assert.Equal(t, want, customer["id"])
+	}
+}
+
+func TestWriteJSONLines_Shapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"top-level array", `[{"id": 1}, {"id": 2}]`, "{\"id\":1}\n{\"id\":2}\n"},
+		{"object", `{"id": 1, "name": "rex"}`, "{\"id\":1,\"name\":\"rex\"}\n"},
+		{"empty array", `[]`, ""},
+		{"non-JSON", `plain text`, "plain text\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteJSONLines(&buf, []byte(tt.body)))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestFormatOutput_JSONL(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+
+	output, err := h.FormatOutput([]byte(stripeListResponse), "jsonl")
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(output, "\n"), 2)
+	assert.True(t, strings.HasPrefix(output, `{"email":"a@example.com"`))
+}
+
+func TestDetermineOutputFormat_KeepsOutputFlag(t *testing.T) {
+	params := map[string]any{"output": "jsonl", "name": "rex"}
+	_, _, _, clean := extractCLIFlags(params, nil, nil)
+
+	assert.NotContains(t, clean, "output")
+	assert.Equal(t, "jsonl", determineOutputFormat(params))
+}
+
+func TestExecuteCommand_ShortOutputFlag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 1}, {"id": 2}]`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "-o", "jsonl"}))
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", out.String())
+}
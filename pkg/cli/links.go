@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+// linkExpressionPattern matches runtime expressions embedded in a string, e.g. "{$response.body#/id}".
+var linkExpressionPattern = regexp.MustCompile(`\{(\$[^{}]+)\}`)
+
+// LinkSuggestion is a follow-up command derived from an OpenAPI response link.
+type LinkSuggestion struct {
+	Name        string
+	Description string
+	Resource    string
+	Verb        string
+	Params      map[string]string
+}
+
+// Command formats the suggestion as an "ob run" command line for the app.
+// Parameters that could not be resolved are shown as <name> placeholders.
+func (s LinkSuggestion) Command(appName string) string {
+	parts := []string{"ob", "run", appName, s.Resource, s.Verb}
+	for _, name := range slices.Sorted(maps.Keys(s.Params)) {
+		parts = append(parts, "--"+name, shellQuote(s.Params[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// linkContext holds the values that link runtime expressions are evaluated against.
+type linkContext struct {
+	method        string
+	statusCode    int
+	requestParams map[string]any
+	responseBody  any
+}
+
+// SuggestLinks returns the follow-up commands described by the links of the response
+// that opSpec declares for statusCode. Links whose target operation has no command
+// in the tree are skipped.
+func SuggestLinks(specDoc *openapi3.T, tree *semantic.CommandTree, opSpec *openapi3.Operation, method string, statusCode int, params map[string]any, body []byte) []LinkSuggestion {
+	if opSpec == nil || opSpec.Responses == nil {
+		return nil
+	}
+	responseRef := opSpec.Responses.Status(statusCode)
+	if responseRef == nil {
+		responseRef = opSpec.Responses.Default()
+	}
+	if responseRef == nil || responseRef.Value == nil || len(responseRef.Value.Links) == 0 {
+		return nil
+	}
+
+	ctx := linkContext{method: method, statusCode: statusCode, requestParams: params}
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &ctx.responseBody)
+	}
+
+	links := responseRef.Value.Links
+	var suggestions []LinkSuggestion
+	for _, name := range slices.Sorted(maps.Keys(links)) {
+		linkRef := links[name]
+		if linkRef == nil || linkRef.Value == nil {
+			continue
+		}
+		link := linkRef.Value
+
+		resource, target := findLinkTarget(specDoc, tree, link)
+		if target == nil {
+			continue
+		}
+
+		suggestion := LinkSuggestion{
+			Name:        name,
+			Description: link.Description,
+			Resource:    resource,
+			Verb:        target.Name,
+			Params:      make(map[string]string, len(link.Parameters)),
+		}
+		for paramName, expr := range link.Parameters {
+			// Parameter names may be qualified with their location, e.g. "path.id".
+			if location, unqualified, ok := strings.Cut(paramName, "."); ok && isParameterLocation(location) {
+				paramName = unqualified
+			}
+			value, ok := ctx.evaluate(expr)
+			if !ok {
+				value = "<" + paramName + ">"
+			}
+			suggestion.Params[paramName] = value
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+// isParameterLocation reports whether s is an OpenAPI parameter location.
+func isParameterLocation(s string) bool {
+	switch s {
+	case openapi3.ParameterInPath, openapi3.ParameterInQuery, openapi3.ParameterInHeader, openapi3.ParameterInCookie:
+		return true
+	default:
+		return false
+	}
+}
+
+// findLinkTarget returns the command resource name and operation that a link points
+// to, by operationId or by a local operationRef such as "#/paths/~1pets~1{id}/get".
+func findLinkTarget(specDoc *openapi3.T, tree *semantic.CommandTree, link *openapi3.Link) (string, *semantic.Operation) {
+	operationID := link.OperationID
+	var path, method string
+	if operationID == "" && link.OperationRef != "" {
+		var ok bool
+		if path, method, ok = parseOperationRef(link.OperationRef); !ok {
+			return "", nil
+		}
+		if specDoc != nil && specDoc.Paths != nil {
+			if pathItem := specDoc.Paths.Value(path); pathItem != nil {
+				if op := getOperationSpec(pathItem, method); op != nil {
+					operationID = op.OperationID
+				}
+			}
+		}
+	}
+
+	match := func(op *semantic.Operation) bool {
+		if operationID != "" {
+			return op.OperationID == operationID
+		}
+		return op.Path == path && op.Method == method
+	}
+	for _, name := range slices.Sorted(maps.Keys(tree.RootResources)) {
+		if resource, op := findOperationInResource(name, tree.RootResources[name], match); op != nil {
+			return resource, op
+		}
+	}
+	return "", nil
+}
+
+// findOperationInResource searches a resource and its sub-resources for a matching operation.
+func findOperationInResource(name string, res *semantic.Resource, match func(*semantic.Operation) bool) (string, *semantic.Operation) {
+	for _, verb := range slices.Sorted(maps.Keys(res.Operations)) {
+		if op := res.Operations[verb]; match(op) {
+			return name, op
+		}
+	}
+	for _, subName := range slices.Sorted(maps.Keys(res.SubResources)) {
+		if resource, op := findOperationInResource(subName, res.SubResources[subName], match); op != nil {
+			return resource, op
+		}
+	}
+	return "", nil
+}
+
+// parseOperationRef splits a local operationRef into its path and upper-case method.
+func parseOperationRef(ref string) (string, string, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#/paths/")
+	if !ok {
+		return "", "", false
+	}
+	idx := strings.LastIndex(pointer, "/")
+	if idx <= 0 {
+		return "", "", false
+	}
+	return unescapeJSONPointer(pointer[:idx]), strings.ToUpper(pointer[idx+1:]), true
+}
+
+// evaluate resolves a link parameter value. Strings that are runtime expressions
+// are evaluated, strings with embedded {$...} expressions are interpolated, and
+// other values are used as constants.
+func (c linkContext) evaluate(value any) (string, bool) {
+	str, isString := value.(string)
+	if !isString {
+		return captureString(value), true
+	}
+	if strings.HasPrefix(str, "$") {
+		return c.evaluateExpression(str)
+	}
+
+	resolved := true
+	result := linkExpressionPattern.ReplaceAllStringFunc(str, func(match string) string {
+		value, ok := c.evaluateExpression(match[1 : len(match)-1])
+		if !ok {
+			resolved = false
+		}
+		return value
+	})
+	return result, resolved
+}
+
+// evaluateExpression evaluates a single runtime expression such as
+// "$response.body#/id" or "$request.path.petId".
+func (c linkContext) evaluateExpression(expr string) (string, bool) {
+	switch {
+	case expr == "$method":
+		return c.method, true
+	case expr == "$statusCode":
+		return strconv.Itoa(c.statusCode), true
+	case strings.HasPrefix(expr, "$response.body"):
+		return lookupJSONPointer(c.responseBody, strings.TrimPrefix(expr, "$response.body"))
+	case strings.HasPrefix(expr, "$request.body"):
+		return lookupJSONPointer(c.requestParams, strings.TrimPrefix(expr, "$request.body"))
+	case strings.HasPrefix(expr, "$request."):
+		_, name, ok := strings.Cut(strings.TrimPrefix(expr, "$request."), ".")
+		if !ok {
+			return "", false
+		}
+		value, ok := c.requestParams[name]
+		if !ok {
+			return "", false
+		}
+		return captureString(value), true
+	default:
+		return "", false
+	}
+}
+
+// lookupJSONPointer returns the value at a "#/a/0/b" fragment of decoded JSON data.
+func lookupJSONPointer(data any, fragment string) (string, bool) {
+	if data == nil {
+		return "", false
+	}
+	pointer, ok := strings.CutPrefix(fragment, "#")
+	if !ok && fragment != "" {
+		return "", false
+	}
+
+	current := data
+	if pointer != "" {
+		for token := range strings.SplitSeq(strings.TrimPrefix(pointer, "/"), "/") {
+			token = unescapeJSONPointer(token)
+			switch v := current.(type) {
+			case map[string]any:
+				if current, ok = v[token]; !ok {
+					return "", false
+				}
+			case []any:
+				index, err := strconv.Atoi(token)
+				if err != nil || index < 0 || index >= len(v) {
+					return "", false
+				}
+				current = v[index]
+			default:
+				return "", false
+			}
+		}
+	}
+	return captureString(current), true
+}
+
+// unescapeJSONPointer decodes the ~1 and ~0 escapes of a JSON pointer token.
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// shellQuote quotes a value for display in a shell command when needed.
+func shellQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"\\$`;&|<>()*?[]#~!{}") {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// printLinkSuggestions writes the follow-up commands for a successful response to
+// stderr when --suggest was given.
+func (h *Handler) printLinkSuggestions(appName string, appConfig *config.AppConfig, opSpec *openapi3.Operation, op *semantic.Operation, statusCode int, params, requestParams map[string]any, body []byte) {
+	if _, ok := params["suggest"]; !ok {
+		return
+	}
+	specDoc, err := h.loadAndCacheSpec(appName, appConfig)
+	if err != nil {
+		return
+	}
+	tree := h.commandTree(appConfig, specDoc)
+	suggestions := SuggestLinks(specDoc, tree, opSpec, op.Method, statusCode, requestParams, body)
+	if len(suggestions) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\nSuggested next commands:\n")
+	for _, suggestion := range suggestions {
+		label := suggestion.Name
+		if suggestion.Description != "" {
+			label += ": " + suggestion.Description
+		}
+		fmt.Fprintf(&sb, "  # %s\n  %s\n", label, suggestion.Command(appName))
+	}
+	_, _ = fmt.Fprint(h.stderr, sb.String())
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const linksSpec = `
+openapi: "3.0.0"
+info:
+  title: Links API
+  version: "1.0.0"
+paths:
+  /pet:
+    post:
+      operationId: addPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        "201":
+          description: created
+          links:
+            GetPet:
+              operationId: getPetById
+              description: Fetch the created pet
+              parameters:
+                petId: $response.body#/id
+            ListOwnerPets:
+              operationRef: '#/paths/~1owner~1{ownerId}~1pet/get'
+              parameters:
+                path.ownerId: $response.body#/owner/id
+                name: 'pet {$request.body#/name}'
+  /pet/{petId}:
+    get:
+      operationId: getPetById
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: ok
+  /owner/{ownerId}/pet:
+    get:
+      operationId: listOwnerPets
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+          schema:
+            type: string
+        - name: name
+          in: query
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+`
+
+func TestExecuteCommand_SuggestLinks(t *testing.T) {
+	server, _ := newCreatedServer(t, `{"id":42,"owner":{"id":"o-7"}}`)
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("shop", linksSpec), withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("shop", appConfig, []string{"pet", "create", "--name", "rex", "--suggest", "--json"})
+	require.NoError(t, err)
+
+	assert.NotContains(t, stdout.String(), "Suggested")
+	assert.Contains(t, stderr.String(), "# GetPet: Fetch the created pet\n  ob run shop pet get --petId 42\n")
+	assert.Contains(t, stderr.String(), "# ListOwnerPets\n  ob run shop owner-pet list --name 'pet rex' --ownerId o-7\n")
+
+	// The suggested command resolves to a real operation.
+	err = handler.ExecuteCommand("shop", appConfig, []string{"owner-pet", "list", "--name", "pet rex", "--ownerId", "o-7", "--json"})
+	require.NoError(t, err)
+}
+
+func TestExecuteCommand_SuggestLinksUnresolved(t *testing.T) {
+	server, _ := newCreatedServer(t, `{"name":"rex"}`)
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("shop", linksSpec), withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("shop", appConfig, []string{"pet", "create", "--name", "rex", "--suggest", "--json"})
+	require.NoError(t, err)
+	assert.Contains(t, stderr.String(), "ob run shop pet get --petId '<petId>'")
+}
+
+func TestExecuteCommand_NoSuggestionsByDefault(t *testing.T) {
+	server, _ := newCreatedServer(t, `{"id":42}`)
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("shop", linksSpec), withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("shop", appConfig, []string{"pet", "create", "--name", "rex", "--json"})
+	require.NoError(t, err)
+	assert.Empty(t, stderr.String())
+}
+
+func TestLookupJSONPointer(t *testing.T) {
+	data := map[string]any{"items": []any{map[string]any{"a/b": "x"}}, "n": float64(3)}
+
+	value, ok := lookupJSONPointer(data, "#/items/0/a~1b")
+	assert.True(t, ok)
+	assert.Equal(t, "x", value)
+
+	value, ok = lookupJSONPointer(data, "#/n")
+	assert.True(t, ok)
+	assert.Equal(t, "3", value)
+
+	_, ok = lookupJSONPointer(data, "#/items/5")
+	assert.False(t, ok)
+}
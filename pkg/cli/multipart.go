@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nomagicln/open-bridge/pkg/request"
+)
+
+// multipartBuildOptions returns the request builder options for a
+// multipart/form-data body: --files <dir> attaches every file in a directory
+// as a part named by its base name, and each --field name=value adds a form
+// field. The --field flag may be repeated, so it is read from the raw CLI
+// arguments rather than the merged parameters, which keep only the last value.
+// flags holds the CLI flags given, as returned by cliFlags.
+func multipartBuildOptions(flags map[string]any, args []string) ([]request.BuildOption, error) {
+	var opts []request.BuildOption
+
+	if files, ok := flags["files"]; ok {
+		dir, isString := files.(string)
+		if !isString || dir == "" {
+			return nil, errors.New("--files requires a directory")
+		}
+		opts = append(opts, request.WithMultipartFiles(dir))
+	}
+
+	if _, ok := flags["field"]; !ok {
+		return opts, nil
+	}
+	fields, err := formFieldFlags(args)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		opts = append(opts, request.WithFormField(field[0], field[1]))
+	}
+	return opts, nil
+}
+
+// formFieldFlags returns the name and value of each --field name=value flag,
+// in order, ignoring request arguments after a "--" delimiter.
+func formFieldFlags(args []string) ([][2]string, error) {
+	return nameValueFlags(args, "field")
+}
+
+// nameValueFlags returns the name and value of each --<flag> name=value flag,
+// in order, ignoring request arguments after a "--" delimiter.
+func nameValueFlags(args []string, flag string) ([][2]string, error) {
+	cliArgs, _, _ := SplitArgs(args)
+
+	var pairs [][2]string
+	for i := 0; i < len(cliArgs); i++ {
+		var value string
+		switch arg := cliArgs[i]; {
+		case arg == "--"+flag:
+			if i+1 >= len(cliArgs) {
+				return nil, fmt.Errorf("--%s requires a name=value argument", flag)
+			}
+			i++
+			value = cliArgs[i]
+		case strings.HasPrefix(arg, "--"+flag+"="):
+			value = strings.TrimPrefix(arg, "--"+flag+"=")
+		default:
+			continue
+		}
+
+		name, pairValue, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --%s %q: expected name=value", flag, value)
+		}
+		pairs = append(pairs, [2]string{name, pairValue})
+	}
+	return pairs, nil
+}
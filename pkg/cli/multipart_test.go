@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_MultipartFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "photo1.png"), []byte("png-1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "photo2.png"), []byte("png-2"), 0644))
+
+	files := make(map[string]string)
+	fields := make(map[string][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fields = r.MultipartForm.Value
+		for name, headers := range r.MultipartForm.File {
+			f, err := headers[0].Open()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			var buf bytes.Buffer
+			_, _ = buf.ReadFrom(f)
+			_ = f.Close()
+			files[name] = buf.String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{
+		"pet", "create", "--files", dir, "--field", "name=doggie", "--field=tag=good boy",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"photo1.png": "png-1", "photo2.png": "png-2"}, files)
+	assert.Equal(t, map[string][]string{"name": {"doggie"}, "tag": {"good boy"}}, fields)
+}
+
+func TestFormFieldFlags(t *testing.T) {
+	fields, err := formFieldFlags([]string{"--field", "a=1", "--json", "--field=b=x=y", "--", "--field", "c=3"})
+	require.NoError(t, err)
+	assert.Equal(t, [][2]string{{"a", "1"}, {"b", "x=y"}}, fields)
+
+	_, err = formFieldFlags([]string{"--field", "novalue"})
+	assert.ErrorContains(t, err, "expected name=value")
+}
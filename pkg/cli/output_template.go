@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// goTemplateFormat prefixes an --output value holding a Go template, as in
+// --output 'go-template={{.name}} ({{.status}})'.
+const goTemplateFormat = "go-template="
+
+// outputTemplate returns the response template given with --format-template or
+// --output go-template=..., if any.
+func outputTemplate(params map[string]any) (string, bool) {
+	if text, ok := params["format-template"].(string); ok {
+		return text, true
+	}
+	if output, ok := params["output"].(string); ok {
+		return strings.CutPrefix(output, goTemplateFormat)
+	}
+	return "", false
+}
+
+// renderOutputTemplate renders a JSON response through a Go text/template. An
+// array response renders the template once per element, one per line; any
+// other response renders it once. The json function encodes a value as JSON,
+// and default returns a fallback for a missing or empty value, as in
+// {{default "none" .tag}}.
+func renderOutputTemplate(body []byte, text string) (string, error) {
+	funcs := template.FuncMap{
+		"json": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+		"default": func(fallback, value any) any {
+			if isEmptyTemplateValue(value) {
+				return fallback
+			}
+			return value
+		},
+	}
+
+	tmpl, err := template.New("output").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid output template: %w", err)
+	}
+
+	var data any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return "", fmt.Errorf("output template requires a JSON response: %w", err)
+	}
+
+	items, isArray := data.([]any)
+	if !isArray {
+		items = []any{data}
+	}
+
+	lines := make([]string, 0, len(items))
+	for i, item := range items {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, item); err != nil {
+			if isArray {
+				return "", fmt.Errorf("failed to render output template for element %d: %w", i, err)
+			}
+			return "", fmt.Errorf("failed to render output template: %w", err)
+		}
+		lines = append(lines, buf.String())
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// isEmptyTemplateValue reports whether a template value is missing or empty.
+func isEmptyTemplateValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_OutputGoTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":10000000,"name":"doggie","status":"available","tags":[{"name":"good"}]}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--output", "go-template={{.name}} ({{.status}})"})
+	require.NoError(t, err)
+	assert.Equal(t, "doggie (available)\n", out.String())
+
+	out.Reset()
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "-o", "go-template={{.name}}"})
+	require.NoError(t, err)
+	assert.Equal(t, "doggie\n", out.String())
+
+	out.Reset()
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1",
+		"--format-template", `{{.id}} {{json .tags}} {{default "none" .nickname}}`})
+	require.NoError(t, err)
+	assert.Equal(t, "10000000 [{\"name\":\"good\"}] none\n", out.String())
+}
+
+func TestFormatOutput_GoTemplateStripeList(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+
+	output, err := h.FormatOutput([]byte(stripeListResponse), `go-template={{range .data}}{{.id}} {{.email}};{{end}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "cus_1 a@example.com;cus_2 b@example.com;", output)
+
+	// Once unwrapped, the list renders the template per customer.
+	body, err := TransformResponse([]byte(stripeListResponse), h.responseTransformers(map[string]any{"unwrap": "data"}))
+	require.NoError(t, err)
+	output, err = h.FormatOutput(body, "go-template={{.id}}: {{.email}}")
+	require.NoError(t, err)
+	assert.Equal(t, "cus_1: a@example.com\ncus_2: b@example.com", output)
+}
+
+func TestFormatOutput_GoTemplateErrors(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+
+	_, err := h.FormatOutput([]byte(`{"name":"doggie"}`), "go-template={{.name")
+	assert.ErrorContains(t, err, "invalid output template")
+
+	_, err = h.FormatOutput([]byte(`[{"owner":{"name":"ann"}},{"owner":"bob"}]`), "go-template={{.owner.name}}")
+	assert.ErrorContains(t, err, "failed to render output template for element 1")
+
+	_, err = h.FormatOutput([]byte(`not json`), "go-template={{.name}}")
+	assert.ErrorContains(t, err, "output template requires a JSON response")
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+// PageError reports a page that failed while following --all pagination.
+// Pages before it were fetched successfully.
+type PageError struct {
+	Page int
+	Err  error
+}
+
+func (e *PageError) Error() string {
+	return fmt.Sprintf("page %d failed: %v", e.Page, e.Err)
+}
+
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// executeAllPages sends a GET request and follows the Link rel="next" headers of
+// its responses, printing the items of every page as one list. With -o jsonl the
+// items are streamed as each page arrives. If a page fails, the items already
+// fetched are still printed before the error is reported, so no results are lost.
+func (h *Handler) executeAllPages(appName string, op *semantic.Operation, opSpec *openapi3.Operation, cleanParams, params map[string]any, profile *config.Profile, retry retryPolicy, opts ...request.BuildOption) error {
+	outputFormat := determineOutputFormat(params)
+	if op.Method != http.MethodGet {
+		return fmt.Errorf("--all only applies to GET operations, not %s", op.Method)
+	}
+
+	req, err := h.buildRequest(appName, op, opSpec, cleanParams, profile, opts...)
+	if err != nil {
+		return h.reportRequestError(err, outputFormat)
+	}
+
+	client, err := h.reqBuilder.HTTPClient(appName, profile)
+	if err != nil {
+		return h.reportRequestError(err, outputFormat)
+	}
+
+	stats := &requestStats{}
+	var items []any
+	pages, err := h.followPages(client, req, retry, stats, h.responseTransformers(params), func(page []any) error {
+		items = append(items, page...)
+		if outputFormat != "jsonl" {
+			return nil
+		}
+		data, err := json.Marshal(page)
+		if err != nil {
+			return fmt.Errorf("failed to encode page: %w", err)
+		}
+		return WriteJSONLines(h.stdout, data)
+	})
+
+	if outputFormat != "jsonl" && (err == nil || len(items) > 0) {
+		data, marshalErr := json.Marshal(items)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to encode results: %w", marshalErr)
+		}
+		if printErr := h.formatAndPrintOutput(data, params); printErr != nil {
+			return printErr
+		}
+	}
+	h.printStats(stats, params)
+
+	if err != nil {
+		var pageErr *PageError
+		if errors.As(err, &pageErr) && pageErr.Page > 1 {
+			_, _ = fmt.Fprintf(h.stderr, "Warning: page %d failed; printed %d items from the first %d pages\n", pageErr.Page, len(items), pages)
+		}
+		return h.reportRequestError(err, outputFormat)
+	}
+	return nil
+}
+
+// followPages sends req and then the request for each next page with client,
+// passing the items of every page to emit. It returns the number of pages
+// fetched, which is also recorded in stats. A failing page is returned as a
+// *PageError, as is a next page on another scheme or host, which is not followed.
+func (h *Handler) followPages(client *http.Client, req *http.Request, retry retryPolicy, stats *requestStats, transformers []ResponseTransformer, emit func([]any) error) (int, error) {
+	start := time.Now()
+	defer func() { stats.duration = time.Since(start) }()
+
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		seen[req.URL.String()] = true
+
+		body, resp, err := h.sendPageRequest(client, req, retry, stats)
+		if err != nil {
+			return page - 1, &PageError{Page: page, Err: err}
+		}
+
+		items, err := pageItems(body, transformers)
+		if err != nil {
+			return page - 1, &PageError{Page: page, Err: err}
+		}
+		if err := emit(items); err != nil {
+			return page - 1, err
+		}
+		stats.pages = page
+
+		next := nextPageURL(resp)
+		if next == "" {
+			return page, nil
+		}
+		nextURL, err := req.URL.Parse(next)
+		if err != nil {
+			return page, &PageError{Page: page + 1, Err: fmt.Errorf("invalid next page URL %q: %w", next, err)}
+		}
+		if seen[nextURL.String()] {
+			return page, nil
+		}
+		// The request carries the profile's credentials, so it is not sent to
+		// another origin.
+		if nextURL.Scheme != req.URL.Scheme || nextURL.Host != req.URL.Host {
+			return page, &PageError{Page: page + 1, Err: fmt.Errorf("next page URL %q is on another origin than %s://%s", next, req.URL.Scheme, req.URL.Host)}
+		}
+
+		req = req.Clone(req.Context())
+		req.URL = nextURL
+		req.Host = ""
+	}
+}
+
+// sendPageRequest sends a page request, retrying it according to the retry
+// policy. The deadline applies to each page and its retries, and each retry
+// is added to the retries in stats.
+func (h *Handler) sendPageRequest(client *http.Client, req *http.Request, retry retryPolicy, stats *requestStats) ([]byte, *http.Response, error) {
+	start := time.Now()
+	ctx, cancel := retry.withDeadline(req.Context())
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			stats.retries++
+		}
+		body, resp, err := h.sendRequest(client, req.Clone(ctx))
+		if resp != nil {
+			stats.status = resp.StatusCode
+		}
+		stats.bytes += len(body)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return body, resp, retry.deadlineError(attempt+1, err)
+		}
+
+		delay, ok := h.retryDelay(retry, http.MethodGet, err, attempt)
+		if !ok {
+			return body, resp, err
+		}
+		if retry.outlasts(start, delay) {
+			return body, resp, retry.deadlineError(attempt+1, err)
+		}
+		h.sleep(delay)
+	}
+}
+
+// pageItems returns the items of a page: its elements if it is an array, or
+// those of its data field. Response transformers such as --unwrap are applied
+// to each page first.
+func pageItems(body []byte, transformers []ResponseTransformer) ([]any, error) {
+	transformed, err := TransformResponse(body, transformers)
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err := json.Unmarshal(transformed, &data); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+	return jsonLineItems(data), nil
+}
+
+// nextPageURL returns the target of the response's Link rel="next" header, if any.
+func nextPageURL(resp *http.Response) string {
+	for _, header := range resp.Header.Values("Link") {
+		for link := range strings.SplitSeq(header, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			for param := range strings.SplitSeq(params, ";") {
+				name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(name, "rel") && strings.EqualFold(strings.Trim(value, `"`), "next") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pagedPetsSpec = `
+openapi: "3.0.0"
+info:
+  title: Paged API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: A page of pets
+`
+
+// newPagedPetsServer serves five pages of two pets linked by Link rel="next"
+// headers, failing with a 500 on failPage.
+func newPagedPetsServer(t *testing.T, failPage int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+		if page == failPage {
+			http.Error(w, `{"message":"backend unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		if page < 5 {
+			w.Header().Set("Link", fmt.Sprintf(`</pets?page=%d>; rel="next", </pets?page=5>; rel="last"`, page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `[{"id":%d},{"id":%d}]`, page*10+1, page*10+2)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteCommand_AllPages(t *testing.T) {
+	server := newPagedPetsServer(t, 0)
+
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("paged", pagedPetsSpec), withErrorOutput(&stderr))
+
+	require.NoError(t, handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--json"}))
+
+	var items []map[string]int
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &items), "stdout: %s", stdout.String())
+	assert.Len(t, items, 10)
+	assert.Equal(t, 52, items[9]["id"])
+}
+
+func TestExecuteCommand_AllPagesStats(t *testing.T) {
+	pages := newPagedPetsServer(t, 0)
+	var failedOnce atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" && !failedOnce.Swap(true) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		pages.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("paged", pagedPetsSpec),
+		withErrorOutput(&stderr), withRetryDelays(new([]time.Duration)))
+
+	require.NoError(t, handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--stats", "--retry", "1", "--retry-on", "503", "--json"}))
+	assert.Regexp(t, `^stats: status=200 duration=\S+ bytes=\d+ pages=5 retries=1\n$`, stderr.String())
+
+	stderr.Reset()
+	require.NoError(t, handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--stats", "--json"}))
+	assert.Regexp(t, `^stats: status=200 duration=\S+ bytes=\d+ pages=5\n$`, stderr.String())
+}
+
+func TestExecuteCommand_AllPagesReusesConnection(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(newPagedPetsServer(t, 0).Config.Handler)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("paged", pagedPetsSpec), withErrorOutput(&stderr))
+
+	for range 3 {
+		require.NoError(t, handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--json"}))
+	}
+	assert.Equal(t, int32(1), conns.Load())
+}
+
+func TestExecuteCommand_AllPagesPartialResults(t *testing.T) {
+	server := newPagedPetsServer(t, 3)
+
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("paged", pagedPetsSpec), withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--output", "yaml"})
+	require.Error(t, err)
+
+	var pageErr *PageError
+	require.ErrorAs(t, err, &pageErr)
+	assert.Equal(t, 3, pageErr.Page)
+	assert.Contains(t, stdout.String(), "id: 11")
+	assert.Contains(t, stdout.String(), "id: 22")
+	assert.NotContains(t, stdout.String(), "id: 31")
+	assert.Contains(t, stderr.String(), "Warning: page 3 failed; printed 4 items from the first 2 pages")
+}
+
+func TestExecuteCommand_AllPagesJSONLinesKeepsStreamedLines(t *testing.T) {
+	server := newPagedPetsServer(t, 3)
+
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("paged", pagedPetsSpec), withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--output", "jsonl"})
+	require.Error(t, err)
+	assert.Equal(t, "{\"id\":11}\n{\"id\":12}\n{\"id\":21}\n{\"id\":22}\n", stdout.String())
+}
+
+func TestExecuteCommand_AllPagesStaysOnOrigin(t *testing.T) {
+	var otherRequests atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		otherRequests.Add(1)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(other.Close)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/pets?page=2>; rel="next"`, other.URL))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":11}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withSpec("paged", pagedPetsSpec), withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("paged", appConfig, []string{"pets", "list", "--all", "--json"})
+	var pageErr *PageError
+	require.ErrorAs(t, err, &pageErr)
+	assert.Equal(t, 2, pageErr.Page)
+	assert.ErrorContains(t, pageErr.Err, "is on another origin")
+	assert.Zero(t, otherRequests.Load())
+}
+
+func TestNextPageURL(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	assert.Empty(t, nextPageURL(resp))
+
+	resp.Header.Set("Link", `<https://api.example.com/pets?page=1>; rel="prev", <https://api.example.com/pets?page=3>; rel=next`)
+	assert.Equal(t, "https://api.example.com/pets?page=3", nextPageURL(resp))
+
+	resp.Header.Set("Link", `<https://api.example.com/pets?page=1>; rel="first"`)
+	resp.Header.Add("Link", `<https://api.example.com/pets?cursor=abc>; rel="next", <https://api.example.com/pets?page=9>; rel="last"`)
+	assert.Equal(t, "https://api.example.com/pets?cursor=abc", nextPageURL(resp))
+
+	resp.Header.Set("Link", `<https://api.example.com/pets?page=1>; rel="prev"`)
+	assert.Empty(t, nextPageURL(resp))
+}
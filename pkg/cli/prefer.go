@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+// preferValue returns the Prefer header value for write operations: the
+// --prefer flag, or else the profile's prefer setting.
+func preferValue(params map[string]any, profile *config.Profile) string {
+	if value, ok := params["prefer"].(string); ok && value != "" {
+		return value
+	}
+	if profile != nil {
+		return profile.Prefer
+	}
+	return ""
+}
+
+// prefersMinimalReturn reports whether a Prefer header value asks the server
+// to omit the response representation.
+func prefersMinimalReturn(prefer string) bool {
+	for preference := range strings.SplitSeq(prefer, ",") {
+		if strings.EqualFold(strings.TrimSpace(preference), "return=minimal") {
+			return true
+		}
+	}
+	return false
+}
+
+// printMinimalSuccess prints a one-line success message for an empty 204
+// response to a request sent with Prefer: return=minimal, reporting whether it
+// did. JSON output keeps printing the (empty) body so scripts see no prose.
+func (h *Handler) printMinimalSuccess(op *semantic.Operation, status int, body []byte, params map[string]any, profile *config.Profile) bool {
+	if status != http.StatusNoContent || len(bytes.TrimSpace(body)) > 0 {
+		return false
+	}
+	if !prefersMinimalReturn(preferValue(params, profile)) {
+		return false
+	}
+	switch determineOutputFormat(params) {
+	case "json", "jsonl":
+		return false
+	}
+	_, _ = fmt.Fprintf(h.stdout, "%s %s succeeded (204 No Content)\n", op.Method, op.Path)
+	return true
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_PreferMinimal(t *testing.T) {
+	var gotPrefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrefer = r.Header.Get("Prefer")
+		if prefersMinimalReturn(gotPrefer) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--prefer", "return=minimal"})
+	require.NoError(t, err)
+	assert.Equal(t, "return=minimal", gotPrefer)
+	assert.Equal(t, "POST /pet succeeded (204 No Content)\n", out.String())
+
+	out.Reset()
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--prefer", "return=representation"})
+	require.NoError(t, err)
+	assert.Equal(t, "return=representation", gotPrefer)
+	assert.Contains(t, out.String(), "doggie")
+}
+
+func TestExecuteCommand_PreferProfileDefault(t *testing.T) {
+	var gotPrefer []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrefer = append(gotPrefer, r.Header.Get("Prefer"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	profile := appConfig.Profiles["default"]
+	profile.Prefer = "return=minimal"
+	appConfig.Profiles["default"] = profile
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie"}))
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1"}))
+
+	assert.Equal(t, []string{"return=minimal", ""}, gotPrefer, "Prefer is only sent on write operations")
+	assert.Contains(t, out.String(), "succeeded (204 No Content)")
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// problemContentType is the media type of RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 problem details error body.
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// parseProblemDetails decodes the body of a response served as
+// application/problem+json. It reports false for other responses and for
+// bodies that are not a problem details object.
+func parseProblemDetails(resp *http.Response, body []byte) (*ProblemDetails, bool) {
+	if resp == nil {
+		return nil, false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != problemContentType {
+		return nil, false
+	}
+
+	var problem ProblemDetails
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return nil, false
+	}
+	if problem.Title == "" && problem.Detail == "" && problem.Type == "" {
+		return nil, false
+	}
+	return &problem, true
+}
+
+// writeProblemDetails writes the fields of a problem details body to the
+// error message, in place of the raw response.
+func writeProblemDetails(sb *strings.Builder, problem *ProblemDetails) {
+	sb.WriteString("\nProblem:\n")
+	if problem.Title != "" {
+		fmt.Fprintf(sb, "  Title:    %s\n", problem.Title)
+	}
+	if problem.Detail != "" {
+		fmt.Fprintf(sb, "  Detail:   %s\n", problem.Detail)
+	}
+	if problem.Status != 0 {
+		fmt.Fprintf(sb, "  Status:   %d\n", problem.Status)
+	}
+	if problem.Type != "" && problem.Type != "about:blank" {
+		fmt.Fprintf(sb, "  Type:     %s\n", problem.Type)
+	}
+	if problem.Instance != "" {
+		fmt.Fprintf(sb, "  Instance: %s\n", problem.Instance)
+	}
+}
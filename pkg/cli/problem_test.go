@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const problemBody = `{"type":"https://example.com/probs/invalid-id","title":"Invalid pet ID","status":400,"detail":"Pet ID must be a positive integer","instance":"/pet/0","invalid":["petId"]}`
+
+// newProblemServer returns a server that answers every request with a 400
+// problem details body.
+func newProblemServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(problemBody))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteCommand_ProblemDetails(t *testing.T) {
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, newProblemServer(t).URL, &out, withErrorOutput(&errOut))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "0"})
+	require.Error(t, err)
+	assert.True(t, IsPrintedError(err))
+	assert.Contains(t, errOut.String(), "Problem:\n")
+	assert.Contains(t, errOut.String(), "  Title:    Invalid pet ID\n")
+	assert.Contains(t, errOut.String(), "  Detail:   Pet ID must be a positive integer\n")
+	assert.Contains(t, errOut.String(), "  Status:   400\n")
+	assert.Contains(t, errOut.String(), "  Type:     https://example.com/probs/invalid-id\n")
+	assert.Contains(t, errOut.String(), "  Instance: /pet/0\n")
+	assert.NotContains(t, errOut.String(), "Response:")
+}
+
+func TestExecuteCommand_ProblemDetailsJSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, newProblemServer(t).URL, &out, withErrorOutput(&errOut))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "0", "--output", "json"})
+	require.Error(t, err)
+
+	var envelope struct {
+		Error struct {
+			Type   string          `json:"type"`
+			Status int             `json:"status"`
+			Body   json.RawMessage `json:"body"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &envelope), "stdout: %s", out.String())
+	assert.Equal(t, ErrorTypeHTTP, envelope.Error.Type)
+	assert.Equal(t, http.StatusBadRequest, envelope.Error.Status)
+	assert.JSONEq(t, problemBody, string(envelope.Error.Body))
+}
+
+func TestParseProblemDetails(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Content-Type": []string{"application/json"}}}
+	_, ok := parseProblemDetails(resp, []byte(problemBody))
+	assert.False(t, ok, "plain JSON is not a problem")
+
+	resp.Header.Set("Content-Type", problemContentType)
+	_, ok = parseProblemDetails(resp, []byte(`not json`))
+	assert.False(t, ok)
+
+	problem, ok := parseProblemDetails(resp, []byte(problemBody))
+	require.True(t, ok)
+	assert.Equal(t, "Invalid pet ID", problem.Title)
+	assert.Equal(t, 400, problem.Status)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/request"
+)
+
+// rawQueryBuildOptions returns a request builder option for each --query
+// name=value flag, which adds name=value to the query string as given,
+// without validation, e.g. for a parameter the spec does not declare. The flag
+// may be repeated, so it is read from the raw CLI arguments, and it is removed
+// from params so it is not sent as a parameter. An operation that declares a
+// parameter or body field named query keeps --query for it.
+func rawQueryBuildOptions(params map[string]any, args []string, opSpec *openapi3.Operation) ([]request.BuildOption, error) {
+	if declaresQueryField(opSpec) {
+		return nil, nil
+	}
+	delete(params, "query")
+
+	pairs, err := nameValueFlags(args, "query")
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]request.BuildOption, 0, len(pairs))
+	for _, pair := range pairs {
+		opts = append(opts, request.WithQueryParam(pair[0], pair[1]))
+	}
+	return opts, nil
+}
+
+// declaresQueryField reports whether an operation has a parameter or body
+// field named query.
+func declaresQueryField(opSpec *openapi3.Operation) bool {
+	if opSpec == nil {
+		return false
+	}
+	for _, paramRef := range opSpec.Parameters {
+		if paramRef != nil && paramRef.Value != nil && paramRef.Value.Name == "query" {
+			return true
+		}
+	}
+	return getBodyParamSchema(opSpec, "query") != nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rawQuerySpec = `openapi: "3.0.0"
+info:
+  title: Pets
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+  /search:
+    get:
+      operationId: searchSearch
+      tags: [search]
+      parameters:
+        - name: query
+          in: query
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`
+
+func TestExecuteCommand_RawQuery(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withSpec("pets", rawQuerySpec))
+
+	err := handler.ExecuteCommand("pets", appConfig, []string{"pets", "list", "--limit", "10",
+		"--query", "debug=true", "--query=tag=a b", "--query", "tag=c", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, "debug=true&limit=10&tag=a+b&tag=c", gotQuery)
+
+	// A raw value replaces the one built from the declared parameter, unvalidated.
+	err = handler.ExecuteCommand("pets", appConfig, []string{"pets", "list", "--limit", "10", "--query", "limit=many", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, "limit=many", gotQuery)
+
+	err = handler.ExecuteCommand("pets", appConfig, []string{"pets", "list", "--query", "debug"})
+	assert.EqualError(t, err, `invalid --query "debug": expected name=value`)
+
+	// An operation with its own query parameter keeps --query for it.
+	err = handler.ExecuteCommand("pets", appConfig, []string{"search", "search", "--query", "name=rex", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, "query=name%3Drex", gotQuery)
+}
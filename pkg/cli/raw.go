@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"errors"
+)
+
+// rawOutputFormat is the --output value that writes the response body to
+// stdout exactly as the server sent it, for piping to other tools.
+const rawOutputFormat = "raw"
+
+// printRawOutput writes the response body, or the body of an HTTP error
+// response, byte for byte with no decoding, pretty-printing or trailing
+// newline. HTTP errors are still returned so that the exit status reflects
+// the response status. It reports whether a body was written; other errors,
+// including a spent request deadline, are left to the caller to report.
+func (h *Handler) printRawOutput(body []byte, err error) (bool, error) {
+	var httpErr *HTTPError
+	if !errors.Is(err, ErrRequestDeadline) && errors.As(err, &httpErr) {
+		_, _ = h.stdout.Write(httpErr.Body)
+		return true, &PrintedError{Err: err}
+	}
+	if err != nil {
+		return false, nil
+	}
+	_, _ = h.stdout.Write(body)
+	return true, nil
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_RawOutput(t *testing.T) {
+	const body = "{\"id\":1,  \"name\":\"doggie\"}\r\n\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/pet/404" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":  "not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--output", "raw"}))
+	assert.Equal(t, body, out.String())
+
+	out.Reset()
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "404", "--output", "raw"})
+	require.Error(t, err)
+	assert.True(t, IsPrintedError(err))
+	assert.Equal(t, `{"message":  "not found"}`, out.String())
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "-o", "raw"}))
+	assert.Equal(t, body, out.String())
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// redirectResponse is what a redirect that is not followed prints: its status
+// and where it points.
+type redirectResponse struct {
+	Status   int    `json:"status"`
+	Location string `json:"location"`
+}
+
+// applyRedirectPolicy overrides the profile's redirect settings with
+// --follow-redirects and --max-redirects for this request. A redirect limit
+// implies following them.
+func applyRedirectPolicy(params map[string]any, profile *config.Profile) error {
+	if value, ok := params["follow-redirects"]; ok {
+		follow, err := strconv.ParseBool(fmt.Sprint(value))
+		if err != nil {
+			return fmt.Errorf("invalid --follow-redirects value %q: must be true or false", fmt.Sprint(value))
+		}
+		profile.FollowRedirects = &follow
+	}
+
+	if value, ok := params["max-redirects"]; ok {
+		limit, err := strconv.Atoi(fmt.Sprint(value))
+		if err != nil || limit <= 0 {
+			return fmt.Errorf("invalid --max-redirects value %q: must be a positive number", fmt.Sprint(value))
+		}
+		if !profile.FollowsRedirects() {
+			return fmt.Errorf("--max-redirects cannot be used when redirects are not followed")
+		}
+		profile.MaxRedirects = limit
+	}
+	return nil
+}
+
+// printRedirect prints the status and Location of a redirect response that
+// was not followed, in the requested output format. It reports whether resp
+// was such a redirect.
+func (h *Handler) printRedirect(resp *http.Response, params map[string]any, profile *config.Profile) (bool, error) {
+	if profile.FollowsRedirects() || resp == nil || resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return false, nil
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return false, nil
+	}
+
+	data, err := json.Marshal(redirectResponse{Status: resp.StatusCode, Location: location})
+	if err != nil {
+		return true, fmt.Errorf("failed to encode redirect: %w", err)
+	}
+	return true, h.formatAndPrintOutput(data, params)
+}
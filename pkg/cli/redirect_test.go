@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_Redirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pet/1" {
+			http.Redirect(w, r, "/pet/2", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":2,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--json"}))
+	assert.Contains(t, out.String(), "doggie")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--follow-redirects=false", "--json"}))
+	var redirect redirectResponse
+	require.NoError(t, json.Unmarshal(out.Bytes(), &redirect), "stdout: %s", out.String())
+	assert.Equal(t, redirectResponse{Status: http.StatusMovedPermanently, Location: "/pet/2"}, redirect)
+
+	// The profile setting applies unless the flag overrides it.
+	follow := false
+	profile := appConfig.Profiles["default"]
+	profile.FollowRedirects = &follow
+	appConfig.Profiles["default"] = profile
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--output", "yaml"}))
+	assert.Contains(t, out.String(), "status: 301")
+	assert.Contains(t, out.String(), "location: /pet/2")
+
+	out.Reset()
+	require.NoError(t, handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--follow-redirects", "--json"}))
+	assert.Contains(t, out.String(), "doggie")
+}
+
+func TestApplyRedirectPolicy(t *testing.T) {
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, "http://127.0.0.1:0", &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--follow-redirects", "sometimes"})
+	assert.EqualError(t, err, `invalid --follow-redirects value "sometimes": must be true or false`)
+
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--max-redirects", "0"})
+	assert.EqualError(t, err, `invalid --max-redirects value "0": must be a positive number`)
+
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--follow-redirects=false", "--max-redirects", "2"})
+	assert.EqualError(t, err, "--max-redirects cannot be used when redirects are not followed")
+}
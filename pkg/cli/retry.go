@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+const (
+	// defaultRetryCount is used when --retry-on is given without a retry count.
+	defaultRetryCount = 3
+	// defaultRetryDelay is the delay before the first retry when the profile sets none.
+	defaultRetryDelay = 500 * time.Millisecond
+	// defaultMaxRetryDelay caps the backoff between retries when the profile sets none.
+	defaultMaxRetryDelay = 10 * time.Second
+)
+
+// defaultRetryStatusCodes are retried when neither the profile nor --retry-on names any.
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ErrRequestDeadline is returned when a request and its retries run past the
+// profile's request deadline or --deadline.
+var ErrRequestDeadline = errors.New("request deadline exceeded")
+
+// retryPolicy controls how a request that failed with an HTTP status or a
+// network error is retried.
+type retryPolicy struct {
+	maxRetries   int
+	statusCodes  []int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	allowUnsafe  bool
+	// deadline caps the time spent on the request and all its retries; zero means no cap.
+	deadline time.Duration
+}
+
+// resolveRetryPolicy combines the profile retry configuration and request deadline
+// with the per-invocation --retry, --retry-on, --retry-unsafe, and --deadline flags,
+// which take precedence. --timeout-retry-budget is an alias of --deadline, and
+// --no-retry disables retries for the invocation.
+func resolveRetryPolicy(profile *config.Profile, params map[string]any) (retryPolicy, error) {
+	retryConfig := profile.RetryConfig
+	policy := retryPolicy{
+		maxRetries:   retryConfig.MaxRetries,
+		statusCodes:  retryConfig.RetryableStatusCodes,
+		initialDelay: retryConfig.InitialDelay.Duration,
+		maxDelay:     retryConfig.MaxDelay.Duration,
+		allowUnsafe:  retryConfig.RetryUnsafe,
+		deadline:     profile.RequestDeadline.Duration,
+	}
+
+	for _, name := range []string{"timeout-retry-budget", "deadline"} {
+		value, ok := params[name]
+		if !ok {
+			continue
+		}
+		deadline, err := time.ParseDuration(fmt.Sprint(value))
+		if err != nil || deadline <= 0 {
+			return policy, fmt.Errorf("invalid --%s value %q: must be a positive duration such as 30s", name, fmt.Sprint(value))
+		}
+		policy.deadline = deadline
+	}
+
+	retrySet := false
+	if value, ok := params["retry"]; ok {
+		count, err := strconv.Atoi(fmt.Sprint(value))
+		if err != nil || count < 0 {
+			return policy, fmt.Errorf("invalid --retry value %q: must be a non-negative integer", fmt.Sprint(value))
+		}
+		policy.maxRetries = count
+		retrySet = true
+	}
+
+	if value, ok := params["retry-on"]; ok {
+		codes, err := parseRetryStatusCodes(fmt.Sprint(value))
+		if err != nil {
+			return policy, err
+		}
+		policy.statusCodes = codes
+		if !retrySet && policy.maxRetries == 0 {
+			policy.maxRetries = defaultRetryCount
+		}
+	}
+
+	if value, ok := params["retry-unsafe"]; ok {
+		unsafe, err := strconv.ParseBool(fmt.Sprint(value))
+		if err != nil {
+			return policy, fmt.Errorf("invalid --retry-unsafe value %q", fmt.Sprint(value))
+		}
+		policy.allowUnsafe = unsafe
+	}
+
+	if flagEnabled(params, "no-retry") {
+		policy.maxRetries = 0
+	}
+
+	if len(policy.statusCodes) == 0 {
+		policy.statusCodes = defaultRetryStatusCodes
+	}
+	if policy.initialDelay <= 0 {
+		policy.initialDelay = defaultRetryDelay
+	}
+	if policy.maxDelay <= 0 {
+		policy.maxDelay = defaultMaxRetryDelay
+	}
+	return policy, nil
+}
+
+// parseRetryStatusCodes parses a comma-separated --retry-on list such as "429,503".
+func parseRetryStatusCodes(value string) ([]int, error) {
+	var codes []int
+	for part := range strings.SplitSeq(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid --retry-on status code %q", strings.TrimSpace(part))
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// shouldRetry reports whether a response with the given status may be retried
+// after attempt retries. Non-idempotent methods are only retried with --retry-unsafe
+// or the profile's retry.retry_unsafe setting.
+func (p retryPolicy) shouldRetry(method string, statusCode, attempt int) bool {
+	if attempt >= p.maxRetries || !slices.Contains(p.statusCodes, statusCode) {
+		return false
+	}
+	return p.allowUnsafe || isIdempotentMethod(method)
+}
+
+// delay returns the backoff before the given retry, doubling up to the maximum delay.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	delay := p.initialDelay
+	for range attempt {
+		delay *= 2
+		if delay >= p.maxDelay {
+			return p.maxDelay
+		}
+	}
+	return min(delay, p.maxDelay)
+}
+
+// shouldRetryNetworkError reports whether a request that received no response
+// may be retried after attempt retries.
+func (p retryPolicy) shouldRetryNetworkError(method string, attempt int) bool {
+	return attempt < p.maxRetries && (p.allowUnsafe || isIdempotentMethod(method))
+}
+
+// isTransientNetworkError reports whether err is a timeout or a refused or
+// reset connection, which a retry may get past. TLS failures, unsupported
+// schemes and redirect loops fail the same way every time and are not.
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// retryDelay returns how long to wait before retrying a request that failed
+// with err after attempt retries, and whether to retry it at all. A Retry-After
+// header on the response sets the wait, capped at the maximum delay; otherwise
+// the backoff is jittered so that clients do not retry in lockstep.
+func (h *Handler) retryDelay(retry retryPolicy, method string, err error, attempt int) (time.Duration, bool) {
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		if !retry.shouldRetry(method, httpErr.Response.StatusCode, attempt) {
+			return 0, false
+		}
+		if wait, ok := retryAfter(httpErr.Response.Header.Get("Retry-After"), time.Now()); ok {
+			return min(wait, retry.maxDelay), true
+		}
+	case isTransientNetworkError(err):
+		if !retry.shouldRetryNetworkError(method, attempt) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	return h.jitter(retry.delay(attempt)), true
+}
+
+// retryAfter parses a Retry-After header value, given in seconds or as an HTTP
+// date, into the wait from now.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
+
+// retryJitter returns a random delay between half of d and d.
+func retryJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// withDeadline returns a context cancelled once the deadline is spent, or ctx
+// itself when there is none.
+func (p retryPolicy) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.deadline <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.deadline)
+}
+
+// outlasts reports whether waiting delay before a retry of a request started
+// at start would run past the deadline.
+func (p retryPolicy) outlasts(start time.Time, delay time.Duration) bool {
+	return p.deadline > 0 && time.Since(start)+delay >= p.deadline
+}
+
+// deadlineError reports that the deadline was spent after attempts requests,
+// the last of which failed with err.
+func (p retryPolicy) deadlineError(attempts int, err error) error {
+	return fmt.Errorf("%w: gave up after %d attempt(s) within %s, last error: %w", ErrRequestDeadline, attempts, p.deadline, err)
+}
+
+// isIdempotentMethod reports whether repeating a request with this method is safe.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
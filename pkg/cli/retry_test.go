@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_RetryOnListedStatus(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	var delays []time.Duration
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(&delays))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "2", "--retry-on", "429,503", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, []time.Duration{defaultRetryDelay, 2 * defaultRetryDelay}, delays)
+}
+
+func TestExecuteCommand_RetryIgnoresUnlistedStatus(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, http.StatusInternalServerError)
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "3", "--retry-on", "503", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestExecuteCommand_RetryStopsAfterCount(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, 503, 503, 503, 503, 503)
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "2", "--retry-on", "503", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestExecuteCommand_RetryUnsafeMethods(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, http.StatusServiceUnavailable)
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--retry", "2", "--retry-on", "503", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "POST must not be retried without --retry-unsafe")
+
+	server, requests = newStatusSequenceServer(t, http.StatusServiceUnavailable)
+	handler, appConfig = newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--retry", "2", "--retry-on", "503", "--retry-unsafe", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	server, requests = newStatusSequenceServer(t, http.StatusServiceUnavailable)
+	handler, appConfig = newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+	profile := appConfig.Profiles["default"]
+	profile.RetryConfig = config.RetryConfig{MaxRetries: 2, RetryableStatusCodes: []int{503}, RetryUnsafe: true}
+	appConfig.Profiles["default"] = profile
+
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "the profile retry policy opts in to retrying POST")
+}
+
+func TestExecuteCommand_RetryFlagsOverrideProfile(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, 503, 503, 503)
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+	profile := appConfig.Profiles["default"]
+	profile.RetryConfig = config.RetryConfig{MaxRetries: 5, RetryableStatusCodes: []int{503}}
+	appConfig.Profiles["default"] = profile
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "1", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestResolveRetryPolicy(t *testing.T) {
+	profile := &config.Profile{}
+
+	policy, err := resolveRetryPolicy(profile, map[string]any{"retry-on": "429, 503"})
+	require.NoError(t, err)
+	assert.Equal(t, defaultRetryCount, policy.maxRetries)
+	assert.Equal(t, []int{429, 503}, policy.statusCodes)
+
+	policy, err = resolveRetryPolicy(profile, map[string]any{})
+	require.NoError(t, err)
+	assert.Zero(t, policy.maxRetries)
+
+	_, err = resolveRetryPolicy(profile, map[string]any{"retry": "-1"})
+	assert.Error(t, err)
+
+	_, err = resolveRetryPolicy(profile, map[string]any{"retry-on": "429,abc"})
+	assert.Error(t, err)
+
+	policy, err = resolveRetryPolicy(&config.Profile{RequestDeadline: config.Duration{Duration: time.Minute}}, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, policy.deadline)
+
+	policy, err = resolveRetryPolicy(profile, map[string]any{"deadline": "30s"})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, policy.deadline)
+
+	_, err = resolveRetryPolicy(profile, map[string]any{"deadline": "0s"})
+	assert.EqualError(t, err, `invalid --deadline value "0s": must be a positive duration such as 30s`)
+
+	policy, err = resolveRetryPolicy(&config.Profile{RetryConfig: config.RetryConfig{MaxRetries: 5}}, map[string]any{"no-retry": "true"})
+	require.NoError(t, err)
+	assert.Zero(t, policy.maxRetries)
+
+	unsafeProfile := &config.Profile{RetryConfig: config.RetryConfig{RetryUnsafe: true}}
+	policy, err = resolveRetryPolicy(unsafeProfile, map[string]any{})
+	require.NoError(t, err)
+	assert.True(t, policy.allowUnsafe, "the profile opts in to retrying POST")
+
+	policy, err = resolveRetryPolicy(unsafeProfile, map[string]any{"retry-unsafe": "false"})
+	require.NoError(t, err)
+	assert.False(t, policy.allowUnsafe, "--retry-unsafe=false overrides the profile")
+}
+
+func TestExecuteCommand_NoRetry(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, http.StatusServiceUnavailable)
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(new([]time.Duration)))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "2", "--no-retry", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestExecuteCommand_RetryHonorsRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch requests.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	t.Cleanup(server.Close)
+	var delays []time.Duration
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(&delays))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "2", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, []time.Duration{3 * time.Second, defaultMaxRetryDelay}, delays, "Retry-After is capped at the maximum delay")
+}
+
+func TestExecuteCommand_RetryNetworkError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				// Closing with no linger resets the connection.
+				_ = conn.(*net.TCPConn).SetLinger(0)
+				_ = conn.Close()
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	t.Cleanup(server.Close)
+	var delays []time.Duration
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(&delays))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "1", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, []time.Duration{defaultRetryDelay}, delays)
+
+	requests.Store(0)
+	err = handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--retry", "1", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "POST is not retried without --retry-unsafe")
+}
+
+func TestExecuteCommand_RetryConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL := server.URL
+	server.Close()
+	var delays []time.Duration
+	handler, appConfig := newPetstoreHandler(t, baseURL, &bytes.Buffer{}, withRetryDelays(&delays))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "2", "--json"})
+	require.Error(t, err)
+	assert.Equal(t, []time.Duration{defaultRetryDelay, 2 * defaultRetryDelay}, delays)
+}
+
+func TestExecuteCommand_RetrySkipsPermanentNetworkErrors(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+
+	loopServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.String(), http.StatusFound)
+	}))
+	t.Cleanup(loopServer.Close)
+
+	tests := []struct {
+		name    string
+		baseURL string
+	}{
+		{"untrusted certificate", tlsServer.URL},
+		{"unsupported scheme", "ftp://example.com"},
+		{"redirect loop", loopServer.URL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			handler, appConfig := newPetstoreHandler(t, tt.baseURL, &bytes.Buffer{}, withRetryDelays(&delays))
+
+			err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "2", "--json"})
+			require.Error(t, err)
+			assert.Empty(t, delays)
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	wait, ok := retryAfter("7", now)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	wait, ok = retryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, wait)
+
+	wait, ok = retryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Zero(t, wait, "a date in the past means no wait")
+
+	_, ok = retryAfter("-3", now)
+	assert.False(t, ok)
+	_, ok = retryAfter("soon", now)
+	assert.False(t, ok)
+	_, ok = retryAfter("", now)
+	assert.False(t, ok)
+}
+
+func TestRetryJitter(t *testing.T) {
+	for range 100 {
+		delay := retryJitter(time.Second)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, time.Second)
+	}
+}
+
+func TestExecuteCommand_DeadlineCutsOffRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	var delays []time.Duration
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out, withRetryDelays(&delays))
+	profile := appConfig.Profiles["default"]
+	profile.RetryConfig = config.RetryConfig{
+		MaxRetries:           100,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		InitialDelay:         config.Duration{Duration: 2 * time.Second},
+		MaxDelay:             config.Duration{Duration: time.Minute},
+	}
+	profile.RequestDeadline = config.Duration{Duration: 5 * time.Second}
+	appConfig.Profiles["default"] = profile
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--output", "json"})
+	require.ErrorIs(t, err, ErrRequestDeadline)
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr, "the deadline error wraps the last attempt's error")
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.Response.StatusCode)
+	assert.Equal(t, int32(3), requests.Load(), "the third backoff of 8s would end past the deadline")
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second}, delays)
+
+	var envelope ErrorEnvelope
+	require.NoError(t, json.Unmarshal(out.Bytes(), &envelope), "stdout: %s", out.String())
+	assert.Equal(t, ErrorTypeRequest, envelope.Error.Type)
+	assert.Contains(t, envelope.Error.Message, "request deadline exceeded")
+	assert.Contains(t, envelope.Error.Message, "HTTP 503")
+}
+
+func TestExecuteCommand_DeadlineSkipsRetryPastIt(t *testing.T) {
+	server, requests := newStatusSequenceServer(t, 503, 503, 503)
+	var delays []time.Duration
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withRetryDelays(&delays))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "3", "--retry-on", "503", "--deadline", "1500ms", "--json"})
+	require.ErrorIs(t, err, ErrRequestDeadline)
+	assert.Equal(t, int32(3), requests.Load(), "the third backoff of 2s would end past the deadline")
+	assert.Equal(t, []time.Duration{defaultRetryDelay, 2 * defaultRetryDelay}, delays)
+}
+
+func TestExecuteCommand_DeadlineCancelsSlowRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--timeout-retry-budget", "100ms", "--json"})
+	require.ErrorIs(t, err, ErrRequestDeadline)
+}
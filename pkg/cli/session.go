@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSessionTTL is how long captured values remain available after the last capture.
+const DefaultSessionTTL = time.Hour
+
+// sessionPlaceholder matches {{name}} references to captured values.
+var sessionPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// SessionStore persists values captured from responses so later commands can reference them.
+// Values expire once the session has been idle for longer than the TTL.
+type SessionStore struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// sessionFile is the on-disk format of a session.
+type sessionFile struct {
+	UpdatedAt time.Time         `json:"updated_at"`
+	Values    map[string]string `json:"values"`
+}
+
+// NewSessionStore creates a session store backed by the file at path.
+func NewSessionStore(path string, ttl time.Duration) *SessionStore {
+	return &SessionStore{path: path, ttl: ttl, now: time.Now}
+}
+
+// DefaultSessionPath returns the session file for the current shell in the
+// user's cache directory dir. OPENBRIDGE_SESSION_FILE overrides the location;
+// otherwise the file is keyed by the parent process so separate terminals do
+// not share captured values.
+func DefaultSessionPath(dir string) string {
+	if path := os.Getenv("OPENBRIDGE_SESSION_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(dir, fmt.Sprintf("session-%d.json", os.Getppid()))
+}
+
+// Load returns the captured values, or an empty map if the session is missing or expired.
+func (s *SessionStore) Load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var file sessionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	if file.Values == nil || s.now().Sub(file.UpdatedAt) > s.ttl {
+		return map[string]string{}, nil
+	}
+	return file.Values, nil
+}
+
+// Save merges values into the session and refreshes its expiry.
+func (s *SessionStore) Save(values map[string]string) error {
+	existing, err := s.Load()
+	if err != nil {
+		existing = map[string]string{}
+	}
+	for name, value := range values {
+		existing[name] = value
+	}
+
+	data, err := json.Marshal(sessionFile{UpdatedAt: s.now(), Values: existing})
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// SetSessionStore sets the store used by --capture and {{name}} references.
+func (h *Handler) SetSessionStore(store *SessionStore) {
+	h.session = store
+}
+
+// ParseCaptureSpec parses a --capture value of the form "name=.path[,name=.path]".
+// The path is a dotted field path into the JSON response; the leading dot is optional.
+func ParseCaptureSpec(spec string) (map[string]string, error) {
+	captures := make(map[string]string)
+	for entry := range strings.SplitSeq(spec, ",") {
+		name, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		name = strings.TrimSpace(name)
+		path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid --capture %q: expected name=.field", entry)
+		}
+		captures[name] = path
+	}
+	return captures, nil
+}
+
+// CaptureValues extracts the requested fields from a JSON response body.
+// Strings are captured verbatim; other values are captured as their JSON encoding.
+func CaptureValues(body []byte, captures map[string]string) (map[string]string, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to capture values: response is not JSON: %w", err)
+	}
+
+	values := make(map[string]string, len(captures))
+	for name, path := range captures {
+		value, err := UnwrapTransformer(path).Transform(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture %s: %w", name, err)
+		}
+		values[name] = captureString(value)
+	}
+	return values, nil
+}
+
+// captureString converts a decoded JSON value to its captured string form.
+func captureString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// SubstituteCapturedValues replaces {{name}} references in string parameter values,
+// including nested body fields, with values captured earlier in the session.
+func SubstituteCapturedValues(params map[string]any, values map[string]string) error {
+	for key, value := range params {
+		substituted, err := substituteValue(value, values)
+		if err != nil {
+			return err
+		}
+		params[key] = substituted
+	}
+	return nil
+}
+
+// substituteValue applies placeholder substitution to a single parameter value.
+func substituteValue(value any, values map[string]string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		var missing string
+		result := sessionPlaceholder.ReplaceAllStringFunc(v, func(match string) string {
+			name := sessionPlaceholder.FindStringSubmatch(match)[1]
+			captured, ok := values[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return captured
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("no captured value for {{%s}}; capture it first with --capture %s=.field", missing, missing)
+		}
+		return result, nil
+	case map[string]any:
+		if err := SubstituteCapturedValues(v, values); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			substituted, err := substituteValue(item, values)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = substituted
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// hasPlaceholders reports whether any string parameter value references a captured value.
+func hasPlaceholders(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return sessionPlaceholder.MatchString(v)
+	case map[string]any:
+		for _, item := range v {
+			if hasPlaceholders(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if hasPlaceholders(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveSessionReferences substitutes {{name}} references using the session store.
+// The session file is only read when a reference is present. Output and body
+// templates, which share the {{...}} syntax, are left for their own rendering.
+func (h *Handler) resolveSessionReferences(params map[string]any) error {
+	for _, flag := range []string{"output", "format-template", "body-template"} {
+		if value, ok := params[flag]; ok {
+			delete(params, flag)
+			defer func() { params[flag] = value }()
+		}
+	}
+
+	if !hasPlaceholders(params) {
+		return nil
+	}
+	if h.session == nil {
+		return errors.New("captured values are not available: no session store configured")
+	}
+
+	values, err := h.session.Load()
+	if err != nil {
+		return err
+	}
+	return SubstituteCapturedValues(params, values)
+}
+
+// captureResponseValues stores the fields requested via --capture in the session.
+func (h *Handler) captureResponseValues(body []byte, params map[string]any) error {
+	spec, ok := params["capture"].(string)
+	if !ok || spec == "" {
+		return nil
+	}
+	if h.session == nil {
+		return errors.New("cannot capture values: no session store configured")
+	}
+
+	captures, err := ParseCaptureSpec(spec)
+	if err != nil {
+		return err
+	}
+	values, err := CaptureValues(body, captures)
+	if err != nil {
+		return err
+	}
+	return h.session.Save(values)
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_CaptureChainsIntoNextCall(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gotPath = r.URL.Path
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	store := NewSessionStore(filepath.Join(t.TempDir(), "session.json"), DefaultSessionTTL)
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+	handler.SetSessionStore(store)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "create", "--name", "doggie", "--capture", "id=.id", "--json"})
+	require.NoError(t, err)
+
+	values, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "42"}, values)
+
+	// A separate handler stands in for a second ob run invocation.
+	next, appConfig := newPetstoreHandler(t, server.URL, &out)
+	next.SetSessionStore(store)
+
+	err = next.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "{{id}}", "--json"})
+	require.NoError(t, err)
+	assert.Equal(t, "/pet/42", gotPath)
+}
+
+func TestSessionStore_Expires(t *testing.T) {
+	store := NewSessionStore(filepath.Join(t.TempDir(), "session.json"), time.Minute)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(map[string]string{"id": "1"}))
+
+	now = now.Add(2 * time.Minute)
+	values, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestDefaultSessionPath(t *testing.T) {
+	t.Setenv("OPENBRIDGE_SESSION_FILE", "")
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	path := DefaultSessionPath(dir)
+	assert.Equal(t, dir, filepath.Dir(path))
+
+	store := NewSessionStore(path, time.Hour)
+	require.NoError(t, store.Save(map[string]string{"id": "42"}))
+	values, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "42", values["id"])
+}
+
+func TestSubstituteCapturedValues(t *testing.T) {
+	params := map[string]any{
+		"petId": "{{id}}",
+		"body":  map[string]any{"owner": "user-{{ owner }}", "tags": []any{"{{id}}"}},
+		"limit": 10,
+	}
+
+	err := SubstituteCapturedValues(params, map[string]string{"id": "42", "owner": "7"})
+	require.NoError(t, err)
+	assert.Equal(t, "42", params["petId"])
+	assert.Equal(t, map[string]any{"owner": "user-7", "tags": []any{"42"}}, params["body"])
+	assert.Equal(t, 10, params["limit"])
+
+	err = SubstituteCapturedValues(map[string]any{"petId": "{{missing}}"}, map[string]string{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no captured value for {{missing}}")
+}
+
+func TestParseCaptureSpec(t *testing.T) {
+	captures, err := ParseCaptureSpec("id=.id, owner=.owner.id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "id", "owner": "owner.id"}, captures)
+
+	_, err = ParseCaptureSpec("id")
+	assert.Error(t, err)
+}
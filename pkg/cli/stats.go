@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// requestStats summarizes an API call for --stats. A call following --all
+// pagination counts its pages, and the retries of all pages, instead of the
+// attempts of a single request.
+type requestStats struct {
+	status   int
+	duration time.Duration
+	bytes    int
+	attempts int
+	pages    int
+	retries  int
+}
+
+// String formats the stats as a single line, e.g.
+// "stats: status=200 duration=12ms bytes=345". The attempt count is included
+// only when the request was retried; with --all, the page count always is and
+// the retry count when a page was retried.
+func (s *requestStats) String() string {
+	line := fmt.Sprintf("stats: status=%d duration=%s bytes=%d", s.status, s.duration.Round(time.Millisecond), s.bytes)
+	if s.attempts > 1 {
+		line += fmt.Sprintf(" attempts=%d", s.attempts)
+	}
+	if s.pages > 0 {
+		line += fmt.Sprintf(" pages=%d", s.pages)
+	}
+	if s.retries > 0 {
+		line += fmt.Sprintf(" retries=%d", s.retries)
+	}
+	return line
+}
+
+// printStats writes the stats line to stderr when --stats was given and a response
+// was received, keeping stdout clean for piping.
+func (h *Handler) printStats(stats *requestStats, params map[string]any) {
+	if !flagEnabled(params, "stats") || stats.status == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(h.stderr, stats.String())
+}
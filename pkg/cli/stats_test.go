@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommand_Stats(t *testing.T) {
+	server, _ := newStatusSequenceServer(t)
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--stats", "--json"})
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^stats: status=200 duration=\d+(\.\d+)?(ns|µs|ms|s) bytes=24\n$`, stderr.String())
+	assert.NotContains(t, stdout.String(), "stats:")
+	assert.Contains(t, stdout.String(), "doggie")
+}
+
+func TestExecuteCommand_StatsAfterRetries(t *testing.T) {
+	server, _ := newStatusSequenceServer(t, http.StatusServiceUnavailable)
+	var stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withErrorOutput(&stderr), withRetryDelays(new([]time.Duration)))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--retry", "1", "--retry-on", "503", "--stats", "--json"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^stats: status=200 duration=\S+ bytes=24 attempts=2\n$`, stderr.String())
+}
+
+func TestExecuteCommand_StatsOnErrorStatus(t *testing.T) {
+	server, _ := newStatusSequenceServer(t, http.StatusNotFound)
+	var stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--stats"})
+	require.Error(t, err)
+	assert.Regexp(t, `(?m)^stats: status=404 duration=\S+ bytes=24$`, stderr.String())
+}
+
+func TestExecuteCommand_NoStatsByDefault(t *testing.T) {
+	server, _ := newStatusSequenceServer(t)
+	var stdout, stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &stdout, withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--json"})
+	require.NoError(t, err)
+	assert.Empty(t, stderr.String())
+}
+
+func TestExecuteCommand_StatsFalse(t *testing.T) {
+	server, _ := newStatusSequenceServer(t)
+	var stderr bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &bytes.Buffer{}, withErrorOutput(&stderr))
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--stats=false", "--json"})
+	require.NoError(t, err)
+	assert.Empty(t, stderr.String())
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// applyConnectTimeout overrides the profile's connect timeout with
+// --timeout-connect for this request.
+func applyConnectTimeout(params map[string]any, profile *config.Profile) error {
+	value, ok := params["timeout-connect"]
+	if !ok {
+		return nil
+	}
+	timeout, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("invalid --timeout-connect value %q: must be a positive duration such as 2s", fmt.Sprint(value))
+	}
+	profile.ConnectTimeout = config.Duration{Duration: timeout}
+	return nil
+}
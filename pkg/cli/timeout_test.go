@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyConnectTimeout(t *testing.T) {
+	profile := &config.Profile{ConnectTimeout: config.Duration{Duration: 5 * time.Second}}
+
+	require.NoError(t, applyConnectTimeout(map[string]any{}, profile))
+	assert.Equal(t, 5*time.Second, profile.ConnectTimeout.Duration)
+
+	require.NoError(t, applyConnectTimeout(map[string]any{"timeout-connect": "250ms"}, profile))
+	assert.Equal(t, 250*time.Millisecond, profile.ConnectTimeout.Duration)
+
+	err := applyConnectTimeout(map[string]any{"timeout-connect": "soon"}, profile)
+	assert.EqualError(t, err, `invalid --timeout-connect value "soon": must be a positive duration such as 2s`)
+}
+
+func TestExecuteCommand_TimeoutConnectAllowsSlowResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"name":"doggie"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	handler, appConfig := newPetstoreHandler(t, server.URL, &out)
+
+	err := handler.ExecuteCommand("petstore", appConfig, []string{"pet", "get", "--petId", "1", "--timeout-connect", "50ms", "--output", "json"})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "doggie")
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFieldNotFound is returned when an unwrap path does not exist in the response.
+var ErrFieldNotFound = errors.New("field not found in response")
+
+// ResponseTransformer post-processes a decoded response before it is displayed.
+// Transformers are chained: each receives the output of the previous one.
+type ResponseTransformer interface {
+	Transform(data any) (any, error)
+}
+
+// ResponseTransformerFunc adapts an ordinary function to a ResponseTransformer.
+type ResponseTransformerFunc func(data any) (any, error)
+
+// Transform calls f(data).
+func (f ResponseTransformerFunc) Transform(data any) (any, error) {
+	return f(data)
+}
+
+// UnwrapTransformer returns a transformer that extracts a nested field as the result.
+// The field may be a dotted path such as "data" or "result.items".
+func UnwrapTransformer(field string) ResponseTransformer {
+	return ResponseTransformerFunc(func(data any) (any, error) {
+		current := data
+		for part := range strings.SplitSeq(field, ".") {
+			obj, ok := current.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+			}
+			current, ok = obj[part]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+			}
+		}
+		return current, nil
+	})
+}
+
+// AddResponseTransformer registers transformers that run on every decoded response.
+func (h *Handler) AddResponseTransformer(transformers ...ResponseTransformer) {
+	h.transformers = append(h.transformers, transformers...)
+}
+
+// responseTransformers returns the registered transformers followed by any requested via flags.
+func (h *Handler) responseTransformers(params map[string]any) []ResponseTransformer {
+	transformers := h.transformers
+	if field, ok := params["unwrap"].(string); ok && field != "" {
+		transformers = append(transformers[:len(transformers):len(transformers)], UnwrapTransformer(field))
+	}
+	return transformers
+}
+
+// TransformResponse applies transformers in order to a JSON response body.
+// Bodies that are not JSON are returned unchanged.
+func TransformResponse(body []byte, transformers []ResponseTransformer) ([]byte, error) {
+	if len(transformers) == 0 {
+		return body, nil
+	}
+
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body, nil
+	}
+
+	for _, transformer := range transformers {
+		var err error
+		data, err = transformer.Transform(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform response: %w", err)
+		}
+	}
+
+	return json.Marshal(data)
+}
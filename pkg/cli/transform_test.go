@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stripeListResponse = `{
+  "object": "list",
+  "url": "/v1/customers",
+  "has_more": false,
+  "data": [
+    {"id": "cus_1", "object": "customer", "email": "a@example.com"},
+    {"id": "cus_2", "object": "customer", "email": "b@example.com"}
+  ]
+}`
+
+func TestTransformResponse_UnwrapStripeData(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+
+	transformers := h.responseTransformers(map[string]any{"unwrap": "data"})
+	body, err := TransformResponse([]byte(stripeListResponse), transformers)
+	require.NoError(t, err)
+
+	var customers []map[string]any
+	require.NoError(t, json.Unmarshal(body, &customers))
+	require.Len(t, customers, 2)
+	assert.Equal(t, "cus_1", customers[0]["id"])
+
+	output, err := h.FormatOutput(body, "json")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(output, "["), "expected unwrapped array output, got: %s", output)
+	assert.NotContains(t, output, "has_more")
+}
+
+func TestTransformResponse_Chain(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	h.AddResponseTransformer(UnwrapTransformer("data"))
+	h.AddResponseTransformer(ResponseTransformerFunc(func(data any) (any, error) {
+		items, _ := data.([]any)
+		return len(items), nil
+	}))
+
+	body, err := TransformResponse([]byte(stripeListResponse), h.responseTransformers(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(body))
+}
+
+func TestTransformResponse_NestedPath(t *testing.T) {
+	body, err := TransformResponse(
+		[]byte(`{"result": {"items": [1, 2, 3]}}`),
+		[]ResponseTransformer{UnwrapTransformer("result.items")},
+	)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[1, 2, 3]`, string(body))
+}
+
+func TestTransformResponse_MissingField(t *testing.T) {
+	_, err := TransformResponse([]byte(stripeListResponse), []ResponseTransformer{UnwrapTransformer("items")})
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+}
+
+func TestTransformResponse_NonJSONUnchanged(t *testing.T) {
+	body, err := TransformResponse([]byte("plain text"), []ResponseTransformer{UnwrapTransformer("data")})
+	require.NoError(t, err)
+	assert.Equal(t, "plain text", string(body))
+}
+
+func TestResponseTransformers_DoesNotMutateRegistered(t *testing.T) {
+	h := NewHandler(nil, nil, nil, nil)
+	h.AddResponseTransformer(UnwrapTransformer("data"))
+
+	_ = h.responseTransformers(map[string]any{"unwrap": "items"})
+	assert.Len(t, h.transformers, 1)
+}
@@ -3,11 +3,14 @@ package completion
 
 import (
 	"context"
+	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
 	"github.com/nomagicln/open-bridge/pkg/semantic"
 	"github.com/nomagicln/open-bridge/pkg/spec"
 )
@@ -17,14 +20,23 @@ type Provider struct {
 	configMgr  *config.Manager
 	specParser *spec.Parser
 	mapper     *semantic.Mapper
+
+	httpClient        *http.Client
+	enumSourceTimeout time.Duration
+	enumSourceTTL     time.Duration
+	now               func() time.Time
 }
 
 // NewProvider creates a new completion provider.
 func NewProvider(configMgr *config.Manager, specParser *spec.Parser, mapper *semantic.Mapper) *Provider {
 	return &Provider{
-		configMgr:  configMgr,
-		specParser: specParser,
-		mapper:     mapper,
+		configMgr:         configMgr,
+		specParser:        specParser,
+		mapper:            mapper,
+		httpClient:        &http.Client{},
+		enumSourceTimeout: defaultEnumSourceTimeout,
+		enumSourceTTL:     defaultEnumSourceTTL,
+		now:               time.Now,
 	}
 }
 
@@ -56,7 +68,7 @@ func (p *Provider) CompleteVerbs(appName, prefix string) []string {
 		return nil
 	}
 
-	tree := p.mapper.BuildCommandTree(specDoc)
+	tree := p.commandTree(appName, specDoc)
 
 	verbs := p.collectVerbs(tree, prefix)
 
@@ -84,7 +96,7 @@ func (p *Provider) CompleteResources(appName, prefix string) []string {
 		return nil
 	}
 
-	tree := p.mapper.BuildCommandTree(specDoc)
+	tree := p.commandTree(appName, specDoc)
 
 	return p.collectResources(tree, prefix)
 }
@@ -108,7 +120,7 @@ func (p *Provider) CompleteResourcesForVerb(appName, verb, prefix string) []stri
 		return nil
 	}
 
-	tree := p.mapper.BuildCommandTree(specDoc)
+	tree := p.commandTree(appName, specDoc)
 
 	return p.collectResourcesWithVerb(tree, verb, prefix)
 }
@@ -138,7 +150,7 @@ func (p *Provider) CompleteVerbsForResource(appName, resource, prefix string) []
 		return nil
 	}
 
-	tree := p.mapper.BuildCommandTree(specDoc)
+	tree := p.commandTree(appName, specDoc)
 
 	return p.collectVerbsForResource(tree, resource, prefix)
 }
@@ -161,8 +173,8 @@ func (p *Provider) collectVerbsForResource(tree *semantic.CommandTree, resource,
 }
 
 // getOperationSpecForCommand finds the OpenAPI operation spec for a resource+verb.
-func (p *Provider) getOperationSpecForCommand(specDoc *openapi3.T, resource, verb string) *openapi3.Operation {
-	tree := p.mapper.BuildCommandTree(specDoc)
+func (p *Provider) getOperationSpecForCommand(specDoc *openapi3.T, appName, resource, verb string) *openapi3.Operation {
+	tree := p.commandTree(appName, specDoc)
 
 	res, ok := tree.RootResources[resource]
 	if !ok {
@@ -195,6 +207,10 @@ func getOperationByMethod(pathItem *openapi3.PathItem, method string) *openapi3.
 		return pathItem.Patch
 	case "DELETE":
 		return pathItem.Delete
+	case "HEAD":
+		return pathItem.Head
+	case "OPTIONS":
+		return pathItem.Options
 	default:
 		return nil
 	}
@@ -205,16 +221,24 @@ func collectParameterFlags(opSpec *openapi3.Operation, prefix string) []string {
 	var flags []string
 	for _, paramRef := range opSpec.Parameters {
 		if paramRef.Value != nil {
-			flagName := paramRef.Value.Name
-			if prefix == "" || strings.HasPrefix(flagName, prefix) {
-				flags = append(flags, "--"+flagName)
+			names := append([]string{paramRef.Value.Name}, spec.ParameterAliases(paramRef.Value)...)
+			for _, flagName := range names {
+				if prefix == "" || strings.HasPrefix(flagName, prefix) {
+					flag := "--" + flagName
+					if paramRef.Value.Deprecated {
+						// Shell completion "name\tdescription" convention.
+						flag += "\tdeprecated"
+					}
+					flags = append(flags, flag)
+				}
 			}
 		}
 	}
 	return flags
 }
 
-// collectBodyPropertyFlags collects flag names from request body properties.
+// collectBodyPropertyFlags collects flag names from request body properties,
+// including dotted flags for the fields of nested objects.
 func collectBodyPropertyFlags(opSpec *openapi3.Operation, prefix string) []string {
 	var flags []string
 	if opSpec.RequestBody == nil || opSpec.RequestBody.Value == nil {
@@ -231,6 +255,11 @@ func collectBodyPropertyFlags(opSpec *openapi3.Operation, prefix string) []strin
 					}
 				}
 			}
+			for _, field := range request.FlattenBodySchema(schema) {
+				if strings.Contains(field.Path, ".") && matchesPrefix(field.Path, prefix) {
+					flags = append(flags, "--"+field.Path)
+				}
+			}
 			break
 		}
 	}
@@ -244,7 +273,7 @@ func (p *Provider) CompleteFlags(appName, resource, verb, prefix string) []strin
 		return nil
 	}
 
-	opSpec := p.getOperationSpecForCommand(specDoc, resource, verb)
+	opSpec := p.getOperationSpecForCommand(specDoc, appName, resource, verb)
 	if opSpec == nil {
 		return nil
 	}
@@ -269,20 +298,45 @@ func (p *Provider) CompleteFlags(appName, resource, verb, prefix string) []strin
 func (p *Provider) completeCommonFlagValues(appName, flagName string) ([]string, bool) {
 	switch flagName {
 	case "output", "o":
-		return []string{"table", "json", "yaml"}, true
+		return []string{"table", "json", "yaml", "jsonl", "headers", "raw", "go-template="}, true
 	case "profile", "p":
 		appConfig, err := p.configMgr.GetAppConfig(appName)
 		if err != nil {
 			return nil, true
 		}
-		profiles := make([]string, 0, len(appConfig.Profiles))
-		for profileName := range appConfig.Profiles {
+		return profileNames(appConfig, ""), true
+	}
+	return nil, false
+}
+
+// CompleteProfiles returns the profile names of an app matching the prefix.
+// The default profile carries a "default" description, using the shell
+// completion "name\tdescription" convention.
+func (p *Provider) CompleteProfiles(appName, prefix string) []string {
+	appConfig, err := p.configMgr.GetAppConfig(appName)
+	if err != nil {
+		return nil
+	}
+
+	profiles := profileNames(appConfig, prefix)
+	for i, name := range profiles {
+		if name == appConfig.DefaultProfile {
+			profiles[i] = name + "\tdefault"
+		}
+	}
+	return profiles
+}
+
+// profileNames returns the sorted profile names of an app matching the prefix.
+func profileNames(appConfig *config.AppConfig, prefix string) []string {
+	profiles := make([]string, 0, len(appConfig.Profiles))
+	for profileName := range appConfig.Profiles {
+		if matchesPrefix(profileName, prefix) {
 			profiles = append(profiles, profileName)
 		}
-		sort.Strings(profiles)
-		return profiles, true
 	}
-	return nil, false
+	sort.Strings(profiles)
+	return profiles
 }
 
 // extractEnumValues extracts string enum values from a slice.
@@ -337,6 +391,14 @@ func findBodyPropertyEnumValues(opSpec *openapi3.Operation, flagName string) []s
 
 // CompleteFlagValues returns possible values for a flag.
 func (p *Provider) CompleteFlagValues(appName, resource, verb, flagName string) []string {
+	return p.CompleteFlagValuesContext(context.Background(), appName, resource, verb, flagName)
+}
+
+// CompleteFlagValuesContext returns possible values for a flag. When the parameter
+// declares an x-ob-enum-source, candidates are fetched from that endpoint within a
+// short timeout and cached; otherwise, or if the fetch fails, static enum values
+// are returned.
+func (p *Provider) CompleteFlagValuesContext(ctx context.Context, appName, resource, verb, flagName string) []string {
 	cleanFlagName := cleanFlagName(flagName)
 
 	if values, handled := p.completeCommonFlagValues(appName, cleanFlagName); handled {
@@ -347,6 +409,13 @@ func (p *Provider) CompleteFlagValues(appName, resource, verb, flagName string)
 	if err != nil || opSpec == nil {
 		return nil
 	}
+	cleanFlagName = spec.ResolveParameterAlias(opSpec.Parameters, cleanFlagName)
+
+	if src, ok := findEnumSource(opSpec, cleanFlagName); ok {
+		if values, err := p.fetchEnumSourceValues(ctx, appName, src); err == nil && len(values) > 0 {
+			return values
+		}
+	}
 
 	return findEnumValues(opSpec, cleanFlagName)
 }
@@ -365,7 +434,7 @@ func (p *Provider) getOperationSpec(appName, resource, verb string) (*openapi3.O
 		return nil, err
 	}
 
-	return p.getOperationSpecForCommand(specDoc, resource, verb), nil
+	return p.getOperationSpecForCommand(specDoc, appName, resource, verb), nil
 }
 
 // findEnumValues searches for enum values in both parameters and request body.
@@ -386,6 +455,20 @@ func (p *Provider) loadSpec(appName string) (*openapi3.T, error) {
 	return p.loadAndCacheSpec(appName)
 }
 
+// commandTree builds the command tree for an app's spec with the app's mapper
+// settings, reusing the tree cached alongside the app's parsed spec when neither
+// the spec nor the mapper changed.
+func (p *Provider) commandTree(appName string, specDoc *openapi3.T) *semantic.CommandTree {
+	mapper, cachePath := p.mapper, ""
+	if p.configMgr != nil {
+		if appConfig, err := p.configMgr.GetAppConfig(appName); err == nil {
+			mapper = mapper.ForApp(appConfig.VerbMappings, appConfig.OperationIDVerbs)
+		}
+		cachePath = p.configMgr.CommandTreeCachePath(appName)
+	}
+	return mapper.BuildCommandTreeCached(specDoc, cachePath)
+}
+
 // getCachedSpec retrieves a cached spec if available.
 func (p *Provider) getCachedSpec(appName string) (*openapi3.T, bool) {
 	return p.specParser.GetCachedSpec(appName)
@@ -399,7 +482,27 @@ func (p *Provider) loadAndCacheSpec(appName string) (*openapi3.T, error) {
 	}
 
 	ctx := context.Background()
-	return p.specParser.LoadSpecWithPersistentCache(ctx, appConfig.SpecSource, appName)
+	specDoc, err := p.specParser.LoadSpecWithPersistentCache(ctx, appConfig.SpecSource, appName)
+	if err != nil {
+		return nil, err
+	}
+	p.specParser.CacheSpec(appName, specDoc)
+	return specDoc, nil
+}
+
+// WatchSpecChanges refreshes completions when a watched spec changes: the
+// cached spec of the changed app is dropped, so the next completion reloads it
+// and reflects the current spec without restarting the shell.
+func (p *Provider) WatchSpecChanges(watchers *config.SpecWatcherManager) {
+	watchers.AddHandler(p.handleSpecChange)
+}
+
+// handleSpecChange drops the cached spec of an app whose spec changed.
+func (p *Provider) handleSpecChange(event config.SpecChangeEvent) {
+	if event.ChangeType == config.SpecChangeError {
+		return
+	}
+	p.specParser.InvalidateCache(event.AppName)
 }
 
 // matchesPrefix checks if a string matches the given prefix.
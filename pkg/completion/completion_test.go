@@ -3,7 +3,9 @@ package completion
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/nomagicln/open-bridge/internal/testutil"
 	"github.com/nomagicln/open-bridge/pkg/config"
@@ -264,3 +266,146 @@ func TestCompleteFlagValues(t *testing.T) {
 		assert.Nil(t, values)
 	})
 }
+
+func TestCompleteProfiles(t *testing.T) {
+	configMgr, specParser, mapper := setupTestEnv(t)
+	provider := NewProvider(configMgr, specParser, mapper)
+
+	result, err := configMgr.InstallApp("testapp", config.InstallOptions{
+		SpecSource: writeTestSpec(t),
+		BaseURL:    "https://api.test.com",
+	})
+	require.NoError(t, err)
+
+	appConfig, err := configMgr.GetAppConfig(result.AppName)
+	require.NoError(t, err)
+	appConfig.Profiles["staging"] = config.Profile{
+		Name:    "staging",
+		BaseURL: "https://api.staging.test.com",
+	}
+	require.NoError(t, configMgr.SaveAppConfig(appConfig))
+
+	t.Run("List all profiles with default marked", func(t *testing.T) {
+		profiles := provider.CompleteProfiles("testapp", "")
+		assert.Equal(t, []string{"default\tdefault", "staging"}, profiles)
+	})
+
+	t.Run("Filter profiles by prefix", func(t *testing.T) {
+		profiles := provider.CompleteProfiles("testapp", "st")
+		assert.Equal(t, []string{"staging"}, profiles)
+	})
+
+	t.Run("Unknown app", func(t *testing.T) {
+		assert.Empty(t, provider.CompleteProfiles("nonexistent", ""))
+	})
+}
+
+func TestCompleteAliasFlags(t *testing.T) {
+	configMgr, specParser, mapper := setupTestEnv(t)
+	provider := NewProvider(configMgr, specParser, mapper)
+
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(`openapi: "3.0.0"
+info:
+  title: Issues API
+  version: "1.0.0"
+paths:
+  /issues:
+    get:
+      operationId: listIssues
+      parameters:
+        - name: issue_state
+          in: query
+          x-ob-alias: state
+          schema:
+            type: string
+            enum: [open, closed]
+      responses:
+        "200":
+          description: OK
+`), 0644))
+	_, err := configMgr.InstallApp("issues", config.InstallOptions{
+		SpecSource: specPath,
+		BaseURL:    "https://api.test.com",
+	})
+	require.NoError(t, err)
+
+	flags := provider.CompleteFlags("issues", "issues", "list", "")
+	assert.Contains(t, flags, "--issue_state")
+	assert.Contains(t, flags, "--state")
+
+	values := provider.CompleteFlagValues("issues", "issues", "list", "--state")
+	assert.Equal(t, []string{"closed", "open"}, values)
+}
+
+func TestCompleteDeprecatedFlags(t *testing.T) {
+	configMgr, specParser, mapper := setupTestEnv(t)
+	provider := NewProvider(configMgr, specParser, mapper)
+
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(`openapi: "3.0.0"
+info:
+  title: Search API
+  version: "1.0.0"
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - name: q
+          in: query
+          schema:
+            type: string
+        - name: sort_by
+          in: query
+          deprecated: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`), 0644))
+	_, err := configMgr.InstallApp("search", config.InstallOptions{
+		SpecSource: specPath,
+		BaseURL:    "https://api.test.com",
+	})
+	require.NoError(t, err)
+
+	flags := provider.CompleteFlags("search", "items", "list", "")
+	assert.Contains(t, flags, "--q")
+	assert.Contains(t, flags, "--sort_by\tdeprecated")
+}
+
+const ordersSpecPaths = `
+  /orders:
+    get:
+      operationId: listOrders
+      responses:
+        "200":
+          description: OK
+`
+
+func TestCompleteResourcesRefreshesOnSpecChange(t *testing.T) {
+	provider := setupProviderWithApp(t)
+	appConfig, err := provider.configMgr.GetAppConfig("testapp")
+	require.NoError(t, err)
+	specPath := appConfig.SpecSource
+
+	resources := provider.CompleteResources("testapp", "")
+	require.Contains(t, resources, "users")
+	require.NotContains(t, resources, "orders")
+
+	watchers := config.NewSpecWatcherManager(nil)
+	provider.WatchSpecChanges(watchers)
+	require.NoError(t, watchers.WatchApp("testapp", specPath))
+	require.NoError(t, watchers.Start())
+	defer func() { _ = watchers.Stop() }()
+
+	// Rewrite the spec until the watcher reports it, in case the first write
+	// lands before the watcher is listening.
+	changed := []byte(testutil.MinimalOpenAPISpec + ordersSpecPaths)
+	assert.Eventually(t, func() bool {
+		require.NoError(t, os.WriteFile(specPath, changed, 0644))
+		return slices.Contains(provider.CompleteResources("testapp", ""), "orders")
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,242 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// enumSourceExtension points a parameter or body property at an endpoint that
+// lists its valid values. The value is either a URL (absolute, or relative to the
+// app's default profile base URL) or an object with "url" and an optional "field"
+// naming the property to read from each item when the endpoint returns objects.
+const enumSourceExtension = "x-ob-enum-source"
+
+const (
+	// defaultEnumSourceTimeout bounds each enum source request so completion stays responsive.
+	defaultEnumSourceTimeout = 2 * time.Second
+	// defaultEnumSourceTTL is how long fetched enum values are reused.
+	defaultEnumSourceTTL = 5 * time.Minute
+	// maxEnumSourceBytes caps the size of an enum source response.
+	maxEnumSourceBytes = 1 << 20
+	// maxEnumSourceRedirects matches the default redirect limit of http.Client.
+	maxEnumSourceRedirects = 10
+)
+
+// enumSource describes an endpoint providing completion candidates.
+type enumSource struct {
+	URL   string
+	Field string
+}
+
+// enumSourceCacheEntry is a cached enum source response.
+type enumSourceCacheEntry struct {
+	Values    []string  `json:"values"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// parseEnumSource parses the value of an x-ob-enum-source extension.
+func parseEnumSource(ext any) (enumSource, bool) {
+	switch v := ext.(type) {
+	case string:
+		return enumSource{URL: v}, v != ""
+	case map[string]any:
+		rawURL, _ := v["url"].(string)
+		field, _ := v["field"].(string)
+		return enumSource{URL: rawURL, Field: field}, rawURL != ""
+	}
+	return enumSource{}, false
+}
+
+// schemaEnumSource returns the enum source declared on a schema, if any.
+func schemaEnumSource(schemaRef *openapi3.SchemaRef) (enumSource, bool) {
+	if schemaRef == nil || schemaRef.Value == nil {
+		return enumSource{}, false
+	}
+	return parseEnumSource(schemaRef.Value.Extensions[enumSourceExtension])
+}
+
+// findEnumSource finds the enum source for a parameter or body property.
+func findEnumSource(opSpec *openapi3.Operation, flagName string) (enumSource, bool) {
+	for _, paramRef := range opSpec.Parameters {
+		if paramRef.Value == nil || paramRef.Value.Name != flagName {
+			continue
+		}
+		if src, ok := parseEnumSource(paramRef.Value.Extensions[enumSourceExtension]); ok {
+			return src, true
+		}
+		return schemaEnumSource(paramRef.Value.Schema)
+	}
+
+	if opSpec.RequestBody == nil || opSpec.RequestBody.Value == nil {
+		return enumSource{}, false
+	}
+	for mediaType, content := range opSpec.RequestBody.Value.Content {
+		if !strings.Contains(mediaType, "json") || content.Schema == nil || content.Schema.Value == nil {
+			continue
+		}
+		return schemaEnumSource(content.Schema.Value.Properties[flagName])
+	}
+	return enumSource{}, false
+}
+
+// fetchEnumSourceValues returns completion candidates from an enum source,
+// using cached values while they are fresh.
+func (p *Provider) fetchEnumSourceValues(ctx context.Context, appName string, src enumSource) ([]string, error) {
+	appConfig, err := p.configMgr.GetAppConfig(appName)
+	if err != nil {
+		return nil, err
+	}
+	profile := appConfig.Profiles[appConfig.DefaultProfile]
+
+	target, err := resolveEnumSourceURL(profile.BaseURL, src.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := target + "#" + src.Field
+	cachePath := filepath.Join(p.configMgr.AppsDir(), appName, "cache", "enum-sources.json")
+	cache := loadEnumSourceCache(cachePath)
+	if entry, ok := cache[cacheKey]; ok && p.now().Sub(entry.FetchedAt) < p.enumSourceTTL {
+		return entry.Values, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.enumSourceTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create enum source request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	// Profile headers may carry credentials, so they only go to the API itself.
+	if sameOrigin(profile.BaseURL, req.URL) {
+		for name, value := range profile.Headers {
+			req.Header.Set(name, value)
+		}
+	}
+
+	client := *p.httpClient
+	client.CheckRedirect = stripHeadersOffOrigin(profile.BaseURL, profile.Headers)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch enum source: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("enum source returned HTTP %d", resp.StatusCode)
+	}
+
+	var data any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxEnumSourceBytes)).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode enum source: %w", err)
+	}
+
+	values := extractSourceValues(data, src.Field)
+	cache[cacheKey] = enumSourceCacheEntry{Values: values, FetchedAt: p.now()}
+	saveEnumSourceCache(cachePath, cache)
+	return values, nil
+}
+
+// resolveEnumSourceURL resolves an enum source URL against the profile base URL.
+func resolveEnumSourceURL(baseURL, source string) (string, error) {
+	ref, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid enum source %q: %w", source, err)
+	}
+	if ref.IsAbs() {
+		return source, nil
+	}
+	if baseURL == "" {
+		return "", fmt.Errorf("enum source %q is relative but the profile has no base URL", source)
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(source, "/"), nil
+}
+
+// sameOrigin reports whether target has the scheme and host of baseURL.
+func sameOrigin(baseURL string, target *url.URL) bool {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return base.Host != "" && strings.EqualFold(base.Scheme, target.Scheme) && strings.EqualFold(base.Host, target.Host)
+}
+
+// stripHeadersOffOrigin returns a redirect policy that removes the profile
+// headers from redirects leaving the API origin, since the client copies the
+// original request headers to every redirect.
+func stripHeadersOffOrigin(baseURL string, headers map[string]string) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxEnumSourceRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxEnumSourceRedirects)
+		}
+		if !sameOrigin(baseURL, req.URL) {
+			for name := range headers {
+				req.Header.Del(name)
+			}
+		}
+		return nil
+	}
+}
+
+// extractSourceValues converts an enum source response into completion candidates.
+// The response must be a JSON array; object items are read through field.
+func extractSourceValues(data any, field string) []string {
+	items, ok := data.([]any)
+	if !ok {
+		return nil
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok && field != "" {
+			item = obj[field]
+		}
+		switch v := item.(type) {
+		case string:
+			values = append(values, v)
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			values = append(values, strconv.FormatBool(v))
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// loadEnumSourceCache reads cached enum source values, ignoring a missing or corrupt cache.
+func loadEnumSourceCache(path string) map[string]enumSourceCacheEntry {
+	cache := make(map[string]enumSourceCacheEntry)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	_ = json.Unmarshal(data, &cache)
+	return cache
+}
+
+// saveEnumSourceCache writes cached enum source values. Failures are ignored
+// because the cache only avoids repeated requests.
+func saveEnumSourceCache(path string, cache map[string]enumSourceCacheEntry) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
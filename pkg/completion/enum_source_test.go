@@ -0,0 +1,186 @@
+package completion
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const enumSourceSpec = `openapi: "3.0.0"
+info:
+  title: Enum Source API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: status
+          in: query
+          x-ob-enum-source: /statuses
+          schema:
+            type: string
+            enum: [available, sold]
+        - name: owner
+          in: query
+          schema:
+            type: string
+            x-ob-enum-source:
+              url: /owners
+              field: login
+      responses:
+        "200":
+          description: OK
+`
+
+// setupEnumSourceProvider installs an app whose enum sources are served by handler.
+func setupEnumSourceProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	configMgr, specParser, mapper := setupTestEnv(t)
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(enumSourceSpec), 0644))
+
+	_, err := configMgr.InstallApp("enumapp", config.InstallOptions{
+		SpecSource: specPath,
+		BaseURL:    server.URL,
+	})
+	require.NoError(t, err)
+
+	return NewProvider(configMgr, specParser, mapper)
+}
+
+func TestCompleteFlagValues_EnumSource(t *testing.T) {
+	var requests atomic.Int32
+	provider := setupEnumSourceProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/statuses":
+			_, _ = w.Write([]byte(`["pending","available","sold"]`))
+		case "/owners":
+			_, _ = w.Write([]byte(`[{"login":"bob"},{"login":"alice"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	values := provider.CompleteFlagValues("enumapp", "pets", "list", "--status")
+	assert.Equal(t, []string{"available", "pending", "sold"}, values)
+
+	values = provider.CompleteFlagValues("enumapp", "pets", "list", "--owner")
+	assert.Equal(t, []string{"alice", "bob"}, values)
+
+	// Cached values are reused without another request.
+	provider.CompleteFlagValues("enumapp", "pets", "list", "--status")
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestCompleteFlagValues_EnumSourceFallsBackToStaticEnum(t *testing.T) {
+	provider := setupEnumSourceProvider(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	values := provider.CompleteFlagValues("enumapp", "pets", "list", "--status")
+	assert.Equal(t, []string{"available", "sold"}, values)
+
+	values = provider.CompleteFlagValues("enumapp", "pets", "list", "--owner")
+	assert.Empty(t, values)
+}
+
+func TestCompleteFlagValues_EnumSourceCancelled(t *testing.T) {
+	var requests atomic.Int32
+	provider := setupEnumSourceProvider(t, func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`["pending"]`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	values := provider.CompleteFlagValuesContext(ctx, "enumapp", "pets", "list", "--status")
+	assert.Equal(t, []string{"available", "sold"}, values)
+	assert.Zero(t, requests.Load())
+}
+
+func TestCompleteFlagValues_EnumSourceHeadersStayOnOrigin(t *testing.T) {
+	var apiAuth, otherAuth atomic.Value
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAuth.Store(r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`["pending"]`))
+	}))
+	t.Cleanup(other.Close)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiAuth.Store(r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"login":"alice"}]`))
+	}))
+	t.Cleanup(api.Close)
+
+	configMgr, specParser, mapper := setupTestEnv(t)
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	specYAML := strings.Replace(enumSourceSpec, "x-ob-enum-source: /statuses", "x-ob-enum-source: "+other.URL+"/statuses", 1)
+	require.NoError(t, os.WriteFile(specPath, []byte(specYAML), 0644))
+	_, err := configMgr.InstallApp("enumapp", config.InstallOptions{
+		SpecSource: specPath,
+		BaseURL:    api.URL,
+		Headers:    map[string]string{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+	provider := NewProvider(configMgr, specParser, mapper)
+
+	assert.Equal(t, []string{"pending"}, provider.CompleteFlagValues("enumapp", "pets", "list", "--status"))
+	assert.Equal(t, "", otherAuth.Load())
+
+	assert.Equal(t, []string{"alice"}, provider.CompleteFlagValues("enumapp", "pets", "list", "--owner"))
+	assert.Equal(t, "Bearer secret", apiAuth.Load())
+}
+
+func TestCompleteFlagValues_EnumSourceRedirectDropsHeaders(t *testing.T) {
+	var otherKey atomic.Value
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherKey.Store(r.Header.Get("X-API-Key"))
+		_, _ = w.Write([]byte(`["pending"]`))
+	}))
+	t.Cleanup(other.Close)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/statuses", http.StatusFound)
+	}))
+	t.Cleanup(api.Close)
+
+	configMgr, specParser, mapper := setupTestEnv(t)
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(enumSourceSpec), 0644))
+	_, err := configMgr.InstallApp("enumapp", config.InstallOptions{
+		SpecSource: specPath,
+		BaseURL:    api.URL,
+		Headers:    map[string]string{"X-API-Key": "secret"},
+	})
+	require.NoError(t, err)
+	provider := NewProvider(configMgr, specParser, mapper)
+
+	assert.Equal(t, []string{"pending"}, provider.CompleteFlagValues("enumapp", "pets", "list", "--status"))
+	assert.Equal(t, "", otherKey.Load())
+}
+
+func TestSameOrigin(t *testing.T) {
+	target, err := url.Parse("https://api.example.com/statuses")
+	require.NoError(t, err)
+
+	assert.True(t, sameOrigin("https://API.example.com/v1", target))
+	assert.False(t, sameOrigin("http://api.example.com", target))
+	assert.False(t, sameOrigin("https://evil.example.com", target))
+	assert.False(t, sameOrigin("", target))
+}
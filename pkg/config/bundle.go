@@ -0,0 +1,135 @@
+// Package config provides configuration management for OpenBridge.
+// This file contains app bundle export/import functionality.
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion is the version of the app bundle format.
+const BundleVersion = "1.0"
+
+// AppBundle is a portable copy of an installed app, for sharing it with
+// teammates. It holds the app configuration and a reference to each stored
+// credential, but never a secret value: credentials are supplied again on
+// import.
+type AppBundle struct {
+	Version     string             `yaml:"version"`
+	ExportedAt  time.Time          `yaml:"exported_at"`
+	App         AppConfig          `yaml:"app"`
+	Credentials []BundleCredential `yaml:"credentials,omitempty"`
+}
+
+// BundleCredential records that a credential is stored for a profile.
+type BundleCredential struct {
+	// Profile is the profile the credential belongs to.
+	Profile string `yaml:"profile"`
+
+	// Type is the credential type, e.g. bearer, api_key or basic.
+	Type string `yaml:"type"`
+
+	// KeyName is the header or query parameter the credential is sent in,
+	// if the profile's auth names one.
+	KeyName string `yaml:"key_name,omitempty"`
+
+	// TokenURL is the token endpoint stored with an oauth2_client_credentials
+	// credential, if it names its own.
+	TokenURL string `yaml:"token_url,omitempty"`
+
+	// Scopes are the space-separated scopes stored with an
+	// oauth2_client_credentials credential, if it names its own.
+	Scopes string `yaml:"scopes,omitempty"`
+}
+
+// ExportBundle exports an app as a bundle. credentials maps the names of the
+// profiles that have a stored credential to its type and, for OAuth2 client
+// credentials, token URL and scopes; the profile and key name are filled in.
+// Headers and query parameters that look like credentials are left out of
+// the bundle.
+func (m *Manager) ExportBundle(appName string, credentials map[string]BundleCredential) ([]byte, error) {
+	config, err := m.GetAppConfig(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := AppBundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now(),
+		App:        *config,
+	}
+
+	for name, profile := range config.Profiles {
+		profile.Headers = copyHeadersForExport(profile)
+		profile.QueryParams = copyQueryParamsForExport(profile)
+		profile.SpecFetchHeaders = copyHeadersForExport(Profile{Headers: profile.SpecFetchHeaders})
+		bundle.App.Profiles[name] = profile
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(credentials)) {
+		profile, ok := config.Profiles[name]
+		if !ok {
+			continue
+		}
+		ref := credentials[name]
+		ref.Profile = name
+		ref.KeyName = profile.Auth.KeyName
+		bundle.Credentials = append(bundle.Credentials, ref)
+	}
+
+	return yaml.Marshal(bundle)
+}
+
+// ParseBundle parses an app bundle written by ExportBundle.
+func ParseBundle(data []byte) (*AppBundle, error) {
+	var bundle AppBundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if bundle.Version == "" {
+		return nil, fmt.Errorf("invalid bundle: missing version")
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version '%s'", bundle.Version)
+	}
+
+	for _, cred := range bundle.Credentials {
+		if _, ok := bundle.App.Profiles[cred.Profile]; !ok {
+			return nil, fmt.Errorf("invalid bundle: credential for unknown profile '%s'", cred.Profile)
+		}
+	}
+	return &bundle, nil
+}
+
+// ImportBundle installs the app configuration of a bundle, named appName or,
+// if appName is empty, as in the bundle. An installed app of the same name is
+// only replaced if overwrite is set. Credentials are not part of the bundle
+// and must be stored separately.
+func (m *Manager) ImportBundle(bundle *AppBundle, appName string, overwrite bool) (*AppConfig, error) {
+	config := bundle.App
+	if appName != "" {
+		config.Name = appName
+	}
+
+	if err := ValidateConfig(&config); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if m.AppExists(config.Name) && !overwrite {
+		return nil, fmt.Errorf("app '%s' is already installed", config.Name)
+	}
+
+	now := time.Now()
+	if config.CreatedAt.IsZero() {
+		config.CreatedAt = now
+	}
+	config.UpdatedAt = now
+
+	if err := m.SaveAppConfig(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportImportBundle(t *testing.T) {
+	m, pm := setupTestApp(t)
+
+	if err := pm.CreateProfile("prod", ProfileOptions{
+		BaseURL:      "https://prod.example.com",
+		AuthType:     "api_key",
+		AuthLocation: "header",
+		AuthKeyName:  "X-API-Key",
+		Headers:      map[string]string{"X-Team": "platform", "Authorization": "Bearer leaked"},
+	}); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+	if err := pm.CreateProfile("oauth", ProfileOptions{
+		BaseURL:  "https://api.example.com",
+		AuthType: "oauth2_client_credentials",
+	}); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+
+	data, err := m.ExportBundle("testapp", map[string]BundleCredential{
+		"default": {Type: "bearer"},
+		"prod":    {Type: "api_key"},
+		"oauth":   {Type: "oauth2_client_credentials", TokenURL: "https://auth.example.com/token", Scopes: "read write"},
+		"deleted": {Type: "basic"},
+	})
+	if err != nil {
+		t.Fatalf("ExportBundle failed: %v", err)
+	}
+	if strings.Contains(string(data), "leaked") {
+		t.Errorf("expected credential headers to be left out, got:\n%s", data)
+	}
+
+	bundle, err := ParseBundle(data)
+	if err != nil {
+		t.Fatalf("ParseBundle failed: %v", err)
+	}
+	want := []BundleCredential{
+		{Profile: "default", Type: "bearer"},
+		{Profile: "oauth", Type: "oauth2_client_credentials", TokenURL: "https://auth.example.com/token", Scopes: "read write"},
+		{Profile: "prod", Type: "api_key", KeyName: "X-API-Key"},
+	}
+	if len(bundle.Credentials) != len(want) {
+		t.Fatalf("expected credentials %+v, got %+v", want, bundle.Credentials)
+	}
+	for i := range want {
+		if bundle.Credentials[i] != want[i] {
+			t.Errorf("expected credential %+v, got %+v", want[i], bundle.Credentials[i])
+		}
+	}
+	if got := bundle.App.Profiles["prod"].Headers["X-Team"]; got != "platform" {
+		t.Errorf("expected header X-Team to be kept, got %q", got)
+	}
+
+	if _, err := m.ImportBundle(bundle, "", false); err == nil {
+		t.Error("expected an error importing over an installed app")
+	}
+
+	imported, err := m.ImportBundle(bundle, "copyapp", false)
+	if err != nil {
+		t.Fatalf("ImportBundle failed: %v", err)
+	}
+	if imported.Name != "copyapp" {
+		t.Errorf("expected app name 'copyapp', got '%s'", imported.Name)
+	}
+
+	config, err := m.GetAppConfig("copyapp")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	if len(config.Profiles) != 3 {
+		t.Errorf("expected 3 profiles, got %d", len(config.Profiles))
+	}
+	if config.Profiles["prod"].Auth.KeyName != "X-API-Key" {
+		t.Errorf("expected the prod auth to be imported, got %+v", config.Profiles["prod"].Auth)
+	}
+}
+
+func TestParseBundle_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing version":     "app:\n  name: testapp\n",
+		"unsupported version": "version: \"9.0\"\napp:\n  name: testapp\n",
+		"unknown profile":     "version: \"1.0\"\napp:\n  name: testapp\ncredentials:\n  - profile: prod\n    type: bearer\n",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseBundle([]byte(data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -50,6 +51,15 @@ type AppConfig struct {
 	// OperationCount is the number of operations in the spec.
 	// Used for determining progressive disclosure recommendation.
 	OperationCount int `yaml:"operation_count,omitempty"`
+
+	// VerbMappings overrides the default HTTP method to CLI verb table,
+	// e.g. PATCH: update. It applies to CLI commands, completion, and MCP tool names.
+	VerbMappings map[string]string `yaml:"verb_mappings,omitempty"`
+
+	// OperationIDVerbs names commands after operationId prefixes such as
+	// "list" in listCustomers, ahead of path patterns. It applies where
+	// VerbMappings does.
+	OperationIDVerbs bool `yaml:"operation_id_verbs,omitempty"`
 }
 
 // Profile represents a configuration profile for an app.
@@ -78,9 +88,32 @@ type Profile struct {
 	// Timeout is the request timeout for this profile.
 	Timeout Duration `yaml:"timeout,omitempty"`
 
+	// ConnectTimeout bounds establishing a connection to the API, independently
+	// of Timeout, so that an unreachable host fails fast while a slow response
+	// can still be read.
+	ConnectTimeout Duration `yaml:"connect_timeout,omitempty"`
+
+	// Proxy is the URL of the HTTP proxy requests are sent through, e.g.
+	// http://proxy.internal:3128. Unset, the HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY environment variables apply.
+	Proxy string `yaml:"proxy,omitempty"`
+
+	// FollowRedirects controls whether redirects are followed. Unset, they are;
+	// false returns the 3xx response itself, so that its Location can be
+	// inspected.
+	FollowRedirects *bool `yaml:"follow_redirects,omitempty"`
+
+	// MaxRedirects limits how many redirects a request follows. Zero uses the
+	// default limit of 10.
+	MaxRedirects int `yaml:"max_redirects,omitempty"`
+
 	// RetryConfig contains retry configuration.
 	RetryConfig RetryConfig `yaml:"retry,omitempty"`
 
+	// RequestDeadline caps the combined time of a request and all its
+	// retries; once it is spent no further attempt is made. Zero means no cap.
+	RequestDeadline Duration `yaml:"request_deadline,omitempty"`
+
 	// Description is an optional description of this profile.
 	Description string `yaml:"description,omitempty"`
 
@@ -100,6 +133,24 @@ type Profile struct {
 	// should be masked when generating code. When true, credentials are replaced with
 	// placeholders like <YOUR_API_KEY>. Default is false (not protected).
 	ProtectSensitiveInfo bool `yaml:"protect_sensitive_info,omitempty"`
+
+	// EnumCaseInsensitive accepts parameter enum values that differ from the spec
+	// only in case, sending them with the spec's casing. Default is strict matching.
+	EnumCaseInsensitive bool `yaml:"enum_case_insensitive,omitempty"`
+
+	// Prefer is the default Prefer header sent on write operations, e.g.
+	// return=minimal. The --prefer flag overrides it.
+	Prefer string `yaml:"prefer,omitempty"`
+
+	// DefaultParams pre-populates parameter values for every operation that
+	// declares the parameter, e.g. limit: "100". Explicit flags override them.
+	DefaultParams map[string]string `yaml:"default_params,omitempty"`
+}
+
+// FollowsRedirects reports whether requests sent with the profile follow
+// redirects.
+func (p *Profile) FollowsRedirects() bool {
+	return p.FollowRedirects == nil || *p.FollowRedirects
 }
 
 // SpecFetchAuthConfig contains authentication configuration for fetching remote specs.
@@ -171,7 +222,8 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 
 // AuthConfig represents authentication configuration.
 type AuthConfig struct {
-	// Type is the authentication type: "bearer", "api_key", "basic", "oauth2", "none".
+	// Type is the authentication type: "bearer", "api_key", "basic", "oauth2",
+	// "oauth2_client_credentials", "none".
 	Type string `yaml:"type"`
 
 	// Location is where to send the credential: "header", "query", "cookie".
@@ -244,6 +296,10 @@ type SafetyConfig struct {
 	// MaxRequestsPerMinute limits the AI's request rate.
 	MaxRequestsPerMinute int `yaml:"max_requests_per_minute,omitempty"`
 
+	// MaxArgumentsBytes limits the JSON size of MCP tool-call arguments (0 means unlimited).
+	// An operation may override it with the x-ob-max-arguments-bytes extension.
+	MaxArgumentsBytes int `yaml:"max_arguments_bytes,omitempty"`
+
 	// DangerousOperationPatterns are regex patterns for dangerous operations.
 	DangerousOperationPatterns []string `yaml:"dangerous_operation_patterns,omitempty"`
 
@@ -259,6 +315,26 @@ type SafetyConfig struct {
 	// HybridSearch contains configuration for the hybrid search engine.
 	// Only used when SearchEngine is set to "hybrid".
 	HybridSearch *HybridSearchSettings `yaml:"hybrid_search,omitempty"`
+
+	// CircuitBreaker stops calling an upstream operation after repeated failures.
+	CircuitBreaker *CircuitBreakerConfig `yaml:"circuit_breaker,omitempty"`
+}
+
+// CircuitBreakerConfig configures the MCP circuit breaker.
+// After FailureThreshold consecutive failures within Window, calls to the
+// operation fail fast for Cooldown, after which a single trial call is allowed.
+type CircuitBreakerConfig struct {
+	// Enabled turns the circuit breaker on.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures that opens the circuit (default: 5).
+	FailureThreshold int `yaml:"failure_threshold,omitempty"`
+
+	// Window is the period in which the failures must occur (default: 1m).
+	Window Duration `yaml:"window,omitempty"`
+
+	// Cooldown is how long the circuit stays open before a trial call (default: 30s).
+	Cooldown Duration `yaml:"cooldown,omitempty"`
 }
 
 // HybridSearchSettings contains configuration for hybrid search.
@@ -310,11 +386,16 @@ type RetryConfig struct {
 
 	// RetryableStatusCodes are HTTP status codes that trigger a retry.
 	RetryableStatusCodes []int `yaml:"retryable_status_codes,omitempty"`
+
+	// RetryUnsafe also retries non-idempotent requests such as POST.
+	RetryUnsafe bool `yaml:"retry_unsafe,omitempty"`
 }
 
 // Manager handles configuration persistence and retrieval.
 type Manager struct {
 	configDir string
+	mu        sync.RWMutex
+	handlers  []AppLifecycleHandler
 }
 
 // ManagerOption is a function that configures a Manager.
@@ -413,6 +494,12 @@ func (m *Manager) AppsDir() string {
 	return filepath.Join(m.configDir, "apps")
 }
 
+// CommandTreeCachePath returns the path of the app's cached command tree, stored
+// next to its cached spec.
+func (m *Manager) CommandTreeCachePath(appName string) string {
+	return filepath.Join(m.AppsDir(), appName, "cache", "command-tree.json")
+}
+
 // readAndParseConfig reads and parses a configuration file.
 func readAndParseConfig(configPath string) (*AppConfig, error) {
 	data, err := os.ReadFile(configPath)
@@ -472,6 +559,20 @@ func (m *Manager) SaveAppConfig(config *AppConfig) error {
 		return err
 	}
 
+	changeType := AppUpdated
+	if !m.AppExists(config.Name) {
+		changeType = AppInstalled
+	}
+	if err := m.writeAppConfig(config); err != nil {
+		return err
+	}
+
+	m.notifyHandlers(AppLifecycleEvent{AppName: config.Name, ChangeType: changeType})
+	return nil
+}
+
+// writeAppConfig writes an app configuration without notifying handlers.
+func (m *Manager) writeAppConfig(config *AppConfig) error {
 	// Update metadata
 	now := time.Now()
 	config.UpdatedAt = now
@@ -507,6 +608,7 @@ func (m *Manager) DeleteAppConfig(appName string) error {
 		return fmt.Errorf("failed to delete config: %w", err)
 	}
 
+	m.notifyHandlers(AppLifecycleEvent{AppName: appName, ChangeType: AppUninstalled})
 	return nil
 }
 
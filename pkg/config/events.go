@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// AppLifecycleEvent describes a change to an installed application.
+type AppLifecycleEvent struct {
+	AppName    string        // Name of the application after the change.
+	OldName    string        // Previous name, set only for renames.
+	ChangeType AppChangeType // Type of change.
+	Timestamp  time.Time     // When the change was made.
+}
+
+// AppChangeType describes the type of application lifecycle change.
+type AppChangeType string
+
+const (
+	AppInstalled   AppChangeType = "installed"   // A new app configuration was saved.
+	AppUpdated     AppChangeType = "updated"     // An existing app configuration was saved.
+	AppUninstalled AppChangeType = "uninstalled" // An app configuration was deleted.
+	AppRenamed     AppChangeType = "renamed"     // An app was renamed.
+)
+
+// AppLifecycleHandler is called after an application lifecycle change.
+type AppLifecycleHandler func(event AppLifecycleEvent)
+
+// OnChange registers a handler that is called after apps are installed, updated,
+// uninstalled, or renamed through this manager. Handlers run synchronously in
+// registration order; a panicking handler is reported and does not affect the
+// operation or other handlers.
+func (m *Manager) OnChange(handler AppLifecycleHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// notifyHandlers delivers an event to all registered handlers.
+func (m *Manager) notifyHandlers(event AppLifecycleEvent) {
+	m.mu.RLock()
+	handlers := make([]AppLifecycleHandler, len(m.handlers))
+	copy(handlers, m.handlers)
+	m.mu.RUnlock()
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	for _, h := range handlers {
+		callHandler(h, event)
+	}
+}
+
+// callHandler invokes a single handler, recovering from panics.
+func callHandler(h AppLifecycleHandler, event AppLifecycleEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s handler for app '%s' panicked: %v\n", event.ChangeType, event.AppName, r)
+		}
+	}()
+	h(event)
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOnChangeLifecycleEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	m, err := NewManager(WithConfigDir(filepath.Join(tmpDir, "config")))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com
+paths: {}
+`
+	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	var events []AppLifecycleEvent
+	m.OnChange(func(event AppLifecycleEvent) {
+		events = append(events, event)
+	})
+
+	if _, err := m.InstallApp("testapi", InstallOptions{SpecSource: specPath}); err != nil {
+		t.Fatalf("InstallApp failed: %v", err)
+	}
+	if _, err := m.InstallApp("testapi", InstallOptions{SpecSource: specPath, Force: true}); err != nil {
+		t.Fatalf("InstallApp with force failed: %v", err)
+	}
+	if err := m.RenameApp("testapi", "renamed"); err != nil {
+		t.Fatalf("RenameApp failed: %v", err)
+	}
+	if err := m.UninstallApp("renamed", false); err != nil {
+		t.Fatalf("UninstallApp failed: %v", err)
+	}
+
+	expected := []AppLifecycleEvent{
+		{AppName: "testapi", ChangeType: AppInstalled},
+		{AppName: "testapi", ChangeType: AppUpdated},
+		{AppName: "renamed", OldName: "testapi", ChangeType: AppRenamed},
+		{AppName: "renamed", ChangeType: AppUninstalled},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.AppName != want.AppName || got.OldName != want.OldName || got.ChangeType != want.ChangeType {
+			t.Errorf("event %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.Timestamp.IsZero() {
+			t.Errorf("event %d: expected timestamp to be set", i)
+		}
+	}
+}
+
+func TestOnChangePanickingHandler(t *testing.T) {
+	m, err := NewManager(WithConfigDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	called := false
+	m.OnChange(func(AppLifecycleEvent) { panic("boom") })
+	m.OnChange(func(AppLifecycleEvent) { called = true })
+
+	if err := m.SaveAppConfig(&AppConfig{Name: "testapi"}); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+	if !called {
+		t.Error("expected handlers after a panicking handler to run")
+	}
+}
+
+func TestRenameApp(t *testing.T) {
+	m, err := NewManager(WithConfigDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(m.AppsDir(), "old", "spec.yaml")
+	if err := writeSpecCopy(specPath, []byte("openapi: 3.0.0")); err != nil {
+		t.Fatalf("failed to write spec copy: %v", err)
+	}
+	if err := m.SaveAppConfig(&AppConfig{Name: "old", SpecSource: specPath}); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+	if err := m.SaveAppConfig(&AppConfig{Name: "taken"}); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+
+	if err := m.RenameApp("old", "taken"); err == nil {
+		t.Error("expected error when renaming onto an existing app")
+	}
+	assertNonexistentAppError(t, m.RenameApp("missing", "other"))
+
+	if err := m.RenameApp("old", "new"); err != nil {
+		t.Fatalf("RenameApp failed: %v", err)
+	}
+	if m.AppExists("old") {
+		t.Error("expected old app to be removed")
+	}
+
+	config, err := m.GetAppConfig("new")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	expectedSpec := filepath.Join(m.AppsDir(), "new", "spec.yaml")
+	if config.SpecSource != expectedSpec {
+		t.Errorf("expected SpecSource %s, got %s", expectedSpec, config.SpecSource)
+	}
+	if _, err := os.Stat(expectedSpec); err != nil {
+		t.Errorf("expected spec copy to be moved: %v", err)
+	}
+}
+
+func TestRenameAppMovesCredentialsAndShim(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shims are created under LOCALAPPDATA on Windows")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	m, err := NewManager(WithConfigDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if err := m.SaveAppConfig(&AppConfig{Name: "old"}); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+	if _, err := m.CreateShim("old", ""); err != nil {
+		t.Fatalf("CreateShim failed: %v", err)
+	}
+
+	var moved []string
+	move := func(oldApp, newApp string) error {
+		moved = append(moved, oldApp+"->"+newApp)
+		return nil
+	}
+	if err := m.RenameApp("old", "new", WithCredentialMove(move)); err != nil {
+		t.Fatalf("RenameApp failed: %v", err)
+	}
+
+	if len(moved) != 1 || moved[0] != "old->new" {
+		t.Errorf("expected credentials to be moved from old to new, got %v", moved)
+	}
+	if m.ShimExists("old") || !m.ShimExists("new") {
+		t.Error("expected the shim to be renamed")
+	}
+}
+
+func TestRenameAppRollsBackOnFailure(t *testing.T) {
+	m, err := NewManager(WithConfigDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(m.AppsDir(), "old", "spec.yaml")
+	if err := writeSpecCopy(specPath, []byte("openapi: 3.0.0")); err != nil {
+		t.Fatalf("failed to write spec copy: %v", err)
+	}
+	if err := m.SaveAppConfig(&AppConfig{Name: "old", SpecSource: specPath}); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+
+	failMove := func(string, string) error { return errors.New("keyring locked") }
+	err = m.RenameApp("old", "new", WithCredentialMove(failMove))
+	if err == nil || !strings.Contains(err.Error(), "keyring locked") {
+		t.Fatalf("expected credential move error, got %v", err)
+	}
+
+	if m.AppExists("new") {
+		t.Error("expected new app config to be removed")
+	}
+	config, err := m.GetAppConfig("old")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	if config.SpecSource != specPath {
+		t.Errorf("expected SpecSource %s, got %s", specPath, config.SpecSource)
+	}
+	if _, err := os.Stat(specPath); err != nil {
+		t.Errorf("expected spec copy to be moved back: %v", err)
+	}
+}
@@ -5,7 +5,9 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -98,16 +100,23 @@ type ProfileExportV2 struct {
 
 // ExportedProfile is a cleaned profile structure for export.
 type ExportedProfile struct {
-	Name         string            `yaml:"name" json:"name"`
-	BaseURL      string            `yaml:"base_url" json:"base_url"`
-	Description  string            `yaml:"description,omitempty" json:"description,omitempty"`
-	Auth         ExportedAuth      `yaml:"auth,omitempty" json:"auth,omitzero"`
-	Headers      map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
-	QueryParams  map[string]string `yaml:"query_params,omitempty" json:"query_params,omitempty"`
-	Timeout      string            `yaml:"timeout,omitempty" json:"timeout,omitempty"`
-	TLSConfig    *TLSConfig        `yaml:"tls,omitempty" json:"tls,omitempty"`
-	SafetyConfig *SafetyConfig     `yaml:"safety,omitempty" json:"safety,omitempty"`
-	RetryConfig  *RetryConfig      `yaml:"retry,omitempty" json:"retry,omitempty"`
+	Name                string            `yaml:"name" json:"name"`
+	BaseURL             string            `yaml:"base_url" json:"base_url"`
+	Description         string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Auth                ExportedAuth      `yaml:"auth,omitempty" json:"auth,omitzero"`
+	Headers             map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	QueryParams         map[string]string `yaml:"query_params,omitempty" json:"query_params,omitempty"`
+	Timeout             string            `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	ConnectTimeout      string            `yaml:"connect_timeout,omitempty" json:"connect_timeout,omitempty"`
+	FollowRedirects     *bool             `yaml:"follow_redirects,omitempty" json:"follow_redirects,omitempty"`
+	MaxRedirects        int               `yaml:"max_redirects,omitempty" json:"max_redirects,omitempty"`
+	TLSConfig           *TLSConfig        `yaml:"tls,omitempty" json:"tls,omitempty"`
+	SafetyConfig        *SafetyConfig     `yaml:"safety,omitempty" json:"safety,omitempty"`
+	RetryConfig         *RetryConfig      `yaml:"retry,omitempty" json:"retry,omitempty"`
+	RequestDeadline     string            `yaml:"request_deadline,omitempty" json:"request_deadline,omitempty"`
+	EnumCaseInsensitive bool              `yaml:"enum_case_insensitive,omitempty" json:"enum_case_insensitive,omitempty"`
+	Prefer              string            `yaml:"prefer,omitempty" json:"prefer,omitempty"`
+	DefaultParams       map[string]string `yaml:"default_params,omitempty" json:"default_params,omitempty"`
 }
 
 // ExportedAuth contains auth configuration for export (no credentials).
@@ -134,7 +143,7 @@ func copyHeadersForExport(profile Profile) map[string]string {
 	}
 	headers := make(map[string]string)
 	for k, v := range profile.Headers {
-		if !isCredentialHeader(k) {
+		if !IsSensitiveHeader(k) {
 			headers[k] = v
 		}
 	}
@@ -155,10 +164,29 @@ func copyQueryParamsForExport(profile Profile) map[string]string {
 	return params
 }
 
+// copyDefaultParamsForExport copies default parameters excluding credentials.
+func copyDefaultParamsForExport(profile Profile) map[string]string {
+	if len(profile.DefaultParams) == 0 {
+		return nil
+	}
+	params := make(map[string]string)
+	for k, v := range profile.DefaultParams {
+		if !isCredentialParam(k) {
+			params[k] = v
+		}
+	}
+	return params
+}
+
 // parseTimeout exports timeout string if duration is set.
 func parseTimeout(profile Profile) string {
-	if profile.Timeout.Duration > 0 {
-		return profile.Timeout.String()
+	return exportDuration(profile.Timeout)
+}
+
+// exportDuration exports a duration as a string if it is set.
+func exportDuration(d Duration) string {
+	if d.Duration > 0 {
+		return d.String()
 	}
 	return ""
 }
@@ -188,36 +216,48 @@ func cleanProfileForExport(profile Profile, opts ExportOptions) ExportedProfile
 			KeyName:  profile.Auth.KeyName,
 			Scheme:   profile.Auth.Scheme,
 		},
-		Headers:     copyHeadersForExport(profile),
-		QueryParams: copyQueryParamsForExport(profile),
-		Timeout:     parseTimeout(profile),
+		Headers:             copyHeadersForExport(profile),
+		QueryParams:         copyQueryParamsForExport(profile),
+		Timeout:             parseTimeout(profile),
+		ConnectTimeout:      exportDuration(profile.ConnectTimeout),
+		RequestDeadline:     exportDuration(profile.RequestDeadline),
+		FollowRedirects:     profile.FollowRedirects,
+		MaxRedirects:        profile.MaxRedirects,
+		EnumCaseInsensitive: profile.EnumCaseInsensitive,
+		Prefer:              profile.Prefer,
+		DefaultParams:       copyDefaultParamsForExport(profile),
 	}
 
 	exported.TLSConfig, exported.SafetyConfig, exported.RetryConfig = includeOptionalConfig(opts, profile)
 	return exported
 }
 
-// isCredentialHeader checks if a header name might contain credentials.
-func isCredentialHeader(name string) bool {
-	lower := strings.ToLower(name)
-	credentialHints := []string{
+// sensitiveHeaders are the headers that always carry credentials, and
+// sensitiveHeaderHints the name fragments of headers that may carry one.
+var (
+	sensitiveHeaders     = []string{"Cookie", "Set-Cookie"}
+	sensitiveHeaderHints = []string{
 		"authorization",
-		"x-api-key",
 		"x-auth",
-		"x-token",
 		"bearer",
+		"token",
 		"api-key",
 		"apikey",
 		"secret",
 		"password",
 		"credential",
+		"session",
 	}
-	for _, hint := range credentialHints {
-		if strings.Contains(lower, hint) {
-			return true
-		}
+)
+
+// IsSensitiveHeader reports whether a header may carry a secret, such as
+// Authorization, Cookie or X-API-Key.
+func IsSensitiveHeader(name string) bool {
+	if slices.Contains(sensitiveHeaders, http.CanonicalHeaderKey(name)) {
+		return true
 	}
-	return false
+	lower := strings.ToLower(name)
+	return slices.ContainsFunc(sensitiveHeaderHints, func(hint string) bool { return strings.Contains(lower, hint) })
 }
 
 // isCredentialParam checks if a query param name might contain credentials.
@@ -526,10 +566,16 @@ func convertLegacyToV2(legacy map[string]any) (*ProfileExportV2, error) {
 // convertExportedToProfile converts ExportedProfile to Profile.
 // parseTimeoutValue parses timeout from string to Duration.
 func parseTimeoutValue(exported ExportedProfile) Duration {
-	if exported.Timeout == "" {
+	return importDuration(exported.Timeout)
+}
+
+// importDuration parses an exported duration, or returns zero if it is unset
+// or invalid.
+func importDuration(value string) Duration {
+	if value == "" {
 		return Duration{}
 	}
-	if d, err := time.ParseDuration(exported.Timeout); err == nil {
+	if d, err := time.ParseDuration(value); err == nil {
 		return Duration{Duration: d}
 	}
 	return Duration{}
@@ -564,9 +610,16 @@ func convertExportedToProfile(exported ExportedProfile, name string) Profile {
 			KeyName:  exported.Auth.KeyName,
 			Scheme:   exported.Auth.Scheme,
 		},
-		Headers:     exported.Headers,
-		QueryParams: exported.QueryParams,
-		Timeout:     parseTimeoutValue(exported),
+		Headers:             exported.Headers,
+		QueryParams:         exported.QueryParams,
+		Timeout:             parseTimeoutValue(exported),
+		ConnectTimeout:      importDuration(exported.ConnectTimeout),
+		RequestDeadline:     importDuration(exported.RequestDeadline),
+		FollowRedirects:     exported.FollowRedirects,
+		MaxRedirects:        exported.MaxRedirects,
+		EnumCaseInsensitive: exported.EnumCaseInsensitive,
+		Prefer:              exported.Prefer,
+		DefaultParams:       exported.DefaultParams,
 	}
 
 	profile.TLSConfig, profile.SafetyConfig, profile.RetryConfig = copyOptionalConfigs(exported)
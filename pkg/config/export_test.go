@@ -571,18 +571,22 @@ profiles:
 	}
 }
 
-func TestIsCredentialHeader(t *testing.T) {
+func TestIsSensitiveHeader(t *testing.T) {
 	tests := []credentialTestCase{
 		{"Authorization", true},
+		{"proxy-authorization", true},
 		{"X-API-Key", true},
 		{"X-Auth-Token", true},
+		{"cookie", true},
+		{"Set-Cookie", true},
+		{"X-Session-Id", true},
 		{"X-Custom-Header", false},
 		{"Content-Type", false},
 		{"Accept", false},
 		{"X-Secret-Header", true},
 	}
 
-	runCredentialDetectorTests(t, "isCredentialHeader", tests, isCredentialHeader)
+	runCredentialDetectorTests(t, "IsSensitiveHeader", tests, IsSensitiveHeader)
 }
 
 func TestIsCredentialParam(t *testing.T) {
@@ -644,3 +648,41 @@ profile:
 		t.Errorf("expected timeout %v, got %v", expected, profile.Timeout.Duration)
 	}
 }
+
+func TestExportImportRoundTrip(t *testing.T) {
+	m, _ := setupTestApp(t)
+
+	config, _ := m.GetAppConfig("testapp")
+	profile := config.Profiles["default"]
+	profile.EnumCaseInsensitive = true
+	profile.Prefer = "return=minimal"
+	profile.DefaultParams = map[string]string{"limit": "100", "api_key": "secret"}
+	config.Profiles["default"] = profile
+	if err := m.SaveAppConfig(config); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+
+	data, err := m.ExportProfileWithOptions("testapp", "default", DefaultExportOptions())
+	if err != nil {
+		t.Fatalf("ExportProfileWithOptions failed: %v", err)
+	}
+	if err := m.ImportProfileWithOptions("testapp", data, ImportOptions{TargetProfileName: "copy"}); err != nil {
+		t.Fatalf("ImportProfileWithOptions failed: %v", err)
+	}
+
+	config, _ = m.GetAppConfig("testapp")
+	imported := config.Profiles["copy"]
+
+	if !imported.EnumCaseInsensitive {
+		t.Error("expected enum_case_insensitive to survive export and import")
+	}
+	if imported.Prefer != "return=minimal" {
+		t.Errorf("expected prefer 'return=minimal', got '%s'", imported.Prefer)
+	}
+	if imported.DefaultParams["limit"] != "100" {
+		t.Errorf("expected default param limit=100, got %v", imported.DefaultParams)
+	}
+	if _, ok := imported.DefaultParams["api_key"]; ok {
+		t.Error("expected credential default param to be excluded from export")
+	}
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GlobalConfigFile is the name of the global configuration file, stored in
+// the configuration directory next to the apps directory, e.g.
+// ~/.config/openbridge/config.yaml on Linux.
+const GlobalConfigFile = "config.yaml"
+
+// GlobalConfig holds settings shared by all installed apps.
+type GlobalConfig struct {
+	// Defaults are merged under every profile of every app. Settings made by
+	// the app's profile always win.
+	Defaults ProfileDefaults `yaml:"defaults,omitempty"`
+
+	// Credential selects where credentials are stored.
+	Credential CredentialBackendConfig `yaml:"credential,omitempty"`
+}
+
+// CredentialBackendConfig selects an external credential backend, such as a
+// secret manager, in place of the system keyring.
+type CredentialBackendConfig struct {
+	// Backend names the credential backend plugin, e.g. vault. Empty or
+	// keyring uses the system keyring, and file the encrypted file keyring.
+	Backend string `yaml:"backend,omitempty"`
+
+	// Settings configure the backend, e.g. its address. The path setting
+	// locates the plugin executable, and the dir setting the file keyring.
+	Settings map[string]string `yaml:"settings,omitempty"`
+}
+
+// ProfileDefaults are the profile settings that can be shared across apps.
+type ProfileDefaults struct {
+	// Headers are sent with every request unless the profile sets the same header.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// QueryParams are sent with every request unless the profile sets the same parameter.
+	QueryParams map[string]string `yaml:"query_params,omitempty"`
+
+	// TLSConfig fills in the TLS settings the profile leaves unset, e.g. a shared
+	// CA bundle. insecure_skip_verify is not shared: only a profile can turn off
+	// certificate verification for itself.
+	TLSConfig TLSConfig `yaml:"tls,omitempty"`
+
+	// Proxy is the HTTP proxy for profiles that don't set one.
+	Proxy string `yaml:"proxy,omitempty"`
+
+	// Timeout is the request timeout for profiles that don't set one.
+	Timeout Duration `yaml:"timeout,omitempty"`
+
+	// RetryConfig fills in the retry settings the profile leaves unset.
+	RetryConfig RetryConfig `yaml:"retry,omitempty"`
+}
+
+// GlobalConfigPath returns the path of the global configuration file.
+func (m *Manager) GlobalConfigPath() string {
+	return filepath.Join(m.configDir, GlobalConfigFile)
+}
+
+// GetGlobalConfig reads the global configuration file. A missing file yields
+// an empty configuration.
+func (m *Manager) GetGlobalConfig() (*GlobalConfig, error) {
+	path := m.GlobalConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &GlobalConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read global config '%s': %w", path, err)
+	}
+
+	var global GlobalConfig
+	if err := yaml.Unmarshal(data, &global); err != nil {
+		return nil, fmt.Errorf("failed to parse global config '%s': %w", path, err)
+	}
+	return &global, nil
+}
+
+// GetEffectiveAppConfig retrieves the configuration for an installed app with
+// the global defaults merged under each of its profiles. Use it when running
+// an app; the result should not be saved back, as that would copy the
+// defaults into the app's own configuration.
+func (m *Manager) GetEffectiveAppConfig(appName string) (*AppConfig, error) {
+	config, err := m.GetAppConfig(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	global, err := m.GetGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, profile := range config.Profiles {
+		global.Defaults.ApplyTo(&profile)
+		config.Profiles[name] = profile
+	}
+	return config, nil
+}
+
+// ApplyTo fills in the settings of profile that it leaves unset. Header names
+// are compared case-insensitively.
+func (d ProfileDefaults) ApplyTo(profile *Profile) {
+	for name, value := range d.Headers {
+		if hasHeader(profile.Headers, name) {
+			continue
+		}
+		if profile.Headers == nil {
+			profile.Headers = make(map[string]string)
+		}
+		profile.Headers[name] = value
+	}
+
+	for name, value := range d.QueryParams {
+		if _, ok := profile.QueryParams[name]; ok {
+			continue
+		}
+		if profile.QueryParams == nil {
+			profile.QueryParams = make(map[string]string)
+		}
+		profile.QueryParams[name] = value
+	}
+
+	applyTLSDefaults(&profile.TLSConfig, d.TLSConfig)
+	applyRetryDefaults(&profile.RetryConfig, d.RetryConfig)
+
+	if profile.Timeout.Duration == 0 {
+		profile.Timeout = d.Timeout
+	}
+	profile.Proxy = valueOrDefault(profile.Proxy, d.Proxy)
+}
+
+// hasHeader reports whether headers contains name, ignoring case.
+func hasHeader(headers map[string]string, name string) bool {
+	for existing := range headers {
+		if strings.EqualFold(existing, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTLSDefaults fills in the unset fields of cfg from defaults. The
+// profile's InsecureSkipVerify is kept as is.
+func applyTLSDefaults(cfg *TLSConfig, defaults TLSConfig) {
+	cfg.CAFile = valueOrDefault(cfg.CAFile, defaults.CAFile)
+	cfg.CertFile = valueOrDefault(cfg.CertFile, defaults.CertFile)
+	cfg.KeyFile = valueOrDefault(cfg.KeyFile, defaults.KeyFile)
+	cfg.ServerName = valueOrDefault(cfg.ServerName, defaults.ServerName)
+	cfg.MinVersion = valueOrDefault(cfg.MinVersion, defaults.MinVersion)
+}
+
+// applyRetryDefaults fills in the unset fields of cfg from defaults.
+func applyRetryDefaults(cfg *RetryConfig, defaults RetryConfig) {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaults.MaxRetries
+	}
+	if cfg.InitialDelay.Duration == 0 {
+		cfg.InitialDelay = defaults.InitialDelay
+	}
+	if cfg.MaxDelay.Duration == 0 {
+		cfg.MaxDelay = defaults.MaxDelay
+	}
+	if len(cfg.RetryableStatusCodes) == 0 {
+		cfg.RetryableStatusCodes = defaults.RetryableStatusCodes
+	}
+	if !cfg.RetryUnsafe {
+		cfg.RetryUnsafe = defaults.RetryUnsafe
+	}
+}
+
+// valueOrDefault returns value, or fallback when value is empty.
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
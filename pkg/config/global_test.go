@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+const testGlobalConfig = `defaults:
+  headers:
+    X-Org: acme
+    X-Client: ob
+  tls:
+    ca_file: /etc/ssl/acme-ca.pem
+    insecure_skip_verify: true
+  proxy: http://proxy.acme.internal:3128
+  timeout: 45s
+  retry:
+    max_retries: 3
+    retry_unsafe: true
+`
+
+func writeGlobalConfig(t *testing.T, m *Manager, content string) {
+	t.Helper()
+	if err := os.WriteFile(m.GlobalConfigPath(), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write global config: %v", err)
+	}
+}
+
+func TestGetEffectiveAppConfig_AppliesGlobalDefaults(t *testing.T) {
+	m, _ := setupTestApp(t)
+	writeGlobalConfig(t, m, testGlobalConfig)
+
+	config, err := m.GetEffectiveAppConfig("testapp")
+	if err != nil {
+		t.Fatalf("GetEffectiveAppConfig failed: %v", err)
+	}
+	profile, ok := config.GetDefaultProfile()
+	if !ok {
+		t.Fatal("expected a default profile")
+	}
+
+	if got := profile.Headers["X-Org"]; got != "acme" {
+		t.Errorf("expected global header X-Org=acme, got %q", got)
+	}
+	if profile.TLSConfig.CAFile != "/etc/ssl/acme-ca.pem" {
+		t.Errorf("expected global CA file, got %q", profile.TLSConfig.CAFile)
+	}
+	if profile.TLSConfig.InsecureSkipVerify {
+		t.Error("expected certificate verification to stay on unless the profile turns it off")
+	}
+	if profile.Proxy != "http://proxy.acme.internal:3128" {
+		t.Errorf("expected global proxy, got %q", profile.Proxy)
+	}
+	if profile.RetryConfig.MaxRetries != 3 {
+		t.Errorf("expected global max retries 3, got %d", profile.RetryConfig.MaxRetries)
+	}
+	if !profile.RetryConfig.RetryUnsafe {
+		t.Error("expected global retry_unsafe to apply")
+	}
+
+	// The defaults must not leak into the stored app configuration.
+	stored, err := m.GetAppConfig("testapp")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	storedProfile, _ := stored.GetDefaultProfile()
+	if _, ok := storedProfile.Headers["X-Org"]; ok {
+		t.Error("expected stored profile to be unchanged")
+	}
+}
+
+func TestGetEffectiveAppConfig_AppSettingsWin(t *testing.T) {
+	m, _ := setupTestApp(t)
+	writeGlobalConfig(t, m, testGlobalConfig)
+
+	config, err := m.GetAppConfig("testapp")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	profile, _ := config.GetDefaultProfile()
+	profile.Headers = map[string]string{"x-org": "umbrella"}
+	profile.Timeout = Duration{Duration: 5 * time.Second}
+	profile.Proxy = "http://proxy.umbrella.internal:8080"
+	config.Profiles[profile.Name] = *profile
+	if err := m.SaveAppConfig(config); err != nil {
+		t.Fatalf("SaveAppConfig failed: %v", err)
+	}
+
+	effective, err := m.GetEffectiveAppConfig("testapp")
+	if err != nil {
+		t.Fatalf("GetEffectiveAppConfig failed: %v", err)
+	}
+	got, _ := effective.GetDefaultProfile()
+
+	if len(got.Headers) != 2 || got.Headers["x-org"] != "umbrella" || got.Headers["X-Client"] != "ob" {
+		t.Errorf("expected app header to override the global one, got %v", got.Headers)
+	}
+	if got.Timeout.Duration != 5*time.Second {
+		t.Errorf("expected app timeout 5s, got %v", got.Timeout.Duration)
+	}
+	if got.Proxy != "http://proxy.umbrella.internal:8080" {
+		t.Errorf("expected app proxy to override the global one, got %q", got.Proxy)
+	}
+}
+
+func TestGetGlobalConfig(t *testing.T) {
+	m, err := NewManager(WithConfigDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	global, err := m.GetGlobalConfig()
+	if err != nil {
+		t.Fatalf("expected a missing global config to be allowed, got %v", err)
+	}
+	if len(global.Defaults.Headers) != 0 {
+		t.Errorf("expected empty defaults, got %+v", global.Defaults)
+	}
+
+	writeGlobalConfig(t, m, "defaults: [")
+	if _, err := m.GetGlobalConfig(); err == nil {
+		t.Error("expected an error for an invalid global config")
+	}
+}
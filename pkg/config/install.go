@@ -4,6 +4,9 @@ package config
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -23,9 +26,16 @@ import (
 // progressive disclosure is recommended.
 const DefaultProgressiveThreshold = 25
 
+// StdinSpecSource is the spec source that reads the specification from InstallOptions.Reader.
+const StdinSpecSource = "-"
+
 // InstallOptions contains options for app installation.
 type InstallOptions struct {
 	// SpecSource is the path or URL to the OpenAPI specification.
+	// StdinSpecSource reads the spec from Reader instead. Because stdin cannot be
+	// read again, a copy of the spec is persisted in the app's directory and the
+	// saved config points at that copy, so later reloads use it. Reinstall with
+	// --spec - to replace the copy.
 	SpecSource string
 
 	// SpecSources allows multiple spec sources for merged APIs.
@@ -61,7 +71,8 @@ type InstallOptions struct {
 	// Headers to include in the default profile.
 	Headers map[string]string
 
-	// AuthParams contains authentication credentials (token, username, password).
+	// AuthParams contains authentication credentials (token, username, password,
+	// or client_id, client_secret, token_url and scopes).
 	// These are returned by the interactive wizard but NOT saved to the config file.
 	// The caller is responsible for saving them to the keyring.
 	AuthParams map[string]string
@@ -103,6 +114,15 @@ type InstallOptions struct {
 	// when generating code. When true, credentials are replaced with placeholders
 	// like <YOUR_API_KEY>. Default is false (not protected).
 	ProtectSensitiveInfo bool
+
+	// VerbMappings overrides the default HTTP method to verb table, e.g.
+	// {"PATCH": "update"}. See semantic.DefaultMethodVerbs for the keys; callers
+	// validate it with semantic.ValidateMethodVerbs.
+	VerbMappings map[string]string
+
+	// OperationIDVerbs names commands after operationId prefixes such as
+	// "list" in listCustomers. See AppConfig.OperationIDVerbs.
+	OperationIDVerbs bool
 }
 
 // InstallResult contains the result of an app installation.
@@ -122,6 +142,12 @@ func mergeSpecInfo(opts InstallOptions, existing *AppConfig) InstallOptions {
 	if opts.Description == "" {
 		opts.Description = existing.Description
 	}
+	if opts.VerbMappings == nil {
+		opts.VerbMappings = existing.VerbMappings
+	}
+	if !opts.OperationIDVerbs {
+		opts.OperationIDVerbs = existing.OperationIDVerbs
+	}
 	return opts
 }
 
@@ -267,14 +293,16 @@ func createAppConfig(appName, specSource string, specSources []string, opts Inst
 	safetyConfig := buildSafetyConfig(opts)
 
 	config := &AppConfig{
-		Name:           appName,
-		SpecSource:     specSource,
-		SpecSources:    specSources,
-		Description:    opts.Description,
-		DefaultProfile: "default",
-		Version:        "1.0",
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		Name:             appName,
+		SpecSource:       specSource,
+		SpecSources:      specSources,
+		Description:      opts.Description,
+		DefaultProfile:   "default",
+		Version:          "1.0",
+		VerbMappings:     opts.VerbMappings,
+		OperationIDVerbs: opts.OperationIDVerbs,
+		CreatedAt:        now,
+		UpdatedAt:        now,
 		Profiles: map[string]Profile{
 			"default": {
 				Name:         "default",
@@ -354,6 +382,10 @@ func (m *Manager) InstallApp(appName string, opts InstallOptions) (*InstallResul
 
 	opts = setDefaultIO(opts)
 
+	if opts.Interactive && usesStdinSpec(opts) {
+		return nil, fmt.Errorf("interactive mode cannot be used when the spec is read from stdin")
+	}
+
 	if opts.Interactive {
 		opts, err = m.promptForMissingInfo(opts)
 		if err != nil {
@@ -368,19 +400,161 @@ func (m *Manager) InstallApp(appName string, opts InstallOptions) (*InstallResul
 	return m.doInstall(appName, opts)
 }
 
+// InstallPlan describes what InstallApp would do for a given set of options.
+type InstallPlan struct {
+	AppName    string
+	ConfigPath string
+	// ShimPath is where the shim would be created, or empty if no shim was requested.
+	ShimPath  string
+	Overwrite bool
+	Config    *AppConfig
+	SpecInfo  *spec.SpecInfo
+	Warnings  []string
+}
+
+// PlanInstall validates an installation without performing it. It loads and
+// validates the spec and computes the configuration and shim InstallApp would
+// create, but writes nothing to the config directory, shim directory, or keyring.
+// Interactive prompts are not used; missing information is reported as an error.
+func (m *Manager) PlanInstall(appName string, opts InstallOptions) (*InstallPlan, error) {
+	if err := validateAppName(appName); err != nil {
+		return nil, err
+	}
+
+	overwrite := m.AppExists(appName)
+	opts, err := m.handleExistingApp(appName, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.Interactive = false
+	opts = setDefaultIO(opts)
+
+	if opts.SpecSource == "" && len(opts.SpecSources) == 0 {
+		return nil, fmt.Errorf("spec source is required")
+	}
+
+	draft, err := m.buildInstallDraft(appName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &InstallPlan{
+		AppName:    appName,
+		ConfigPath: m.getAppConfigPath(appName),
+		Overwrite:  overwrite,
+		Config:     draft.config,
+		SpecInfo:   draft.specInfo,
+	}
+
+	if overwrite {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("app '%s' already exists and will be overwritten", appName))
+	}
+
+	if opts.CreateShim {
+		shimPath, err := shimPathFor(appName, opts.ShimDir)
+		if err != nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("cannot determine shim location: %v", err))
+		} else {
+			plan.ShimPath = shimPath
+			if !IsShimDirInPath(filepath.Dir(shimPath)) {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("shim directory %s is not in your PATH", filepath.Dir(shimPath)))
+			}
+		}
+	}
+
+	if schemes := spec.GetSecuritySchemes(draft.specDoc); len(schemes) > 0 && opts.AuthType == "" {
+		names := make([]string, len(schemes))
+		for i, scheme := range schemes {
+			names[i] = scheme.Name
+		}
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("spec declares security schemes (%s) but no auth type was given", strings.Join(names, ", ")))
+	}
+
+	return plan, nil
+}
+
 // doInstall performs the actual installation after validation.
 func (m *Manager) doInstall(appName string, opts InstallOptions) (*InstallResult, error) {
+	draft, err := m.buildInstallDraft(appName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if draft.stdinSpec != nil {
+		if err := m.removeSpecCopies(appName); err != nil {
+			return nil, fmt.Errorf("failed to replace persisted spec: %w", err)
+		}
+		if err := writeSpecCopy(draft.primarySource, draft.stdinSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.SaveAppConfig(draft.config); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+
+	// Cache the spec if it's from a remote URL or git repository
+	if isWebURL(draft.primarySource) || spec.IsGitSource(draft.primarySource) {
+		cacheManager := NewSpecCacheManager(m.AppsDir())
+		_, cacheErr := cacheManager.FetchWithCache(appName, draft.primarySource)
+		if cacheErr != nil {
+			// Warning but don't fail installation
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache spec: %v\n", cacheErr)
+		}
+	}
+
+	result := &InstallResult{
+		AppName:    appName,
+		ConfigPath: m.getAppConfigPath(appName),
+		SpecInfo:   draft.specInfo,
+	}
+
+	m.createShimIfRequested(opts, appName, result)
+	return result, nil
+}
+
+// installDraft is the configuration an installation would save, along with the loaded spec.
+type installDraft struct {
+	config        *AppConfig
+	specDoc       *openapi3.T
+	specInfo      *spec.SpecInfo
+	primarySource string
+	// stdinSpec is the spec read from stdin, to be persisted at primarySource.
+	stdinSpec []byte
+}
+
+// buildInstallDraft loads and validates the spec and builds the app configuration
+// that an installation would save. It does not write anything.
+func (m *Manager) buildInstallDraft(appName string, opts InstallOptions) (*installDraft, error) {
 	specSource, specSources, primarySource, err := prepareSpecSources(opts.SpecSource, opts.SpecSources)
 	if err != nil {
 		return nil, err
 	}
 
-	parser := spec.NewParser()
-	specDoc, err := parser.LoadSpec(primarySource)
+	parser := spec.NewParser(spec.WithEagerRefResolution())
+	var specDoc *openapi3.T
+	var stdinSpec []byte
+	if primarySource == StdinSpecSource {
+		stdinSpec, err = io.ReadAll(opts.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spec from stdin: %w", err)
+		}
+		specDoc, err = parser.LoadSpecFromReader(bytes.NewReader(stdinSpec))
+
+		// Point the config at the persisted copy instead of stdin.
+		primarySource = m.stdinSpecPath(appName, stdinSpec)
+		specSource, specSources = replaceStdinSource(specSource, specSources, primarySource)
+	} else {
+		specDoc, err = parser.LoadSpec(primarySource)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load spec: %w", err)
 	}
 
+	if err := spec.ValidateParameterAliases(specDoc); err != nil {
+		return nil, fmt.Errorf("invalid parameter aliases: %w", err)
+	}
+
 	baseURL, err := resolveBaseURL(opts, specDoc)
 	if err != nil {
 		return nil, err
@@ -400,37 +574,71 @@ func (m *Manager) doInstall(appName string, opts InstallOptions) (*InstallResult
 		config.OperationCount = specInfo.Operations
 	}
 
-	if err := m.SaveAppConfig(config); err != nil {
-		return nil, fmt.Errorf("failed to save config: %w", err)
+	return &installDraft{
+		config:        config,
+		specDoc:       specDoc,
+		specInfo:      specInfo,
+		primarySource: primarySource,
+		stdinSpec:     stdinSpec,
+	}, nil
+}
+
+// usesStdinSpec reports whether any spec source reads from stdin.
+func usesStdinSpec(opts InstallOptions) bool {
+	return opts.SpecSource == StdinSpecSource || slices.Contains(opts.SpecSources, StdinSpecSource)
+}
+
+// stdinSpecPath returns where a spec read from stdin is persisted for an app.
+func (m *Manager) stdinSpecPath(appName string, data []byte) string {
+	ext := "yaml"
+	if json.Valid(data) {
+		ext = "json"
 	}
+	return filepath.Join(m.AppsDir(), appName, "spec."+ext)
+}
 
-	// Cache the spec if it's from a remote URL
-	if isWebURL(primarySource) {
-		cacheManager := NewSpecCacheManager(m.AppsDir())
-		_, cacheErr := cacheManager.FetchWithCache(appName, primarySource)
-		if cacheErr != nil {
-			// Warning but don't fail installation
-			fmt.Fprintf(os.Stderr, "Warning: failed to cache spec: %v\n", cacheErr)
+// replaceStdinSource replaces stdin spec sources with the path of the persisted copy.
+func replaceStdinSource(specSource string, specSources []string, path string) (string, []string) {
+	if specSource == StdinSpecSource {
+		specSource = path
+	}
+	for i, source := range specSources {
+		if source == StdinSpecSource {
+			specSources[i] = path
 		}
 	}
+	return specSource, specSources
+}
 
-	result := &InstallResult{
-		AppName:    appName,
-		ConfigPath: m.getAppConfigPath(appName),
-		SpecInfo:   specInfo,
+// writeSpecCopy persists a spec read from stdin.
+func writeSpecCopy(path string, data []byte) error {
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create spec directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to persist spec: %w", err)
 	}
+	return nil
+}
 
-	m.createShimIfRequested(opts, appName, result)
-	return result, nil
+// removeSpecCopies removes any spec persisted from stdin for an app.
+func (m *Manager) removeSpecCopies(appName string) error {
+	for _, ext := range []string{"yaml", "json"} {
+		path := filepath.Join(m.AppsDir(), appName, "spec."+ext)
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
 }
 
-// normalizeSpecSource resolves local spec paths to absolute paths and preserves HTTP URLs.
+// normalizeSpecSource resolves local spec paths to absolute paths and preserves HTTP URLs and git sources.
 // It does not validate that the file exists; loading the spec fails later if the path is invalid.
 func normalizeSpecSource(source string) (string, error) {
-	if source == "" {
-		return "", nil
+	if source == "" || source == StdinSpecSource {
+		return source, nil
 	}
-	if isWebURL(source) {
+	if isWebURL(source) || spec.IsGitSource(source) {
 		return source, nil
 	}
 	absPath, err := filepath.Abs(source)
@@ -440,7 +648,7 @@ func normalizeSpecSource(source string) (string, error) {
 	return absPath, nil
 }
 
-// normalizeSpecSources resolves local spec paths to absolute paths and preserves HTTP URLs.
+// normalizeSpecSources resolves local spec paths to absolute paths and preserves HTTP URLs and git sources.
 func normalizeSpecSources(sources []string) ([]string, error) {
 	normalized := make([]string, 0, len(sources))
 	for _, source := range sources {
@@ -515,7 +723,7 @@ func (m *Manager) promptForMissingInfo(opts InstallOptions) (InstallOptions, err
 
 	if opts.AuthType == "" {
 		authType, err := promptChoice(reader, writer, "Authentication type",
-			[]string{"none", "bearer", "api_key", "basic"}, "none")
+			[]string{"none", "bearer", "api_key", "basic", "oauth2_client_credentials"}, "none")
 		if err != nil {
 			return opts, err
 		}
@@ -556,9 +764,124 @@ func (m *Manager) UninstallApp(appName string, removeShim bool) error {
 		return fmt.Errorf("failed to delete config: %w", err)
 	}
 
+	if err := m.removeSpecCopies(appName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove persisted spec: %v\n", err)
+	}
+
+	return nil
+}
+
+// RenameOption configures Manager.RenameApp.
+type RenameOption func(*renameOptions)
+
+// renameOptions holds the settings applied by RenameOptions.
+type renameOptions struct {
+	moveCredentials func(oldApp, newApp string) error
+}
+
+// WithCredentialMove makes RenameApp move the app's stored credentials with
+// moveCredentials, e.g. credential.Manager.MoveCredentials. Credentials are
+// keyed by the app name, so without it they are left behind and requests made
+// under the new name are sent without them.
+func WithCredentialMove(moveCredentials func(oldApp, newApp string) error) RenameOption {
+	return func(o *renameOptions) {
+		o.moveCredentials = moveCredentials
+	}
+}
+
+// RenameApp renames an installed application, moving its configuration, any
+// cached or persisted spec files and its shim. If a step fails, the steps
+// already taken are undone.
+func (m *Manager) RenameApp(oldName, newName string, opts ...RenameOption) error {
+	if err := validateAppName(oldName); err != nil {
+		return err
+	}
+	if err := validateAppName(newName); err != nil {
+		return err
+	}
+	if m.AppExists(newName) {
+		return fmt.Errorf("app '%s' already exists", newName)
+	}
+
+	var options renameOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	config, err := m.GetAppConfig(oldName)
+	if err != nil {
+		return err
+	}
+
+	oldDir := filepath.Join(m.AppsDir(), oldName)
+	newDir := filepath.Join(m.AppsDir(), newName)
+	undoMove := func() {}
+	if _, err := os.Stat(oldDir); err == nil {
+		if err := os.Rename(oldDir, newDir); err != nil {
+			return fmt.Errorf("failed to move app data: %w", err)
+		}
+		undoMove = func() { _ = os.Rename(newDir, oldDir) }
+	}
+
+	config.Name = newName
+	config.SpecSource = movedPath(config.SpecSource, oldDir, newDir)
+	for i, source := range config.SpecSources {
+		config.SpecSources[i] = movedPath(source, oldDir, newDir)
+	}
+
+	if err := m.writeAppConfig(config); err != nil {
+		undoMove()
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	undoConfig := func() {
+		_ = os.Remove(m.getAppConfigPath(newName))
+		undoMove()
+	}
+
+	if options.moveCredentials != nil {
+		if err := options.moveCredentials(oldName, newName); err != nil {
+			undoConfig()
+			return fmt.Errorf("failed to move credentials: %w", err)
+		}
+	}
+
+	if err := os.Remove(m.getAppConfigPath(oldName)); err != nil {
+		if options.moveCredentials != nil {
+			_ = options.moveCredentials(newName, oldName)
+		}
+		undoConfig()
+		return fmt.Errorf("failed to delete config: %w", err)
+	}
+
+	if m.ShimExists(oldName) {
+		m.moveShim(oldName, newName)
+	}
+
+	m.notifyHandlers(AppLifecycleEvent{AppName: newName, OldName: oldName, ChangeType: AppRenamed})
 	return nil
 }
 
+// moveShim replaces the shim of a renamed app with one for its new name. A
+// failure does not fail the rename, as with UninstallApp.
+func (m *Manager) moveShim(oldName, newName string) {
+	if _, err := m.CreateShim(newName, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create shim: %v\n", err)
+		return
+	}
+	if err := m.RemoveShim(oldName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove shim: %v\n", err)
+	}
+}
+
+// movedPath rewrites path if it is inside oldDir so that it points into newDir.
+func movedPath(path, oldDir, newDir string) string {
+	rel, err := filepath.Rel(oldDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(newDir, rel)
+}
+
 // CreateShim creates a shim script for the app in the specified directory.
 func (m *Manager) CreateShim(appName, shimDir string) (string, error) {
 	if shimDir == "" {
@@ -616,19 +939,11 @@ func (m *Manager) CreateShim(appName, shimDir string) (string, error) {
 
 // RemoveShim removes the shim script for an app.
 func (m *Manager) RemoveShim(appName string) error {
-	shimDir, err := getDefaultShimDir()
+	shimPath, err := shimPathFor(appName, "")
 	if err != nil {
 		return err
 	}
 
-	var shimPath string
-	switch runtime.GOOS {
-	case "windows":
-		shimPath = filepath.Join(shimDir, appName+".cmd")
-	default:
-		shimPath = filepath.Join(shimDir, appName)
-	}
-
 	if err := os.Remove(shimPath); err != nil {
 		if os.IsNotExist(err) {
 			return nil // Already removed
@@ -658,6 +973,23 @@ func (m *Manager) ShimExists(appName string) bool {
 	return err == nil
 }
 
+// shimPathFor returns the path of the shim for an app in shimDir,
+// using the default shim directory when shimDir is empty.
+func shimPathFor(appName, shimDir string) (string, error) {
+	if shimDir == "" {
+		var err error
+		shimDir, err = getDefaultShimDir()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(shimDir, appName+".cmd"), nil
+	}
+	return filepath.Join(shimDir, appName), nil
+}
+
 // getDefaultShimDir returns the default directory for shim scripts.
 func getDefaultShimDir() (string, error) {
 	if runtime.GOOS == "windows" {
@@ -752,6 +1084,41 @@ Then reload your shell:
 	}
 }
 
+// DetectShell returns the name of the user's shell, such as bash, zsh, fish, or
+// powershell, from $SHELL, or powershell on Windows when $SHELL is not set.
+func DetectShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return strings.TrimSuffix(filepath.Base(shell), ".exe")
+	}
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	return ""
+}
+
+// GetAliasInstructions returns instructions for defining a shell alias that
+// runs an app without a shim, e.g. alias petstore='ob run petstore', in the
+// syntax of the given shell.
+func GetAliasInstructions(appName, shell string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf(`To run '%s' without 'ob run', add this line to ~/.config/fish/config.fish:
+  alias %s 'ob run %s'`, appName, appName, appName)
+
+	case "powershell", "pwsh":
+		return fmt.Sprintf(`To run '%s' without 'ob run', add this line to your PowerShell profile ($PROFILE):
+  function %s { ob run %s @args }`, appName, appName, appName)
+
+	case "zsh":
+		return fmt.Sprintf(`To run '%s' without 'ob run', add this line to ~/.zshrc:
+  alias %s='ob run %s'`, appName, appName, appName)
+
+	default:
+		return fmt.Sprintf(`To run '%s' without 'ob run', add this line to your shell profile (~/.bashrc or ~/.profile):
+  alias %s='ob run %s'`, appName, appName, appName)
+	}
+}
+
 // getOBBinaryPath finds the path to the ob binary.
 func getOBBinaryPath() (string, error) {
 	// Try to find ob in PATH
@@ -877,7 +1244,7 @@ func (m *Manager) UpdateApp(appName string, opts InstallOptions) error {
 
 	// Reload spec to validate
 	if opts.SpecSource != "" || len(opts.SpecSources) > 0 {
-		parser := spec.NewParser()
+		parser := spec.NewParser(spec.WithEagerRefResolution())
 		specSource := config.SpecSource
 		if specSource == "" && len(config.SpecSources) > 0 {
 			specSource = config.SpecSources[0]
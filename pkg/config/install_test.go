@@ -7,6 +7,8 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/nomagicln/open-bridge/pkg/spec"
 )
 
 func TestInstallApp(t *testing.T) {
@@ -207,6 +209,216 @@ func TestInstallAppMissingSpecSource(t *testing.T) {
 	}
 }
 
+func TestInstallAppFromStdin(t *testing.T) {
+	tmpDir := t.TempDir()
+	m, err := NewManager(WithConfigDir(tmpDir))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Piped API
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+`
+
+	result, err := m.InstallApp("piped", InstallOptions{
+		SpecSource: StdinSpecSource,
+		Reader:     strings.NewReader(specContent),
+		Writer:     &bytes.Buffer{},
+	})
+	if err != nil {
+		t.Fatalf("InstallApp failed: %v", err)
+	}
+	if result.SpecInfo == nil || result.SpecInfo.Title != "Piped API" {
+		t.Errorf("unexpected spec info: %+v", result.SpecInfo)
+	}
+
+	cfg, err := m.GetAppConfig("piped")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	expectedPath := filepath.Join(m.AppsDir(), "piped", "spec.yaml")
+	if cfg.SpecSource != expectedPath {
+		t.Errorf("expected SpecSource %s, got %s", expectedPath, cfg.SpecSource)
+	}
+
+	// The persisted copy must reload without stdin.
+	doc, err := spec.NewParser().LoadSpec(cfg.SpecSource)
+	if err != nil {
+		t.Fatalf("failed to reload persisted spec: %v", err)
+	}
+	if doc.Info.Title != "Piped API" {
+		t.Errorf("expected title 'Piped API', got '%s'", doc.Info.Title)
+	}
+
+	if err := m.UninstallApp("piped", false); err != nil {
+		t.Fatalf("UninstallApp failed: %v", err)
+	}
+	if _, err := os.Stat(expectedPath); !os.IsNotExist(err) {
+		t.Errorf("expected persisted spec to be removed, got %v", err)
+	}
+}
+
+func TestInstallAppFromStdinRejectsInteractive(t *testing.T) {
+	m, err := NewManager(WithConfigDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	_, err = m.InstallApp("piped", InstallOptions{
+		SpecSource:  StdinSpecSource,
+		Interactive: true,
+		Reader:      strings.NewReader(""),
+		Writer:      &bytes.Buffer{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "stdin") {
+		t.Errorf("expected stdin error, got %v", err)
+	}
+}
+
+func TestInstallAppRejectsConflictingAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+	m, err := NewManager(WithConfigDir(filepath.Join(tmpDir, "config")))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Issues API
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com
+paths:
+  /issues/{issue_number}:
+    get:
+      parameters:
+        - name: issue_number
+          in: path
+          required: true
+          x-ob-alias: issue
+          schema:
+            type: integer
+        - name: issue_id
+          in: query
+          x-ob-alias: issue
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`
+	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	_, err = m.InstallApp("issues", InstallOptions{SpecSource: specPath})
+	if err == nil || !strings.Contains(err.Error(), "alias 'issue'") {
+		t.Errorf("expected alias conflict error, got %v", err)
+	}
+	if m.AppExists("issues") {
+		t.Error("expected app not to be installed")
+	}
+}
+
+func TestPlanInstall(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	m, err := NewManager(WithConfigDir(configDir))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+`
+	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	shimDir := filepath.Join(tmpDir, "bin")
+	plan, err := m.PlanInstall("testapi", InstallOptions{
+		SpecSource: specPath,
+		CreateShim: true,
+		ShimDir:    shimDir,
+	})
+	if err != nil {
+		t.Fatalf("PlanInstall failed: %v", err)
+	}
+
+	if plan.Config.Profiles["default"].BaseURL != "https://api.example.com" {
+		t.Errorf("expected planned base URL from spec, got %q", plan.Config.Profiles["default"].BaseURL)
+	}
+	if plan.ShimPath == "" || filepath.Dir(plan.ShimPath) != shimDir {
+		t.Errorf("expected planned shim in %s, got %q", shimDir, plan.ShimPath)
+	}
+	if !strings.Contains(strings.Join(plan.Warnings, "\n"), "bearerAuth") {
+		t.Errorf("expected warning about unconfigured security scheme, got %v", plan.Warnings)
+	}
+
+	// Nothing may be written.
+	if m.AppExists("testapi") {
+		t.Error("expected app not to be installed by PlanInstall")
+	}
+	if _, err := os.Stat(plan.ConfigPath); !os.IsNotExist(err) {
+		t.Errorf("expected no config file at %s, got err=%v", plan.ConfigPath, err)
+	}
+	if _, err := os.Stat(shimDir); !os.IsNotExist(err) {
+		t.Errorf("expected shim directory not to be created, got err=%v", err)
+	}
+}
+
+func TestPlanInstallInvalidSpec(t *testing.T) {
+	tmpDir := t.TempDir()
+	m, err := NewManager(WithConfigDir(tmpDir))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	if err := os.WriteFile(specPath, []byte("openapi: \"3.0.0\"\ninfo: {}\npaths: {}\n"), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	if _, err := m.PlanInstall("testapi", InstallOptions{SpecSource: specPath, BaseURL: "https://api.example.com"}); err == nil {
+		t.Error("expected validation error for invalid spec")
+	}
+	if m.AppExists("testapi") {
+		t.Error("expected app not to be installed")
+	}
+}
+
 func TestInstallAppInteractive(t *testing.T) {
 	tmpDir := t.TempDir()
 	m, err := NewManager(WithConfigDir(tmpDir))
@@ -684,6 +896,44 @@ func TestGetPathInstructions(t *testing.T) {
 	}
 }
 
+func TestGetAliasInstructions(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", "alias petstore='ob run petstore'"},
+		{"zsh", "alias petstore='ob run petstore'"},
+		{"", "alias petstore='ob run petstore'"},
+		{"fish", "alias petstore 'ob run petstore'"},
+		{"powershell", "function petstore { ob run petstore @args }"},
+		{"pwsh", "function petstore { ob run petstore @args }"},
+	}
+	for _, tt := range tests {
+		if got := GetAliasInstructions("petstore", tt.shell); !strings.Contains(got, tt.want) {
+			t.Errorf("GetAliasInstructions(%q) = %q, want it to contain %q", tt.shell, got, tt.want)
+		}
+	}
+	if got := GetAliasInstructions("petstore", "zsh"); !strings.Contains(got, "~/.zshrc") {
+		t.Errorf("expected zsh instructions to mention ~/.zshrc, got %q", got)
+	}
+}
+
+func TestDetectShell(t *testing.T) {
+	t.Setenv("SHELL", "/usr/bin/zsh")
+	if got := DetectShell(); got != "zsh" {
+		t.Errorf("expected zsh, got %q", got)
+	}
+
+	t.Setenv("SHELL", "")
+	want := ""
+	if runtime.GOOS == "windows" {
+		want = "powershell"
+	}
+	if got := DetectShell(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
 func TestIsShimDirInPath(t *testing.T) {
 	// Test with a directory that's definitely not in PATH
 	notInPath := "/definitely/not/in/path/xyz123"
@@ -839,3 +1089,52 @@ paths: {}
 		})
 	}
 }
+
+func TestInstallAppWithVerbMappings(t *testing.T) {
+	tmpDir := t.TempDir()
+	m, err := NewManager(WithConfigDir(filepath.Join(tmpDir, "config")))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Repos API
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com
+paths:
+  /repos/{repo}:
+    patch:
+      parameters:
+        - name: repo
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`
+	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	opts := InstallOptions{SpecSource: specPath, VerbMappings: map[string]string{"PATCH": "update"}, OperationIDVerbs: true}
+	if _, err := m.InstallApp("repos", opts); err != nil {
+		t.Fatalf("InstallApp failed: %v", err)
+	}
+
+	appConfig, err := m.GetAppConfig("repos")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	if appConfig.VerbMappings["PATCH"] != "update" {
+		t.Fatalf("expected verb mappings to be saved, got %v", appConfig.VerbMappings)
+	}
+	if !appConfig.OperationIDVerbs {
+		t.Error("expected operation_id_verbs to be saved")
+	}
+}
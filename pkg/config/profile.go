@@ -243,7 +243,7 @@ func (pm *ProfileManager) RenameProfile(oldName, newName string) error {
 	return pm.Save()
 }
 
-// copyProfileMaps copies header and query param maps from source to destination.
+// copyProfileMaps copies header, query param, and default param maps from source to destination.
 func copyProfileMaps(dest *Profile, source Profile) {
 	if source.Headers != nil {
 		dest.Headers = make(map[string]string)
@@ -253,6 +253,10 @@ func copyProfileMaps(dest *Profile, source Profile) {
 		dest.QueryParams = make(map[string]string)
 		maps.Copy(dest.QueryParams, source.QueryParams)
 	}
+	if source.DefaultParams != nil {
+		dest.DefaultParams = make(map[string]string)
+		maps.Copy(dest.DefaultParams, source.DefaultParams)
+	}
 }
 
 // checkCopyProfileExistence checks if source exists and destination name is valid.
@@ -281,14 +285,21 @@ func (pm *ProfileManager) CopyProfile(sourceName, destName string) error {
 	}
 
 	dest := Profile{
-		Name:         destName,
-		BaseURL:      source.BaseURL,
-		Description:  source.Description,
-		Auth:         source.Auth,
-		TLSConfig:    source.TLSConfig,
-		SafetyConfig: source.SafetyConfig,
-		Timeout:      source.Timeout,
-		RetryConfig:  source.RetryConfig,
+		Name:                destName,
+		BaseURL:             source.BaseURL,
+		Description:         source.Description,
+		Auth:                source.Auth,
+		TLSConfig:           source.TLSConfig,
+		SafetyConfig:        source.SafetyConfig,
+		Timeout:             source.Timeout,
+		ConnectTimeout:      source.ConnectTimeout,
+		Proxy:               source.Proxy,
+		FollowRedirects:     source.FollowRedirects,
+		MaxRedirects:        source.MaxRedirects,
+		RetryConfig:         source.RetryConfig,
+		RequestDeadline:     source.RequestDeadline,
+		EnumCaseInsensitive: source.EnumCaseInsensitive,
+		Prefer:              source.Prefer,
 	}
 
 	copyProfileMaps(&dest, source)
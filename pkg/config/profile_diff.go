@@ -0,0 +1,164 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProfileDifference is a setting whose value differs between two profiles.
+// Values of credential-like headers and query parameters are masked.
+type ProfileDifference struct {
+	Setting string `json:"setting" yaml:"setting"`
+	Left    string `json:"left" yaml:"left"`
+	Right   string `json:"right" yaml:"right"`
+}
+
+// profileSetting is a single named setting of a profile.
+type profileSetting struct {
+	name      string
+	value     string
+	sensitive bool
+}
+
+// DiffProfiles compares the base URL, auth, TLS, headers, query parameters,
+// timeout, and safety settings of two profiles. Differences are returned in a
+// stable order; unset values are reported as empty strings.
+func DiffProfiles(left, right *Profile) []ProfileDifference {
+	leftSettings := indexSettings(profileSettings(left))
+	rightSettings := indexSettings(profileSettings(right))
+
+	names := slices.Collect(maps.Keys(leftSettings))
+	for name := range rightSettings {
+		if _, ok := leftSettings[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, compareSettingNames)
+
+	var diffs []ProfileDifference
+	for _, name := range names {
+		l, r := leftSettings[name], rightSettings[name]
+		if l.value == r.value {
+			continue
+		}
+		diffs = append(diffs, ProfileDifference{
+			Setting: name,
+			Left:    displaySetting(l),
+			Right:   displaySetting(r),
+		})
+	}
+	return diffs
+}
+
+// settingOrder lists the setting groups in display order.
+var settingOrder = []string{"base_url", "description", "auth", "tls", "headers", "query_params", "timeout", "connect_timeout", "request_deadline", "follow_redirects", "max_redirects", "safety"}
+
+// compareSettingNames orders settings by group, then by name.
+func compareSettingNames(a, b string) int {
+	groupA, _, _ := strings.Cut(a, ".")
+	groupB, _, _ := strings.Cut(b, ".")
+	if diff := slices.Index(settingOrder, groupA) - slices.Index(settingOrder, groupB); diff != 0 {
+		return diff
+	}
+	return strings.Compare(a, b)
+}
+
+// indexSettings indexes settings by name, dropping unset ones.
+func indexSettings(settings []profileSetting) map[string]profileSetting {
+	index := make(map[string]profileSetting, len(settings))
+	for _, s := range settings {
+		if s.value != "" {
+			index[s.name] = s
+		}
+	}
+	return index
+}
+
+// displaySetting returns the value of a setting for display, masking secrets.
+func displaySetting(s profileSetting) string {
+	if s.sensitive && s.value != "" {
+		return maskSecret(s.value)
+	}
+	return s.value
+}
+
+// maskSecret masks a secret value, showing only its first and last two characters.
+func maskSecret(value string) string {
+	if len(value) <= 4 {
+		return "****"
+	}
+	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
+}
+
+// profileSettings flattens the comparable settings of a profile.
+func profileSettings(p *Profile) []profileSetting {
+	if p == nil {
+		return nil
+	}
+
+	settings := []profileSetting{
+		{name: "base_url", value: p.BaseURL},
+		{name: "description", value: p.Description},
+		{name: "auth.type", value: p.Auth.Type},
+		{name: "auth.location", value: p.Auth.Location},
+		{name: "auth.key_name", value: p.Auth.KeyName},
+		{name: "auth.scheme", value: p.Auth.Scheme},
+		{name: "tls.insecure_skip_verify", value: boolSetting(p.TLSConfig.InsecureSkipVerify)},
+		{name: "tls.ca_file", value: p.TLSConfig.CAFile},
+		{name: "tls.cert_file", value: p.TLSConfig.CertFile},
+		{name: "tls.key_file", value: p.TLSConfig.KeyFile},
+		{name: "tls.server_name", value: p.TLSConfig.ServerName},
+		{name: "tls.min_version", value: p.TLSConfig.MinVersion},
+		{name: "safety.read_only_mode", value: boolSetting(p.SafetyConfig.ReadOnlyMode)},
+		{name: "safety.allowed_operations", value: strings.Join(p.SafetyConfig.AllowedOperations, ", ")},
+		{name: "safety.denied_operations", value: strings.Join(p.SafetyConfig.DeniedOperations, ", ")},
+		{name: "safety.require_confirm", value: strings.Join(p.SafetyConfig.RequireConfirm, ", ")},
+		{name: "safety.max_requests_per_minute", value: intSetting(p.SafetyConfig.MaxRequestsPerMinute)},
+		{name: "safety.max_arguments_bytes", value: intSetting(p.SafetyConfig.MaxArgumentsBytes)},
+		{name: "safety.progressive_disclosure", value: boolSetting(p.SafetyConfig.ProgressiveDisclosure)},
+		{name: "safety.search_engine", value: p.SafetyConfig.SearchEngine},
+	}
+	if p.Timeout.Duration > 0 {
+		settings = append(settings, profileSetting{name: "timeout", value: p.Timeout.String()})
+	}
+	if p.ConnectTimeout.Duration > 0 {
+		settings = append(settings, profileSetting{name: "connect_timeout", value: p.ConnectTimeout.String()})
+	}
+	if p.RequestDeadline.Duration > 0 {
+		settings = append(settings, profileSetting{name: "request_deadline", value: p.RequestDeadline.String()})
+	}
+	if p.FollowRedirects != nil {
+		settings = append(settings, profileSetting{name: "follow_redirects", value: strconv.FormatBool(*p.FollowRedirects)})
+	}
+	if p.MaxRedirects > 0 {
+		settings = append(settings, profileSetting{name: "max_redirects", value: strconv.Itoa(p.MaxRedirects)})
+	}
+	for name, value := range p.Headers {
+		settings = append(settings, profileSetting{name: "headers." + name, value: value, sensitive: IsSensitiveHeader(name)})
+	}
+	for name, value := range p.QueryParams {
+		settings = append(settings, profileSetting{name: "query_params." + name, value: value, sensitive: isCredentialParam(name)})
+	}
+	for name, value := range p.DefaultParams {
+		settings = append(settings, profileSetting{name: "default_params." + name, value: value, sensitive: isCredentialParam(name)})
+	}
+	return settings
+}
+
+// boolSetting formats a boolean setting, leaving false unset.
+func boolSetting(value bool) string {
+	if !value {
+		return ""
+	}
+	return strconv.FormatBool(value)
+}
+
+// intSetting formats an integer setting, leaving zero unset.
+func intSetting(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
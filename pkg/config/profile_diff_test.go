@@ -0,0 +1,75 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffProfiles(t *testing.T) {
+	prod := &Profile{
+		Name:    "prod",
+		BaseURL: "https://api.example.com",
+		Auth:    AuthConfig{Type: "bearer"},
+		Headers: map[string]string{
+			"Authorization": "Bearer prod-secret-token",
+			"X-Client":      "ob",
+		},
+		SafetyConfig: SafetyConfig{ReadOnlyMode: true},
+	}
+	staging := &Profile{
+		Name:    "staging",
+		BaseURL: "https://staging.example.com",
+		Auth:    AuthConfig{Type: "api_key", Location: "header", KeyName: "X-API-Key"},
+		Headers: map[string]string{
+			"Authorization": "Bearer staging-token",
+			"X-Client":      "ob",
+		},
+		TLSConfig: TLSConfig{InsecureSkipVerify: true},
+	}
+
+	want := []ProfileDifference{
+		{Setting: "base_url", Left: "https://api.example.com", Right: "https://staging.example.com"},
+		{Setting: "auth.key_name", Left: "", Right: "X-API-Key"},
+		{Setting: "auth.location", Left: "", Right: "header"},
+		{Setting: "auth.type", Left: "bearer", Right: "api_key"},
+		{Setting: "tls.insecure_skip_verify", Left: "", Right: "true"},
+		{Setting: "headers.Authorization", Left: "Be********************en", Right: "Be****************en"},
+		{Setting: "safety.read_only_mode", Left: "true", Right: ""},
+	}
+
+	got := DiffProfiles(prod, staging)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffProfiles() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffProfiles_Identical(t *testing.T) {
+	left := &Profile{
+		Name:    "left",
+		BaseURL: "https://api.example.com",
+		Headers: map[string]string{"X-Client": "ob"},
+	}
+	right := &Profile{
+		Name:    "right",
+		BaseURL: "https://api.example.com",
+		Headers: map[string]string{"X-Client": "ob"},
+	}
+
+	if diffs := DiffProfiles(left, right); len(diffs) != 0 {
+		t.Errorf("expected no differences, got %+v", diffs)
+	}
+}
+
+func TestDiffProfiles_Redirects(t *testing.T) {
+	follow := false
+	left := &Profile{Name: "left", BaseURL: "https://api.example.com", FollowRedirects: &follow}
+	right := &Profile{Name: "right", BaseURL: "https://api.example.com", MaxRedirects: 3}
+
+	want := []ProfileDifference{
+		{Setting: "follow_redirects", Left: "false", Right: ""},
+		{Setting: "max_redirects", Left: "", Right: "3"},
+	}
+	if got := DiffProfiles(left, right); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffProfiles() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -3,6 +3,9 @@
 package config
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -17,6 +20,7 @@ import (
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 )
 
 // DefaultCacheTTL is the default time-to-live for cached specs when no HTTP cache headers are present.
@@ -64,6 +68,11 @@ type SpecCacheMeta struct {
 	// FetchAuthLocation is where api_key auth was sent: "header" or "query".
 	FetchAuthLocation string `json:"fetch_auth_location,omitempty"`
 
+	// FetchOptionsHash is a hash of all fetch options the spec was fetched
+	// with, credentials included, so that changing them invalidates the cache
+	// without storing the credentials themselves.
+	FetchOptionsHash string `json:"fetch_options_hash,omitempty"`
+
 	// ParsedSpecPath is the file path where the parsed spec is stored.
 	ParsedSpecPath string `json:"parsed_spec_path,omitempty"`
 
@@ -79,25 +88,9 @@ func (m *SpecCacheMeta) IsStale() bool {
 	return time.Now().After(m.ExpiresAt)
 }
 
-// SpecFetchOptions contains options for fetching remote specs.
-// This is imported from pkg/spec but redefined here to avoid circular imports.
-// The values are compatible and can be converted between the two types.
-type SpecFetchOptions struct {
-	// Headers contains custom HTTP headers to send with the fetch request.
-	Headers map[string]string
-
-	// AuthType is the authentication type: "bearer", "api_key", "basic", or empty for none.
-	AuthType string
-
-	// AuthToken is the authentication token or credential value.
-	AuthToken string
-
-	// AuthKeyName is the header or query parameter name for api_key auth.
-	AuthKeyName string
-
-	// AuthLocation is where to send api_key auth: "header" or "query".
-	AuthLocation string
-}
+// SpecFetchOptions contains options for fetching remote specs, shared with
+// pkg/spec so both fetch paths send the same headers and authentication.
+type SpecFetchOptions = spec.SpecFetchOptions
 
 // SpecCacheManager manages caching of OpenAPI specifications.
 type SpecCacheManager struct {
@@ -258,6 +251,9 @@ func (c *SpecCacheManager) FetchWithCache(appName, source string) (*FetchResult,
 // FetchWithCacheAndOptions fetches a spec with caching support and custom fetch options.
 // The provided options are merged with manager defaults (per-spec override takes precedence).
 func (c *SpecCacheManager) FetchWithCacheAndOptions(appName, source string, opts *SpecFetchOptions) (*FetchResult, error) {
+	if spec.IsGitSource(source) {
+		return c.fetchGitWithCache(appName, source, c.mergeFetchOptions(opts))
+	}
 	if !isWebURL(source) {
 		return c.fetchLocalFile(source)
 	}
@@ -266,51 +262,56 @@ func (c *SpecCacheManager) FetchWithCacheAndOptions(appName, source string, opts
 	return c.fetchRemoteWithCache(appName, source, effectiveOpts)
 }
 
-// copyHeaders creates a copy of a header map.
-func copyHeaders(headers map[string]string) map[string]string {
-	if headers == nil {
-		return nil
+// fetchGitWithCache fetches a spec from a git repository with caching. The
+// content is cached under a key made of the repository, ref and path, so that
+// pinning another ref or file fetches it again.
+func (c *SpecCacheManager) fetchGitWithCache(appName, source string, opts *SpecFetchOptions) (*FetchResult, error) {
+	src, err := spec.ParseGitSource(source)
+	if err != nil {
+		return nil, err
 	}
-	out := make(map[string]string, len(headers))
-	maps.Copy(out, headers)
-	return out
-}
+	key := src.CacheKey()
 
-// mergeFetchOptions merges manager defaults with per-spec overrides.
-func (c *SpecCacheManager) mergeFetchOptions(override *SpecFetchOptions) *SpecFetchOptions {
-	switch {
-	case c.fetchOptions == nil && override == nil:
-		return nil
-	case c.fetchOptions == nil:
-		return override
-	case override == nil:
-		return c.fetchOptions
-	}
-
-	// Start with defaults
-	merged := &SpecFetchOptions{
-		AuthType:     c.fetchOptions.AuthType,
-		AuthToken:    c.fetchOptions.AuthToken,
-		AuthKeyName:  c.fetchOptions.AuthKeyName,
-		AuthLocation: c.fetchOptions.AuthLocation,
-		Headers:      copyHeaders(c.fetchOptions.Headers),
-	}
-
-	// Apply overrides (override takes precedence)
-	if override.AuthType != "" {
-		merged.AuthType = override.AuthType
-		merged.AuthToken = override.AuthToken
-		merged.AuthKeyName = override.AuthKeyName
-		merged.AuthLocation = override.AuthLocation
-	}
-	if override.Headers != nil {
-		if merged.Headers == nil {
-			merged.Headers = make(map[string]string)
+	meta, _ := c.LoadMeta(appName)
+	if meta != nil && meta.FetchOptionsHash != c.hashFetchOptions(opts) {
+		meta = nil
+	}
+	if result, ok := c.tryLoadValidCache(appName, key, meta); ok {
+		return result, nil
+	}
+
+	content, err := spec.FetchGitSpec(context.Background(), source, opts)
+	if err != nil {
+		if meta != nil && meta.SourceURL != key {
+			meta = nil
 		}
-		maps.Copy(merged.Headers, override.Headers)
+		return c.tryUseStaleCache(appName, meta, err)
+	}
+
+	format := detectFormat("", src.Path)
+	result := &FetchResult{
+		Content: content,
+		Format:  format,
+		Meta: &SpecCacheMeta{
+			SourceURL:   key,
+			Format:      format,
+			FetchedAt:   time.Now(),
+			ExpiresAt:   time.Now().Add(DefaultCacheTTL),
+			ContentHash: computeHash(content),
+			Size:        int64(len(content)),
+		},
+	}
+	c.persistFetchOptions(result.Meta, opts)
+
+	if err := c.saveToCache(appName, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache spec: %v\n", err)
 	}
+	return result, nil
+}
 
-	return merged
+// mergeFetchOptions merges manager defaults with per-spec overrides.
+func (c *SpecCacheManager) mergeFetchOptions(override *SpecFetchOptions) *SpecFetchOptions {
+	return c.fetchOptions.Merge(override)
 }
 
 // fetchLocalFile reads a local spec file.
@@ -385,9 +386,14 @@ func (c *SpecCacheManager) handleNotModified(result *FetchResult, appName string
 	return c.SaveMeta(appName, result.Meta)
 }
 
-// fetchRemoteWithCache fetches a remote spec with caching.
+// fetchRemoteWithCache fetches a remote spec with caching. A spec cached with
+// other fetch options is ignored, since other credentials or headers may be
+// served another document.
 func (c *SpecCacheManager) fetchRemoteWithCache(appName, url string, opts *SpecFetchOptions) (*FetchResult, error) {
 	meta, _ := c.LoadMeta(appName)
+	if meta != nil && meta.FetchOptionsHash != c.hashFetchOptions(opts) {
+		meta = nil
+	}
 
 	// Try to use valid cache first
 	if result, ok := c.tryLoadValidCache(appName, url, meta); ok {
@@ -415,8 +421,9 @@ func (c *SpecCacheManager) fetchRemoteWithCache(appName, url string, opts *SpecF
 	return result, nil
 }
 
-// persistFetchOptions saves non-sensitive fetch options to metadata.
-func persistFetchOptions(meta *SpecCacheMeta, opts *SpecFetchOptions) {
+// persistFetchOptions saves non-sensitive fetch options to metadata, along
+// with a keyed hash of all of them.
+func (c *SpecCacheManager) persistFetchOptions(meta *SpecCacheMeta, opts *SpecFetchOptions) {
 	if opts == nil {
 		return
 	}
@@ -427,10 +434,59 @@ func persistFetchOptions(meta *SpecCacheMeta, opts *SpecFetchOptions) {
 	meta.FetchAuthType = opts.AuthType
 	meta.FetchAuthKeyName = opts.AuthKeyName
 	meta.FetchAuthLocation = opts.AuthLocation
+	meta.FetchOptionsHash = c.hashFetchOptions(opts)
+}
+
+// hashFetchOptions returns the HMAC-SHA256 of fetch options keyed with the
+// cache's local secret, or an empty string when there are none, as for specs
+// cached before the hash was kept. The options include credentials, so a
+// plain hash in the metadata could be brute-forced offline.
+func (c *SpecCacheManager) hashFetchOptions(opts *SpecFetchOptions) string {
+	if opts == nil || (len(opts.Headers) == 0 && opts.AuthType == "" && opts.AuthToken == "" &&
+		opts.AuthKeyName == "" && opts.AuthLocation == "" && opts.AuthUsername == "" && opts.AuthPassword == "") {
+		return ""
+	}
+	// Maps are encoded with sorted keys, so equal options hash the same.
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return ""
+	}
+	key, err := c.fetchOptionsKey()
+	if err != nil {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// fetchOptionsKeyFile is the file, in the cache base directory, holding the
+// secret that keys fetch option hashes.
+const fetchOptionsKeyFile = ".fetch-options.key"
+
+// fetchOptionsKey returns the secret that keys fetch option hashes, creating
+// it on first use. It is readable by the user only.
+func (c *SpecCacheManager) fetchOptionsKey() ([]byte, error) {
+	path := filepath.Join(c.baseDir, fetchOptionsKeyFile)
+	if key, err := os.ReadFile(path); err == nil && len(key) >= 32 {
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate fetch options key: %w", err)
+	}
+	if err := os.MkdirAll(c.baseDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write fetch options key: %w", err)
+	}
+	return key, nil
 }
 
 // buildMetaFromResponse creates SpecCacheMeta from HTTP response and content.
-func buildMetaFromResponse(resp *http.Response, url string, content []byte, opts *SpecFetchOptions) *SpecCacheMeta {
+func buildMetaFromResponse(resp *http.Response, url string, content []byte) *SpecCacheMeta {
 	meta := &SpecCacheMeta{
 		SourceURL:    url,
 		Format:       detectFormat(resp.Header.Get("Content-Type"), url),
@@ -441,7 +497,6 @@ func buildMetaFromResponse(resp *http.Response, url string, content []byte, opts
 		ContentHash:  computeHash(content),
 		Size:         int64(len(content)),
 	}
-	persistFetchOptions(meta, opts)
 	return meta
 }
 
@@ -502,7 +557,12 @@ func (c *SpecCacheManager) fetchRemote(url string, existingMeta *SpecCacheMeta,
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	meta := buildMetaFromResponse(resp, url, content, opts)
+	if err := spec.CheckHTMLResponse(url, resp.Header.Get("Content-Type"), content); err != nil {
+		return nil, err
+	}
+
+	meta := buildMetaFromResponse(resp, url, content)
+	c.persistFetchOptions(meta, opts)
 	return &FetchResult{Content: content, Format: meta.Format, Meta: meta, FromCache: false}, nil
 }
 
@@ -518,6 +578,12 @@ func applyFetchOptions(req *http.Request, opts *SpecFetchOptions) {
 	}
 
 	// Apply authentication
+	if opts.AuthType == "basic" {
+		if token := opts.BasicAuthToken(); token != "" {
+			req.Header.Set("Authorization", "Basic "+token)
+		}
+		return
+	}
 	if opts.AuthToken == "" {
 		return
 	}
@@ -525,8 +591,6 @@ func applyFetchOptions(req *http.Request, opts *SpecFetchOptions) {
 	switch opts.AuthType {
 	case "bearer":
 		req.Header.Set("Authorization", "Bearer "+opts.AuthToken)
-	case "basic":
-		req.Header.Set("Authorization", "Basic "+opts.AuthToken)
 	case "api_key":
 		keyName := opts.AuthKeyName
 		if keyName == "" {
@@ -590,7 +654,7 @@ func (c *SpecCacheManager) SaveMeta(appName string, meta *SpecCacheMeta) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(metaPath, data, 0644)
+	return os.WriteFile(metaPath, data, 0600)
 }
 
 // Clear removes all cached data for an app.
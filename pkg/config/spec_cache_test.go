@@ -1,13 +1,17 @@
 package config
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -535,6 +539,50 @@ func TestFetchWithCacheAndOptions_BasicAuth(t *testing.T) {
 	assert.Equal(t, "Basic dXNlcjpwYXNz", receivedHeaders.Get("Authorization"))
 }
 
+func TestFetchWithCacheAndOptions_BasicAuthUsernamePassword(t *testing.T) {
+	tmpDir, cleanup := createTestCacheDir(t)
+	defer cleanup()
+
+	var receivedHeaders http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Test","version":"1.0.0"},"paths":{}}`))
+	}))
+	defer server.Close()
+
+	manager := NewSpecCacheManager(tmpDir)
+	opts := &SpecFetchOptions{
+		AuthType:     "basic",
+		AuthToken:    "c3RhbGU6dG9rZW4=", // ignored when username/password are set
+		AuthUsername: "user",
+		AuthPassword: "pass",
+	}
+
+	_, err := manager.FetchWithCacheAndOptions("test-app", server.URL, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Basic dXNlcjpwYXNz", receivedHeaders.Get("Authorization"))
+}
+
+func TestFetchWithCache_HTMLResponse(t *testing.T) {
+	tmpDir, cleanup := createTestCacheDir(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body>Sign in</body></html>`))
+	}))
+	defer server.Close()
+
+	manager := NewSpecCacheManager(tmpDir)
+	_, err := manager.FetchWithCache("test-app", server.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, spec.ErrHTMLResponse)
+	assert.Contains(t, err.Error(), "authentication")
+}
+
 func TestFetchWithCacheAndOptions_MergeFetchOptions(t *testing.T) {
 	tmpDir, cleanup := createTestCacheDir(t)
 	defer cleanup()
@@ -622,6 +670,89 @@ func TestSpecCacheMeta_FetchOptionsFields(t *testing.T) {
 	assert.Equal(t, "header", meta.FetchAuthLocation)
 }
 
+func TestFetchWithCacheAndOptions_AuthChangeInvalidatesCache(t *testing.T) {
+	tmpDir, cleanup := createTestCacheDir(t)
+	defer cleanup()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		title := "Public"
+		if r.Header.Get("Authorization") == "Bearer admin-token" {
+			title = "Admin"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"` + title + `","version":"1.0.0"},"paths":{}}`))
+	}))
+	defer server.Close()
+
+	manager := NewSpecCacheManager(tmpDir)
+	userOpts := &SpecFetchOptions{AuthType: "bearer", AuthToken: "user-token"}
+
+	result, err := manager.FetchWithCacheAndOptions("test-app", server.URL, userOpts)
+	require.NoError(t, err)
+	assert.False(t, result.FromCache)
+	assert.Contains(t, string(result.Content), "Public")
+	require.NoError(t, manager.SaveParsedSpec("test-app", &openapi3.T{OpenAPI: "3.0.0"}))
+
+	// The same options are served from the cache.
+	result, err = manager.FetchWithCacheAndOptions("test-app", server.URL, &SpecFetchOptions{AuthType: "bearer", AuthToken: "user-token"})
+	require.NoError(t, err)
+	assert.True(t, result.FromCache)
+	assert.Equal(t, 1, requests)
+
+	meta, err := manager.LoadMeta("test-app")
+	require.NoError(t, err)
+	assert.NotEmpty(t, meta.FetchOptionsHash)
+	assert.NotContains(t, meta.FetchOptionsHash, "user-token")
+
+	// Other credentials fetch the spec again and drop the parsed spec.
+	result, err = manager.FetchWithCacheAndOptions("test-app", server.URL, &SpecFetchOptions{AuthType: "bearer", AuthToken: "admin-token"})
+	require.NoError(t, err)
+	assert.False(t, result.FromCache)
+	assert.Contains(t, string(result.Content), "Admin")
+	assert.Equal(t, 2, requests)
+
+	valid, err := manager.ValidateParsedSpec("test-app")
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	// So do other headers.
+	result, err = manager.FetchWithCacheAndOptions("test-app", server.URL, &SpecFetchOptions{
+		AuthType: "bearer", AuthToken: "admin-token", Headers: map[string]string{"X-Tenant": "acme"},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.FromCache)
+	assert.Equal(t, 3, requests)
+}
+
+func TestHashFetchOptions(t *testing.T) {
+	tmpDir, cleanup := createTestCacheDir(t)
+	defer cleanup()
+	hashFetchOptions := NewSpecCacheManager(tmpDir).hashFetchOptions
+
+	assert.Empty(t, hashFetchOptions(nil))
+	assert.Empty(t, hashFetchOptions(&SpecFetchOptions{Headers: map[string]string{}}))
+
+	opts := &SpecFetchOptions{Headers: map[string]string{"A": "1", "B": "2"}, AuthType: "basic", AuthUsername: "u", AuthPassword: "p"}
+	assert.Equal(t, hashFetchOptions(opts), hashFetchOptions(&SpecFetchOptions{Headers: map[string]string{"B": "2", "A": "1"}, AuthType: "basic", AuthUsername: "u", AuthPassword: "p"}))
+	assert.NotEqual(t, hashFetchOptions(opts), hashFetchOptions(&SpecFetchOptions{Headers: opts.Headers, AuthType: "basic", AuthUsername: "u", AuthPassword: "other"}))
+
+	// The hash is keyed with a secret of the cache, not a plain SHA-256.
+	data, err := json.Marshal(opts)
+	require.NoError(t, err)
+	assert.NotEqual(t, computeHash(data), hashFetchOptions(opts))
+
+	otherDir, otherCleanup := createTestCacheDir(t)
+	defer otherCleanup()
+	assert.NotEqual(t, hashFetchOptions(opts), NewSpecCacheManager(otherDir).hashFetchOptions(opts))
+
+	info, err := os.Stat(filepath.Join(tmpDir, fetchOptionsKeyFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
 func TestMergeFetchOptions(t *testing.T) {
 	tmpDir, cleanup := createTestCacheDir(t)
 	defer cleanup()
@@ -663,3 +794,53 @@ func TestMergeFetchOptions(t *testing.T) {
 		assert.Equal(t, "override", result.Headers["X-Override"])
 	})
 }
+
+func TestFetchWithCache_GitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	specContent := `{"openapi": "3.0.0", "info": {"title": "Git API", "version": "1.0.0"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "openapi.json"), []byte(specContent), 0644))
+	git("init", "--quiet", "--initial-branch=main")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "spec")
+	git("tag", "v1")
+
+	tmpDir, cleanup := createTestCacheDir(t)
+	defer cleanup()
+	manager := NewSpecCacheManager(tmpDir)
+	source := "git+file://" + filepath.ToSlash(repoDir) + "//openapi.json@v1"
+
+	result, err := manager.FetchWithCache("git-app", source)
+	require.NoError(t, err)
+	assert.False(t, result.FromCache)
+	assert.Equal(t, "json", result.Format)
+	assert.JSONEq(t, specContent, string(result.Content))
+
+	meta, err := manager.LoadMeta("git-app")
+	require.NoError(t, err)
+	assert.Equal(t, "file://"+filepath.ToSlash(repoDir)+"@v1//openapi.json", meta.SourceURL)
+
+	// Served from the cache without cloning the repository again
+	require.NoError(t, os.RemoveAll(repoDir))
+	result, err = manager.FetchWithCache("git-app", source)
+	require.NoError(t, err)
+	assert.True(t, result.FromCache)
+	assert.JSONEq(t, specContent, string(result.Content))
+
+	// Another ref is a different cache key, so the cached spec is not used for it
+	_, err = manager.FetchWithCache("git-app", "git+file://"+filepath.ToSlash(repoDir)+"//openapi.json@v2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// IssueSeverity is the severity of a ConfigIssue.
+type IssueSeverity string
+
+const (
+	// IssueError marks a problem that will make requests fail.
+	IssueError IssueSeverity = "error"
+
+	// IssueWarning marks a setting that is likely a mistake.
+	IssueWarning IssueSeverity = "warning"
+)
+
+// ConfigIssue is an inconsistency found in an app configuration.
+type ConfigIssue struct {
+	// Severity tells whether the issue is an error or a warning.
+	Severity IssueSeverity `json:"severity" yaml:"severity"`
+
+	// Profile is the profile the issue belongs to, empty for app-level issues.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// Field is the configuration key the issue is about, e.g. tls.ca_file.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+
+	// Message describes the issue.
+	Message string `json:"message" yaml:"message"`
+}
+
+// String formats the issue, without its severity, for display.
+func (i ConfigIssue) String() string {
+	var location []string
+	if i.Profile != "" {
+		location = append(location, "profile '"+i.Profile+"'")
+	}
+	if i.Field != "" {
+		location = append(location, i.Field)
+	}
+	if len(location) == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(location, " "), i.Message)
+}
+
+// HasErrors reports whether any of the issues is an error.
+func HasErrors(issues []ConfigIssue) bool {
+	return slices.ContainsFunc(issues, func(i ConfigIssue) bool { return i.Severity == IssueError })
+}
+
+// ValidateOption configures Manager.ValidateConfig.
+type ValidateOption func(*validateOptions)
+
+// validateOptions holds the settings applied by ValidateOptions.
+type validateOptions struct {
+	hasCredential func(appName, profileName string) bool
+}
+
+// WithCredentialLookup makes ValidateConfig report profiles whose auth type
+// needs a credential that hasCredential cannot find, e.g. in the keyring.
+func WithCredentialLookup(hasCredential func(appName, profileName string) bool) ValidateOption {
+	return func(o *validateOptions) {
+		o.hasCredential = hasCredential
+	}
+}
+
+// knownAuthTypes are the supported values of auth.type.
+var knownAuthTypes = []string{"", "none", "bearer", "api_key", "basic", "oauth2", "oauth2_client_credentials"}
+
+// ValidateConfig checks an installed app's configuration for inconsistencies
+// that loading alone does not catch, such as a default profile that does not
+// exist or TLS files that are missing. The global defaults are merged in
+// first, so settings such as a shared CA file are checked as the app will use
+// them. Unlike the package-level ValidateConfig, it reports every issue
+// instead of stopping at the first one.
+func (m *Manager) ValidateConfig(appName string, opts ...ValidateOption) []ConfigIssue {
+	config, err := m.GetEffectiveAppConfig(appName)
+	if err != nil {
+		return []ConfigIssue{{Severity: IssueError, Message: err.Error()}}
+	}
+	return ValidateAppConfig(config, opts...)
+}
+
+// ValidateAppConfig is like Manager.ValidateConfig for a configuration that
+// is already loaded, e.g. the result of GetEffectiveAppConfig.
+func ValidateAppConfig(config *AppConfig, opts ...ValidateOption) []ConfigIssue {
+	var options validateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var issues []ConfigIssue
+	if config.SpecSource == "" && len(config.SpecSources) == 0 {
+		issues = append(issues, ConfigIssue{Severity: IssueError, Field: "spec_source", Message: "no spec source is configured"})
+	}
+
+	if len(config.Profiles) == 0 {
+		issues = append(issues, ConfigIssue{Severity: IssueWarning, Field: "profiles", Message: "no profiles are configured"})
+	}
+	if config.DefaultProfile != "" {
+		if _, ok := config.Profiles[config.DefaultProfile]; !ok {
+			issues = append(issues, ConfigIssue{
+				Severity: IssueError,
+				Field:    "default_profile",
+				Message:  fmt.Sprintf("default profile '%s' does not exist", config.DefaultProfile),
+			})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Profiles)) {
+		issues = append(issues, validateProfile(config.Name, name, config.Profiles[name], options)...)
+	}
+	return issues
+}
+
+// validateProfile checks a single profile.
+func validateProfile(appName, name string, profile Profile, options validateOptions) []ConfigIssue {
+	var issues []ConfigIssue
+	add := func(severity IssueSeverity, field, format string, args ...any) {
+		issues = append(issues, ConfigIssue{Severity: severity, Profile: name, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if profile.BaseURL == "" {
+		add(IssueError, "base_url", "base URL is not set")
+	}
+
+	authType := profile.Auth.Type
+	switch {
+	case !slices.Contains(knownAuthTypes, authType):
+		add(IssueError, "auth.type", "unknown auth type '%s'", authType)
+	case authType != "" && authType != "none" && options.hasCredential != nil && !options.hasCredential(appName, name):
+		add(IssueWarning, "auth.type", "auth type is '%s' but no credential is stored", authType)
+	}
+
+	tls := profile.TLSConfig
+	if tls.CAFile != "" {
+		if _, err := ValidateTLSCACertFile(tls.CAFile); err != nil {
+			add(IssueError, "tls.ca_file", "%v", err)
+		}
+	}
+	if tls.CertFile != "" {
+		if _, err := ValidateTLSCertFile(tls.CertFile); err != nil {
+			add(IssueError, "tls.cert_file", "%v", err)
+		}
+	}
+	if tls.KeyFile != "" {
+		if _, err := ValidateTLSKeyFile(tls.KeyFile); err != nil {
+			add(IssueError, "tls.key_file", "%v", err)
+		}
+	}
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		add(IssueError, "tls", "client certificate and key must be provided together")
+	}
+	if tls.InsecureSkipVerify {
+		add(IssueWarning, "tls.insecure_skip_verify", "certificate verification is disabled")
+	}
+
+	return issues
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestManagerValidateConfig_MissingDefaultProfile(t *testing.T) {
+	m, _ := setupTestApp(t)
+
+	config, err := m.GetAppConfig("testapp")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	config.DefaultProfile = "staging"
+	if err := m.writeAppConfig(config); err != nil {
+		t.Fatalf("writeAppConfig failed: %v", err)
+	}
+
+	issues := m.ValidateConfig("testapp")
+	want := ConfigIssue{Severity: IssueError, Field: "default_profile", Message: "default profile 'staging' does not exist"}
+	found := false
+	for _, issue := range issues {
+		if issue == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected issue %+v, got %+v", want, issues)
+	}
+	if !HasErrors(issues) {
+		t.Error("expected the issues to contain an error")
+	}
+}
+
+func TestManagerValidateConfig_ProfileIssues(t *testing.T) {
+	m, _ := setupTestApp(t)
+
+	config, err := m.GetAppConfig("testapp")
+	if err != nil {
+		t.Fatalf("GetAppConfig failed: %v", err)
+	}
+	profile := config.Profiles[config.DefaultProfile]
+	profile.Auth.Type = "bearer"
+	profile.TLSConfig.CAFile = filepath.Join(t.TempDir(), "missing-ca.pem")
+	config.Profiles[config.DefaultProfile] = profile
+	if err := m.writeAppConfig(config); err != nil {
+		t.Fatalf("writeAppConfig failed: %v", err)
+	}
+
+	noCredentials := WithCredentialLookup(func(appName, profileName string) bool { return false })
+	issues := m.ValidateConfig("testapp", noCredentials)
+
+	fields := make(map[string]IssueSeverity)
+	for _, issue := range issues {
+		if issue.Profile != config.DefaultProfile {
+			t.Errorf("expected issue for profile %q, got %+v", config.DefaultProfile, issue)
+		}
+		fields[issue.Field] = issue.Severity
+	}
+	if fields["auth.type"] != IssueWarning {
+		t.Errorf("expected a missing credential warning, got %+v", issues)
+	}
+	if fields["tls.ca_file"] != IssueError {
+		t.Errorf("expected a missing CA file error, got %+v", issues)
+	}
+}
+
+func TestManagerValidateConfig_GlobalDefaults(t *testing.T) {
+	m, _ := setupTestApp(t)
+	caFile := filepath.Join(t.TempDir(), "missing-ca.pem")
+	writeGlobalConfig(t, m, "defaults:\n  tls:\n    ca_file: "+caFile+"\n")
+
+	issues := m.ValidateConfig("testapp")
+	if len(issues) != 1 || issues[0].Field != "tls.ca_file" || issues[0].Severity != IssueError {
+		t.Errorf("expected the global CA file to be checked, got %+v", issues)
+	}
+}
+
+func TestValidateAppConfig(t *testing.T) {
+	config := &AppConfig{
+		Name:           "testapp",
+		SpecSource:     "spec.yaml",
+		DefaultProfile: "default",
+		Profiles:       map[string]Profile{"default": {Name: "default"}},
+	}
+
+	issues := ValidateAppConfig(config)
+	if len(issues) != 1 || issues[0].Field != "base_url" || issues[0].Profile != "default" {
+		t.Errorf("expected a missing base URL error, got %+v", issues)
+	}
+}
+
+func TestManagerValidateConfig_Valid(t *testing.T) {
+	m, _ := setupTestApp(t)
+
+	if issues := m.ValidateConfig("testapp"); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+
+	issues := m.ValidateConfig("missing")
+	if len(issues) != 1 || issues[0].Severity != IssueError {
+		t.Errorf("expected a single error for a missing app, got %+v", issues)
+	}
+}
+
+func TestConfigIssueString(t *testing.T) {
+	issue := ConfigIssue{Severity: IssueError, Profile: "prod", Field: "base_url", Message: "base URL is not set"}
+	if got := issue.String(); got != "profile 'prod' base_url: base URL is not set" {
+		t.Errorf("unexpected string %q", got)
+	}
+}
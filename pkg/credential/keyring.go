@@ -2,7 +2,6 @@
 package credential
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -37,6 +36,11 @@ const (
 
 	// CredentialTypeOAuth2 represents OAuth2 credentials.
 	CredentialTypeOAuth2 CredentialType = "oauth2"
+
+	// CredentialTypeOAuth2ClientCredentials represents an OAuth2 client
+	// (client id and secret) that obtains access tokens with the client
+	// credentials grant.
+	CredentialTypeOAuth2ClientCredentials CredentialType = "oauth2_client_credentials"
 )
 
 // Credential represents stored authentication credentials.
@@ -59,6 +63,12 @@ type Credential struct {
 	TokenType    string    `json:"token_type,omitempty"`
 	ExpiresAt    time.Time `json:"expires_at,omitzero"`
 
+	// OAuth2 client credentials grant
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	TokenURL     string   `json:"token_url,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+
 	// Metadata
 	CreatedAt time.Time `json:"created_at,omitzero"`
 	UpdatedAt time.Time `json:"updated_at,omitzero"`
@@ -86,7 +96,7 @@ func (c *Credential) GetAuthValue() string {
 	switch c.Type {
 	case CredentialTypeBasic:
 		return c.Username + ":" + c.Password
-	case CredentialTypeOAuth2:
+	case CredentialTypeOAuth2, CredentialTypeOAuth2ClientCredentials:
 		return c.AccessToken
 	default:
 		// Bearer, APIKey, and other types use Token field
@@ -94,11 +104,20 @@ func (c *Credential) GetAuthValue() string {
 	}
 }
 
-// Manager handles credential storage and retrieval using the system keyring.
+// Manager handles credential storage and retrieval using the system keyring,
+// or an external CredentialProvider.
 type Manager struct {
+	provider CredentialProvider
+	// ring is the system keyring, or nil when an external provider is used.
 	ring        keyring.Keyring
 	backend     BackendType
 	initialized bool
+	// fileDir is the directory of the file keyring, or empty when an external
+	// provider is used.
+	fileDir string
+	// plugin and settings name the external backend plugin in use, if any.
+	plugin   string
+	settings map[string]string
 }
 
 // BackendType represents the keyring backend being used.
@@ -137,6 +156,9 @@ type managerConfig struct {
 	passPrefix      string
 	fileDir         string
 	filePasswordFn  func(string) (string, error)
+	provider        CredentialProvider
+	backend         string
+	backendSettings map[string]string
 }
 
 // WithAllowedBackends sets the allowed keyring backends.
@@ -210,6 +232,23 @@ func NewManager(opts ...ManagerOption) (*Manager, error) {
 		opt(cfg)
 	}
 
+	if cfg.provider == nil && cfg.backend != "" {
+		provider, err := NewPluginProvider(cfg.backend, cfg.backendSettings)
+		if err != nil {
+			return nil, err
+		}
+		cfg.provider = provider
+	}
+	if cfg.provider != nil {
+		return &Manager{
+			provider:    cfg.provider,
+			backend:     BackendType(cfg.provider.Name()),
+			initialized: true,
+			plugin:      cfg.backend,
+			settings:    cfg.backendSettings,
+		}, nil
+	}
+
 	fileDir, err := getFileDir(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get keyring directory: %w", err)
@@ -223,10 +262,16 @@ func NewManager(opts ...ManagerOption) (*Manager, error) {
 		return nil, fmt.Errorf("failed to open keyring: %w", err)
 	}
 
+	backend := detectBackend()
+	if len(cfg.allowedBackends) == 1 && cfg.allowedBackends[0] == keyring.FileBackend {
+		backend = BackendFile
+	}
 	return &Manager{
+		provider:    &keyringProvider{ring: ring, backend: backend},
 		ring:        ring,
-		backend:     detectBackend(),
+		backend:     backend,
 		initialized: true,
+		fileDir:     filepath.Clean(fileDir),
 	}, nil
 }
 
@@ -300,7 +345,8 @@ func detectBackend() BackendType {
 	}
 }
 
-// Backend returns the detected keyring backend type.
+// Backend returns the detected keyring backend type, or the name of the
+// external credential provider.
 func (m *Manager) Backend() BackendType {
 	return m.backend
 }
@@ -363,39 +409,12 @@ func (m *Manager) StoreCredential(appName, profileName string, cred *Credential)
 
 	updateTimestamps(cred)
 
-	data, err := json.Marshal(cred)
-	if err != nil {
-		return fmt.Errorf("failed to serialize credential: %w", err)
-	}
-
-	key := buildKey(appName, profileName)
-	item := createKeyringItem(key, appName, profileName, data)
-	err = m.ring.Set(item)
-	if err != nil {
-		return fmt.Errorf("failed to store credential: %w", err)
-	}
-
-	return nil
+	return m.provider.Store(appName, profileName, cred)
 }
 
 // GetCredential retrieves a credential from the keyring.
 func (m *Manager) GetCredential(appName, profileName string) (*Credential, error) {
-	key := buildKey(appName, profileName)
-
-	item, err := m.ring.Get(key)
-	if err != nil {
-		if errors.Is(err, keyring.ErrKeyNotFound) {
-			return nil, &CredentialNotFoundError{AppName: appName, ProfileName: profileName}
-		}
-		return nil, fmt.Errorf("failed to get credential: %w", err)
-	}
-
-	var cred Credential
-	if err := json.Unmarshal(item.Data, &cred); err != nil {
-		return nil, fmt.Errorf("failed to parse credential: %w", err)
-	}
-
-	return &cred, nil
+	return m.provider.Retrieve(appName, profileName)
 }
 
 // handleRemoveError handles errors from ring.Remove().
@@ -411,8 +430,7 @@ func handleRemoveError(err error) error {
 
 // DeleteCredential removes a credential from the keyring.
 func (m *Manager) DeleteCredential(appName, profileName string) error {
-	key := buildKey(appName, profileName)
-	return handleRemoveError(m.ring.Remove(key))
+	return m.provider.Delete(appName, profileName)
 }
 
 // getOrCreateCredential gets an existing credential or creates a new one.
@@ -447,20 +465,7 @@ func (m *Manager) UpdateCredential(appName, profileName string, updateFn func(*C
 
 // ListCredentials returns a list of all profile names with stored credentials for an app.
 func (m *Manager) ListCredentials(appName string) ([]string, error) {
-	keys, err := m.ring.Keys()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list credentials: %w", err)
-	}
-
-	var profiles []string
-	for _, key := range keys {
-		app, profile, ok := parseKey(key)
-		if ok && app == appName {
-			profiles = append(profiles, profile)
-		}
-	}
-
-	return profiles, nil
+	return m.provider.List(appName)
 }
 
 // ListAllCredentials returns all stored credentials.
@@ -477,6 +482,10 @@ func buildCredentialInfo(appName, profileName string, cred *Credential) Credenti
 }
 
 func (m *Manager) ListAllCredentials() ([]CredentialInfo, error) {
+	if m.ring == nil {
+		return nil, fmt.Errorf("listing all credentials is not supported by the %s backend", m.backend)
+	}
+
 	keys, err := m.ring.Keys()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list credentials: %w", err)
@@ -532,6 +541,37 @@ func (m *Manager) DeleteAllCredentials(appName string) error {
 	return nil
 }
 
+// MoveCredentials moves every credential stored for oldApp to newApp, keeping
+// the profile names, e.g. when an app is renamed. The old credentials are
+// deleted only once all of them have been stored under newApp.
+func (m *Manager) MoveCredentials(oldApp, newApp string) error {
+	profiles, err := m.ListCredentials(oldApp)
+	if err != nil {
+		return err
+	}
+
+	for i, profile := range profiles {
+		cred, err := m.GetCredential(oldApp, profile)
+		if err == nil {
+			err = m.provider.Store(newApp, profile, cred)
+		}
+		if err != nil {
+			for _, stored := range profiles[:i] {
+				_ = m.DeleteCredential(newApp, stored)
+			}
+			return fmt.Errorf("failed to move credential for profile '%s': %w", profile, err)
+		}
+	}
+
+	for _, profile := range profiles {
+		if err := m.DeleteCredential(oldApp, profile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // createCredentialCopy creates a copy of a credential with reset timestamps.
 func createCredentialCopy(cred *Credential) *Credential {
 	newCred := *cred
@@ -600,6 +640,17 @@ func NewOAuth2Credential(accessToken, refreshToken, tokenType string, expiresAt
 	}
 }
 
+// NewOAuth2ClientCredentials creates a new OAuth2 client credentials credential.
+func NewOAuth2ClientCredentials(clientID, clientSecret, tokenURL string, scopes []string) *Credential {
+	return &Credential{
+		Type:         CredentialTypeOAuth2ClientCredentials,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       scopes,
+	}
+}
+
 // getPlatformBackend returns platform-specific backend information.
 func getPlatformBackend(osName string) (bool, string, BackendType) {
 	switch osName {
@@ -233,6 +233,27 @@ func TestManagerCopyCredentialNotFound(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestManagerMoveCredentials(t *testing.T) {
+	mgr := setupTestManager(t)
+
+	require.NoError(t, mgr.StoreCredential("oldapp", "default", NewBearerCredential("default-token")))
+	require.NoError(t, mgr.StoreCredential("oldapp", "prod", NewBearerCredential("prod-token")))
+
+	require.NoError(t, mgr.MoveCredentials("oldapp", "newapp"))
+
+	moved, err := mgr.GetCredential("newapp", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-token", moved.Token)
+
+	profiles, err := mgr.ListCredentials("newapp")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"default", "prod"}, profiles)
+
+	profiles, err = mgr.ListCredentials("oldapp")
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+}
+
 func TestManagerStoreCredentialNil(t *testing.T) {
 	mgr := setupTestManager(t)
 
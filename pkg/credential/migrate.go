@@ -0,0 +1,98 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+)
+
+// ErrSameStore is returned when credentials would be migrated to the store
+// they are already in.
+var ErrSameStore = errors.New("source and destination are the same credential store")
+
+// MigrationResult is the outcome of migrating the credential of one app
+// profile. ProfileName is empty when the app's credentials could not be listed.
+type MigrationResult struct {
+	AppName     string
+	ProfileName string
+	Err         error
+}
+
+// SameStore reports whether m and other keep their credentials in the same
+// place: the same system keyring, file keyring directory, external provider,
+// or backend plugin with the same settings.
+func (m *Manager) SameStore(other *Manager) bool {
+	if m.provider == other.provider {
+		return true
+	}
+	if m.ring != nil && other.ring != nil {
+		if m.backend != other.backend {
+			return false
+		}
+		return m.backend != BackendFile || m.fileDir == other.fileDir
+	}
+	return m.plugin != "" && m.plugin == other.plugin && maps.Equal(m.settings, other.settings)
+}
+
+// CheckMigration reports whether the credentials of m can be migrated to
+// dest. Migrating to the same store fails with ErrSameStore, as the purge
+// would delete the credentials just stored, except that a file keyring can be
+// migrated to its own directory without purge: each credential is then
+// rewritten encrypted with the passphrase of dest.
+func (m *Manager) CheckMigration(dest *Manager, purge bool) error {
+	if !m.SameStore(dest) {
+		return nil
+	}
+	if m.backend == BackendFile && m.provider != dest.provider && !purge {
+		return nil
+	}
+	return ErrSameStore
+}
+
+// Migrate copies the stored credentials of the given apps to dest, keeping
+// their timestamps. With purge, each credential copied is then deleted from m.
+// It continues past failures and returns one result per credential, in app
+// and profile name order. A migration refused by CheckMigration returns a
+// single result with its error.
+func (m *Manager) Migrate(dest *Manager, appNames []string, purge bool) []MigrationResult {
+	if err := m.CheckMigration(dest, purge); err != nil {
+		return []MigrationResult{{Err: err}}
+	}
+
+	var results []MigrationResult
+	for _, appName := range appNames {
+		profiles, err := m.ListCredentials(appName)
+		if err != nil {
+			results = append(results, MigrationResult{AppName: appName, Err: err})
+			continue
+		}
+		sort.Strings(profiles)
+
+		for _, profileName := range profiles {
+			results = append(results, MigrationResult{
+				AppName:     appName,
+				ProfileName: profileName,
+				Err:         m.migrateCredential(dest, appName, profileName, purge),
+			})
+		}
+	}
+	return results
+}
+
+// migrateCredential copies the credential of one app profile to dest.
+func (m *Manager) migrateCredential(dest *Manager, appName, profileName string, purge bool) error {
+	cred, err := m.GetCredential(appName, profileName)
+	if err != nil {
+		return err
+	}
+	if err := dest.provider.Store(appName, profileName, cred); err != nil {
+		return err
+	}
+	if purge {
+		if err := m.DeleteCredential(appName, profileName); err != nil {
+			return fmt.Errorf("copied, but failed to delete the original: %w", err)
+		}
+	}
+	return nil
+}
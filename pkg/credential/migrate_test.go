@@ -0,0 +1,134 @@
+package credential
+
+import (
+	"testing"
+	"time"
+
+	"github.com/99designs/keyring"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerMigrate(t *testing.T) {
+	provider := newMockProvider()
+	src, err := NewManager(WithProvider(provider))
+	require.NoError(t, err)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	bearer := NewBearerCredential("prod-token")
+	bearer.CreatedAt, bearer.UpdatedAt = created, created
+	require.NoError(t, provider.Store("petstore", "prod", bearer))
+	require.NoError(t, src.StoreCredential("petstore", "staging", NewBasicCredential("user", "pass")))
+	require.NoError(t, src.StoreCredential("other", "default", NewAPIKeyCredential("key")))
+
+	t.Setenv(FilePassphraseEnv, "test-passphrase")
+	dest, err := NewManager(BackendOptions("file", map[string]string{"dir": t.TempDir()})...)
+	require.NoError(t, err)
+	require.Equal(t, BackendFile, dest.Backend())
+
+	results := src.Migrate(dest, []string{"petstore", "other", "empty"}, false)
+	require.Equal(t, []MigrationResult{
+		{AppName: "petstore", ProfileName: "prod"},
+		{AppName: "petstore", ProfileName: "staging"},
+		{AppName: "other", ProfileName: "default"},
+	}, results)
+
+	got, err := dest.GetCredential("petstore", "prod")
+	require.NoError(t, err)
+	require.Equal(t, "prod-token", got.Token)
+	require.True(t, got.CreatedAt.Equal(created))
+	require.True(t, got.UpdatedAt.Equal(created))
+
+	got, err = dest.GetCredential("petstore", "staging")
+	require.NoError(t, err)
+	require.Equal(t, "pass", got.Password)
+	require.True(t, src.HasCredential("petstore", "staging"), "the source is kept without purge")
+
+	src.Migrate(dest, []string{"petstore"}, true)
+	require.False(t, src.HasCredential("petstore", "prod"))
+	require.False(t, src.HasCredential("petstore", "staging"))
+	require.True(t, src.HasCredential("other", "default"))
+	require.True(t, dest.HasCredential("petstore", "prod"))
+}
+
+func TestManagerMigrate_SameStore(t *testing.T) {
+	t.Setenv(FilePassphraseEnv, "test-passphrase")
+	dir := t.TempDir()
+	src, err := NewManager(BackendOptions("file", map[string]string{"dir": dir})...)
+	require.NoError(t, err)
+	same, err := NewManager(BackendOptions("file", map[string]string{"dir": dir + "/"})...)
+	require.NoError(t, err)
+	other, err := NewManager(BackendOptions("file", map[string]string{"dir": t.TempDir()})...)
+	require.NoError(t, err)
+
+	require.True(t, src.SameStore(same))
+	require.False(t, src.SameStore(other))
+
+	require.NoError(t, src.StoreCredential("petstore", "default", NewBearerCredential("token")))
+	results := src.Migrate(same, []string{"petstore"}, true)
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err, ErrSameStore)
+	require.True(t, src.HasCredential("petstore", "default"), "nothing is purged")
+}
+
+func TestManagerMigrate_SystemKeyringToFile(t *testing.T) {
+	dir := t.TempDir()
+	ring := keyring.NewArrayKeyring(nil)
+	src := &Manager{
+		provider:    &keyringProvider{ring: ring, backend: BackendSecretService},
+		ring:        ring,
+		backend:     BackendSecretService,
+		initialized: true,
+		fileDir:     dir,
+	}
+	require.NoError(t, src.StoreCredential("petstore", "default", NewBearerCredential("token")))
+
+	t.Setenv(FilePassphraseEnv, "test-passphrase")
+	dest, err := NewManager(BackendOptions("file", map[string]string{"dir": dir})...)
+	require.NoError(t, err)
+
+	require.False(t, src.SameStore(dest), "the system keyring is not the file keyring in its default directory")
+	results := src.Migrate(dest, []string{"petstore"}, true)
+	require.Equal(t, []MigrationResult{{AppName: "petstore", ProfileName: "default"}}, results)
+	require.True(t, dest.HasCredential("petstore", "default"))
+	require.False(t, src.HasCredential("petstore", "default"))
+}
+
+func TestManagerMigrate_Rekey(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(FilePassphraseEnv, "old-passphrase")
+	src, err := NewManager(BackendOptions("file", map[string]string{"dir": dir})...)
+	require.NoError(t, err)
+	require.NoError(t, src.StoreCredential("petstore", "default", NewBearerCredential("token")))
+
+	t.Setenv(FileNewPassphraseEnv, "new-passphrase")
+	dest, err := NewManager(MigrationBackendOptions("file", map[string]string{"dir": dir})...)
+	require.NoError(t, err)
+	require.True(t, src.SameStore(dest))
+
+	require.ErrorIs(t, src.CheckMigration(dest, true), ErrSameStore, "purging would delete the re-encrypted credentials")
+	results := src.Migrate(dest, []string{"petstore"}, false)
+	require.Equal(t, []MigrationResult{{AppName: "petstore", ProfileName: "default"}}, results)
+
+	t.Setenv(FilePassphraseEnv, "new-passphrase")
+	rekeyed, err := NewManager(BackendOptions("file", map[string]string{"dir": dir})...)
+	require.NoError(t, err)
+	got, err := rekeyed.GetCredential("petstore", "default")
+	require.NoError(t, err)
+	require.Equal(t, "token", got.Token)
+
+	_, err = src.GetCredential("petstore", "default")
+	require.Error(t, err, "the old passphrase no longer decrypts the credential")
+}
+
+func TestBackendOptions(t *testing.T) {
+	require.Empty(t, BackendOptions("", nil))
+	require.Empty(t, BackendOptions("keyring", nil))
+	require.Len(t, BackendOptions("file", nil), 2)
+
+	cfg := &managerConfig{}
+	for _, opt := range BackendOptions("vault", map[string]string{"addr": "https://vault"}) {
+		opt(cfg)
+	}
+	require.Equal(t, "vault", cfg.backend)
+	require.Equal(t, "https://vault", cfg.backendSettings["addr"])
+}
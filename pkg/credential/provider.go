@@ -0,0 +1,293 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/99designs/keyring"
+)
+
+// CredentialProvider is a credential store. The system keyring is the default;
+// external providers, such as a secret manager, fetch credentials from their
+// store on every lookup and never persist them locally.
+type CredentialProvider interface {
+	// Name identifies the provider, e.g. vault. Manager.Backend reports it.
+	Name() string
+
+	// Store saves the credential of an app profile.
+	Store(appName, profileName string, cred *Credential) error
+
+	// Retrieve returns the credential of an app profile, or a
+	// *CredentialNotFoundError if none is stored.
+	Retrieve(appName, profileName string) (*Credential, error)
+
+	// Delete removes the credential of an app profile. Deleting a credential
+	// that does not exist is not an error.
+	Delete(appName, profileName string) error
+
+	// List returns the names of the profiles of an app that have a credential.
+	List(appName string) ([]string, error)
+}
+
+// WithProvider makes the manager use provider instead of the system keyring.
+func WithProvider(provider CredentialProvider) ManagerOption {
+	return func(c *managerConfig) {
+		c.provider = provider
+	}
+}
+
+// WithBackend makes the manager use the named external credential backend,
+// configured by settings, instead of the system keyring. See NewPluginProvider.
+func WithBackend(name string, settings map[string]string) ManagerOption {
+	return func(c *managerConfig) {
+		c.backend = name
+		c.backendSettings = settings
+	}
+}
+
+// FilePassphraseEnv is the environment variable holding the passphrase of the
+// encrypted file backend selected by BackendOptions.
+const FilePassphraseEnv = "OB_CREDENTIAL_PASSPHRASE"
+
+// FileNewPassphraseEnv is the environment variable holding the passphrase of
+// the encrypted file backend selected by MigrationBackendOptions. It defaults
+// to the value of FilePassphraseEnv.
+const FileNewPassphraseEnv = "OB_CREDENTIAL_NEW_PASSPHRASE"
+
+// BackendOptions returns the manager options selecting a credential backend by
+// name, as set in the global configuration: "" or "keyring" for the system
+// keyring, "file" for the encrypted file keyring, kept in settings["dir"] or
+// the default keyring directory, or else an external backend (see WithBackend).
+func BackendOptions(name string, settings map[string]string) []ManagerOption {
+	return backendOptions(name, settings, os.Getenv(FilePassphraseEnv))
+}
+
+// MigrationBackendOptions returns the manager options selecting the backend
+// credentials are migrated to, like BackendOptions, except that the file
+// keyring is encrypted with the passphrase in FileNewPassphraseEnv. Migrating
+// a file keyring to its own directory thus changes its passphrase.
+func MigrationBackendOptions(name string, settings map[string]string) []ManagerOption {
+	passphrase := os.Getenv(FileNewPassphraseEnv)
+	if passphrase == "" {
+		passphrase = os.Getenv(FilePassphraseEnv)
+	}
+	return backendOptions(name, settings, passphrase)
+}
+
+// backendOptions implements BackendOptions with the given file keyring
+// passphrase, or the default one when it is empty.
+func backendOptions(name string, settings map[string]string, passphrase string) []ManagerOption {
+	switch name {
+	case "", "keyring":
+		return nil
+	case string(BackendFile):
+		passwordFn := keyring.FixedStringPrompt(ServiceName)
+		if passphrase != "" {
+			passwordFn = keyring.FixedStringPrompt(passphrase)
+		}
+		return []ManagerOption{
+			WithAllowedBackends(keyring.FileBackend),
+			WithFileBackend(settings["dir"], passwordFn),
+		}
+	default:
+		return []ManagerOption{WithBackend(name, settings)}
+	}
+}
+
+// keyringProvider stores credentials in the system keyring.
+type keyringProvider struct {
+	ring    keyring.Keyring
+	backend BackendType
+}
+
+func (p *keyringProvider) Name() string {
+	return string(p.backend)
+}
+
+func (p *keyringProvider) Store(appName, profileName string, cred *Credential) error {
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("failed to serialize credential: %w", err)
+	}
+
+	key := buildKey(appName, profileName)
+	if err := p.ring.Set(createKeyringItem(key, appName, profileName, data)); err != nil {
+		return fmt.Errorf("failed to store credential: %w", err)
+	}
+	return nil
+}
+
+func (p *keyringProvider) Retrieve(appName, profileName string) (*Credential, error) {
+	item, err := p.ring.Get(buildKey(appName, profileName))
+	if err != nil {
+		if errors.Is(err, keyring.ErrKeyNotFound) {
+			return nil, &CredentialNotFoundError{AppName: appName, ProfileName: profileName}
+		}
+		return nil, fmt.Errorf("failed to get credential: %w", err)
+	}
+
+	var cred Credential
+	if err := json.Unmarshal(item.Data, &cred); err != nil {
+		return nil, fmt.Errorf("failed to parse credential: %w", err)
+	}
+	return &cred, nil
+}
+
+func (p *keyringProvider) Delete(appName, profileName string) error {
+	return handleRemoveError(p.ring.Remove(buildKey(appName, profileName)))
+}
+
+func (p *keyringProvider) List(appName string) ([]string, error) {
+	keys, err := p.ring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	var profiles []string
+	for _, key := range keys {
+		app, profile, ok := parseKey(key)
+		if ok && app == appName {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// PluginPrefix prefixes the executable name of a credential backend plugin:
+// the vault backend runs ob-credential-vault.
+const PluginPrefix = "ob-credential-"
+
+// pluginProvider delegates to a credential backend plugin, an executable run
+// once per operation:
+//
+//	ob-credential-<name> get <app> <profile>     prints the credential as JSON, or nothing if none is stored
+//	ob-credential-<name> store <app> <profile>   reads the credential as JSON from stdin
+//	ob-credential-<name> delete <app> <profile>
+//	ob-credential-<name> list <app>              prints the profile names as a JSON array
+//
+// The backend settings are passed in the environment as OB_CREDENTIAL_<KEY>,
+// so a vault plugin reads its address from OB_CREDENTIAL_ADDRESS.
+type pluginProvider struct {
+	name string
+	path string
+	env  []string
+}
+
+// NewPluginProvider returns the provider of the named credential backend
+// plugin. The plugin is the executable given by the path setting, or
+// ob-credential-<name> on the PATH.
+func NewPluginProvider(name string, settings map[string]string) (CredentialProvider, error) {
+	path := settings["path"]
+	if path == "" {
+		found, err := exec.LookPath(PluginPrefix + name)
+		if err != nil {
+			return nil, fmt.Errorf("credential backend '%s' not found: %w", name, err)
+		}
+		path = found
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		if key != "path" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	env := os.Environ()
+	for _, key := range keys {
+		env = append(env, pluginEnvName(key)+"="+settings[key])
+	}
+
+	return &pluginProvider{name: name, path: path, env: env}, nil
+}
+
+// pluginEnvName returns the environment variable a backend setting is passed in.
+func pluginEnvName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+	return "OB_CREDENTIAL_" + strings.ToUpper(name)
+}
+
+func (p *pluginProvider) Name() string {
+	return p.name
+}
+
+func (p *pluginProvider) Store(appName, profileName string, cred *Credential) error {
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("failed to serialize credential: %w", err)
+	}
+	if _, err := p.run(data, "store", appName, profileName); err != nil {
+		return fmt.Errorf("failed to store credential: %w", err)
+	}
+	return nil
+}
+
+func (p *pluginProvider) Retrieve(appName, profileName string) (*Credential, error) {
+	out, err := p.run(nil, "get", appName, profileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential: %w", err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, &CredentialNotFoundError{AppName: appName, ProfileName: profileName}
+	}
+
+	var cred Credential
+	if err := json.Unmarshal(out, &cred); err != nil {
+		return nil, fmt.Errorf("failed to parse credential: %w", err)
+	}
+	return &cred, nil
+}
+
+func (p *pluginProvider) Delete(appName, profileName string) error {
+	if _, err := p.run(nil, "delete", appName, profileName); err != nil {
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+	return nil
+}
+
+func (p *pluginProvider) List(appName string) ([]string, error) {
+	out, err := p.run(nil, "list", appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var profiles []string
+	if err := json.Unmarshal(out, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse credential list: %w", err)
+	}
+	return profiles, nil
+}
+
+// run runs the plugin with args and stdin, returning its output. A failing
+// plugin's error output is included in the error.
+func (p *pluginProvider) run(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command(p.path, args...)
+	cmd.Env = p.env
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential backend '%s': %w: %s", p.name, err, msg)
+		}
+		return nil, fmt.Errorf("credential backend '%s': %w", p.name, err)
+	}
+	return out, nil
+}
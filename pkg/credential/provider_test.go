@@ -0,0 +1,142 @@
+package credential
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockProvider is an in-memory external credential provider.
+type mockProvider struct {
+	creds map[string]Credential
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{creds: make(map[string]Credential)}
+}
+
+func (p *mockProvider) Name() string { return "vault" }
+
+func (p *mockProvider) Store(appName, profileName string, cred *Credential) error {
+	p.creds[appName+"/"+profileName] = *cred
+	return nil
+}
+
+func (p *mockProvider) Retrieve(appName, profileName string) (*Credential, error) {
+	cred, ok := p.creds[appName+"/"+profileName]
+	if !ok {
+		return nil, &CredentialNotFoundError{AppName: appName, ProfileName: profileName}
+	}
+	return &cred, nil
+}
+
+func (p *mockProvider) Delete(appName, profileName string) error {
+	delete(p.creds, appName+"/"+profileName)
+	return nil
+}
+
+func (p *mockProvider) List(appName string) ([]string, error) {
+	var profiles []string
+	for key := range p.creds {
+		if app, profile, _ := strings.Cut(key, "/"); app == appName {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+func TestManagerWithProvider(t *testing.T) {
+	provider := newMockProvider()
+	mgr, err := NewManager(WithProvider(provider))
+	require.NoError(t, err)
+	require.Equal(t, BackendType("vault"), mgr.Backend())
+	require.True(t, mgr.IsInitialized())
+
+	require.NoError(t, mgr.StoreCredential("petstore", "prod", NewBearerCredential("secret-token")))
+	require.Contains(t, provider.creds, "petstore/prod")
+
+	cred, err := mgr.GetCredential("petstore", "prod")
+	require.NoError(t, err)
+	require.Equal(t, "secret-token", cred.Token)
+	require.False(t, cred.CreatedAt.IsZero())
+
+	profiles, err := mgr.ListCredentials("petstore")
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod"}, profiles)
+
+	require.NoError(t, mgr.DeleteCredential("petstore", "prod"))
+	_, err = mgr.GetCredential("petstore", "prod")
+	var notFound *CredentialNotFoundError
+	require.True(t, errors.As(err, &notFound))
+	require.False(t, mgr.HasCredential("petstore", "prod"))
+
+	_, err = mgr.ListAllCredentials()
+	require.ErrorContains(t, err, "not supported by the vault backend")
+}
+
+func TestManagerWithPluginBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test uses a shell script")
+	}
+
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "ob-credential-vault")
+	script := `#!/bin/sh
+case "$1" in
+get)
+  if [ "$3" = "prod" ]; then
+    printf '{"type":"bearer","token":"%s-token"}' "$OB_CREDENTIAL_ADDRESS"
+  fi ;;
+store)
+  cat > "` + filepath.Join(dir, "stored") + `" ;;
+list)
+  echo '["prod"]' ;;
+delete)
+  echo "delete is not allowed" >&2
+  exit 1 ;;
+esac
+`
+	require.NoError(t, os.WriteFile(plugin, []byte(script), 0755))
+
+	mgr, err := NewManager(WithBackend("vault", map[string]string{"path": plugin, "address": "vault.example.com"}))
+	require.NoError(t, err)
+	require.Equal(t, BackendType("vault"), mgr.Backend())
+
+	cred, err := mgr.GetCredential("petstore", "prod")
+	require.NoError(t, err)
+	require.Equal(t, CredentialTypeBearer, cred.Type)
+	require.Equal(t, "vault.example.com-token", cred.Token)
+
+	_, err = mgr.GetCredential("petstore", "staging")
+	var notFound *CredentialNotFoundError
+	require.True(t, errors.As(err, &notFound))
+
+	require.NoError(t, mgr.StoreCredential("petstore", "staging", NewAPIKeyCredential("key")))
+	stored, err := os.ReadFile(filepath.Join(dir, "stored"))
+	require.NoError(t, err)
+	require.Contains(t, string(stored), `"token":"key"`)
+
+	profiles, err := mgr.ListCredentials("petstore")
+	require.NoError(t, err)
+	require.Equal(t, []string{"prod"}, profiles)
+
+	err = mgr.DeleteCredential("petstore", "prod")
+	require.ErrorContains(t, err, "delete is not allowed")
+}
+
+func TestNewManagerUnknownBackend(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := NewManager(WithBackend("vault", nil))
+	require.ErrorContains(t, err, "credential backend 'vault' not found")
+}
+
+func TestPluginEnvName(t *testing.T) {
+	require.Equal(t, "OB_CREDENTIAL_ADDRESS", pluginEnvName("address"))
+	require.Equal(t, "OB_CREDENTIAL_MOUNT_PATH", pluginEnvName("mount-path"))
+}
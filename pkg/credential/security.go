@@ -17,6 +17,10 @@ import (
 type SecurePrompter struct {
 	reader io.Reader
 	writer io.Writer
+
+	// lines scans non-terminal input. It is kept across prompts so that
+	// input buffered while reading one answer is not lost to the next.
+	lines *bufio.Scanner
 }
 
 // NewSecurePrompter creates a new secure prompter.
@@ -44,6 +48,8 @@ func (p *SecurePrompter) PromptCredential(authType string) (*Credential, error)
 		return p.PromptAPIKey()
 	case string(CredentialTypeBasic):
 		return p.PromptBasicAuth()
+	case string(CredentialTypeOAuth2ClientCredentials):
+		return p.PromptOAuth2ClientCredentials("", nil)
 	default:
 		return nil, fmt.Errorf("unsupported auth type: %s", authType)
 	}
@@ -104,6 +110,32 @@ func (p *SecurePrompter) PromptBasicAuth() (*Credential, error) {
 	return NewBasicCredential(username, password), nil
 }
 
+// PromptOAuth2ClientCredentials prompts for an OAuth2 client ID and secret.
+// The token URL and scopes are stored with them as given.
+func (p *SecurePrompter) PromptOAuth2ClientCredentials(tokenURL string, scopes []string) (*Credential, error) {
+	_, _ = fmt.Fprint(p.writer, "Client ID: ")
+	clientID, err := p.readInput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client ID: %w", err)
+	}
+
+	if clientID == "" {
+		return nil, fmt.Errorf("client ID cannot be empty")
+	}
+
+	_, _ = fmt.Fprint(p.writer, "Client secret: ")
+	clientSecret, err := p.readSecureInput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client secret: %w", err)
+	}
+
+	if clientSecret == "" {
+		return nil, fmt.Errorf("client secret cannot be empty")
+	}
+
+	return NewOAuth2ClientCredentials(clientID, clientSecret, tokenURL, scopes), nil
+}
+
 // PromptToken prompts for a single token/secret securely.
 func (p *SecurePrompter) PromptToken(prompt string) (string, error) {
 	_, _ = fmt.Fprint(p.writer, prompt)
@@ -157,7 +189,10 @@ func (p *SecurePrompter) readSecureInput() (string, error) {
 
 // readInput reads a line of input.
 func (p *SecurePrompter) readInput() (string, error) {
-	scanner := bufio.NewScanner(p.reader)
+	if p.lines == nil {
+		p.lines = bufio.NewScanner(p.reader)
+	}
+	scanner := p.lines
 	if scanner.Scan() {
 		return strings.TrimSpace(scanner.Text()), nil
 	}
@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// Default circuit breaker settings.
+const (
+	defaultFailureThreshold = 5
+	defaultFailureWindow    = time.Minute
+	defaultCooldown         = 30 * time.Second
+)
+
+// CircuitState is the state of a single circuit.
+type CircuitState int
+
+// Circuit states.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker tracks upstream failures per operation and host.
+// A nil *CircuitBreaker allows every call.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+	circuits  map[string]*circuit
+}
+
+// circuit holds the state for a single operation/host key.
+type circuit struct {
+	state        CircuitState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	probing      bool
+}
+
+// NewCircuitBreaker creates a circuit breaker from configuration.
+// Returns nil if cfg is nil or disabled.
+func NewCircuitBreaker(cfg *config.CircuitBreakerConfig) *CircuitBreaker {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	cb := &CircuitBreaker{
+		threshold: cfg.FailureThreshold,
+		window:    cfg.Window.Duration,
+		cooldown:  cfg.Cooldown.Duration,
+		now:       time.Now,
+		circuits:  make(map[string]*circuit),
+	}
+	if cb.threshold <= 0 {
+		cb.threshold = defaultFailureThreshold
+	}
+	if cb.window <= 0 {
+		cb.window = defaultFailureWindow
+	}
+	if cb.cooldown <= 0 {
+		cb.cooldown = defaultCooldown
+	}
+	return cb
+}
+
+// newCircuitBreakerForProfile creates a circuit breaker from the profile's safety config.
+func newCircuitBreakerForProfile(appConfig *config.AppConfig, profileName string) *CircuitBreaker {
+	_, profile, err := getActiveProfile(appConfig, profileName)
+	if err != nil {
+		return nil
+	}
+	return NewCircuitBreaker(profile.SafetyConfig.CircuitBreaker)
+}
+
+// circuitKey identifies the operation and host a request targets.
+func circuitKey(req *http.Request, operationPath string) string {
+	return req.Method + " " + req.URL.Host + operationPath
+}
+
+// Allow reports whether a call for key may proceed. When the circuit is open,
+// it also returns the time remaining until a trial call is permitted.
+func (cb *CircuitBreaker) Allow(key string) (bool, time.Duration) {
+	if cb == nil {
+		return true, 0
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[key]
+	if !ok {
+		return true, 0
+	}
+
+	switch c.state {
+	case CircuitOpen:
+		elapsed := cb.now().Sub(c.openedAt)
+		if elapsed < cb.cooldown {
+			return false, cb.cooldown - elapsed
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+		return true, 0
+	case CircuitHalfOpen:
+		// Only one trial call at a time while half-open.
+		if c.probing {
+			return false, 0
+		}
+		c.probing = true
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// RecordSuccess records a successful call and closes the circuit.
+func (cb *CircuitBreaker) RecordSuccess(key string) {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	delete(cb.circuits, key)
+}
+
+// RecordFailure records a failed call, opening the circuit when the threshold is reached.
+func (cb *CircuitBreaker) RecordFailure(key string) {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	c, ok := cb.circuits[key]
+	if !ok {
+		c = &circuit{}
+		cb.circuits[key] = c
+	}
+
+	if c.state == CircuitHalfOpen {
+		c.state = CircuitOpen
+		c.openedAt = now
+		c.probing = false
+		return
+	}
+
+	if c.failures == 0 || now.Sub(c.firstFailure) > cb.window {
+		c.failures = 0
+		c.firstFailure = now
+	}
+	c.failures++
+
+	if c.failures >= cb.threshold {
+		c.state = CircuitOpen
+		c.openedAt = now
+	}
+}
+
+// Record records the outcome of a call based on its HTTP status code.
+// Server errors and rate limiting count as failures.
+func (cb *CircuitBreaker) Record(key string, statusCode int) {
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests {
+		cb.RecordFailure(key)
+		return
+	}
+	cb.RecordSuccess(key)
+}
+
+// State returns the current state of the circuit for key.
+func (cb *CircuitBreaker) State(key string) CircuitState {
+	if cb == nil {
+		return CircuitClosed
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if c, ok := cb.circuits[key]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// circuitOpenResult creates the structured error returned while a circuit is open.
+func circuitOpenResult(key string, retryAfter time.Duration) *mcp.CallToolResult {
+	return formatJSONResult(map[string]any{
+		"error":               "circuit_open",
+		"message":             "upstream operation is failing repeatedly; calls are paused",
+		"operation":           key,
+		"retry_after_seconds": int(retryAfter.Round(time.Second).Seconds()),
+	}, true)
+}
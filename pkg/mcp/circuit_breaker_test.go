@@ -0,0 +1,256 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+// fakeClock is a controllable time source for circuit breaker tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1700000000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// breakerConfig returns an enabled circuit breaker config with a 1m window and 30s cooldown.
+func breakerConfig(threshold int) *config.CircuitBreakerConfig {
+	return &config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: threshold,
+		Window:           config.Duration{Duration: time.Minute},
+		Cooldown:         config.Duration{Duration: 30 * time.Second},
+	}
+}
+
+func TestNewCircuitBreaker_Disabled(t *testing.T) {
+	if cb := NewCircuitBreaker(nil); cb != nil {
+		t.Error("expected nil breaker for nil config")
+	}
+	if cb := NewCircuitBreaker(&config.CircuitBreakerConfig{FailureThreshold: 3}); cb != nil {
+		t.Error("expected nil breaker when disabled")
+	}
+
+	// A nil breaker allows every call.
+	var cb *CircuitBreaker
+	cb.RecordFailure("GET /pets")
+	if ok, _ := cb.Allow("GET /pets"); !ok {
+		t.Error("expected nil breaker to allow calls")
+	}
+}
+
+func TestCircuitBreaker_OpensAndRecovers(t *testing.T) {
+	clock := newFakeClock()
+	cb := NewCircuitBreaker(breakerConfig(3))
+	cb.now = clock.Now
+	key := "GET api.example.com/pets"
+
+	for range 3 {
+		if ok, _ := cb.Allow(key); !ok {
+			t.Fatal("expected calls to be allowed while closed")
+		}
+		cb.RecordFailure(key)
+	}
+
+	if cb.State(key) != CircuitOpen {
+		t.Fatalf("expected circuit to be open, got %s", cb.State(key))
+	}
+	ok, retryAfter := cb.Allow(key)
+	if ok {
+		t.Fatal("expected open circuit to reject calls")
+	}
+	if retryAfter != 30*time.Second {
+		t.Errorf("expected retry after 30s, got %v", retryAfter)
+	}
+
+	// After the cooldown a single trial call is allowed.
+	clock.Advance(31 * time.Second)
+	if ok, _ := cb.Allow(key); !ok {
+		t.Fatal("expected trial call after cooldown")
+	}
+	if cb.State(key) != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", cb.State(key))
+	}
+	if ok, _ := cb.Allow(key); ok {
+		t.Error("expected concurrent calls to be rejected while half-open")
+	}
+
+	cb.RecordSuccess(key)
+	if cb.State(key) != CircuitClosed {
+		t.Fatalf("expected circuit to close after success, got %s", cb.State(key))
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	clock := newFakeClock()
+	cb := NewCircuitBreaker(breakerConfig(1))
+	cb.now = clock.Now
+	key := "GET api.example.com/pets"
+
+	cb.RecordFailure(key)
+	clock.Advance(31 * time.Second)
+	if ok, _ := cb.Allow(key); !ok {
+		t.Fatal("expected trial call after cooldown")
+	}
+
+	cb.RecordFailure(key)
+	if cb.State(key) != CircuitOpen {
+		t.Fatalf("expected failed trial to reopen circuit, got %s", cb.State(key))
+	}
+}
+
+func TestCircuitBreaker_FailuresOutsideWindowReset(t *testing.T) {
+	clock := newFakeClock()
+	cb := NewCircuitBreaker(breakerConfig(2))
+	cb.now = clock.Now
+	key := "GET api.example.com/pets"
+
+	cb.RecordFailure(key)
+	clock.Advance(2 * time.Minute)
+	cb.RecordFailure(key)
+
+	if cb.State(key) != CircuitClosed {
+		t.Errorf("expected failures outside the window not to open the circuit, got %s", cb.State(key))
+	}
+}
+
+func TestHandleCallTool_CircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	spec := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test", Version: "1.0.0"}, Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/pets", &openapi3.PathItem{Get: &openapi3.Operation{OperationID: "listPets"}})
+
+	profile := config.Profile{Name: "default", BaseURL: server.URL}
+	profile.SafetyConfig.CircuitBreaker = breakerConfig(2)
+	appConfig := &config.AppConfig{
+		Name:           "testapp",
+		Profiles:       map[string]config.Profile{"default": profile},
+		DefaultProfile: "default",
+	}
+
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(nil), server.Client())
+	handler.SetSpec(spec)
+	handler.SetAppConfig(appConfig, "default")
+
+	clock := newFakeClock()
+	handler.breaker.now = clock.Now
+
+	call := func() *mcp.CallToolResult {
+		t.Helper()
+		result, err := handler.HandleCallTool(context.Background(), &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: "listPets"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	// Two upstream failures open the circuit.
+	call()
+	call()
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", calls.Load())
+	}
+
+	result := call()
+	if !result.IsError {
+		t.Fatal("expected open circuit to return an error result")
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "circuit_open") || !strings.Contains(text, "retry_after_seconds") {
+		t.Errorf("expected structured circuit_open error, got: %s", text)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected open circuit not to call upstream, got %d calls", calls.Load())
+	}
+
+	// Upstream recovers; after the cooldown the trial call succeeds and closes the circuit.
+	healthy.Store(true)
+	clock.Advance(31 * time.Second)
+
+	if result := call(); result.IsError {
+		t.Fatalf("expected trial call to succeed, got: %v", result.Content[0].(*mcp.TextContent).Text)
+	}
+	if result := call(); result.IsError {
+		t.Fatal("expected closed circuit to allow calls")
+	}
+	if calls.Load() != 4 {
+		t.Errorf("expected 4 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestProgressiveHandler_ClientErrorKeepsHalfOpenTrial(t *testing.T) {
+	handler, err := NewProgressiveHandler(request.NewBuilder(nil), nil, SearchEnginePredicate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = handler.Close() }()
+	if err := handler.SetSpec(createTestOpenAPISpec(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A missing CA file makes the pooled client fail before any call is made.
+	profile := config.Profile{Name: "default", BaseURL: "http://api.example.com"}
+	profile.TLSConfig.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	profile.SafetyConfig.CircuitBreaker = breakerConfig(1)
+	handler.SetAppConfig(&config.AppConfig{
+		Name:           "testapp",
+		Profiles:       map[string]config.Profile{"default": profile},
+		DefaultProfile: "default",
+	}, "default")
+
+	clock := newFakeClock()
+	handler.breaker.now = clock.Now
+	key := "GET api.example.com/pets"
+	handler.breaker.RecordFailure(key)
+	clock.Advance(31 * time.Second)
+
+	if _, err := handler.handleLoadTool(context.Background(), createCallToolRequest(MetaToolLoad, `{"toolId": "listPets"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := handler.handleInvokeTool(context.Background(), createCallToolRequest(MetaToolInvoke, `{"toolId": "listPets", "arguments": {}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected the client error to be reported")
+	}
+
+	// The failed call never reached upstream, so the trial call is still free.
+	if ok, _ := handler.breaker.Allow(key); !ok {
+		t.Error("expected the half-open trial call to still be allowed")
+	}
+}
@@ -14,24 +14,28 @@ import (
 	"github.com/nomagicln/open-bridge/pkg/config"
 	"github.com/nomagicln/open-bridge/pkg/request"
 	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 )
 
 // Handler implements the MCP protocol for AI agent integration.
 type Handler struct {
+	baseMapper     *semantic.Mapper
 	mapper         *semantic.Mapper
 	requestBuilder *request.Builder
 	httpClient     *http.Client
+	metrics        *Metrics
 	spec           *openapi3.T
 	appConfig      *config.AppConfig
 	profileName    string
+	breaker        *CircuitBreaker
+	filter         semantic.OperationFilter
 }
 
-// NewHandler creates a new MCP handler.
+// NewHandler creates a new MCP handler. Without an httpClient, upstream calls
+// use the request builder's pooled client for the active profile.
 func NewHandler(mapper *semantic.Mapper, requestBuilder *request.Builder, httpClient *http.Client) *Handler {
-	if httpClient == nil {
-		httpClient = &http.Client{}
-	}
 	return &Handler{
+		baseMapper:     mapper,
 		mapper:         mapper,
 		requestBuilder: requestBuilder,
 		httpClient:     httpClient,
@@ -44,9 +48,39 @@ func (h *Handler) SetSpec(spec *openapi3.T) {
 }
 
 // SetAppConfig sets the app configuration for the handler.
+// The profile's circuit breaker settings and the app's verb mappings and
+// operationId verbs setting, if any, are applied.
 func (h *Handler) SetAppConfig(appCfg *config.AppConfig, profileName string) {
 	h.appConfig = appCfg
 	h.profileName = profileName
+	h.breaker = newCircuitBreakerForProfile(appCfg, profileName)
+	h.mapper = h.baseMapper
+	if appCfg != nil {
+		h.mapper = h.baseMapper.ForApp(appCfg.VerbMappings, appCfg.OperationIDVerbs)
+	}
+}
+
+// SetOperationFilter restricts the operations exposed as tools to those the
+// filter accepts, in addition to the safety config allow and deny lists.
+func (h *Handler) SetOperationFilter(filter semantic.OperationFilter) {
+	h.filter = filter
+}
+
+// SetCircuitBreaker overrides the circuit breaker used for upstream calls.
+// Passing nil disables it.
+func (h *Handler) SetCircuitBreaker(cb *CircuitBreaker) {
+	h.breaker = cb
+}
+
+// SetMetrics records the handler's upstream requests in metrics.
+func (h *Handler) SetMetrics(metrics *Metrics) {
+	h.metrics = metrics
+}
+
+// GetMapper returns the semantic mapper the handler was created with, before
+// any app's verb settings are applied.
+func (h *Handler) GetMapper() *semantic.Mapper {
+	return h.baseMapper
 }
 
 // GetRequestBuilder returns the request builder used by the handler.
@@ -100,6 +134,10 @@ func (h *Handler) getActiveProfile() (string, *config.Profile, error) {
 
 // buildAndExecuteRequest builds and executes the HTTP request.
 func (h *Handler) buildAndExecuteRequest(operation *openapi3.Operation, method, path string, arguments map[string]any, profileName string, profile *config.Profile) (*mcp.CallToolResult, error) {
+	if result := checkArgumentLimits(operation, arguments, &profile.SafetyConfig); result != nil {
+		return result, nil
+	}
+
 	httpReq, err := h.buildRequest(method, path, arguments, operation, profile)
 	if err != nil {
 		return errorResult("Failed to build request: %v", err), nil
@@ -109,12 +147,24 @@ func (h *Handler) buildAndExecuteRequest(operation *openapi3.Operation, method,
 		return nil, fmt.Errorf("failed to inject authentication: %w", err)
 	}
 
-	httpResp, err := h.executeRequest(httpReq)
+	if err := h.requestBuilder.ApplyInterceptors(httpReq); err != nil {
+		return errorResult("Request aborted: %v", err), nil
+	}
+
+	key := circuitKey(httpReq, path)
+	if ok, retryAfter := h.breaker.Allow(key); !ok {
+		return circuitOpenResult(key, retryAfter), nil
+	}
+
+	httpResp, err := h.executeRequest(httpReq, profile)
 	if err != nil {
+		h.breaker.RecordFailure(key)
 		return errorResult("Failed to execute API call: %v", err), nil
 	}
 	defer func() { _ = httpResp.Body.Close() }()
 
+	h.breaker.Record(key, httpResp.StatusCode)
+
 	bodyBytes, err := io.ReadAll(httpResp.Body)
 	if err != nil {
 		return errorResult("Failed to read response body: %v", err), nil
@@ -123,32 +173,48 @@ func (h *Handler) buildAndExecuteRequest(operation *openapi3.Operation, method,
 	return h.FormatMCPResult(httpResp.StatusCode, bodyBytes), nil
 }
 
-// buildRequest builds the HTTP request with parameters.
+// buildRequest builds the HTTP request with parameters, sent to the
+// operation's or path's own server when the spec declares one.
 func (h *Handler) buildRequest(method, path string, arguments map[string]any, operation *openapi3.Operation, profile *config.Profile) (*http.Request, error) {
-	var requestBody *openapi3.RequestBody
-	if operation.RequestBody != nil {
-		requestBody = operation.RequestBody.Value
-	}
-
-	return h.requestBuilder.BuildRequest(method, path, profile.BaseURL, arguments, operation.Parameters, requestBody)
+	baseURL := spec.OperationBaseURL(h.spec, path, operation, profile.BaseURL)
+	return h.requestBuilder.BuildRequestForOperation(method, path, baseURL, operation, arguments)
 }
 
-// injectAuthAndHeaders injects authentication and custom headers into the request.
+// injectAuthAndHeaders sets the profile's custom headers and then injects
+// authentication, so a stored credential wins over a profile header of the
+// same name, as in the CLI.
 func (h *Handler) injectAuthAndHeaders(httpReq *http.Request, profileName string, profile *config.Profile) error {
-	if err := h.requestBuilder.InjectAuth(httpReq, h.appConfig.Name, profileName, &profile.Auth); err != nil {
-		return err
-	}
-
 	for key, value := range profile.Headers {
 		httpReq.Header.Set(key, value)
 	}
 
-	return nil
+	return h.requestBuilder.InjectAuth(httpReq, h.appConfig.Name, profile)
 }
 
 // executeRequest performs the HTTP request and returns the response.
-func (h *Handler) executeRequest(httpReq *http.Request) (*http.Response, error) {
-	return h.httpClient.Do(httpReq)
+func (h *Handler) executeRequest(httpReq *http.Request, profile *config.Profile) (*http.Response, error) {
+	client, err := upstreamClient(h.httpClient, h.requestBuilder, h.appConfig.Name, profile, h.metrics)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(httpReq)
+}
+
+// upstreamClient returns the client for upstream calls: the handler's own
+// client if one was given, or else the request builder's pooled client for the
+// profile, recorded in metrics if set.
+func upstreamClient(client *http.Client, builder *request.Builder, appName string, profile *config.Profile, metrics *Metrics) (*http.Client, error) {
+	if client == nil {
+		pooled, err := builder.HTTPClient(appName, profile)
+		if err != nil {
+			return nil, err
+		}
+		client = pooled
+	}
+	if metrics != nil {
+		client = metrics.InstrumentClient(client)
+	}
+	return client, nil
 }
 
 // HandleCallTool handles tool execution requests.
@@ -198,11 +264,11 @@ func (h *Handler) findOperationInPath(path string, pathItem *openapi3.PathItem,
 	}
 
 	for method, op := range operations {
-		if op == nil {
+		if op == nil || semantic.IsHidden(op) || !h.acceptsOperation(method, path, op) {
 			continue
 		}
 
-		if matchesToolName(op, method, path, toolName) {
+		if matchesToolName(h.mapper, op, method, path, toolName) {
 			return op, method, true
 		}
 	}
@@ -211,14 +277,14 @@ func (h *Handler) findOperationInPath(path string, pathItem *openapi3.PathItem,
 }
 
 // matchesToolName checks if an operation matches the given tool name.
-func matchesToolName(op *openapi3.Operation, method, path, toolName string) bool {
+func matchesToolName(mapper *semantic.Mapper, op *openapi3.Operation, method, path, toolName string) bool {
 	// Match by operationId
 	if op.OperationID == toolName {
 		return true
 	}
 
 	// Match by generated tool name (same logic as BuildMCPTools)
-	generatedName := GenerateToolName(method, path, op)
+	generatedName := GenerateToolNameWithMapper(mapper, method, path, op)
 	return generatedName == toolName
 }
 
@@ -249,11 +315,11 @@ func (h *Handler) processPathItem(path string, pathItem *openapi3.PathItem, safe
 	}
 
 	for method, op := range operations {
-		if op == nil {
+		if op == nil || semantic.IsHidden(op) {
 			continue
 		}
 
-		if h.shouldSkipOperation(method, safetyConfig) {
+		if h.shouldSkipOperation(method, safetyConfig) || !h.acceptsOperation(method, path, op) {
 			continue
 		}
 
@@ -275,10 +341,15 @@ func (h *Handler) shouldSkipOperation(method string, safetyConfig *config.Safety
 	return safetyConfig.ReadOnlyMode && method != "GET"
 }
 
+// acceptsOperation reports whether the operation filter, if any, accepts an operation.
+func (h *Handler) acceptsOperation(method, path string, op *openapi3.Operation) bool {
+	return h.filter == nil || h.filter(op.OperationID, method, path, op)
+}
+
 // convertOperationToTool converts an OpenAPI operation to an MCP tool.
 // Delegates to the shared convertOperationToMCPTool function.
 func (h *Handler) convertOperationToTool(method, path string, op *openapi3.Operation) mcp.Tool {
-	return convertOperationToMCPTool(method, path, op)
+	return convertOperationToMCPTool(h.mapper, method, path, op)
 }
 
 // isToolAllowed checks if a tool is allowed based on safety configuration.
@@ -7,9 +7,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/99designs/keyring"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
 	"github.com/nomagicln/open-bridge/pkg/request"
 	"github.com/nomagicln/open-bridge/pkg/semantic"
 )
@@ -132,6 +134,66 @@ func TestHandleCallTool_Success(t *testing.T) {
 	}
 }
 
+func TestHandleCallTool_ProfileHeadersDoNotOverrideAuth(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Test API", Version: "1.0.0"},
+		Paths:   &openapi3.Paths{},
+	}
+	spec.Paths.Set("/users", &openapi3.PathItem{
+		Get: &openapi3.Operation{OperationID: "listUsers"},
+	})
+
+	credMgr, err := credential.NewManager(
+		credential.WithAllowedBackends(keyring.FileBackend),
+		credential.WithFileBackend(t.TempDir(), keyring.FixedStringPrompt("test-password")),
+	)
+	if err != nil {
+		t.Fatalf("failed to create credential manager: %v", err)
+	}
+	if err := credMgr.StoreCredential("testapp", "default", credential.NewBearerCredential("s3cret-token")); err != nil {
+		t.Fatalf("failed to store credential: %v", err)
+	}
+
+	appConfig := &config.AppConfig{
+		Name: "testapp",
+		Profiles: map[string]config.Profile{
+			"default": {
+				Name:    "default",
+				BaseURL: server.URL,
+				Auth:    config.AuthConfig{Type: "bearer"},
+				Headers: map[string]string{"Authorization": "Bearer stale-token"},
+			},
+		},
+		DefaultProfile: "default",
+	}
+
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(credMgr), server.Client())
+	handler.SetSpec(spec)
+	handler.SetAppConfig(appConfig, "default")
+
+	result, err := handler.HandleCallTool(context.Background(), &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Name: "listUsers", Arguments: json.RawMessage(`{}`)},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Expected success, got error result: %v", result.Content)
+	}
+	if gotAuth != "Bearer s3cret-token" {
+		t.Errorf("expected the stored credential to win over the profile header, got %q", gotAuth)
+	}
+}
+
 func TestHandleCallTool_ToolNotFound(t *testing.T) {
 	// Create OpenAPI spec
 	spec := &openapi3.T{
@@ -219,6 +281,29 @@ func TestHandleCallTool_MissingToolName(t *testing.T) {
 	}
 }
 
+func TestBuildMCPTools_SkipsHiddenOperations(t *testing.T) {
+	spec := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test API", Version: "1.0.0"}, Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/users", &openapi3.PathItem{
+		Get: &openapi3.Operation{OperationID: "listUsers"},
+		Delete: &openapi3.Operation{
+			OperationID: "purgeUsers",
+			Extensions:  map[string]any{semantic.HiddenExtension: true},
+		},
+	})
+
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(nil), nil)
+	handler.SetSpec(spec)
+
+	tools := handler.BuildMCPTools(spec, nil)
+	if len(tools) != 1 || tools[0].Name != "listUsers" {
+		t.Fatalf("expected only listUsers, got %v", tools)
+	}
+
+	if _, _, _, err := handler.MapToolToOperation("purgeUsers"); err == nil {
+		t.Error("expected hidden operation not to be callable as a tool")
+	}
+}
+
 func TestMapToolToOperation(t *testing.T) {
 	// Create OpenAPI spec
 	spec := &openapi3.T{
@@ -320,6 +405,29 @@ func TestMapToolToOperation(t *testing.T) {
 	}
 }
 
+func TestGetTools_WithOperationFilter(t *testing.T) {
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(nil), nil)
+	handler.SetSpec(createTestOpenAPISpec())
+	handler.SetOperationFilter(func(_, method, _ string, _ *openapi3.Operation) bool {
+		return method == "GET"
+	})
+
+	names := make(map[string]bool)
+	for _, tool := range handler.GetTools(nil) {
+		names[tool.Name] = true
+	}
+	if len(names) != 2 || !names["listPets"] || !names["getPetById"] {
+		t.Errorf("expected only GET tools, got %v", names)
+	}
+
+	if _, _, _, err := handler.MapToolToOperation("createPet"); err == nil {
+		t.Error("expected filtered tool not to map to an operation")
+	}
+	if _, method, _, err := handler.MapToolToOperation("listPets"); err != nil || method != "GET" {
+		t.Errorf("expected listPets to map to GET, got %q, %v", method, err)
+	}
+}
+
 func TestFormatMCPResult(t *testing.T) {
 	mapper := semantic.NewMapper()
 	builder := request.NewBuilder(nil)
@@ -374,3 +482,23 @@ func TestFormatMCPResult(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_SetAppConfigKeepsBaseMapper(t *testing.T) {
+	base := semantic.NewMapper(semantic.WithOperationIDVerbs())
+	handler := NewHandler(base, request.NewBuilder(nil), nil)
+
+	handler.SetAppConfig(&config.AppConfig{Name: "a", VerbMappings: map[string]string{"PATCH": "update"}}, "")
+	op := &openapi3.Operation{OperationID: "listCustomers"}
+	if verb := handler.mapper.MapVerb("POST", "/customers/search", op); verb != "list" {
+		t.Errorf("expected the base mapper's operationId verbs to be kept, got %q", verb)
+	}
+	if verb := handler.mapper.MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}); verb != "update" {
+		t.Errorf("expected the app's method verb, got %q", verb)
+	}
+
+	// Another app's settings replace the previous app's instead of adding to them.
+	handler.SetAppConfig(&config.AppConfig{Name: "b"}, "")
+	if handler.mapper != base {
+		t.Error("expected the base mapper for an app without verb settings")
+	}
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// maxArgumentsBytesExtension overrides the profile's argument size limit for one operation.
+const maxArgumentsBytesExtension = "x-ob-max-arguments-bytes"
+
+// checkArgumentLimits validates tool-call arguments against the configured size limit
+// and any schema maxItems constraints. It returns a structured error result when a
+// limit is exceeded, or nil if the arguments are acceptable.
+func checkArgumentLimits(operation *openapi3.Operation, arguments map[string]any, safety *config.SafetyConfig) *mcp.CallToolResult {
+	if limit := maxArgumentsBytes(operation, safety); limit > 0 {
+		data, err := json.Marshal(arguments)
+		if err != nil {
+			return limitExceededResult("invalid_arguments", fmt.Sprintf("failed to encode arguments: %v", err), nil)
+		}
+		if len(data) > limit {
+			return limitExceededResult(
+				"arguments_too_large",
+				fmt.Sprintf("arguments are %d bytes, limit is %d bytes", len(data), limit),
+				map[string]any{"size_bytes": len(data), "limit_bytes": limit},
+			)
+		}
+	}
+
+	for name, value := range arguments {
+		items, ok := value.([]any)
+		if !ok {
+			continue
+		}
+		schema := argumentSchema(operation, name)
+		if schema == nil || schema.MaxItems == nil || uint64(len(items)) <= *schema.MaxItems {
+			continue
+		}
+		return limitExceededResult(
+			"too_many_items",
+			fmt.Sprintf("argument '%s' has %d items, maxItems is %d", name, len(items), *schema.MaxItems),
+			map[string]any{"argument": name, "items": len(items), "max_items": *schema.MaxItems},
+		)
+	}
+
+	return nil
+}
+
+// maxArgumentsBytes returns the effective argument size limit for an operation.
+func maxArgumentsBytes(operation *openapi3.Operation, safety *config.SafetyConfig) int {
+	if operation != nil {
+		switch v := operation.Extensions[maxArgumentsBytesExtension].(type) {
+		case float64:
+			return int(v)
+		case int:
+			return v
+		}
+	}
+	if safety == nil {
+		return 0
+	}
+	return safety.MaxArgumentsBytes
+}
+
+// argumentSchema finds the schema for a named argument among the operation's
+// parameters and top-level JSON request body properties.
+func argumentSchema(operation *openapi3.Operation, name string) *openapi3.Schema {
+	if operation == nil {
+		return nil
+	}
+
+	for _, paramRef := range operation.Parameters {
+		if paramRef == nil || paramRef.Value == nil || paramRef.Value.Name != name {
+			continue
+		}
+		if paramRef.Value.Schema == nil {
+			return nil
+		}
+		return paramRef.Value.Schema.Value
+	}
+
+	if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil
+	}
+	for mediaType, content := range operation.RequestBody.Value.Content {
+		if !strings.Contains(mediaType, "json") || content.Schema == nil || content.Schema.Value == nil {
+			continue
+		}
+		if prop, ok := content.Schema.Value.Properties[name]; ok && prop != nil {
+			return prop.Value
+		}
+	}
+
+	return nil
+}
+
+// limitExceededResult creates a structured error result for a rejected tool call.
+func limitExceededResult(code, message string, details map[string]any) *mcp.CallToolResult {
+	payload := map[string]any{
+		"error":   code,
+		"message": message,
+	}
+	maps.Copy(payload, details)
+	return formatJSONResult(payload, true)
+}
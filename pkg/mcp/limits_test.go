@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+// limitsTestOperation returns an operation with a bounded array body property.
+func limitsTestOperation() *openapi3.Operation {
+	tags := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()).WithMaxItems(2)
+	return &openapi3.Operation{
+		OperationID: "createPet",
+		RequestBody: &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(
+			openapi3.NewObjectSchema().
+				WithProperty("name", openapi3.NewStringSchema()).
+				WithProperty("tags", tags),
+		)},
+	}
+}
+
+// resultText returns the text of the first content item of a tool result.
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected TextContent, got %T", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestCheckArgumentLimits_OversizedArguments(t *testing.T) {
+	args := map[string]any{"name": strings.Repeat("x", 2048)}
+
+	result := checkArgumentLimits(limitsTestOperation(), args, &config.SafetyConfig{MaxArgumentsBytes: 1024})
+	if result == nil || !result.IsError {
+		t.Fatal("expected oversized arguments to be rejected")
+	}
+	if text := resultText(t, result); !strings.Contains(text, "arguments_too_large") {
+		t.Errorf("expected arguments_too_large error, got: %s", text)
+	}
+
+	if result := checkArgumentLimits(limitsTestOperation(), map[string]any{"name": "rex"}, &config.SafetyConfig{MaxArgumentsBytes: 1024}); result != nil {
+		t.Errorf("expected small arguments to pass, got: %s", resultText(t, result))
+	}
+}
+
+func TestCheckArgumentLimits_OperationOverride(t *testing.T) {
+	op := limitsTestOperation()
+	op.Extensions = map[string]any{maxArgumentsBytesExtension: float64(16)}
+
+	result := checkArgumentLimits(op, map[string]any{"name": "a long pet name"}, &config.SafetyConfig{MaxArgumentsBytes: 1024})
+	if result == nil {
+		t.Fatal("expected the operation limit to override the profile limit")
+	}
+}
+
+func TestCheckArgumentLimits_MaxItems(t *testing.T) {
+	args := map[string]any{"tags": []any{"a", "b", "c"}}
+
+	result := checkArgumentLimits(limitsTestOperation(), args, &config.SafetyConfig{})
+	if result == nil || !result.IsError {
+		t.Fatal("expected array over maxItems to be rejected")
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(resultText(t, result)), &payload); err != nil {
+		t.Fatalf("expected structured JSON error: %v", err)
+	}
+	if payload["error"] != "too_many_items" || payload["argument"] != "tags" {
+		t.Errorf("unexpected error payload: %v", payload)
+	}
+
+	if result := checkArgumentLimits(limitsTestOperation(), map[string]any{"tags": []any{"a", "b"}}, nil); result != nil {
+		t.Errorf("expected array within maxItems to pass, got: %s", resultText(t, result))
+	}
+}
+
+func TestHandleCallTool_RejectsOversizedArguments(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	spec := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test", Version: "1.0.0"}, Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/pets", &openapi3.PathItem{Post: limitsTestOperation()})
+
+	profile := config.Profile{Name: "default", BaseURL: server.URL}
+	profile.SafetyConfig.MaxArgumentsBytes = 64
+	appConfig := &config.AppConfig{
+		Name:           "testapp",
+		Profiles:       map[string]config.Profile{"default": profile},
+		DefaultProfile: "default",
+	}
+
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(nil), server.Client())
+	handler.SetSpec(spec)
+	handler.SetAppConfig(appConfig, "default")
+
+	args, _ := json.Marshal(map[string]any{"name": strings.Repeat("x", 256)})
+	result, err := handler.HandleCallTool(context.Background(), &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Name: "createPet", Arguments: args},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(resultText(t, result), "arguments_too_large") {
+		t.Errorf("expected arguments_too_large error, got: %s", resultText(t, result))
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected no upstream request, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetricsPath is the HTTP path serving metrics in the Prometheus text format.
+const MetricsPath = "/metrics"
+
+// upstreamDurationBuckets are the upper bounds, in seconds, of the upstream
+// request duration histogram buckets.
+var upstreamDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// toolCallKey labels a tool call counter.
+type toolCallKey struct {
+	tool    string
+	outcome string
+}
+
+// Metrics collects tool call and upstream request metrics for a long-running
+// MCP server and serves them in the Prometheus text exposition format.
+type Metrics struct {
+	mu              sync.Mutex
+	toolCalls       map[toolCallKey]uint64
+	upstreamErrors  map[string]uint64
+	durationBuckets []uint64
+	durationSum     float64
+	durationCount   uint64
+	activeRequests  int64
+}
+
+// NewMetrics creates an empty metrics collector.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		toolCalls:       make(map[toolCallKey]uint64),
+		upstreamErrors:  make(map[string]uint64),
+		durationBuckets: make([]uint64, len(upstreamDurationBuckets)),
+	}
+}
+
+// Middleware returns MCP server middleware that counts tool calls by tool name
+// and outcome ("success" or "error").
+func (m *Metrics) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+				outcome := "success"
+				if toolResult, ok := result.(*mcp.CallToolResult); err != nil || (ok && toolResult.IsError) {
+					outcome = "error"
+				}
+				m.mu.Lock()
+				m.toolCalls[toolCallKey{tool: call.Params.Name, outcome: outcome}]++
+				m.mu.Unlock()
+			}
+			return result, err
+		}
+	}
+}
+
+// InstrumentClient returns a copy of client whose requests are recorded as
+// upstream requests.
+func (m *Metrics) InstrumentClient(client *http.Client) *http.Client {
+	instrumented := *client
+	instrumented.Transport = &metricsTransport{metrics: m, base: client.Transport}
+	return &instrumented
+}
+
+// metricsTransport records the duration, outcome, and concurrency of upstream requests.
+type metricsTransport struct {
+	metrics *Metrics
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t.metrics.mu.Lock()
+	t.metrics.activeRequests++
+	t.metrics.mu.Unlock()
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	t.metrics.observeUpstream(time.Since(start), resp, err)
+	return resp, err
+}
+
+// observeUpstream records a finished upstream request.
+func (m *Metrics) observeUpstream(elapsed time.Duration, resp *http.Response, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.activeRequests--
+
+	seconds := elapsed.Seconds()
+	m.durationSum += seconds
+	m.durationCount++
+	for i, bound := range upstreamDurationBuckets {
+		if seconds <= bound {
+			m.durationBuckets[i]++
+		}
+	}
+
+	switch {
+	case err != nil:
+		m.upstreamErrors["transport"]++
+	case resp.StatusCode >= 400:
+		m.upstreamErrors[fmt.Sprintf("%dxx", resp.StatusCode/100)]++
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.writeText(w)
+}
+
+// writeText writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) writeText(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, _ = fmt.Fprintln(w, "# HELP ob_mcp_tool_calls_total Tool calls handled, by tool and outcome.")
+	_, _ = fmt.Fprintln(w, "# TYPE ob_mcp_tool_calls_total counter")
+	keys := slices.SortedFunc(maps.Keys(m.toolCalls), func(a, b toolCallKey) int {
+		return cmp.Or(cmp.Compare(a.tool, b.tool), cmp.Compare(a.outcome, b.outcome))
+	})
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "ob_mcp_tool_calls_total{tool=%s,outcome=%s} %d\n",
+			strconv.Quote(key.tool), strconv.Quote(key.outcome), m.toolCalls[key])
+	}
+
+	_, _ = fmt.Fprintln(w, "# HELP ob_mcp_upstream_request_duration_seconds Duration of upstream API requests.")
+	_, _ = fmt.Fprintln(w, "# TYPE ob_mcp_upstream_request_duration_seconds histogram")
+	for i, bound := range upstreamDurationBuckets {
+		_, _ = fmt.Fprintf(w, "ob_mcp_upstream_request_duration_seconds_bucket{le=\"%s\"} %d\n",
+			strconv.FormatFloat(bound, 'g', -1, 64), m.durationBuckets[i])
+	}
+	_, _ = fmt.Fprintf(w, "ob_mcp_upstream_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.durationCount)
+	_, _ = fmt.Fprintf(w, "ob_mcp_upstream_request_duration_seconds_sum %s\n", strconv.FormatFloat(m.durationSum, 'g', -1, 64))
+	_, _ = fmt.Fprintf(w, "ob_mcp_upstream_request_duration_seconds_count %d\n", m.durationCount)
+
+	_, _ = fmt.Fprintln(w, "# HELP ob_mcp_upstream_errors_total Failed upstream API requests, by status class or transport error.")
+	_, _ = fmt.Fprintln(w, "# TYPE ob_mcp_upstream_errors_total counter")
+	for _, class := range slices.Sorted(maps.Keys(m.upstreamErrors)) {
+		_, _ = fmt.Fprintf(w, "ob_mcp_upstream_errors_total{class=%s} %d\n", strconv.Quote(class), m.upstreamErrors[class])
+	}
+
+	_, _ = fmt.Fprintln(w, "# HELP ob_mcp_active_requests Upstream API requests in flight.")
+	_, _ = fmt.Fprintln(w, "# TYPE ob_mcp_active_requests gauge")
+	_, _ = fmt.Fprintf(w, "ob_mcp_active_requests %d\n", m.activeRequests)
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint_AfterToolCall(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer upstream.Close()
+
+	spec := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test", Version: "1.0.0"}, Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/pets", &openapi3.PathItem{
+		Get:    &openapi3.Operation{OperationID: "listPets"},
+		Delete: &openapi3.Operation{OperationID: "deletePets"},
+	})
+	appConfig := &config.AppConfig{
+		Name:           "testapp",
+		Profiles:       map[string]config.Profile{"default": {Name: "default", BaseURL: upstream.URL}},
+		DefaultProfile: "default",
+	}
+
+	factory := NewServerFactory("testapp", "test")
+	factory.Metrics = NewMetrics()
+	server := factory.CreateServer()
+
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(nil), upstream.Client())
+	handler.SetSpec(spec)
+	handler.SetAppConfig(appConfig, "default")
+	handler.SetMetrics(factory.Metrics)
+	handler.Register(server, &config.SafetyConfig{})
+
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "listPets"})
+	require.NoError(t, err)
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "deletePets"})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	factory.HTTPHandler(server).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, `ob_mcp_tool_calls_total{tool="listPets",outcome="success"} 1`)
+	assert.Contains(t, body, `ob_mcp_tool_calls_total{tool="deletePets",outcome="error"} 1`)
+	assert.Contains(t, body, `ob_mcp_upstream_request_duration_seconds_bucket{le="+Inf"} 2`)
+	assert.Contains(t, body, "ob_mcp_upstream_request_duration_seconds_count 2")
+	assert.Contains(t, body, `ob_mcp_upstream_errors_total{class="5xx"} 1`)
+	assert.Contains(t, body, "ob_mcp_active_requests 0")
+}
+
+func TestServerFactoryHTTPHandler_NoMetrics(t *testing.T) {
+	factory := NewServerFactory("testapp", "test")
+	handler := factory.HTTPHandler(factory.CreateServer())
+
+	_, isMux := handler.(*http.ServeMux)
+	assert.False(t, isMux, "the metrics endpoint must not be served unless enabled")
+}
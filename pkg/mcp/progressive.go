@@ -14,6 +14,8 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nomagicln/open-bridge/pkg/config"
 	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+	"github.com/nomagicln/open-bridge/pkg/spec"
 )
 
 // MetaToolName constants for the three meta-tools.
@@ -65,21 +67,22 @@ type ProgressiveHandler struct {
 	searchEngine   ToolSearchEngine
 	requestBuilder *request.Builder
 	httpClient     *http.Client
+	mapper         *semantic.Mapper
+	metrics        *Metrics
 	spec           *openapi3.T
 	appConfig      *config.AppConfig
 	profileName    string
+	breaker        *CircuitBreaker
 }
 
-// NewProgressiveHandler creates a new progressive disclosure handler.
+// NewProgressiveHandler creates a new progressive disclosure handler. Without
+// an httpClient, upstream calls use the request builder's pooled client for
+// the active profile.
 func NewProgressiveHandler(
 	requestBuilder *request.Builder,
 	httpClient *http.Client,
 	engineType SearchEngineType,
 ) (*ProgressiveHandler, error) {
-	if httpClient == nil {
-		httpClient = &http.Client{}
-	}
-
 	engine, err := NewSearchEngine(engineType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create search engine: %w", err)
@@ -111,7 +114,15 @@ func (h *ProgressiveHandler) SetSpec(spec *openapi3.T, safetyConfig *config.Safe
 	return nil
 }
 
+// SetMapper sets the semantic mapper used to name tools, which the app's verb
+// settings are applied on top of. Call it before SetAppConfig.
+func (h *ProgressiveHandler) SetMapper(mapper *semantic.Mapper) {
+	h.mapper = mapper
+}
+
 // SetAppConfig sets the app configuration.
+// The app's verb mappings and operationId verbs setting are used to name
+// tools, so call it before SetSpec.
 // Panics if appCfg is nil or appCfg.Name is empty.
 func (h *ProgressiveHandler) SetAppConfig(appCfg *config.AppConfig, profileName string) {
 	if appCfg == nil {
@@ -122,6 +133,19 @@ func (h *ProgressiveHandler) SetAppConfig(appCfg *config.AppConfig, profileName
 	}
 	h.appConfig = appCfg
 	h.profileName = profileName
+	h.breaker = newCircuitBreakerForProfile(appCfg, profileName)
+	h.registry.SetMapper(h.mapper.ForApp(appCfg.VerbMappings, appCfg.OperationIDVerbs))
+}
+
+// SetMetrics records the handler's upstream requests in metrics.
+func (h *ProgressiveHandler) SetMetrics(metrics *Metrics) {
+	h.metrics = metrics
+}
+
+// SetOperationFilter restricts the tools that can be searched, loaded, and
+// invoked to operations the filter accepts. Call it before SetSpec.
+func (h *ProgressiveHandler) SetOperationFilter(filter semantic.OperationFilter) {
+	h.registry.SetOperationFilter(filter)
 }
 
 // Register registers the three meta-tools with the MCP server.
@@ -462,30 +486,50 @@ func (h *ProgressiveHandler) buildAndExecuteRequest(
 	profileName string,
 	profile *config.Profile,
 ) (*mcp.CallToolResult, error) {
-	var requestBody *openapi3.RequestBody
-	if operation.RequestBody != nil {
-		requestBody = operation.RequestBody.Value
+	if result := checkArgumentLimits(operation, arguments, &profile.SafetyConfig); result != nil {
+		return result, nil
 	}
 
-	httpReq, err := h.requestBuilder.BuildRequest(method, path, profile.BaseURL, arguments, operation.Parameters, requestBody)
+	baseURL := spec.OperationBaseURL(h.spec, path, operation, profile.BaseURL)
+	httpReq, err := h.requestBuilder.BuildRequestForOperation(method, path, baseURL, operation, arguments)
 	if err != nil {
 		return errorResultProg("Failed to build request: %v", err), nil
 	}
 
-	if err := h.requestBuilder.InjectAuth(httpReq, h.appConfig.Name, profileName, &profile.Auth); err != nil {
+	// Profile headers go first so a stored credential wins over them
+	for key, value := range profile.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	if err := h.requestBuilder.InjectAuth(httpReq, h.appConfig.Name, profile); err != nil {
 		return nil, fmt.Errorf("failed to inject authentication: %w", err)
 	}
 
-	for key, value := range profile.Headers {
-		httpReq.Header.Set(key, value)
+	if err := h.requestBuilder.ApplyInterceptors(httpReq); err != nil {
+		return errorResultProg("Request aborted: %v", err), nil
+	}
+
+	client, err := upstreamClient(h.httpClient, h.requestBuilder, h.appConfig.Name, profile, h.metrics)
+	if err != nil {
+		return errorResultProg("Failed to execute API call: %v", err), nil
 	}
 
-	httpResp, err := h.httpClient.Do(httpReq)
+	// Ask the breaker only once the call can be made, so a half-open trial
+	// slot is never taken by a call that records no outcome.
+	key := circuitKey(httpReq, path)
+	if ok, retryAfter := h.breaker.Allow(key); !ok {
+		return circuitOpenResult(key, retryAfter), nil
+	}
+
+	httpResp, err := client.Do(httpReq)
 	if err != nil {
+		h.breaker.RecordFailure(key)
 		return errorResultProg("Failed to execute API call: %v", err), nil
 	}
 	defer func() { _ = httpResp.Body.Close() }()
 
+	h.breaker.Record(key, httpResp.StatusCode)
+
 	bodyBytes, err := io.ReadAll(httpResp.Body)
 	if err != nil {
 		return errorResultProg("Failed to read response body: %v", err), nil
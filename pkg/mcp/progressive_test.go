@@ -8,9 +8,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nomagicln/open-bridge/pkg/config"
 	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -420,3 +422,17 @@ func getTextContent(result *mcp.CallToolResult) string {
 	}
 	return ""
 }
+
+func TestProgressiveHandler_SetMapper(t *testing.T) {
+	handler, err := NewProgressiveHandler(request.NewBuilder(nil), nil, SearchEnginePredicate)
+	require.NoError(t, err)
+	defer func() { _ = handler.Close() }()
+
+	base := semantic.NewMapper(semantic.WithOperationIDVerbs())
+	handler.SetMapper(base)
+	handler.SetAppConfig(&config.AppConfig{Name: "test", VerbMappings: map[string]string{"PATCH": "update"}}, "")
+
+	mapper := handler.registry.mapper
+	assert.Equal(t, "list", mapper.MapVerb("POST", "/customers/search", &openapi3.Operation{OperationID: "listCustomers"}))
+	assert.Equal(t, "update", mapper.MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}))
+}
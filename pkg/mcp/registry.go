@@ -9,6 +9,7 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
 )
 
 // ToolRegistry stores all tool definitions and manages loaded tool cache.
@@ -26,6 +27,12 @@ type ToolRegistry struct {
 	// operationMap maps tool ID to (method, path) for request building
 	operationMap map[string]OperationInfo
 
+	// mapper derives tool names for operations without a usable operationId
+	mapper *semantic.Mapper
+
+	// filter excludes operations from the registry, in addition to the safety config
+	filter semantic.OperationFilter
+
 	mu sync.RWMutex
 }
 
@@ -46,6 +53,22 @@ func NewToolRegistry() *ToolRegistry {
 	}
 }
 
+// SetMapper sets the semantic mapper used to name tools. It must be called
+// before BuildFromSpec to take effect.
+func (r *ToolRegistry) SetMapper(mapper *semantic.Mapper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mapper = mapper
+}
+
+// SetOperationFilter restricts the registry to operations the filter accepts.
+// It must be called before BuildFromSpec to take effect.
+func (r *ToolRegistry) SetOperationFilter(filter semantic.OperationFilter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filter = filter
+}
+
 // BuildFromSpec populates the registry from an OpenAPI specification.
 // It extracts all operations and converts them to tool definitions and metadata.
 func (r *ToolRegistry) BuildFromSpec(spec *openapi3.T, safetyConfig *config.SafetyConfig) error {
@@ -92,7 +115,7 @@ func (r *ToolRegistry) processPathItem(path string, pathItem *openapi3.PathItem,
 
 // processOperation processes a single operation.
 func (r *ToolRegistry) processOperation(path, method string, op *openapi3.Operation, safetyConfig *config.SafetyConfig) {
-	if op == nil {
+	if op == nil || semantic.IsHidden(op) {
 		return
 	}
 
@@ -100,7 +123,11 @@ func (r *ToolRegistry) processOperation(path, method string, op *openapi3.Operat
 		return
 	}
 
-	toolID := GenerateToolName(method, path, op)
+	if r.filter != nil && !r.filter(op.OperationID, method, path, op) {
+		return
+	}
+
+	toolID := GenerateToolNameWithMapper(r.mapper, method, path, op)
 
 	if !isToolAllowedByConfig(toolID, safetyConfig) {
 		return
@@ -119,7 +146,7 @@ func (r *ToolRegistry) isOperationAllowed(method string, safetyConfig *config.Sa
 
 // registerTool registers a tool in the registry.
 func (r *ToolRegistry) registerTool(path, method string, op *openapi3.Operation, toolID string) {
-	tool := convertOperationToMCPTool(method, path, op)
+	tool := convertOperationToMCPTool(r.mapper, method, path, op)
 	meta := r.buildToolMetadata(toolID, tool, method, path)
 
 	if op != nil && op.Tags != nil {
@@ -147,9 +174,9 @@ func (r *ToolRegistry) buildToolMetadata(toolID string, tool mcp.Tool, method, p
 }
 
 // convertOperationToMCPTool converts an OpenAPI operation to an MCP tool.
-func convertOperationToMCPTool(method, path string, op *openapi3.Operation) mcp.Tool {
+func convertOperationToMCPTool(mapper *semantic.Mapper, method, path string, op *openapi3.Operation) mcp.Tool {
 	// Use smart naming to generate clean, non-redundant tool names
-	name := GenerateToolName(method, path, op)
+	name := GenerateToolNameWithMapper(mapper, method, path, op)
 
 	properties := make(map[string]any)
 	required := []string{} // Initialize as empty slice, not nil (nil becomes null in JSON)
@@ -170,6 +197,7 @@ func convertOperationToMCPTool(method, path string, op *openapi3.Operation) mcp.
 		Name:        name,
 		Description: op.Summary,
 		InputSchema: inputSchema,
+		Annotations: toolAnnotations(method, op),
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -66,6 +67,38 @@ func TestToolRegistry_BuildFromSpec_WithSafetyConfig(t *testing.T) {
 	}
 }
 
+func TestToolRegistry_BuildFromSpec_WithOperationFilter(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.SetOperationFilter(func(_, method, _ string, _ *openapi3.Operation) bool {
+		return method == "GET"
+	})
+
+	err := registry.BuildFromSpec(createTestSpec(), &config.SafetyConfig{DeniedOperations: []string{"getPetById"}})
+	require.NoError(t, err)
+
+	// The filter applies in addition to the deny list
+	ids := make([]string, 0)
+	for _, m := range registry.GetMetadata() {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"listPets"}, ids)
+}
+
+func TestToolRegistry_BuildFromSpec_SkipsHiddenOperations(t *testing.T) {
+	spec := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test", Version: "1.0.0"}, Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/pets", &openapi3.PathItem{
+		Get:  &openapi3.Operation{OperationID: "listPets"},
+		Post: &openapi3.Operation{OperationID: "createPet", Extensions: map[string]any{semantic.HiddenExtension: true}},
+	})
+
+	registry := NewToolRegistry()
+	require.NoError(t, registry.BuildFromSpec(spec, nil))
+
+	assert.Equal(t, 1, registry.ToolCount())
+	_, ok := registry.GetOperationInfo("createPet")
+	assert.False(t, ok)
+}
+
 func TestToolRegistry_BuildFromSpec_WithDeniedOperations(t *testing.T) {
 	registry := NewToolRegistry()
 
@@ -12,6 +12,10 @@ import (
 // ServerFactory creates and manages MCP servers.
 type ServerFactory struct {
 	Impl *mcp.Implementation
+
+	// Metrics, if set, counts the tool calls of created servers and is served
+	// at MetricsPath by the sse transport.
+	Metrics *Metrics
 }
 
 // NewServerFactory creates a new server factory.
@@ -26,7 +30,27 @@ func NewServerFactory(name, version string) *ServerFactory {
 
 // CreateServer creates a new MCP server instance.
 func (f *ServerFactory) CreateServer() *mcp.Server {
-	return mcp.NewServer(f.Impl, &mcp.ServerOptions{})
+	server := mcp.NewServer(f.Impl, &mcp.ServerOptions{})
+	if f.Metrics != nil {
+		server.AddReceivingMiddleware(f.Metrics.Middleware())
+	}
+	return server
+}
+
+// HTTPHandler returns the HTTP handler serving server over SSE, along with
+// the metrics endpoint if metrics are enabled.
+func (f *ServerFactory) HTTPHandler(server *mcp.Server) http.Handler {
+	sseHandler := mcp.NewSSEHandler(func(r *http.Request) *mcp.Server {
+		return server
+	}, nil)
+	if f.Metrics == nil {
+		return sseHandler
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, f.Metrics)
+	mux.Handle("/", sseHandler)
+	return mux
 }
 
 // RunServer runs the server with the specified transport.
@@ -35,13 +59,9 @@ func (f *ServerFactory) RunServer(ctx context.Context, server *mcp.Server, trans
 	case "stdio":
 		return server.Run(ctx, &mcp.StdioTransport{})
 	case "sse":
-		sseHandler := mcp.NewSSEHandler(func(r *http.Request) *mcp.Server {
-			return server
-		}, nil)
-
 		addr := ":" + port
 		fmt.Fprintf(os.Stderr, "Starting SSE server on %s\n", addr)
-		return http.ListenAndServe(addr, sseHandler)
+		return http.ListenAndServe(addr, f.HTTPHandler(server))
 	default:
 		return fmt.Errorf("unsupported transport: %s", transport)
 	}
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// readOnlyVerbs are x-cli-verb values that mark an operation as read-only
+// regardless of its HTTP method, e.g. a search exposed as POST.
+var readOnlyVerbs = map[string]bool{
+	"get": true, "list": true, "describe": true, "show": true,
+	"search": true, "query": true, "find": true, "check": true,
+}
+
+// destructiveVerbs are x-cli-verb values that mark an operation as destructive
+// regardless of its HTTP method, e.g. a delete exposed as POST.
+var destructiveVerbs = map[string]bool{
+	"delete": true, "remove": true, "destroy": true, "purge": true,
+}
+
+// toolAnnotations derives the MCP behavior hints of an operation from its HTTP
+// method: GET, HEAD, and OPTIONS are read-only, DELETE is destructive, PUT and
+// DELETE are idempotent, and POST is additive. An x-cli-verb extension naming a
+// read or delete verb overrides the method.
+func toolAnnotations(method string, op *openapi3.Operation) *mcp.ToolAnnotations {
+	verb := ""
+	if op != nil {
+		if ext, ok := op.Extensions["x-cli-verb"].(string); ok {
+			verb = strings.ToLower(ext)
+		}
+	}
+
+	switch {
+	case readOnlyVerbs[verb]:
+		return readOnlyAnnotations()
+	case destructiveVerbs[verb]:
+		return &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)}
+	}
+
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		return readOnlyAnnotations()
+	case "PUT", "DELETE":
+		return &mcp.ToolAnnotations{DestructiveHint: boolPtr(true), IdempotentHint: true}
+	case "POST":
+		return &mcp.ToolAnnotations{DestructiveHint: boolPtr(false)}
+	default:
+		return &mcp.ToolAnnotations{DestructiveHint: boolPtr(true)}
+	}
+}
+
+// readOnlyAnnotations returns the hints of a tool that does not modify state.
+func readOnlyAnnotations() *mcp.ToolAnnotations {
+	return &mcp.ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+}
+
+// boolPtr returns a pointer to b.
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/request"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
+)
+
+func TestBuildMCPTools_Annotations(t *testing.T) {
+	handler := NewHandler(semantic.NewMapper(), request.NewBuilder(nil), nil)
+
+	annotations := make(map[string]bool)
+	for _, tool := range handler.BuildMCPTools(createTestOpenAPISpec(), nil) {
+		if tool.Annotations == nil {
+			t.Fatalf("expected tool %s to be annotated", tool.Name)
+		}
+		annotations[tool.Name] = true
+
+		a := tool.Annotations
+		switch tool.Name {
+		case "listPets", "getPetById":
+			if !a.ReadOnlyHint || !a.IdempotentHint {
+				t.Errorf("expected GET tool %s to be read-only and idempotent, got %+v", tool.Name, a)
+			}
+		case "deletePet":
+			if a.ReadOnlyHint || a.DestructiveHint == nil || !*a.DestructiveHint || !a.IdempotentHint {
+				t.Errorf("expected DELETE tool to be destructive and idempotent, got %+v", a)
+			}
+		case "createPet":
+			if a.ReadOnlyHint || a.DestructiveHint == nil || *a.DestructiveHint || a.IdempotentHint {
+				t.Errorf("expected POST tool to be additive and not idempotent, got %+v", a)
+			}
+		}
+	}
+	if !annotations["listPets"] || !annotations["deletePet"] {
+		t.Fatalf("expected listPets and deletePet tools, got %v", annotations)
+	}
+}
+
+func TestToolAnnotations_VerbOverride(t *testing.T) {
+	search := &openapi3.Operation{Extensions: map[string]any{"x-cli-verb": "search"}}
+	if a := toolAnnotations("POST", search); !a.ReadOnlyHint {
+		t.Errorf("expected POST with x-cli-verb search to be read-only, got %+v", a)
+	}
+
+	remove := &openapi3.Operation{Extensions: map[string]any{"x-cli-verb": "remove"}}
+	if a := toolAnnotations("POST", remove); a.ReadOnlyHint || a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("expected POST with x-cli-verb remove to be destructive, got %+v", a)
+	}
+
+	if a := toolAnnotations("PUT", &openapi3.Operation{}); !a.IdempotentHint || a.ReadOnlyHint {
+		t.Errorf("expected PUT to be idempotent, got %+v", a)
+	}
+}
@@ -20,6 +20,13 @@ import (
 //   - update_todo_todos__todo_id__put → todos_update
 //   - batch_create_todos_todos_batch_post → todos_batch_create
 func GenerateToolName(method, path string, op *openapi3.Operation) string {
+	return GenerateToolNameWithMapper(nil, method, path, op)
+}
+
+// GenerateToolNameWithMapper is like GenerateToolName but derives fallback names
+// with the given mapper, so an app's custom verb mappings apply. A nil mapper
+// uses the default mapping.
+func GenerateToolNameWithMapper(mapper *semantic.Mapper, method, path string, op *openapi3.Operation) string {
 	// If operationId is clean and doesn't have redundancy, use it directly
 	if op.OperationID != "" && !hasRedundancy(op.OperationID) {
 		return op.OperationID
@@ -34,7 +41,9 @@ func GenerateToolName(method, path string, op *openapi3.Operation) string {
 	}
 
 	// Use semantic mapper to extract resource and verb
-	mapper := semantic.NewMapper()
+	if mapper == nil {
+		mapper = semantic.NewMapper()
+	}
 	resource := mapper.ExtractResource(path, op)
 	verb := mapper.MapVerb(method, path, op)
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/nomagicln/open-bridge/pkg/semantic"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -267,3 +268,11 @@ func TestNormalizeOperationID(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateToolNameWithMapper(t *testing.T) {
+	op := &openapi3.Operation{}
+	mapper := semantic.NewMapper(semantic.WithMethodVerbs(map[string]string{"PATCH": "update"}))
+
+	assert.Equal(t, "repos_apply", GenerateToolName("PATCH", "/repos/{repo}", op))
+	assert.Equal(t, "repos_update", GenerateToolNameWithMapper(mapper, "PATCH", "/repos/{repo}", op))
+}
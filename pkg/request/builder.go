@@ -2,31 +2,151 @@
 package request
 
 import (
-	"bytes"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	ghodssyaml "github.com/ghodss/yaml"
 	"github.com/nomagicln/open-bridge/pkg/config"
 	"github.com/nomagicln/open-bridge/pkg/credential"
 )
 
+// BodyYAMLParam is the parameter carrying a request body given as YAML with
+// --body-yaml, inline or as @file.
+const BodyYAMLParam = "body-yaml"
+
+// ErrCircularSchema is returned when a value cannot be converted because its schema refers to itself.
+var ErrCircularSchema = errors.New("circular schema reference")
+
+// schemaSet tracks the schemas on the current conversion path.
+// Schemas are keyed by pointer, so a $ref cycle resolves to the same entry.
+type schemaSet map[*openapi3.Schema]bool
+
+// HeaderRequestID is the header carrying the generated request correlation id.
+const HeaderRequestID = "X-Request-ID"
+
 // Builder constructs HTTP requests from OpenAPI operations and parameters.
 type Builder struct {
-	credMgr *credential.Manager
+	credMgr      *credential.Manager
+	interceptors []RequestInterceptor
+	userAgent    string
+	clients      *ClientPool
+	tokens       *tokenCache
+}
+
+// BuilderOption configures a Builder.
+type BuilderOption func(*Builder)
+
+// WithUserAgent sets the User-Agent sent on requests that do not set one.
+func WithUserAgent(userAgent string) BuilderOption {
+	return func(b *Builder) {
+		b.userAgent = userAgent
+	}
+}
+
+// UserAgent returns the default User-Agent for an OpenBridge version.
+func UserAgent(version string) string {
+	return "OpenBridge/" + version
+}
+
+// BuildOption adjusts how a single request is validated and built.
+type BuildOption func(*buildConfig)
+
+// buildConfig holds the per-request settings applied by BuildOptions.
+type buildConfig struct {
+	skipBodyValidation  bool
+	enumCaseInsensitive bool
+	prefer              string
+	filesDir            string
+	formFields          []formField
+	queryParams         []queryParam
+	contentType         string
+	skipTokenRequest    bool
+	skipRequestID       bool
+}
+
+// WithoutBodyValidation skips request body checks: required body fields are not
+// enforced, a --body value is sent verbatim, and body fields that cannot be
+// shaped by the schema are sent as given. Path, query, and header parameters
+// are still validated and substituted.
+func WithoutBodyValidation() BuildOption {
+	return func(c *buildConfig) {
+		c.skipBodyValidation = true
+	}
+}
+
+// WithEnumCaseInsensitive makes parameter enum validation ignore case. Matching
+// values are replaced with the casing declared in the spec before sending.
+func WithEnumCaseInsensitive() BuildOption {
+	return func(c *buildConfig) {
+		c.enumCaseInsensitive = true
+	}
+}
+
+// WithPrefer sets the Prefer header, e.g. return=minimal, on write requests
+// (POST, PUT, PATCH, and DELETE) that do not already set one.
+func WithPrefer(value string) BuildOption {
+	return func(c *buildConfig) {
+		c.prefer = value
+	}
+}
+
+// WithoutTokenRequest makes InjectAuth send OAuth2TokenPlaceholder as the
+// bearer token of an oauth2_client_credentials profile instead of requesting
+// an access token, for requests that are printed rather than sent.
+func WithoutTokenRequest() BuildOption {
+	return func(c *buildConfig) {
+		c.skipTokenRequest = true
+	}
+}
+
+// WithoutRequestID leaves out the generated X-Request-ID header, for examples
+// whose output should not change from one run to the next.
+func WithoutRequestID() BuildOption {
+	return func(c *buildConfig) {
+		c.skipRequestID = true
+	}
+}
+
+// newBuildConfig applies the options to a default configuration.
+func newBuildConfig(opts []BuildOption) buildConfig {
+	var cfg buildConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
 }
 
 // NewBuilder creates a new request builder.
-func NewBuilder(credMgr *credential.Manager) *Builder {
-	return &Builder{
-		credMgr: credMgr,
+func NewBuilder(credMgr *credential.Manager, opts ...BuilderOption) *Builder {
+	b := &Builder{
+		credMgr:   credMgr,
+		userAgent: UserAgent("dev"),
+		clients:   NewClientPool(),
+		tokens:    newTokenCache(),
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// HTTPClient returns the pooled HTTP client for a profile of an app, configured
+// with the profile's TLS settings. Requests built by this builder for the same
+// profile share the client and its connections.
+func (b *Builder) HTTPClient(appName string, profile *config.Profile) (*http.Client, error) {
+	return b.clients.Client(appName, profile)
 }
 
 // BuildRequest constructs an HTTP request from an operation and parameters.
@@ -35,37 +155,57 @@ func (b *Builder) BuildRequest(
 	params map[string]any,
 	opParams openapi3.Parameters,
 	requestBody *openapi3.RequestBody,
+	opts ...BuildOption,
 ) (*http.Request, error) {
-	// Substitute path parameters
-	finalPath := b.substitutePathParams(path, params, opParams)
-
-	// Build query string
-	queryString := b.buildQueryString(params, opParams)
-
-	// Construct full URL
-	fullURL := b.buildFullURL(baseURL, finalPath, queryString)
-
-	// Build request body for non-GET methods
-	bodyReader, err := b.createBodyReader(method, params, opParams, requestBody)
+	planned, err := b.PlanRequest(method, path, baseURL, params, opParams, requestBody, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build request body: %w", err)
+		return nil, err
 	}
 
-	// Create request
-	req, err := b.createHTTPRequest(method, fullURL, bodyReader)
+	req, err := planned.HTTPRequest()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	return req, nil
+}
 
-	// Set content type if we have a body
-	if bodyReader != nil {
-		req.Header.Set("Content-Type", "application/json")
+// isWriteMethod reports whether method modifies server state.
+func isWriteMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
 	}
+	return false
+}
 
-	// Add header parameters
-	b.addHeaderParams(req, params, opParams)
+// newRequestID returns a random (version 4) UUID.
+func newRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
 
-	return req, nil
+// BuildRequestForOperation constructs an HTTP request directly from an operation object.
+// The operation's parameters and request body are extracted internally, so callers
+// only need the method, path template, and base URL alongside the parameter values.
+func (b *Builder) BuildRequestForOperation(
+	method, pathTemplate, baseURL string,
+	op *openapi3.Operation,
+	params map[string]any,
+	opts ...BuildOption,
+) (*http.Request, error) {
+	if op == nil {
+		return b.BuildRequest(method, pathTemplate, baseURL, params, nil, nil, opts...)
+	}
+
+	var requestBody *openapi3.RequestBody
+	if op.RequestBody != nil {
+		requestBody = op.RequestBody.Value
+	}
+
+	return b.BuildRequest(method, pathTemplate, baseURL, params, op.Parameters, requestBody, opts...)
 }
 
 // buildFullURL constructs the full URL from base URL, path, and query string.
@@ -77,43 +217,131 @@ func (b *Builder) buildFullURL(baseURL, path, queryString string) string {
 	return fullURL
 }
 
-// createBodyReader creates a body reader for the request.
-func (b *Builder) createBodyReader(method string, params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody) (*bytes.Reader, error) {
-	if method == http.MethodGet || method == http.MethodHead {
-		return nil, nil
+// createBody builds the request body and returns its content type, or a nil
+// body if the request has none.
+func (b *Builder) createBody(method string, params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody, opts ...BuildOption) ([]byte, string, error) {
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return nil, "", nil
 	}
 
-	bodyData, err := b.buildRequestBody(params, opParams, requestBody)
+	cfg := newBuildConfig(opts)
+	if cfg.contentType != "" {
+		bodyData, contentType, err := b.createBodyForContentType(params, opParams, requestBody, cfg, opts)
+		if err != nil || bodyData == nil {
+			return nil, "", err
+		}
+		return bodyData, contentType, nil
+	}
+	if cfg.isMultipart() {
+		bodyData, contentType, err := b.buildMultipartBody(params, opParams, requestBody, cfg)
+		if err != nil {
+			return nil, "", err
+		}
+		return bodyData, contentType, nil
+	}
+
+	contentType := "application/json"
+	var bodyData []byte
+	var err error
+	if rawType := rawBodyContentType(requestBody); rawType != "" {
+		contentType = rawType
+		bodyData, err = b.buildRawBody(params)
+	} else if ndjsonType := ndjsonBodyContentType(requestBody); ndjsonType != "" {
+		contentType = ndjsonType
+		bodyData, err = b.buildNDJSONBody(params)
+	} else {
+		bodyData, err = b.buildRequestBody(params, opParams, requestBody, opts...)
+	}
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if bodyData == nil {
-		return nil, nil
+		return nil, "", nil
+	}
+	return bodyData, contentType, nil
+}
+
+// rawBodyContentType returns the declared media type when the request body only
+// accepts plain text or binary content, or "" if the body should be JSON-encoded.
+func rawBodyContentType(requestBody *openapi3.RequestBody) string {
+	if requestBody == nil || len(requestBody.Content) == 0 {
+		return ""
 	}
 
-	reader := bytes.NewReader(bodyData)
-	return reader, nil
+	var textType, binaryType string
+	for declared := range requestBody.Content {
+		mediaType, _, err := mime.ParseMediaType(declared)
+		if err != nil {
+			return ""
+		}
+		switch mediaType {
+		case "text/plain":
+			textType = declared
+		case "application/octet-stream":
+			binaryType = declared
+		default:
+			return ""
+		}
+	}
+
+	if binaryType != "" {
+		return binaryType
+	}
+	return textType
 }
 
-// createHTTPRequest creates an HTTP request with or without a body.
-func (b *Builder) createHTTPRequest(method, urlStr string, bodyReader *bytes.Reader) (*http.Request, error) {
-	if bodyReader != nil {
-		return http.NewRequest(method, urlStr, bodyReader)
+// buildRawBody returns the --body value verbatim, reading it from a file for @path values.
+// Unlike JSON bodies, the content is not parsed or validated.
+func (b *Builder) buildRawBody(params map[string]any) ([]byte, error) {
+	body, ok := params["body"]
+	if !ok {
+		return nil, nil
+	}
+
+	switch v := body.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if filename, ok := strings.CutPrefix(v, "@"); ok {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read body from file %s: %w", filename, err)
+			}
+			return data, nil
+		}
+		return []byte(v), nil
+	default:
+		return []byte(fmt.Sprintf("%v", v)), nil
 	}
-	return http.NewRequest(method, urlStr, nil)
 }
 
-// InjectAuth adds authentication to a request based on configuration.
-func (b *Builder) InjectAuth(req *http.Request, appName, profileName string, authConfig *config.AuthConfig) error {
+// InjectAuth adds authentication to a request based on the profile's auth
+// configuration. With the oauth2_client_credentials auth type, an access
+// token is first obtained from the token endpoint through the profile's
+// pooled client, or reused from an earlier request of the profile, and sent
+// as a bearer token.
+func (b *Builder) InjectAuth(req *http.Request, appName string, profile *config.Profile, opts ...BuildOption) error {
 	if b.credMgr == nil {
 		return nil
 	}
 
-	cred, err := b.credMgr.GetCredential(appName, profileName)
+	cred, err := b.credMgr.GetCredential(appName, profile.Name)
 	if err != nil {
 		return nil // No credential, skip auth
 	}
 
+	authConfig := &profile.Auth
+	if authConfig.Type == string(credential.CredentialTypeOAuth2ClientCredentials) {
+		if newBuildConfig(opts).skipTokenRequest {
+			return injectBearerToken(req, OAuth2TokenPlaceholder)
+		}
+		token, err := b.clientCredentialsToken(req, appName, profile, cred)
+		if err != nil {
+			return err
+		}
+		return injectBearerToken(req, token)
+	}
+
 	return b.injectAuthCredentials(req, authConfig, cred)
 }
 
@@ -191,7 +419,7 @@ func (b *Builder) buildQueryString(params map[string]any, opParams openapi3.Para
 			continue
 		}
 		if val, ok := params[param.Name]; ok {
-			values.Add(param.Name, fmt.Sprintf("%v", val))
+			addQueryParam(values, param, val)
 		}
 	}
 	return values.Encode()
@@ -208,17 +436,77 @@ func (b *Builder) addHeaderParams(req *http.Request, params map[string]any, opPa
 			continue
 		}
 		if val, ok := params[param.Name]; ok {
-			req.Header.Set(param.Name, fmt.Sprintf("%v", val))
+			req.Header.Set(param.Name, simpleStyleValue(param, val))
 		}
 	}
 }
 
+// simpleStyleValue serializes a header parameter value in the OpenAPI simple
+// style. Arrays are comma-joined (a,b,c) whether or not they are exploded.
+// Objects are joined as key,value pairs (k1,v1,k2,v2), or as k1=v1,k2=v2 when
+// exploded. For array and object parameters, a JSON value given as a string,
+// as CLI flags are, is decoded first.
+func simpleStyleValue(param *openapi3.Parameter, val any) string {
+	explode := param.Explode != nil && *param.Explode
+	if str, ok := val.(string); ok && isStructuredParam(param) {
+		var decoded any
+		if err := json.Unmarshal([]byte(str), &decoded); err == nil {
+			val = decoded
+		}
+	}
+
+	switch v := val.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+		return strings.Join(items, ",")
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]any:
+		keys := slices.Sorted(maps.Keys(v))
+		parts := make([]string, 0, 2*len(keys))
+		for _, key := range keys {
+			if explode {
+				parts = append(parts, fmt.Sprintf("%s=%v", key, v[key]))
+			} else {
+				parts = append(parts, key, fmt.Sprintf("%v", v[key]))
+			}
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// isStructuredParam reports whether a parameter's schema is an array or object.
+func isStructuredParam(param *openapi3.Parameter) bool {
+	if param.Schema == nil || param.Schema.Value == nil || param.Schema.Value.Type == nil {
+		return false
+	}
+	types := param.Schema.Value.Type
+	return types.Is("array") || types.Is("object")
+}
+
 // buildRequestBody builds the request body from parameters.
-func (b *Builder) buildRequestBody(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody) ([]byte, error) {
+func (b *Builder) buildRequestBody(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody, opts ...BuildOption) ([]byte, error) {
+	cfg := newBuildConfig(opts)
+
+	if err := checkWholeBodyFlags(params); err != nil {
+		return nil, err
+	}
+
 	// Check for direct body input via --body flag
 	if body, ok := params["body"]; ok {
+		if str, isString := body.(string); isString && cfg.skipBodyValidation && !strings.HasPrefix(str, "@") {
+			return []byte(str), nil
+		}
 		return b.handleBodyFlag(body)
 	}
+	if body, ok := params[BodyYAMLParam]; ok {
+		return b.handleBodyYAMLFlag(body)
+	}
 
 	// Filter out path, query, and header params - remaining are body params
 	bodyParams := b.extractBodyParams(params, opParams)
@@ -226,7 +514,11 @@ func (b *Builder) buildRequestBody(params map[string]any, opParams openapi3.Para
 	// If we have a request body schema, construct body from schema
 	if requestBody != nil && requestBody.Content != nil {
 		if jsonContent, ok := requestBody.Content["application/json"]; ok && jsonContent.Schema != nil {
-			return b.buildBodyFromSchema(bodyParams, jsonContent.Schema.Value)
+			data, err := b.buildBodyFromSchema(bodyParams, jsonContent.Schema.Value)
+			if err != nil && cfg.skipBodyValidation {
+				return json.Marshal(bodyParams)
+			}
+			return data, err
 		}
 	}
 
@@ -241,7 +533,7 @@ func (b *Builder) buildRequestBody(params map[string]any, opParams openapi3.Para
 // handleBodyFlag processes the --body flag value.
 // Supports:
 // - Direct JSON: --body '{"key":"value"}'
-// - File input: --body @file.json
+// - File input: --body @file.json, or @file.yaml / @file.yml for YAML
 func (b *Builder) handleBodyFlag(body any) ([]byte, error) {
 	switch v := body.(type) {
 	case string:
@@ -267,8 +559,41 @@ func (b *Builder) handleBodyFlag(body any) ([]byte, error) {
 	}
 }
 
+// checkWholeBodyFlags rejects a body given both with --body and --body-yaml.
+func checkWholeBodyFlags(params map[string]any) error {
+	_, hasBody := params["body"]
+	_, hasYAML := params[BodyYAMLParam]
+	if hasBody && hasYAML {
+		return fmt.Errorf("--body and --body-yaml cannot be used together")
+	}
+	return nil
+}
+
+// handleBodyYAMLFlag processes the --body-yaml flag value, inline YAML or
+// @file, and converts it to JSON.
+func (b *Builder) handleBodyYAMLFlag(body any) ([]byte, error) {
+	str, ok := body.(string)
+	if !ok {
+		return json.Marshal(body)
+	}
+	if filename, ok := strings.CutPrefix(str, "@"); ok {
+		return readYAMLBodyFromFile(filename)
+	}
+
+	data, err := yamlBodyToJSON([]byte(str))
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML in --body-yaml flag: %w", err)
+	}
+	return data, nil
+}
+
 // readBodyFromFile reads request body from a file.
+// Files with a .yaml or .yml extension are read as YAML.
 func (b *Builder) readBodyFromFile(filename string) ([]byte, error) {
+	if isYAMLFile(filename) {
+		return readYAMLBodyFromFile(filename)
+	}
+
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read body from file %s: %w", filename, err)
@@ -283,6 +608,37 @@ func (b *Builder) readBodyFromFile(filename string) ([]byte, error) {
 	return data, nil
 }
 
+// readYAMLBodyFromFile reads a YAML request body from a file and converts it to JSON.
+func readYAMLBodyFromFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body from file %s: %w", filename, err)
+	}
+
+	body, err := yamlBodyToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("file %s does not contain valid YAML: %w", filename, err)
+	}
+	return body, nil
+}
+
+// yamlBodyToJSON converts a YAML document to JSON. An empty document is an error.
+func yamlBodyToJSON(data []byte) ([]byte, error) {
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, errors.New("empty document")
+	}
+	return ghodssyaml.YAMLToJSON(data)
+}
+
+// isYAMLFile reports whether filename has a .yaml or .yml extension.
+func isYAMLFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
 // extractBodyParams filters out path, query, and header params.
 func (b *Builder) extractBodyParams(params map[string]any, opParams openapi3.Parameters) map[string]any {
 	bodyParams := make(map[string]any)
@@ -297,7 +653,7 @@ func (b *Builder) extractBodyParams(params map[string]any, opParams openapi3.Par
 
 	for key, val := range params {
 		// Skip known parameter names and special flags
-		if !paramNames[key] && key != "body" && key != "output" && key != "json" && key != "yaml" {
+		if !paramNames[key] && key != "body" && key != BodyYAMLParam && key != "output" && key != "json" && key != "yaml" {
 			bodyParams[key] = val
 		}
 	}
@@ -312,30 +668,35 @@ func (b *Builder) buildBodyFromSchema(params map[string]any, schema *openapi3.Sc
 		return json.Marshal(params)
 	}
 
+	// Rebuild dotted flags such as --address.city into nested objects
+	params, err := nestDottedParams(params, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request body: %w", err)
+	}
+
 	// Build body according to schema type
-	body := b.constructFromSchema(params, schema)
+	body, err := b.constructFromSchema(params, schema, schemaSet{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request body: %w", err)
+	}
 
 	return json.Marshal(body)
 }
 
 // constructFromSchema recursively constructs data structure from schema.
-func (b *Builder) constructFromSchema(params map[string]any, schema *openapi3.Schema) any {
-	if schema == nil || schema.Type == nil {
-		return params
-	}
-
-	switch {
-	case schema.Type.Is("object"):
-		return b.constructObject(params, schema)
-	case schema.Type.Is("array"):
-		return b.constructArray(params, schema)
+func (b *Builder) constructFromSchema(params map[string]any, schema *openapi3.Schema, visiting schemaSet) (any, error) {
+	switch singleType(schema) {
+	case "object":
+		return b.constructObject(params, schema, visiting)
+	case "array":
+		return b.constructArray(params, schema, visiting)
 	default:
-		return params
+		return params, nil
 	}
 }
 
 // constructObject constructs an object from parameters according to schema.
-func (b *Builder) constructObject(params map[string]any, schema *openapi3.Schema) map[string]any {
+func (b *Builder) constructObject(params map[string]any, schema *openapi3.Schema, visiting schemaSet) (map[string]any, error) {
 	result := make(map[string]any)
 
 	// Process each property defined in schema
@@ -348,7 +709,17 @@ func (b *Builder) constructObject(params map[string]any, schema *openapi3.Schema
 
 		// Check if we have a value for this property
 		if val, ok := params[propName]; ok {
-			result[propName] = b.convertToSchemaType(val, propSchema)
+			// --name null sends null to a property that accepts it, such as
+			// an OpenAPI 3.1 ["string", "null"] property
+			if val == nullType && AllowsNull(propSchema) {
+				result[propName] = nil
+				continue
+			}
+			converted, err := b.convertToSchemaType(val, propSchema, visiting)
+			if err != nil {
+				return nil, fmt.Errorf("property '%s': %w", propName, err)
+			}
+			result[propName] = converted
 		}
 	}
 
@@ -359,13 +730,13 @@ func (b *Builder) constructObject(params map[string]any, schema *openapi3.Schema
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 // constructArray constructs an array from parameters according to schema.
 // This is called when the top-level schema is an array type.
-func (b *Builder) constructArray(params any, schema *openapi3.Schema) []any {
-	return b.constructArrayValue(params, schema)
+func (b *Builder) constructArray(params any, schema *openapi3.Schema, visiting schemaSet) ([]any, error) {
+	return b.constructArrayValue(params, schema, visiting)
 }
 
 // constructArrayValue handles array values from flags.
@@ -384,20 +755,32 @@ func parseCommaSeparatedValues(v string) []any {
 }
 
 // convertArrayWithSchema converts array items according to schema.
-func (b *Builder) convertArrayWithSchema(rawItems []any, schema *openapi3.Schema) []any {
+// An array schema that is reached again while converting its own items refers
+// to itself, and converting a scalar against it would never terminate.
+func (b *Builder) convertArrayWithSchema(rawItems []any, schema *openapi3.Schema, visiting schemaSet) ([]any, error) {
 	if schema == nil || schema.Items == nil || schema.Items.Value == nil {
-		return rawItems
+		return rawItems, nil
+	}
+	if visiting[schema] {
+		return nil, fmt.Errorf("%w: array items refer back to the array schema", ErrCircularSchema)
 	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
 	itemSchema := schema.Items.Value
 	result := make([]any, len(rawItems))
 	for i, item := range rawItems {
-		result[i] = b.convertToSchemaType(item, itemSchema)
+		converted, err := b.convertToSchemaType(item, itemSchema, visiting)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = converted
 	}
-	return result
+	return result, nil
 }
 
 // constructArrayValue converts a value to an array.
-func (b *Builder) constructArrayValue(val any, schema *openapi3.Schema) []any {
+func (b *Builder) constructArrayValue(val any, schema *openapi3.Schema, visiting schemaSet) ([]any, error) {
 	var rawItems []any
 	switch v := val.(type) {
 	case []any:
@@ -417,22 +800,27 @@ func (b *Builder) constructArrayValue(val any, schema *openapi3.Schema) []any {
 		rawItems = []any{val}
 	}
 
-	return b.convertArrayWithSchema(rawItems, schema)
+	return b.convertArrayWithSchema(rawItems, schema, visiting)
 }
 
 // convertToSchemaType converts a value to match the schema type.
-func (b *Builder) convertToSchemaType(val any, schema *openapi3.Schema) any {
+func (b *Builder) convertToSchemaType(val any, schema *openapi3.Schema, visiting schemaSet) (any, error) {
 	if schema == nil || schema.Type == nil {
-		return val
+		return val, nil
+	}
+
+	// Convert the fields of nested objects, e.g. ones built from dotted flags
+	if obj, ok := val.(map[string]any); ok && slices.Contains(SchemaTypes(schema), "object") {
+		return b.constructObject(obj, schema, visiting)
 	}
 
 	// Handle non-string values
 	if !isStringValue(val) {
-		return handleNonStringValue(val, schema)
+		return handleNonStringValue(val, schema), nil
 	}
 
 	// Convert string values based on schema type
-	return convertStringValue(val.(string), schema, b)
+	return convertStringValue(val.(string), schema, b, visiting)
 }
 
 // isStringValue checks if the value is a string.
@@ -450,20 +838,29 @@ func handleNonStringValue(val any, schema *openapi3.Schema) any {
 }
 
 // convertStringValue converts a string value according to schema type.
-func convertStringValue(strVal string, schema *openapi3.Schema, builder *Builder) any {
-	switch {
-	case schema.Type.Is("integer"):
-		return convertToInt(strVal)
-	case schema.Type.Is("number"):
-		return convertToFloat(strVal)
-	case schema.Type.Is("boolean"):
-		return convertToBool(strVal)
-	case schema.Type.Is("object"):
-		return convertToObject(strVal)
-	case schema.Type.Is("array"):
-		return builder.constructArrayValue(strVal, schema)
+func convertStringValue(strVal string, schema *openapi3.Schema, builder *Builder, visiting schemaSet) (any, error) {
+	types := SchemaTypes(schema)
+	if len(types) > 1 {
+		return convertUnionValue(strVal, types, schema, builder, visiting)
+	}
+	return convertStringToType(strVal, singleType(schema), schema, builder, visiting)
+}
+
+// convertStringToType converts a string value to one of the schema's types.
+func convertStringToType(strVal, typ string, schema *openapi3.Schema, builder *Builder, visiting schemaSet) (any, error) {
+	switch typ {
+	case "integer":
+		return convertToInt(strVal), nil
+	case "number":
+		return convertToFloat(strVal), nil
+	case "boolean":
+		return convertToBool(strVal), nil
+	case "object":
+		return convertToObject(strVal), nil
+	case "array":
+		return builder.constructArrayValue(strVal, schema, visiting)
 	default:
-		return strVal
+		return strVal, nil
 	}
 }
 
@@ -504,7 +901,7 @@ func convertToObject(strVal string) any {
 }
 
 // checkRequiredParams validates that all required parameters are present.
-func (b *Builder) checkRequiredParams(params map[string]any, opParams openapi3.Parameters) error {
+func (b *Builder) checkRequiredParams(params map[string]any, opParams openapi3.Parameters, cfg buildConfig) error {
 	for _, paramRef := range opParams {
 		if paramRef == nil || paramRef.Value == nil {
 			continue
@@ -520,7 +917,13 @@ func (b *Builder) checkRequiredParams(params map[string]any, opParams openapi3.P
 		}
 
 		if param.Schema != nil && param.Schema.Value != nil {
-			convertedVal := b.convertToSchemaType(val, param.Schema.Value)
+			convertedVal, err := b.convertToSchemaType(val, param.Schema.Value, schemaSet{})
+			if err != nil {
+				return fmt.Errorf("parameter '%s': %w", param.Name, err)
+			}
+			if cfg.enumCaseInsensitive {
+				convertedVal = canonicalEnumValue(convertedVal, param.Schema.Value.Enum)
+			}
 			params[param.Name] = convertedVal
 			val = convertedVal
 		}
@@ -541,6 +944,9 @@ func (b *Builder) hasRequiredBody(params map[string]any, opParams openapi3.Param
 	if _, ok := params["body"]; ok {
 		return true
 	}
+	if _, ok := params[BodyYAMLParam]; ok {
+		return true
+	}
 
 	bodyParams := b.extractBodyParams(params, opParams)
 	return len(bodyParams) > 0
@@ -563,8 +969,10 @@ func (b *Builder) checkRequiredBodyFields(params map[string]any, opParams openap
 		return nil
 	}
 
-	// Extract body parameters
-	bodyParams := b.extractBodyParams(params, opParams)
+	bodyParams, err := b.requiredBodyFieldSource(params, opParams, schema)
+	if err != nil || bodyParams == nil {
+		return err
+	}
 
 	// Check if all required fields are present
 	for _, requiredField := range schema.Required {
@@ -577,18 +985,70 @@ func (b *Builder) checkRequiredBodyFields(params map[string]any, opParams openap
 }
 
 // ValidateParams validates parameters against the operation schema.
-func (b *Builder) ValidateParams(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody) error {
-	if err := b.checkRequiredParams(params, opParams); err != nil {
+func (b *Builder) ValidateParams(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody, opts ...BuildOption) error {
+	cfg := newBuildConfig(opts)
+	if err := b.checkRequiredParams(params, opParams, cfg); err != nil {
+		return err
+	}
+	if err := b.checkConditionalParams(params, opParams); err != nil {
+		return err
+	}
+	ndjson := ndjsonBodyContentType(requestBody) != ""
+	if cfg.contentType != "" {
+		declared, err := offeredContentType(requestBody, cfg.contentType)
+		if err != nil {
+			return err
+		}
+		mediaType, _, _ := mime.ParseMediaType(declared)
+		ndjson = mediaType == NDJSONMediaType
+		requestBody = jsonView(requestBody, declared)
+	}
+
+	if err := checkWholeBodyFlags(params); err != nil {
 		return err
 	}
+	if cfg.skipBodyValidation || cfg.isMultipart() {
+		return nil
+	}
 
 	if !b.hasRequiredBody(params, opParams, requestBody) {
 		return fmt.Errorf("request body is required for this operation")
 	}
 
+	// The schema of an NDJSON body describes each line, which is checked
+	// when the body is built.
+	if ndjson {
+		return nil
+	}
+
 	return b.checkRequiredBodyFields(params, opParams, requestBody)
 }
 
+// requiredBodyFieldSource returns the body fields to check for required ones:
+// those of a whole body given with --body or --body-yaml, or else the body
+// parameters, treating dotted flags as setting their parent object. It
+// returns nil if a whole body is not a JSON object, which is left to the API.
+func (b *Builder) requiredBodyFieldSource(params map[string]any, opParams openapi3.Parameters, schema *openapi3.Schema) (map[string]any, error) {
+	var data []byte
+	var err error
+	if body, ok := params["body"]; ok {
+		data, err = b.handleBodyFlag(body)
+	} else if body, ok := params[BodyYAMLParam]; ok {
+		data, err = b.handleBodyYAMLFlag(body)
+	} else {
+		return nestDottedParams(b.extractBodyParams(params, opParams), schema)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) != nil {
+		return nil, nil
+	}
+	return fields, nil
+}
+
 // typeMatchesAny checks if the actual type matches any of the expected schema types.
 // This implements OpenAPI 3.1 union type semantics where a value is valid if it
 // matches ANY type in the type array.
@@ -630,6 +1090,21 @@ func validateEnumValue(name string, value any, enums []any) error {
 	return fmt.Errorf("parameter '%s' must be one of %v, got %v", name, enums, value)
 }
 
+// canonicalEnumValue returns the enum value that matches a string value ignoring
+// case, or the value unchanged when there is no such match.
+func canonicalEnumValue(value any, enums []any) any {
+	str, ok := value.(string)
+	if !ok || slices.Contains(enums, value) {
+		return value
+	}
+	for _, enum := range enums {
+		if enumStr, ok := enum.(string); ok && strings.EqualFold(enumStr, str) {
+			return enumStr
+		}
+	}
+	return value
+}
+
 // validateParameterType validates a parameter value against its schema type.
 // For OpenAPI 3.1 union types (e.g., ["string", "integer"]), the value is valid
 // if it matches ANY of the types in the array.
@@ -170,11 +170,11 @@ func TestInjectAuth_NilCredManager(t *testing.T) {
 	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
 	require.NoError(t, err)
 
-	authConfig := &config.AuthConfig{
+	authConfig := config.AuthConfig{
 		Type: "bearer",
 	}
 
-	err = b.InjectAuth(req, "myapp", "default", authConfig)
+	err = b.InjectAuth(req, "myapp", &config.Profile{Name: "default", Auth: authConfig})
 	assert.NoError(t, err)
 
 	// No Authorization header should be set when credMgr is nil
@@ -191,11 +191,11 @@ func TestInjectAuth_BearerToken(t *testing.T) {
 	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
 	require.NoError(t, err)
 
-	authConfig := &config.AuthConfig{
+	authConfig := config.AuthConfig{
 		Type: "bearer",
 	}
 
-	err = b.InjectAuth(req, "testapp", "default", authConfig)
+	err = b.InjectAuth(req, "testapp", &config.Profile{Name: "default", Auth: authConfig})
 	assert.NoError(t, err)
 	assert.Equal(t, "Bearer my-bearer-token", req.Header.Get("Authorization"))
 }
@@ -210,13 +210,13 @@ func TestInjectAuth_APIKeyHeader(t *testing.T) {
 	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
 	require.NoError(t, err)
 
-	authConfig := &config.AuthConfig{
+	authConfig := config.AuthConfig{
 		Type:     "api_key",
 		Location: "header",
 		KeyName:  "X-API-Key",
 	}
 
-	err = b.InjectAuth(req, "testapp", "default", authConfig)
+	err = b.InjectAuth(req, "testapp", &config.Profile{Name: "default", Auth: authConfig})
 	assert.NoError(t, err)
 	assert.Equal(t, "my-api-key", req.Header.Get("X-API-Key"))
 }
@@ -231,13 +231,13 @@ func TestInjectAuth_APIKeyQuery(t *testing.T) {
 	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
 	require.NoError(t, err)
 
-	authConfig := &config.AuthConfig{
+	authConfig := config.AuthConfig{
 		Type:     "api_key",
 		Location: "query",
 		KeyName:  "api_key",
 	}
 
-	err = b.InjectAuth(req, "testapp", "default", authConfig)
+	err = b.InjectAuth(req, "testapp", &config.Profile{Name: "default", Auth: authConfig})
 	assert.NoError(t, err)
 	assert.Equal(t, "my-api-key", req.URL.Query().Get("api_key"))
 }
@@ -253,11 +253,11 @@ func TestInjectAuth_BasicAuth(t *testing.T) {
 	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
 	require.NoError(t, err)
 
-	authConfig := &config.AuthConfig{
+	authConfig := config.AuthConfig{
 		Type: "basic",
 	}
 
-	err = b.InjectAuth(req, "testapp", "default", authConfig)
+	err = b.InjectAuth(req, "testapp", &config.Profile{Name: "default", Auth: authConfig})
 	assert.NoError(t, err)
 
 	username, password, ok := req.BasicAuth()
@@ -307,11 +307,11 @@ func TestInjectAuth_NoAuthApplied(t *testing.T) {
 			req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
 			require.NoError(t, err)
 
-			authConfig := &config.AuthConfig{
+			authConfig := config.AuthConfig{
 				Type: tt.authType,
 			}
 
-			err = b.InjectAuth(req, tt.requestApp, tt.requestProfile, authConfig)
+			err = b.InjectAuth(req, tt.requestApp, &config.Profile{Name: tt.requestProfile, Auth: authConfig})
 			assert.NoError(t, err)
 			assert.Empty(t, req.Header.Get("Authorization"), tt.description)
 		})
@@ -395,6 +395,42 @@ func TestAddHeaderParams(t *testing.T) {
 	}
 }
 
+func TestAddHeaderParams_SimpleStyle(t *testing.T) {
+	b := NewBuilder(nil)
+
+	arraySchema := &openapi3.Schema{Type: &openapi3.Types{"array"}, Items: openapi3.NewSchemaRef("", stringSchema())}
+	objectSchema := &openapi3.Schema{Type: &openapi3.Types{"object"}}
+	exploded := func(ref *openapi3.ParameterRef) *openapi3.ParameterRef {
+		ref.Value.Explode = openapi3.Ptr(true)
+		return ref
+	}
+
+	tests := []struct {
+		name     string
+		value    any
+		param    *openapi3.ParameterRef
+		expected string
+	}{
+		{"array value", []any{"red", "green", "blue"}, paramRef("X-Tags", "header", false, arraySchema), "red,green,blue"},
+		{"string slice", []string{"red", "green"}, paramRef("X-Tags", "header", false, arraySchema), "red,green"},
+		{"JSON array flag", `["red","green"]`, paramRef("X-Tags", "header", false, arraySchema), "red,green"},
+		{"exploded array", []any{"red", "green"}, exploded(paramRef("X-Tags", "header", false, arraySchema)), "red,green"},
+		{"object", map[string]any{"role": "admin", "first": "Alex"}, paramRef("X-Tags", "header", false, objectSchema), "first,Alex,role,admin"},
+		{"exploded object", `{"role":"admin","first":"Alex"}`, exploded(paramRef("X-Tags", "header", false, objectSchema)), "first=Alex,role=admin"},
+		{"JSON-like string for string param", `["red"]`, paramRef("X-Tags", "header", false, stringSchema()), `["red"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+			require.NoError(t, err)
+
+			b.addHeaderParams(req, map[string]any{"X-Tags": tt.value}, openapi3.Parameters{tt.param})
+			assert.Equal(t, tt.expected, req.Header.Get("X-Tags"))
+		})
+	}
+}
+
 func TestBuildRequest(t *testing.T) {
 	b := NewBuilder(nil)
 
@@ -607,6 +643,118 @@ func TestBuildRequest(t *testing.T) {
 	}
 }
 
+func TestBuildRequestForOperation(t *testing.T) {
+	b := NewBuilder(nil)
+
+	op := &openapi3.Operation{
+		OperationID: "createUserPost",
+		Parameters: openapi3.Parameters{
+			paramRef("userId", "path", true, intSchema()),
+			paramRef("notify", "query", false, stringSchema()),
+			paramRef("X-Request-Source", "header", false, stringSchema()),
+		},
+		RequestBody: &openapi3.RequestBodyRef{
+			Value: &openapi3.RequestBody{
+				Required: true,
+				Content: openapi3.Content{
+					"application/json": &openapi3.MediaType{
+						Schema: openapi3.NewSchemaRef("", objectSchemaWithStringArray("title", "tags")),
+					},
+				},
+			},
+		},
+	}
+
+	req, err := b.BuildRequestForOperation(http.MethodPost, "/users/{userId}/posts", "https://api.example.com", op, map[string]any{
+		"userId":           42,
+		"notify":           "email",
+		"X-Request-Source": "cli",
+		"title":            "Hello",
+		"tags":             "news,go",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "https://api.example.com/users/42/posts?notify=email", req.URL.String())
+	assert.Equal(t, "cli", req.Header.Get("X-Request-Source"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"Hello","tags":["news","go"]}`, string(body))
+}
+
+func TestBuildRequest_PlainTextBody(t *testing.T) {
+	b := NewBuilder(nil)
+
+	requestBody := &openapi3.RequestBody{
+		Content: openapi3.Content{
+			"text/plain; charset=utf-8": &openapi3.MediaType{Schema: openapi3.NewSchemaRef("", stringSchema())},
+		},
+	}
+
+	req, err := b.BuildRequest(http.MethodPost, "/notes", "https://api.example.com",
+		map[string]any{"body": "not {json"}, nil, requestBody)
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/plain; charset=utf-8", req.Header.Get("Content-Type"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "not {json", string(body))
+}
+
+func TestBuildRequest_BinaryBodyFromFile(t *testing.T) {
+	b := NewBuilder(nil)
+
+	payload := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, '\n'}
+	path := filepath.Join(t.TempDir(), "image.png")
+	require.NoError(t, os.WriteFile(path, payload, 0644))
+
+	requestBody := &openapi3.RequestBody{
+		Required: true,
+		Content: openapi3.Content{
+			"application/octet-stream": &openapi3.MediaType{
+				Schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"string"}, Format: "binary"}),
+			},
+		},
+	}
+
+	req, err := b.BuildRequest(http.MethodPut, "/images/{id}", "https://api.example.com",
+		map[string]any{"id": 7, "body": "@" + path},
+		openapi3.Parameters{paramRef("id", "path", true, intSchema())}, requestBody)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.example.com/images/7", req.URL.String())
+	assert.Equal(t, "application/octet-stream", req.Header.Get("Content-Type"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, payload, body)
+}
+
+func TestRawBodyContentType(t *testing.T) {
+	media := func(types ...string) *openapi3.RequestBody {
+		content := openapi3.Content{}
+		for _, mt := range types {
+			content[mt] = &openapi3.MediaType{}
+		}
+		return &openapi3.RequestBody{Content: content}
+	}
+
+	assert.Empty(t, rawBodyContentType(nil))
+	assert.Empty(t, rawBodyContentType(media("application/json")))
+	assert.Empty(t, rawBodyContentType(media("application/json", "text/plain")))
+	assert.Equal(t, "text/plain", rawBodyContentType(media("text/plain")))
+	assert.Equal(t, "application/octet-stream", rawBodyContentType(media("text/plain", "application/octet-stream")))
+}
+
+func TestBuildRequestForOperation_NilOperation(t *testing.T) {
+	b := NewBuilder(nil)
+
+	req, err := b.BuildRequestForOperation(http.MethodGet, "/health", "https://api.example.com", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/health", req.URL.String())
+}
+
 func TestHandleBodyFlag_DirectJSON(t *testing.T) {
 	b := NewBuilder(nil)
 
@@ -756,7 +904,10 @@ func TestConstructArrayValue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := b.constructArrayValue(tt.input, schema)
+			result, err := b.constructArrayValue(tt.input, schema, schemaSet{})
+			if err != nil {
+				t.Fatalf("constructArrayValue() error = %v", err)
+			}
 			if len(result) != tt.expected {
 				t.Errorf("constructArrayValue() length = %d, want %d", len(result), tt.expected)
 			}
@@ -886,6 +1037,52 @@ func TestBuildBodyFromSchema_Arrays(t *testing.T) {
 	}
 }
 
+func TestBuildBodyFromSchema_CircularSchema(t *testing.T) {
+	b := NewBuilder(nil)
+
+	// A "Matrix" array schema whose items reference itself.
+	matrix := &openapi3.Schema{Type: &openapi3.Types{"array"}}
+	matrix.Items = openapi3.NewSchemaRef("#/components/schemas/Matrix", matrix)
+
+	schema := &openapi3.Schema{
+		Type:       &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{"cells": openapi3.NewSchemaRef("#/components/schemas/Matrix", matrix)},
+	}
+
+	_, err := b.buildBodyFromSchema(map[string]any{"cells": "1,2"}, schema)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCircularSchema)
+	assert.Contains(t, err.Error(), "cells")
+
+	t.Run("self-referencing object with nested values", func(t *testing.T) {
+		node := &openapi3.Schema{Type: &openapi3.Types{"object"}, Properties: openapi3.Schemas{}}
+		node.Properties["name"] = openapi3.NewSchemaRef("", stringSchema())
+		node.Properties["children"] = openapi3.NewSchemaRef("", &openapi3.Schema{
+			Type:  &openapi3.Types{"array"},
+			Items: openapi3.NewSchemaRef("#/components/schemas/Node", node),
+		})
+
+		result, err := b.buildBodyFromSchema(map[string]any{
+			"name":     "root",
+			"children": []any{map[string]any{"name": "leaf"}},
+		}, node)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"root","children":[{"name":"leaf"}]}`, string(result))
+	})
+}
+
+func TestValidateParams_CircularSchema(t *testing.T) {
+	b := NewBuilder(nil)
+
+	matrix := &openapi3.Schema{Type: &openapi3.Types{"array"}}
+	matrix.Items = openapi3.NewSchemaRef("#/components/schemas/Matrix", matrix)
+	opParams := openapi3.Parameters{paramRef("ids", "query", false, matrix)}
+
+	err := b.ValidateParams(map[string]any{"ids": "1"}, opParams, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCircularSchema)
+}
+
 func TestBuildRequestBody_Integration(t *testing.T) {
 	b := NewBuilder(nil)
 
@@ -1224,6 +1421,27 @@ func TestValidateParams_EnumValidation(t *testing.T) {
 	}
 }
 
+func TestValidateParams_EnumCaseInsensitive(t *testing.T) {
+	b := NewBuilder(nil)
+
+	opParams := openapi3.Parameters{
+		paramRef("status", "query", true, &openapi3.Schema{
+			Type: &openapi3.Types{"string"},
+			Enum: []any{"available", "pending", "sold"},
+		}),
+	}
+
+	require.Error(t, b.ValidateParams(map[string]any{"status": "Available"}, opParams, nil))
+
+	params := map[string]any{"status": "Available"}
+	require.NoError(t, b.ValidateParams(params, opParams, nil, WithEnumCaseInsensitive()))
+	assert.Equal(t, "available", params["status"])
+
+	err := b.ValidateParams(map[string]any{"status": "deleted"}, opParams, nil, WithEnumCaseInsensitive())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be one of")
+}
+
 func TestValidateParams_RequestBodyRequired(t *testing.T) {
 	b := NewBuilder(nil)
 
@@ -1504,3 +1722,210 @@ func TestBuilder_NilParamRefHandling(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestBuildRequest_WithoutBodyValidation(t *testing.T) {
+	b := NewBuilder(nil)
+
+	schema := objectSchemaWithStringArray("title", "tags")
+	schema.Required = []string{"title"}
+	op := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			paramRef("userId", "path", true, intSchema()),
+		},
+		RequestBody: &openapi3.RequestBodyRef{
+			Value: &openapi3.RequestBody{
+				Required: true,
+				Content: openapi3.Content{
+					"application/json": &openapi3.MediaType{Schema: openapi3.NewSchemaRef("", schema)},
+				},
+			},
+		},
+	}
+	requestBody := op.RequestBody.Value
+
+	params := map[string]any{"userId": 42, "tags": "go"}
+	require.Error(t, b.ValidateParams(params, op.Parameters, requestBody))
+	require.NoError(t, b.ValidateParams(params, op.Parameters, requestBody, WithoutBodyValidation()))
+
+	// Path parameters are still required.
+	err := b.ValidateParams(map[string]any{"tags": "go"}, op.Parameters, requestBody, WithoutBodyValidation())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "userId")
+
+	req, err := b.BuildRequestForOperation(http.MethodPost, "/users/{userId}/posts", "https://api.example.com", op, params, WithoutBodyValidation())
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/users/42/posts", req.URL.String())
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tags":["go"]}`, string(body))
+
+	_, err = b.BuildRequestForOperation(http.MethodPost, "/users/{userId}/posts", "https://api.example.com", op,
+		map[string]any{"userId": 42, "body": "{not json"})
+	require.Error(t, err)
+
+	req, err = b.BuildRequestForOperation(http.MethodPost, "/users/{userId}/posts", "https://api.example.com", op,
+		map[string]any{"userId": 42, "body": "{not json"}, WithoutBodyValidation())
+	require.NoError(t, err)
+	body, err = io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "{not json", string(body))
+}
+
+func TestBuildRequest_DefaultIdentityHeaders(t *testing.T) {
+	req, err := NewBuilder(nil).BuildRequest(http.MethodGet, "/pets", "https://api.example.com", nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "OpenBridge/dev", req.Header.Get("User-Agent"))
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, req.Header.Get(HeaderRequestID))
+
+	b := NewBuilder(nil, WithUserAgent(UserAgent("1.2.3")))
+	req, err = b.BuildRequest(http.MethodGet, "/pets", "https://api.example.com", nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "OpenBridge/1.2.3", req.Header.Get("User-Agent"))
+
+	// Header parameters take precedence over the defaults.
+	opParams := openapi3.Parameters{
+		paramRef("User-Agent", "header", false, stringSchema()),
+		paramRef(HeaderRequestID, "header", false, stringSchema()),
+	}
+	params := map[string]any{"User-Agent": "custom/1.0", HeaderRequestID: "abc"}
+	req, err = b.BuildRequest(http.MethodGet, "/pets", "https://api.example.com", params, opParams, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "custom/1.0", req.Header.Get("User-Agent"))
+	assert.Equal(t, "abc", req.Header.Get(HeaderRequestID))
+
+	req, err = b.BuildRequest(http.MethodGet, "/pets", "https://api.example.com", nil, nil, nil, WithoutRequestID())
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Values(HeaderRequestID))
+	assert.Equal(t, "OpenBridge/1.2.3", req.Header.Get("User-Agent"))
+}
+
+func TestBuildRequest_WithPrefer(t *testing.T) {
+	b := NewBuilder(nil)
+	params := map[string]any{"name": "doggie"}
+
+	req, err := b.BuildRequest(http.MethodPost, "/pets", "https://api.example.com", params, nil, nil, WithPrefer("return=minimal"))
+	require.NoError(t, err)
+	assert.Equal(t, "return=minimal", req.Header.Get("Prefer"))
+
+	req, err = b.BuildRequest(http.MethodGet, "/pets", "https://api.example.com", nil, nil, nil, WithPrefer("return=minimal"))
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("Prefer"), "Prefer is only sent on write operations")
+
+	req, err = b.BuildRequest(http.MethodDelete, "/pets/1", "https://api.example.com", nil, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("Prefer"))
+}
+
+func TestBuildRequest_NullableAndUnionBody(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name: {type: string}
+                nickname: {type: [string, "null"]}
+                age: {type: [integer, "null"]}
+                tag: {type: [string, integer]}
+                weight: {type: [integer, boolean]}
+                owner:
+                  type: [object, "null"]
+                  properties:
+                    id: {type: integer}
+      responses:
+        "201": {description: Created}
+`))
+	require.NoError(t, err)
+	requestBody := doc.Paths.Value("/pets").Post.RequestBody.Value
+
+	build := func(params map[string]any) map[string]any {
+		t.Helper()
+		req, err := NewBuilder(nil).BuildRequest(http.MethodPost, "/pets", "https://api.example.com", params, nil, requestBody)
+		require.NoError(t, err)
+		data, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(data, &body), "body: %s", data)
+		return body
+	}
+
+	body := build(map[string]any{"name": "null", "nickname": "null", "age": "null", "owner": "null"})
+	assert.Equal(t, map[string]any{"name": "null", "nickname": nil, "age": nil, "owner": nil}, body)
+
+	body = build(map[string]any{"nickname": "rex", "age": "3", "tag": "7", "weight": "true", "owner": map[string]any{"id": "1"}})
+	assert.Equal(t, "rex", body["nickname"])
+	assert.Equal(t, float64(3), body["age"])
+	assert.Equal(t, "7", body["tag"], "a union with string keeps the value a string")
+	assert.Equal(t, true, body["weight"])
+	assert.Equal(t, map[string]any{"id": float64(1)}, body["owner"])
+
+	body = build(map[string]any{"tag": 7, "weight": "12"})
+	assert.Equal(t, float64(7), body["tag"])
+	assert.Equal(t, float64(12), body["weight"])
+}
+
+func TestHandleBodyYAMLFlag(t *testing.T) {
+	b := NewBuilder(nil)
+	tmpDir := t.TempDir()
+
+	yamlFile := filepath.Join(tmpDir, "pet.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("name: doggie\ntags:\n  - good\n"), 0644))
+	invalidFile := filepath.Join(tmpDir, "invalid.yml")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("name: [unclosed\n"), 0644))
+
+	tests := []struct {
+		name    string
+		params  map[string]any
+		want    string
+		wantErr string
+	}{
+		{name: "inline", params: map[string]any{"body-yaml": "name: doggie\nage: 3"}, want: `{"name":"doggie","age":3}`},
+		{name: "file", params: map[string]any{"body-yaml": "@" + yamlFile}, want: `{"name":"doggie","tags":["good"]}`},
+		{name: "YAML file given to --body", params: map[string]any{"body": "@" + yamlFile}, want: `{"name":"doggie","tags":["good"]}`},
+		{name: "invalid inline", params: map[string]any{"body-yaml": "name: [unclosed"}, wantErr: "invalid YAML in --body-yaml flag"},
+		{name: "invalid file", params: map[string]any{"body": "@" + invalidFile}, wantErr: "does not contain valid YAML"},
+		{name: "empty", params: map[string]any{"body-yaml": "  "}, wantErr: "invalid YAML in --body-yaml flag: empty document"},
+		{name: "with --body", params: map[string]any{"body": "{}", "body-yaml": "name: doggie"}, wantErr: "--body and --body-yaml cannot be used together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := b.buildRequestBody(tt.params, nil, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestValidateParams_WholeBodyRequiredFields(t *testing.T) {
+	b := NewBuilder(nil)
+	requestBody := &openapi3.RequestBody{
+		Required: true,
+		Content: openapi3.Content{"application/json": &openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type:       &openapi3.Types{"object"},
+			Required:   []string{"name"},
+			Properties: openapi3.Schemas{"name": {Value: openapi3.NewStringSchema()}},
+		}}}},
+	}
+
+	require.NoError(t, b.ValidateParams(map[string]any{"body-yaml": "name: doggie"}, nil, requestBody))
+	require.NoError(t, b.ValidateParams(map[string]any{"body": `{"name":"doggie"}`}, nil, requestBody))
+
+	err := b.ValidateParams(map[string]any{"body-yaml": "status: sold"}, nil, requestBody)
+	assert.EqualError(t, err, "required body parameter 'name' is missing")
+
+	err = b.ValidateParams(map[string]any{"body-yaml": "name: [unclosed"}, nil, requestBody)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid YAML in --body-yaml flag")
+}
@@ -0,0 +1,198 @@
+package request
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+)
+
+// maxIdleConnsPerHost is how many idle connections a pooled transport keeps
+// per host, so that concurrent batch requests can reuse their connections
+// instead of the default two.
+const maxIdleConnsPerHost = 32
+
+// dialKeepAlive is the keep-alive period of connections dialed with a profile's
+// connect timeout, the same as the default transport's.
+const dialKeepAlive = 30 * time.Second
+
+// ClientPool hands out one HTTP client per app profile, so that every request
+// sent for a profile, from the CLI or an MCP server, shares one transport and
+// reuses its keep-alive connections. It is safe for concurrent use.
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+}
+
+// pooledClient is a client and the profile settings it was built with.
+type pooledClient struct {
+	tls            config.TLSConfig
+	proxy          string
+	connectTimeout time.Duration
+	redirects      redirectPolicy
+	client         *http.Client
+}
+
+// redirectPolicy is a profile's redirect settings.
+type redirectPolicy struct {
+	follow bool
+	max    int
+}
+
+// NewClientPool creates an empty client pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{clients: make(map[string]*pooledClient)}
+}
+
+// Client returns the HTTP client for a profile of an app, creating it on first
+// use. The client is rebuilt if the profile's TLS settings, proxy, connect
+// timeout, or redirect settings have changed since.
+func (p *ClientPool) Client(appName string, profile *config.Profile) (*http.Client, error) {
+	key := appName + "/" + profile.Name
+	connectTimeout := profile.ConnectTimeout.Duration
+	redirects := redirectPolicy{follow: profile.FollowsRedirects(), max: profile.MaxRedirects}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pooled, ok := p.clients[key]; ok && pooled.tls == profile.TLSConfig && pooled.proxy == profile.Proxy &&
+		pooled.connectTimeout == connectTimeout && pooled.redirects == redirects {
+		return pooled.client, nil
+	}
+
+	transport, err := NewTransport(profile.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Proxy != "" {
+		proxyURL, err := url.Parse(profile.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL '%s': %w", profile.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if connectTimeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: dialKeepAlive}).DialContext
+	}
+	if previous, ok := p.clients[key]; ok {
+		previous.client.CloseIdleConnections()
+	}
+
+	client := &http.Client{Transport: transport, CheckRedirect: redirects.checkRedirect()}
+	p.clients[key] = &pooledClient{tls: profile.TLSConfig, proxy: profile.Proxy, connectTimeout: connectTimeout, redirects: redirects, client: client}
+	return client, nil
+}
+
+// checkRedirect returns the client's CheckRedirect function: nil for the
+// default of following up to 10 redirects, or one that stops at the 3xx
+// response when redirects are not followed, or fails after max redirects.
+func (r redirectPolicy) checkRedirect() func(*http.Request, []*http.Request) error {
+	switch {
+	case !r.follow:
+		return func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	case r.max > 0:
+		return func(_ *http.Request, via []*http.Request) error {
+			if len(via) > r.max {
+				return fmt.Errorf("stopped after %d redirects", r.max)
+			}
+			return nil
+		}
+	default:
+		return nil
+	}
+}
+
+// CloseIdleConnections closes the idle connections of every pooled client.
+func (p *ClientPool) CloseIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, pooled := range p.clients {
+		pooled.client.CloseIdleConnections()
+	}
+}
+
+// NewTransport returns a transport with the default proxy settings
+// (HTTP_PROXY, HTTPS_PROXY, NO_PROXY), keeping up to maxIdleConnsPerHost idle
+// connections per host, configured with a profile's TLS settings.
+func NewTransport(cfg config.TLSConfig) (*http.Transport, error) {
+	tlsConfig, err := tlsClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+// tlsClientConfig converts a profile's TLS settings to a tls.Config, or nil if
+// it uses the defaults.
+func tlsClientConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if cfg == (config.TLSConfig{}) {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // opted into by the profile
+		ServerName:         cfg.ServerName,
+	}
+
+	if cfg.MinVersion != "" {
+		version, err := parseTLSVersion(cfg.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file '%s' contains no PEM certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseTLSVersion parses a TLS version such as 1.2.
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version: %s (valid versions: 1.0, 1.1, 1.2, 1.3)", version)
+	}
+}
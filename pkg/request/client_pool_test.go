@@ -0,0 +1,247 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newConnCountingServer returns a server that counts the connections opened to it.
+func newConnCountingServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &conns
+}
+
+func TestBuilderHTTPClient_ReusesConnections(t *testing.T) {
+	server, conns := newConnCountingServer(t)
+	b := NewBuilder(nil)
+	profile := &config.Profile{Name: "default", BaseURL: server.URL}
+
+	for range 20 {
+		client, err := b.HTTPClient("petstore", profile)
+		require.NoError(t, err)
+
+		req, err := b.BuildRequest(http.MethodGet, "/pets", profile.BaseURL, nil, nil, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, int32(1), conns.Load())
+}
+
+func TestClientPool_ConcurrentUse(t *testing.T) {
+	server, conns := newConnCountingServer(t)
+	pool := NewClientPool()
+	profile := &config.Profile{Name: "default"}
+
+	var wg sync.WaitGroup
+	clients := make([]*http.Client, 8)
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := pool.Client("petstore", profile)
+			assert.NoError(t, err)
+			clients[i] = client
+			for range 10 {
+				resp, err := client.Get(server.URL)
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, client := range clients {
+		assert.Same(t, clients[0], client)
+	}
+	// At most one connection per concurrent caller; sequential calls reuse them.
+	assert.LessOrEqual(t, conns.Load(), int32(len(clients)))
+}
+
+func TestClientPool_PerProfile(t *testing.T) {
+	pool := NewClientPool()
+
+	prod, err := pool.Client("petstore", &config.Profile{Name: "prod"})
+	require.NoError(t, err)
+	staging, err := pool.Client("petstore", &config.Profile{Name: "staging"})
+	require.NoError(t, err)
+	other, err := pool.Client("other", &config.Profile{Name: "prod"})
+	require.NoError(t, err)
+	assert.NotSame(t, prod, staging)
+	assert.NotSame(t, prod, other)
+
+	again, err := pool.Client("petstore", &config.Profile{Name: "prod"})
+	require.NoError(t, err)
+	assert.Same(t, prod, again)
+
+	changed, err := pool.Client("petstore", &config.Profile{Name: "prod", TLSConfig: config.TLSConfig{MinVersion: "1.3"}})
+	require.NoError(t, err)
+	assert.NotSame(t, prod, changed)
+}
+
+func TestClientPool_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer proxy.Close()
+
+	pool := NewClientPool()
+	client, err := pool.Client("petstore", &config.Profile{Name: "default", Proxy: proxy.URL})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://api.example.test/pets")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "http://api.example.test/pets", proxied)
+
+	_, err = pool.Client("petstore", &config.Profile{Name: "default", Proxy: "http://proxy\x7f"})
+	require.ErrorContains(t, err, "invalid proxy URL")
+}
+
+func TestClientPool_ConnectTimeout(t *testing.T) {
+	// 10.255.255.1 is not routed, so connecting hangs until the connect timeout.
+	const unroutable = "10.255.255.1:81"
+	if conn, err := net.DialTimeout("tcp", unroutable, 100*time.Millisecond); err == nil {
+		_ = conn.Close()
+		t.Skip("the network accepts connections to unroutable addresses")
+	}
+
+	pool := NewClientPool()
+	profile := &config.Profile{Name: "default", ConnectTimeout: config.Duration{Duration: 100 * time.Millisecond}}
+	client, err := pool.Client("petstore", profile)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Get("http://" + unroutable + "/pets")
+	require.Error(t, err)
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr), "unexpected error: %v", err)
+	assert.True(t, netErr.Timeout(), "unexpected error: %v", err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClientPool_ConnectTimeoutAllowsSlowResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	pool := NewClientPool()
+	profile := &config.Profile{Name: "default", ConnectTimeout: config.Duration{Duration: 50 * time.Millisecond}}
+	client, err := pool.Client("petstore", profile)
+	require.NoError(t, err)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ok":true}`, string(body))
+
+	again, err := pool.Client("petstore", &config.Profile{Name: "default"})
+	require.NoError(t, err)
+	assert.NotSame(t, client, again, "changing the connect timeout rebuilds the client")
+}
+
+func TestClientPool_Redirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, "/middle", http.StatusFound)
+		case "/middle":
+			http.Redirect(w, r, "/end", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	get := func(profile *config.Profile) (*http.Response, error) {
+		client, err := NewClientPool().Client("petstore", profile)
+		require.NoError(t, err)
+		resp, err := client.Get(server.URL + "/start")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	resp, err := get(&config.Profile{Name: "default"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/end", resp.Request.URL.Path)
+
+	follow := false
+	resp, err = get(&config.Profile{Name: "default", FollowRedirects: &follow})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/middle", resp.Header.Get("Location"))
+
+	resp, err = get(&config.Profile{Name: "default", MaxRedirects: 2})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = get(&config.Profile{Name: "default", MaxRedirects: 1})
+	assert.ErrorContains(t, err, "stopped after 1 redirects")
+
+	pool := NewClientPool()
+	client, err := pool.Client("petstore", &config.Profile{Name: "default"})
+	require.NoError(t, err)
+	again, err := pool.Client("petstore", &config.Profile{Name: "default", FollowRedirects: &follow})
+	require.NoError(t, err)
+	assert.NotSame(t, client, again, "changing the redirect policy rebuilds the client")
+}
+
+func TestNewTransport_TLSConfig(t *testing.T) {
+	transport, err := NewTransport(config.TLSConfig{})
+	require.NoError(t, err)
+	assert.NotNil(t, transport.Proxy)
+	assert.Equal(t, maxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+
+	transport, err = NewTransport(config.TLSConfig{InsecureSkipVerify: true, ServerName: "api.internal", MinVersion: "1.2"})
+	require.NoError(t, err)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "api.internal", transport.TLSClientConfig.ServerName)
+
+	_, err = NewTransport(config.TLSConfig{MinVersion: "2.0"})
+	assert.EqualError(t, err, "unsupported TLS version: 2.0 (valid versions: 1.0, 1.1, 1.2, 1.3)")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0644))
+	_, err = NewTransport(config.TLSConfig{CAFile: caFile})
+	assert.ErrorContains(t, err, "contains no PEM certificates")
+}
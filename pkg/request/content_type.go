@@ -0,0 +1,131 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// WithContentType selects the request body media type, such as
+// application/x-www-form-urlencoded, when an operation offers several. The body
+// is encoded to match: JSON, NDJSON, URL-encoded form, multipart form, or the
+// raw --body value for text and binary types. Without it, JSON is preferred.
+func WithContentType(contentType string) BuildOption {
+	return func(c *buildConfig) {
+		c.contentType = contentType
+	}
+}
+
+// offeredContentType returns the media type declared by the request body that
+// matches contentType, ignoring case and parameters such as charset.
+func offeredContentType(requestBody *openapi3.RequestBody, contentType string) (string, error) {
+	wanted, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type '%s': %w", contentType, err)
+	}
+	if requestBody == nil || len(requestBody.Content) == 0 {
+		return "", fmt.Errorf("content type '%s' is not offered: the operation declares no request body", contentType)
+	}
+
+	offered := slices.Sorted(maps.Keys(requestBody.Content))
+	for _, declared := range offered {
+		if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType == wanted {
+			return declared, nil
+		}
+	}
+	return "", fmt.Errorf("content type '%s' is not offered by this operation (offered: %s)", contentType, strings.Join(offered, ", "))
+}
+
+// createBodyForContentType encodes the request body as the selected content type.
+func (b *Builder) createBodyForContentType(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody, cfg buildConfig, opts []BuildOption) ([]byte, string, error) {
+	declared, err := offeredContentType(requestBody, cfg.contentType)
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(declared)
+
+	if cfg.isMultipart() && mediaType != "multipart/form-data" {
+		return nil, "", fmt.Errorf("files and form fields are sent as multipart/form-data, not %s", declared)
+	}
+
+	var data []byte
+	switch {
+	case mediaType == "multipart/form-data":
+		return b.buildMultipartBody(params, opParams, requestBody, cfg)
+	case mediaType == "application/x-www-form-urlencoded":
+		data, err = b.buildFormBody(params, opParams, jsonView(requestBody, declared), opts)
+	case mediaType == "text/plain" || mediaType == "application/octet-stream":
+		data, err = b.buildRawBody(params)
+	case mediaType == NDJSONMediaType:
+		data, err = b.buildNDJSONBody(params)
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		data, err = b.buildRequestBody(params, opParams, jsonView(requestBody, declared), opts...)
+	default:
+		return nil, "", fmt.Errorf("content type '%s' cannot be encoded; use --body with a supported type", declared)
+	}
+	if err != nil || data == nil {
+		return nil, "", err
+	}
+	return data, declared, nil
+}
+
+// jsonView returns a copy of requestBody whose application/json content is the
+// declared media type's, so the body is shaped by that media type's schema.
+func jsonView(requestBody *openapi3.RequestBody, declared string) *openapi3.RequestBody {
+	view := *requestBody
+	view.Content = openapi3.Content{"application/json": requestBody.Content[declared]}
+	return &view
+}
+
+// buildFormBody encodes the body as application/x-www-form-urlencoded. The
+// body is shaped like a JSON body, then each property becomes a form field:
+// arrays repeat the field and nested objects are JSON-encoded. A --body value
+// that is not a JSON object or file reference is sent as already encoded.
+func (b *Builder) buildFormBody(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody, opts []BuildOption) ([]byte, error) {
+	if body, ok := params["body"].(string); ok && !strings.HasPrefix(body, "@") && !strings.HasPrefix(strings.TrimSpace(body), "{") {
+		return []byte(body), nil
+	}
+
+	data, err := b.buildRequestBody(params, opParams, requestBody, opts...)
+	if err != nil || data == nil {
+		return data, err
+	}
+
+	var obj map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("form body must be an object: %w", err)
+	}
+
+	values := url.Values{}
+	for name, val := range obj {
+		items, isArray := val.([]any)
+		if !isArray {
+			items = []any{val}
+		}
+		for _, item := range items {
+			values.Add(name, formScalarValue(item))
+		}
+	}
+	return []byte(values.Encode()), nil
+}
+
+// formScalarValue formats a single form field value.
+func formScalarValue(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case map[string]any, []any:
+		return formFieldValue(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
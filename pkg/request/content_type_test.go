@@ -0,0 +1,86 @@
+package request
+
+import (
+	"io"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadCreatePetOperation returns the complex spec's createPet operation, whose
+// request body is offered as JSON, a URL-encoded form, and a multipart form.
+func loadCreatePetOperation(t *testing.T) *openapi3.Operation {
+	t.Helper()
+
+	specPath := filepath.Join("..", "..", "internal", "integration", "testdata", "complex_openapi3.json")
+	doc, err := openapi3.NewLoader().LoadFromFile(specPath)
+	require.NoError(t, err)
+
+	op := doc.Paths.Find("/pets").Post
+	require.NotNil(t, op)
+	require.Len(t, op.RequestBody.Value.Content, 3)
+	return op
+}
+
+func TestBuildRequest_ContentTypeForm(t *testing.T) {
+	op := loadCreatePetOperation(t)
+	b := NewBuilder(nil)
+	params := map[string]any{"name": "Rex", "species": "dog", "tags": "good,small"}
+	opts := []BuildOption{WithContentType("application/x-www-form-urlencoded")}
+
+	require.NoError(t, b.ValidateParams(params, op.Parameters, op.RequestBody.Value, opts...))
+	req, err := b.BuildRequestForOperation("POST", "/pets", "http://api.example.com", op, params, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	form, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"name": {"Rex"}, "species": {"dog"}, "tags": {"good", "small"}}, form)
+}
+
+func TestBuildRequest_ContentTypeDefaultsToJSON(t *testing.T) {
+	op := loadCreatePetOperation(t)
+	b := NewBuilder(nil)
+
+	req, err := b.BuildRequestForOperation("POST", "/pets", "http://api.example.com", op,
+		map[string]any{"name": "Rex", "species": "dog"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Rex","species":"dog"}`, string(body))
+}
+
+func TestBuildRequest_ContentTypeMultipart(t *testing.T) {
+	op := loadCreatePetOperation(t)
+	b := NewBuilder(nil)
+
+	req, err := b.BuildRequestForOperation("POST", "/pets", "http://api.example.com", op,
+		map[string]any{"name": "Rex"}, WithContentType("multipart/form-data"))
+	require.NoError(t, err)
+
+	parts := readMultipartParts(t, req)
+	assert.Equal(t, "Rex", parts["name"].content)
+}
+
+func TestValidateParams_ContentTypeNotOffered(t *testing.T) {
+	op := loadCreatePetOperation(t)
+	b := NewBuilder(nil)
+
+	err := b.ValidateParams(map[string]any{"name": "Rex", "species": "dog"}, op.Parameters, op.RequestBody.Value,
+		WithContentType("text/plain"))
+	require.Error(t, err)
+	assert.Equal(t, "content type 'text/plain' is not offered by this operation "+
+		"(offered: application/json, application/x-www-form-urlencoded, multipart/form-data)", err.Error())
+
+	_, err = b.BuildRequest("POST", "/pets", "http://api.example.com", nil, nil, nil,
+		WithContentType("application/json"))
+	assert.ErrorContains(t, err, "declares no request body")
+}
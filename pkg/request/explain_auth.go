@@ -0,0 +1,113 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
+)
+
+// AuthExplanation describes how InjectAuth authenticates requests for a
+// profile. It never contains the secret itself.
+type AuthExplanation struct {
+	// Scheme is the configured auth type, or "none".
+	Scheme string `json:"scheme"`
+
+	// Injection describes where the credential is placed on the request.
+	Injection string `json:"injection"`
+
+	// CredentialFound reports whether a credential is stored for the profile.
+	CredentialFound bool `json:"credential_found"`
+
+	// CredentialType is the type of the stored credential, if any.
+	CredentialType string `json:"credential_type,omitempty"`
+
+	// Problem explains why requests will not be authenticated as configured.
+	Problem string `json:"problem,omitempty"`
+}
+
+// ExplainAuth reports how InjectAuth would authenticate requests for a profile,
+// looking up the credential store without revealing the secret.
+func (b *Builder) ExplainAuth(appName, profileName string, authConfig *config.AuthConfig) AuthExplanation {
+	explanation := AuthExplanation{Scheme: authConfig.Type, Injection: authInjection(authConfig)}
+	if explanation.Scheme == "" {
+		explanation.Scheme = "none"
+	}
+
+	if b.credMgr == nil {
+		explanation.Problem = "the credential store is unavailable, so no credential is sent"
+		return explanation
+	}
+
+	cred, err := b.credMgr.GetCredential(appName, profileName)
+	var notFound *credential.CredentialNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		if explanation.Scheme != "none" {
+			explanation.Problem = fmt.Sprintf("no credential is stored for app '%s' profile '%s', so requests are sent without authentication", appName, profileName)
+		}
+		return explanation
+	case err != nil:
+		explanation.Problem = fmt.Sprintf("failed to read the credential: %v", err)
+		return explanation
+	}
+
+	explanation.CredentialFound = true
+	explanation.CredentialType = string(cred.Type)
+	explanation.Problem = credentialProblem(authConfig, cred)
+	return explanation
+}
+
+// authInjection describes where injectAuthCredentials places a credential.
+func authInjection(authConfig *config.AuthConfig) string {
+	switch authConfig.Type {
+	case "bearer":
+		return "header Authorization: Bearer <token>"
+	case "api_key":
+		if authConfig.Location == "query" {
+			return fmt.Sprintf("query parameter %s=<api key>", authConfig.KeyName)
+		}
+		return fmt.Sprintf("header %s: <api key>", authConfig.KeyName)
+	case "basic":
+		return "header Authorization: Basic <username:password>"
+	case string(credential.CredentialTypeOAuth2ClientCredentials):
+		return "header Authorization: Bearer <access token from the token URL>"
+	default:
+		return "not injected"
+	}
+}
+
+// credentialProblem reports a stored credential that cannot be used with the
+// configured auth type.
+func credentialProblem(authConfig *config.AuthConfig, cred *credential.Credential) string {
+	switch authConfig.Type {
+	case "", "none":
+		return "a credential is stored but the profile's auth type is none, so it is not sent"
+	case "bearer":
+		if cred.Token == "" {
+			return "the stored credential has no token"
+		}
+	case "api_key":
+		if authConfig.KeyName == "" {
+			return "auth.key_name is not set, so the API key has no header or parameter name"
+		}
+		if cred.Token == "" {
+			return "the stored credential has no API key"
+		}
+	case "basic":
+		if cred.Username == "" {
+			return "the stored credential has no username"
+		}
+	case string(credential.CredentialTypeOAuth2ClientCredentials):
+		if cred.ClientID == "" {
+			return "the stored credential has no client id"
+		}
+		if cred.TokenURL == "" && (authConfig.OAuth2Config == nil || authConfig.OAuth2Config.TokenURL == "") {
+			return "neither the stored credential nor auth.oauth2.token_url has a token URL"
+		}
+	default:
+		return fmt.Sprintf("auth type '%s' is not supported, so the credential is not sent", authConfig.Type)
+	}
+	return ""
+}
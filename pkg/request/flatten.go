@@ -0,0 +1,126 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MaxFlattenDepth is how many levels of nested objects FlattenBodySchema expands.
+const MaxFlattenDepth = 4
+
+// FlatField is a request body field addressed by a dotted path such as "address.city".
+type FlatField struct {
+	Path     string
+	Schema   *openapi3.SchemaRef
+	Required bool
+}
+
+// FlattenBodySchema returns the leaf fields of an object body schema as dotted
+// paths, so each leaf can be given as its own flag (--address.city Paris).
+// Objects with properties are expanded up to MaxFlattenDepth levels. Arrays,
+// including arrays of objects, objects without declared properties, objects
+// deeper than the limit, and schemas that refer back to themselves are listed
+// as a single field that takes a whole JSON value. A field is Required when it
+// and every enclosing object are required.
+func FlattenBodySchema(schema *openapi3.Schema) []FlatField {
+	var fields []FlatField
+	flattenInto(&fields, schema, "", true, 1, schemaSet{})
+	return fields
+}
+
+// flattenInto appends the flattened fields of schema's properties under prefix.
+func flattenInto(fields *[]FlatField, schema *openapi3.Schema, prefix string, required bool, depth int, visiting schemaSet) {
+	if schema == nil || visiting[schema] {
+		return
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+		propRef := schema.Properties[name]
+		if propRef == nil || propRef.Value == nil {
+			continue
+		}
+		path := prefix + name
+		propRequired := required && slices.Contains(schema.Required, name)
+
+		if isExpandableObject(propRef.Value) && depth < MaxFlattenDepth && !visiting[propRef.Value] {
+			flattenInto(fields, propRef.Value, path+".", propRequired, depth+1, visiting)
+			continue
+		}
+		*fields = append(*fields, FlatField{Path: path, Schema: propRef, Required: propRequired})
+	}
+}
+
+// isExpandableObject reports whether a schema is an object with declared properties.
+func isExpandableObject(schema *openapi3.Schema) bool {
+	return schema.Type != nil && schema.Type.Is("object") && len(schema.Properties) > 0
+}
+
+// nestDottedParams rebuilds dotted keys such as "address.city" into nested
+// objects when the first segment names an object property of schema. Other keys,
+// including property names that themselves contain dots, are left as they are.
+func nestDottedParams(params map[string]any, schema *openapi3.Schema) (map[string]any, error) {
+	if schema == nil || len(schema.Properties) == 0 {
+		return params, nil
+	}
+
+	var dotted []string
+	for key := range params {
+		if _, isProperty := schema.Properties[key]; isProperty {
+			continue
+		}
+		root, _, ok := strings.Cut(key, ".")
+		if propRef := schema.Properties[root]; ok && propRef != nil && propRef.Value != nil && isExpandableObject(propRef.Value) {
+			dotted = append(dotted, key)
+		}
+	}
+	if len(dotted) == 0 {
+		return params, nil
+	}
+
+	nested := maps.Clone(params)
+	for _, key := range slices.Sorted(slices.Values(dotted)) {
+		value := nested[key]
+		delete(nested, key)
+		if err := setDottedValue(nested, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return nested, nil
+}
+
+// setDottedValue sets value at a dotted path, creating intermediate objects.
+func setDottedValue(obj map[string]any, path string, value any) error {
+	parts := strings.Split(path, ".")
+	current := obj
+	for i, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return fmt.Errorf("invalid field path '%s'", path)
+		}
+		next, exists := current[part]
+		if !exists {
+			next = make(map[string]any)
+			current[part] = next
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("field '%s' is set both directly and through '%s'", strings.Join(parts[:i+1], "."), path)
+		}
+		current = child
+	}
+
+	last := parts[len(parts)-1]
+	if last == "" {
+		return fmt.Errorf("invalid field path '%s'", path)
+	}
+	if _, exists := current[last]; exists {
+		return fmt.Errorf("field '%s' is set more than once", path)
+	}
+	current[last] = value
+	return nil
+}
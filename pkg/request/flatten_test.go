@@ -0,0 +1,99 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nestedUserSchema returns a user body schema with a required two-level nested address.
+func nestedUserSchema() *openapi3.Schema {
+	geo := &openapi3.Schema{
+		Type: &openapi3.Types{"object"},
+		Properties: openapi3.Schemas{
+			"lat": openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"number"}}),
+		},
+	}
+	address := &openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		Required: []string{"city"},
+		Properties: openapi3.Schemas{
+			"city": openapi3.NewSchemaRef("", stringSchema()),
+			"zip":  openapi3.NewSchemaRef("", intSchema()),
+			"geo":  openapi3.NewSchemaRef("", geo),
+		},
+	}
+	return &openapi3.Schema{
+		Type:     &openapi3.Types{"object"},
+		Required: []string{"name", "address"},
+		Properties: openapi3.Schemas{
+			"name":    openapi3.NewSchemaRef("", stringSchema()),
+			"address": openapi3.NewSchemaRef("", address),
+			"tags":    openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"array"}, Items: openapi3.NewSchemaRef("", stringSchema())}),
+		},
+	}
+}
+
+func TestFlattenBodySchema(t *testing.T) {
+	fields := FlattenBodySchema(nestedUserSchema())
+
+	var paths []string
+	required := map[string]bool{}
+	for _, field := range fields {
+		paths = append(paths, field.Path)
+		required[field.Path] = field.Required
+	}
+
+	assert.Equal(t, []string{"address.city", "address.geo.lat", "address.zip", "name", "tags"}, paths)
+	assert.True(t, required["name"])
+	assert.True(t, required["address.city"])
+	assert.False(t, required["address.zip"])
+	assert.False(t, required["address.geo.lat"])
+}
+
+func TestFlattenBodySchema_SelfReference(t *testing.T) {
+	node := &openapi3.Schema{Type: &openapi3.Types{"object"}, Properties: openapi3.Schemas{}}
+	node.Properties["name"] = openapi3.NewSchemaRef("", stringSchema())
+	node.Properties["parent"] = openapi3.NewSchemaRef("#/components/schemas/Node", node)
+
+	var paths []string
+	for _, field := range FlattenBodySchema(node) {
+		paths = append(paths, field.Path)
+	}
+	assert.Equal(t, []string{"name", "parent"}, paths)
+}
+
+func TestBuildBodyFromSchema_DottedFlags(t *testing.T) {
+	b := NewBuilder(nil)
+
+	body, err := b.buildBodyFromSchema(map[string]any{
+		"name":            "alice",
+		"address.city":    "Paris",
+		"address.zip":     "75001",
+		"address.geo.lat": "48.85",
+	}, nestedUserSchema())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"alice","address":{"city":"Paris","zip":75001,"geo":{"lat":48.85}}}`, string(body))
+
+	_, err = b.buildBodyFromSchema(map[string]any{
+		"address":      map[string]any{"city": "Paris"},
+		"address.city": "Lyon",
+	}, nestedUserSchema())
+	assert.Error(t, err)
+}
+
+func TestValidateParams_DottedFlagsSatisfyRequiredObject(t *testing.T) {
+	b := NewBuilder(nil)
+	requestBody := &openapi3.RequestBody{
+		Required: true,
+		Content:  openapi3.NewContentWithJSONSchema(nestedUserSchema()),
+	}
+
+	err := b.ValidateParams(map[string]any{"name": "alice", "address.city": "Paris"}, nil, requestBody)
+	assert.NoError(t, err)
+
+	err = b.ValidateParams(map[string]any{"name": "alice"}, nil, requestBody)
+	assert.ErrorContains(t, err, "address")
+}
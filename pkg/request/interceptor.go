@@ -0,0 +1,77 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Built-in interceptor names.
+const (
+	InterceptorAddHeader = "add-header"
+	InterceptorAddQuery  = "add-query"
+)
+
+// ErrUnknownInterceptor is returned when a built-in interceptor name is not recognized.
+var ErrUnknownInterceptor = errors.New("unknown interceptor")
+
+// RequestInterceptor inspects or mutates a request after it is built and before it is sent.
+// Returning an error aborts the request.
+type RequestInterceptor interface {
+	Before(req *http.Request) error
+}
+
+// RequestInterceptorFunc adapts an ordinary function to a RequestInterceptor.
+type RequestInterceptorFunc func(req *http.Request) error
+
+// Before calls f(req).
+func (f RequestInterceptorFunc) Before(req *http.Request) error {
+	return f(req)
+}
+
+// AddHeaderInterceptor returns an interceptor that sets a header on every request.
+func AddHeaderInterceptor(name, value string) RequestInterceptor {
+	return RequestInterceptorFunc(func(req *http.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	})
+}
+
+// AddQueryInterceptor returns an interceptor that sets a query parameter on every request.
+func AddQueryInterceptor(key, value string) RequestInterceptor {
+	return RequestInterceptorFunc(func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set(key, value)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	})
+}
+
+// NewBuiltinInterceptor creates a built-in interceptor by name.
+// Supported names are "add-header" and "add-query".
+func NewBuiltinInterceptor(name, key, value string) (RequestInterceptor, error) {
+	switch name {
+	case InterceptorAddHeader:
+		return AddHeaderInterceptor(key, value), nil
+	case InterceptorAddQuery:
+		return AddQueryInterceptor(key, value), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownInterceptor, name)
+	}
+}
+
+// Use registers interceptors. They run in registration order.
+func (b *Builder) Use(interceptors ...RequestInterceptor) {
+	b.interceptors = append(b.interceptors, interceptors...)
+}
+
+// ApplyInterceptors runs the registered interceptors against the request.
+// The first interceptor error aborts the chain and is returned.
+func (b *Builder) ApplyInterceptors(req *http.Request) error {
+	for i, interceptor := range b.interceptors {
+		if err := interceptor.Before(req); err != nil {
+			return fmt.Errorf("request aborted by interceptor %d: %w", i, err)
+		}
+	}
+	return nil
+}
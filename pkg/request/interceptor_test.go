@@ -0,0 +1,76 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyInterceptors_AddsHeader(t *testing.T) {
+	b := NewBuilder(nil)
+	b.Use(RequestInterceptorFunc(func(req *http.Request) error {
+		req.Header.Set("X-Correlation-ID", "abc-123")
+		return nil
+	}))
+
+	req, err := b.BuildRequest("GET", "/users", "https://api.example.com", nil, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.ApplyInterceptors(req))
+	assert.Equal(t, "abc-123", req.Header.Get("X-Correlation-ID"))
+}
+
+func TestApplyInterceptors_AbortsOnError(t *testing.T) {
+	errDenied := errors.New("denied")
+	secondCalled := false
+
+	b := NewBuilder(nil)
+	b.Use(
+		RequestInterceptorFunc(func(*http.Request) error { return errDenied }),
+		RequestInterceptorFunc(func(*http.Request) error {
+			secondCalled = true
+			return nil
+		}),
+	)
+
+	req, err := b.BuildRequest("GET", "/users", "https://api.example.com", nil, nil, nil)
+	require.NoError(t, err)
+
+	err = b.ApplyInterceptors(req)
+	require.ErrorIs(t, err, errDenied)
+	assert.False(t, secondCalled, "interceptors after a failure must not run")
+}
+
+func TestApplyInterceptors_RunInOrder(t *testing.T) {
+	b := NewBuilder(nil)
+	b.Use(AddHeaderInterceptor("X-Step", "first"), AddHeaderInterceptor("X-Step", "second"))
+
+	req, err := b.BuildRequest("GET", "/users", "https://api.example.com", nil, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.ApplyInterceptors(req))
+	assert.Equal(t, "second", req.Header.Get("X-Step"))
+}
+
+func TestNewBuiltinInterceptor(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://api.example.com/users?page=1", nil)
+	require.NoError(t, err)
+
+	header, err := NewBuiltinInterceptor(InterceptorAddHeader, "X-Env", "staging")
+	require.NoError(t, err)
+	query, err := NewBuiltinInterceptor(InterceptorAddQuery, "tenant", "acme")
+	require.NoError(t, err)
+
+	require.NoError(t, header.Before(req))
+	require.NoError(t, query.Before(req))
+
+	assert.Equal(t, "staging", req.Header.Get("X-Env"))
+	assert.Equal(t, "acme", req.URL.Query().Get("tenant"))
+	assert.Equal(t, "1", req.URL.Query().Get("page"))
+
+	_, err = NewBuiltinInterceptor("rewrite-url", "a", "b")
+	assert.ErrorIs(t, err, ErrUnknownInterceptor)
+}
@@ -0,0 +1,234 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// MaxMultipartFilesSize caps the total size of the files attached from a
+// directory, since the whole multipart body is held in memory.
+const MaxMultipartFilesSize = 64 << 20
+
+// formField is a multipart form field added with WithFormField.
+type formField struct {
+	name  string
+	value string
+}
+
+// WithMultipartFiles sends the body as multipart/form-data, attaching every
+// regular file in dir as a part named by the file's base name. Subdirectories
+// are skipped.
+func WithMultipartFiles(dir string) BuildOption {
+	return func(c *buildConfig) {
+		c.filesDir = dir
+	}
+}
+
+// WithFormField sends the body as multipart/form-data and adds a form field.
+// Repeating a name adds the field once per value.
+func WithFormField(name, value string) BuildOption {
+	return func(c *buildConfig) {
+		c.formFields = append(c.formFields, formField{name: name, value: value})
+	}
+}
+
+// isMultipart reports whether the options request a multipart/form-data body.
+func (c buildConfig) isMultipart() bool {
+	return c.filesDir != "" || len(c.formFields) > 0
+}
+
+// buildMultipartBody encodes the form fields, the remaining body parameters,
+// and the files of the configured directory as multipart/form-data. Fields
+// whose schema in the operation's multipart/form-data body is an object are
+// sent as application/json parts. It returns the body and its content type,
+// which carries the part boundary.
+func (b *Builder) buildMultipartBody(params map[string]any, opParams openapi3.Parameters, requestBody *openapi3.RequestBody, cfg buildConfig) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	schema := multipartSchema(requestBody)
+
+	for _, field := range cfg.formFields {
+		if err := writeFormField(writer, schema, field.name, field.value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for name, val := range b.extractBodyParams(params, opParams) {
+		if err := writeFormField(writer, schema, name, val); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if cfg.filesDir != "" {
+		if err := addDirectoryFiles(writer, cfg.filesDir); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to finish multipart body: %w", err)
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// multipartSchema returns the schema of the request body's multipart/form-data
+// content, or nil if it declares none.
+func multipartSchema(requestBody *openapi3.RequestBody) *openapi3.Schema {
+	if requestBody == nil {
+		return nil
+	}
+	for declared, mediaType := range requestBody.Content {
+		if parsed, _, err := mime.ParseMediaType(declared); err == nil && parsed == "multipart/form-data" {
+			if mediaType != nil && mediaType.Schema != nil {
+				return mediaType.Schema.Value
+			}
+		}
+	}
+	return nil
+}
+
+// isJSONPart reports whether the multipart schema declares the named field as
+// an object, which is sent as a JSON part.
+func isJSONPart(schema *openapi3.Schema, name string) bool {
+	if schema == nil {
+		return false
+	}
+	prop := schema.Properties[name]
+	return prop != nil && prop.Value != nil && prop.Value.Type != nil && prop.Value.Type.Is("object")
+}
+
+// writeFormField writes a form field, as a JSON part if the schema declares it
+// as an object.
+func writeFormField(writer *multipart.Writer, schema *openapi3.Schema, name string, val any) error {
+	if isJSONPart(schema, name) {
+		return writeJSONPart(writer, name, val)
+	}
+	if err := writer.WriteField(name, formFieldValue(val)); err != nil {
+		return fmt.Errorf("failed to write form field %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeJSONPart writes a form field as a part with Content-Type
+// application/json. A string value, as given on the command line, must already
+// be JSON; other values are JSON-encoded.
+func writeJSONPart(writer *multipart.Writer, name string, val any) error {
+	var data []byte
+	if str, ok := val.(string); ok {
+		if !json.Valid([]byte(str)) {
+			return fmt.Errorf("form field %s must be valid JSON", name)
+		}
+		data = []byte(str)
+	} else {
+		var err error
+		if data, err = json.Marshal(val); err != nil {
+			return fmt.Errorf("failed to encode form field %s: %w", name, err)
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(name)))
+	header.Set("Content-Type", "application/json")
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to write form field %s: %w", name, err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to write form field %s: %w", name, err)
+	}
+	return nil
+}
+
+// formFieldValue formats a body parameter as a form field value. Objects and
+// arrays are JSON-encoded.
+func formFieldValue(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// addDirectoryFiles attaches every regular file in dir as a file part named by
+// its base name, in name order.
+func addDirectoryFiles(writer *multipart.Writer, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read files directory %s: %w", dir, err)
+	}
+
+	var paths []string
+	var total int64
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		total += info.Size()
+		if total > MaxMultipartFilesSize {
+			return fmt.Errorf("files in %s exceed the %d MiB multipart size limit", dir, MaxMultipartFilesSize>>20)
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("files directory %s contains no files", dir)
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		if err := writeFilePart(writer, filepath.Base(path), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteEscaper escapes a Content-Disposition parameter value, as mime/multipart does.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// writeFilePart writes a file part whose field name and filename are both
+// name, typed by the file extension.
+func writeFilePart(writer *multipart.Writer, name string, data []byte) error {
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	escaped := quoteEscaper.Replace(name)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escaped, escaped))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to write file part %s: %w", name, err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to write file part %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package request
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formPart is a decoded multipart/form-data part.
+type formPart struct {
+	filename    string
+	contentType string
+	content     string
+}
+
+// readMultipartParts returns the parts of a multipart request, keyed by form name.
+func readMultipartParts(t *testing.T, req *http.Request) map[string]formPart {
+	t.Helper()
+
+	mediaType, mediaParams, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/form-data", mediaType)
+
+	parts := make(map[string]formPart)
+	reader := multipart.NewReader(req.Body, mediaParams["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parts[part.FormName()] = formPart{
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			content:     string(data),
+		}
+	}
+}
+
+func TestBuildRequest_MultipartFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"b":true}`), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0755))
+
+	b := NewBuilder(nil)
+	req, err := b.BuildRequest("POST", "/upload", "http://api.example.com",
+		map[string]any{"album": "holiday"}, nil, nil,
+		WithMultipartFiles(dir), WithFormField("caption", "beach day"))
+	require.NoError(t, err)
+
+	parts := readMultipartParts(t, req)
+	require.Len(t, parts, 4)
+
+	assert.Equal(t, formPart{filename: "a.txt", contentType: "text/plain; charset=utf-8", content: "alpha"}, parts["a.txt"])
+	assert.Equal(t, formPart{filename: "b.json", contentType: "application/json", content: `{"b":true}`}, parts["b.json"])
+	assert.Equal(t, "beach day", parts["caption"].content)
+	assert.Equal(t, "holiday", parts["album"].content)
+	assert.NotContains(t, parts, "nested")
+}
+
+func TestBuildRequest_MultipartFilesErrors(t *testing.T) {
+	b := NewBuilder(nil)
+
+	_, err := b.BuildRequest("POST", "/upload", "http://api.example.com", nil, nil, nil,
+		WithMultipartFiles(filepath.Join(t.TempDir(), "missing")))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read files directory")
+
+	_, err = b.BuildRequest("POST", "/upload", "http://api.example.com", nil, nil, nil,
+		WithMultipartFiles(t.TempDir()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains no files")
+}
+
+func TestBuildRequest_MultipartJSONPart(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("%PDF-1.7"), 0644))
+
+	requestBody := &openapi3.RequestBody{
+		Content: openapi3.Content{
+			"multipart/form-data": &openapi3.MediaType{
+				Schema: openapi3.NewSchemaRef("", &openapi3.Schema{
+					Type: &openapi3.Types{"object"},
+					Properties: openapi3.Schemas{
+						"metadata": openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"object"}}),
+						"options":  openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"object"}}),
+						"title":    openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"string"}}),
+					},
+				}),
+			},
+		},
+	}
+
+	b := NewBuilder(nil)
+	req, err := b.BuildRequest("POST", "/documents", "http://api.example.com",
+		map[string]any{"options": map[string]any{"ocr": true}, "title": "Q3"}, nil, requestBody,
+		WithMultipartFiles(dir), WithFormField("metadata", `{"author":"ada","tags":["q3"]}`))
+	require.NoError(t, err)
+
+	parts := readMultipartParts(t, req)
+	require.Len(t, parts, 4)
+	assert.Equal(t, "application/json", parts["metadata"].contentType)
+	assert.JSONEq(t, `{"author":"ada","tags":["q3"]}`, parts["metadata"].content)
+	assert.Equal(t, "application/json", parts["options"].contentType)
+	assert.JSONEq(t, `{"ocr":true}`, parts["options"].content)
+	assert.Equal(t, formPart{content: "Q3"}, parts["title"])
+	assert.Equal(t, formPart{filename: "report.pdf", contentType: "application/pdf", content: "%PDF-1.7"}, parts["report.pdf"])
+
+	_, err = b.BuildRequest("POST", "/documents", "http://api.example.com", nil, nil, requestBody,
+		WithFormField("metadata", "author=ada"))
+	assert.EqualError(t, err, "failed to build request body: form field metadata must be valid JSON")
+}
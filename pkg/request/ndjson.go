@@ -0,0 +1,87 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// NDJSONMediaType is the media type of newline-delimited JSON bodies, as
+// accepted by bulk ingest endpoints.
+const NDJSONMediaType = "application/x-ndjson"
+
+// ndjsonBodyContentType returns the declared NDJSON media type when the request
+// body offers NDJSON but not JSON, or "" otherwise.
+func ndjsonBodyContentType(requestBody *openapi3.RequestBody) string {
+	if requestBody == nil {
+		return ""
+	}
+
+	var ndjsonType string
+	for declared := range requestBody.Content {
+		mediaType, _, err := mime.ParseMediaType(declared)
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" {
+			return ""
+		}
+		if mediaType == NDJSONMediaType {
+			ndjsonType = declared
+		}
+	}
+	return ndjsonType
+}
+
+// buildNDJSONBody returns the --body value as NDJSON. A string, such as the
+// contents of an @file.ndjson reference, is sent verbatim once every line is
+// checked to be valid JSON; an array is encoded one element per line.
+func (b *Builder) buildNDJSONBody(params map[string]any) ([]byte, error) {
+	if items, ok := params["body"].([]any); ok {
+		var buf bytes.Buffer
+		for _, item := range items {
+			line, err := json.Marshal(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode NDJSON line: %w", err)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes(), nil
+	}
+
+	data, err := b.buildRawBody(params)
+	if err != nil || data == nil {
+		return data, err
+	}
+	if err := validateNDJSON(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// validateNDJSON checks that every non-blank line of data is a JSON value.
+func validateNDJSON(data []byte) error {
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return fmt.Errorf("NDJSON body line %d is not valid JSON: %s", i+1, truncateLine(line))
+		}
+	}
+	return nil
+}
+
+// truncateLine shortens a line for display in an error message.
+func truncateLine(line []byte) string {
+	const maxLen = 60
+	if len(line) <= maxLen {
+		return string(line)
+	}
+	return string(line[:maxLen]) + "..."
+}
@@ -0,0 +1,91 @@
+package request
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ndjsonRequestBody returns a required request body offered only as NDJSON,
+// whose lines are events with a required type.
+func ndjsonRequestBody() *openapi3.RequestBody {
+	event := openapi3.NewObjectSchema().WithProperty("type", stringSchema())
+	event.Required = []string{"type"}
+	return &openapi3.RequestBody{
+		Required: true,
+		Content: openapi3.Content{
+			NDJSONMediaType: &openapi3.MediaType{Schema: event.NewRef()},
+		},
+	}
+}
+
+func TestBuildRequest_NDJSONBodyFromFile(t *testing.T) {
+	b := NewBuilder(nil)
+	payload := "{\"type\":\"click\",\"x\":1}\n\n{\"type\":\"view\"}\n"
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte(payload), 0644))
+
+	params := map[string]any{"body": "@" + path}
+	require.NoError(t, b.ValidateParams(params, nil, ndjsonRequestBody()))
+
+	req, err := b.BuildRequest(http.MethodPost, "/events/bulk", "https://api.example.com", params, nil, ndjsonRequestBody())
+	require.NoError(t, err)
+
+	assert.Equal(t, NDJSONMediaType, req.Header.Get("Content-Type"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(body))
+}
+
+func TestBuildRequest_NDJSONBodyMalformedLine(t *testing.T) {
+	b := NewBuilder(nil)
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"click\"}\n{\"type\":\n"), 0644))
+
+	_, err := b.BuildRequest(http.MethodPost, "/events/bulk", "https://api.example.com",
+		map[string]any{"body": "@" + path}, nil, ndjsonRequestBody())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `NDJSON body line 2 is not valid JSON: {"type":`)
+}
+
+func TestBuildRequest_NDJSONBodyFromArray(t *testing.T) {
+	b := NewBuilder(nil)
+
+	req, err := b.BuildRequest(http.MethodPost, "/events/bulk", "https://api.example.com",
+		map[string]any{"body": []any{map[string]any{"type": "click"}, map[string]any{"type": "view"}}}, nil, ndjsonRequestBody())
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"type\":\"click\"}\n{\"type\":\"view\"}\n", string(body))
+}
+
+func TestBuildRequest_NDJSONContentTypeSelected(t *testing.T) {
+	b := NewBuilder(nil)
+	requestBody := ndjsonRequestBody()
+	requestBody.Content["application/json"] = &openapi3.MediaType{Schema: openapi3.NewObjectSchema().NewRef()}
+	params := map[string]any{"body": "{\"type\":\"click\"}\n"}
+	opts := []BuildOption{WithContentType(NDJSONMediaType)}
+
+	require.NoError(t, b.ValidateParams(params, nil, requestBody, opts...))
+	req, err := b.BuildRequest(http.MethodPost, "/events/bulk", "https://api.example.com", params, nil, requestBody, opts...)
+	require.NoError(t, err)
+
+	assert.Equal(t, NDJSONMediaType, req.Header.Get("Content-Type"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"type\":\"click\"}\n", string(body))
+}
+
+func TestValidateParams_NDJSONBodyRequired(t *testing.T) {
+	b := NewBuilder(nil)
+
+	err := b.ValidateParams(map[string]any{}, nil, ndjsonRequestBody())
+	assert.EqualError(t, err, "request body is required for this operation")
+}
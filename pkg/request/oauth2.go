@@ -0,0 +1,167 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
+)
+
+// oauth2TokenTimeout bounds a request to an OAuth2 token endpoint.
+const oauth2TokenTimeout = 30 * time.Second
+
+// OAuth2TokenPlaceholder stands in for the access token of an
+// oauth2_client_credentials profile in requests built WithoutTokenRequest.
+const OAuth2TokenPlaceholder = "<oauth2-access-token>"
+
+// oauth2ExpiryMargin is how long before its expiry a cached access token is
+// replaced, so that it does not expire while a request is in flight.
+const oauth2ExpiryMargin = 30 * time.Second
+
+// tokenCache holds the access tokens obtained with the OAuth2 client
+// credentials grant, one per app profile. It is safe for concurrent use.
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+	now    func() time.Time
+}
+
+// cachedToken is an access token and when it stops being reused.
+type cachedToken struct {
+	clientID    string
+	accessToken string
+	expiresAt   time.Time
+}
+
+// newTokenCache creates an empty token cache.
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: make(map[string]cachedToken), now: time.Now}
+}
+
+// get returns the unexpired token cached for key and client.
+func (c *tokenCache) get(key, clientID string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[key]
+	if !ok || token.clientID != clientID || !c.now().Before(token.expiresAt) {
+		return "", false
+	}
+	return token.accessToken, true
+}
+
+// set caches an access token for key and client that expires in expiresIn
+// seconds. Tokens without an expiry are not cached.
+func (c *tokenCache) set(key, clientID, accessToken string, expiresIn int64) {
+	if expiresIn <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[key] = cachedToken{
+		clientID:    clientID,
+		accessToken: accessToken,
+		expiresAt:   c.now().Add(time.Duration(expiresIn)*time.Second - oauth2ExpiryMargin),
+	}
+}
+
+// oauth2TokenResponse is a token endpoint response (RFC 6749, section 5).
+type oauth2TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// clientCredentialsToken returns an access token for an app profile's OAuth2
+// client, reusing the cached one until shortly before it expires. The token
+// URL and scopes stored with the credential take precedence over the
+// profile's auth.oauth2 settings.
+func (b *Builder) clientCredentialsToken(req *http.Request, appName string, profile *config.Profile, cred *credential.Credential) (string, error) {
+	key := appName + "/" + profile.Name
+	if token, ok := b.tokens.get(key, cred.ClientID); ok {
+		return token, nil
+	}
+
+	tokenURL, scopes := cred.TokenURL, cred.Scopes
+	if oauth2 := profile.Auth.OAuth2Config; oauth2 != nil {
+		if tokenURL == "" {
+			tokenURL = oauth2.TokenURL
+		}
+		if len(scopes) == 0 {
+			scopes = oauth2.Scopes
+		}
+	}
+	if tokenURL == "" {
+		return "", fmt.Errorf("oauth2 client credentials for %s have no token URL", key)
+	}
+
+	client, err := b.clients.Client(appName, profile)
+	if err != nil {
+		return "", err
+	}
+	token, err := fetchClientCredentialsToken(req.Context(), client, tokenURL, cred.ClientID, cred.ClientSecret, scopes)
+	if err != nil {
+		return "", err
+	}
+	b.tokens.set(key, cred.ClientID, token.AccessToken, token.ExpiresIn)
+	return token.AccessToken, nil
+}
+
+// fetchClientCredentialsToken requests an access token from tokenURL with the
+// client credentials grant, authenticating the client with HTTP basic auth.
+// The request is bounded by oauth2TokenTimeout.
+func fetchClientCredentialsToken(ctx context.Context, client *http.Client, tokenURL, clientID, clientSecret string, scopes []string) (*oauth2TokenResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, oauth2TokenTimeout)
+	defer cancel()
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oauth2 token request: %w", err)
+	}
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tokenReq.Header.Set("Accept", "application/json")
+	tokenReq.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := client.Do(tokenReq)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2 token request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oauth2 token response: %w", err)
+	}
+
+	var token oauth2TokenResponse
+	decodeErr := json.Unmarshal(body, &token)
+	if resp.StatusCode >= 400 {
+		if token.Error != "" {
+			return nil, fmt.Errorf("oauth2 token request failed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(token.Error+" "+token.ErrorDescription))
+		}
+		return nil, fmt.Errorf("oauth2 token request failed: HTTP %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("invalid oauth2 token response: %w", decodeErr)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2 token response has no access_token")
+	}
+	return &token, nil
+}
@@ -0,0 +1,165 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nomagicln/open-bridge/pkg/config"
+	"github.com/nomagicln/open-bridge/pkg/credential"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenServer returns a token endpoint that issues numbered access tokens
+// valid for expiresIn seconds to client "ob-client" with secret "s3cret".
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "ob-client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "read write" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+			return
+		}
+		n := issued.Add(1)
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+// clientCredentialsProfile returns the default profile with the given auth.
+func clientCredentialsProfile(auth config.AuthConfig) *config.Profile {
+	return &config.Profile{Name: "default", Auth: auth}
+}
+
+// injectClientCredentialsAuth injects auth for testapp/default into a new
+// request and returns its Authorization header.
+func injectClientCredentialsAuth(t *testing.T, b *Builder) string {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
+	require.NoError(t, err)
+	require.NoError(t, b.InjectAuth(req, "testapp", clientCredentialsProfile(config.AuthConfig{Type: "oauth2_client_credentials"})))
+	return req.Header.Get("Authorization")
+}
+
+func TestInjectAuth_OAuth2ClientCredentials(t *testing.T) {
+	server, issued := newTokenServer(t, 3600)
+	b, cleanup := setupBuilderWithCredentials(t, "testapp", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", server.URL, []string{"read", "write"}))
+	defer cleanup()
+
+	now := time.Now()
+	b.tokens.now = func() time.Time { return now }
+
+	assert.Equal(t, "Bearer token-1", injectClientCredentialsAuth(t, b))
+	assert.Equal(t, "Bearer token-1", injectClientCredentialsAuth(t, b))
+	assert.Equal(t, int32(1), issued.Load(), "the token is cached until it expires")
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, "Bearer token-2", injectClientCredentialsAuth(t, b))
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestInjectAuth_OAuth2ClientCredentials_ProfileTokenURL(t *testing.T) {
+	server, _ := newTokenServer(t, 3600)
+	b, cleanup := setupBuilderWithCredentials(t, "testapp", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", "", nil))
+	defer cleanup()
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
+	require.NoError(t, err)
+
+	err = b.InjectAuth(req, "testapp", clientCredentialsProfile(config.AuthConfig{
+		Type:         "oauth2_client_credentials",
+		OAuth2Config: &config.OAuth2Config{TokenURL: server.URL, Scopes: []string{"read", "write"}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+}
+
+func TestInjectAuth_OAuth2ClientCredentials_Errors(t *testing.T) {
+	server, _ := newTokenServer(t, 3600)
+
+	b, cleanup := setupBuilderWithCredentials(t, "testapp", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "wrong", server.URL, []string{"read", "write"}))
+	defer cleanup()
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
+	require.NoError(t, err)
+	err = b.InjectAuth(req, "testapp", clientCredentialsProfile(config.AuthConfig{Type: "oauth2_client_credentials"}))
+	assert.EqualError(t, err, "oauth2 token request failed: HTTP 401: invalid_client")
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	b, cleanup = setupBuilderWithCredentials(t, "testapp", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", "", nil))
+	defer cleanup()
+
+	err = b.InjectAuth(req, "testapp", clientCredentialsProfile(config.AuthConfig{Type: "oauth2_client_credentials"}))
+	assert.EqualError(t, err, "oauth2 client credentials for testapp/default have no token URL")
+}
+
+func TestInjectAuth_OAuth2ClientCredentials_ProfileTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"tls-token","expires_in":3600}`))
+	}))
+	t.Cleanup(server.Close)
+
+	b, cleanup := setupBuilderWithCredentials(t, "testapp", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", server.URL, nil))
+	defer cleanup()
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
+	require.NoError(t, err)
+	profile := clientCredentialsProfile(config.AuthConfig{Type: "oauth2_client_credentials"})
+
+	require.Error(t, b.InjectAuth(req, "testapp", profile), "the server certificate is not trusted by default")
+
+	profile.TLSConfig = config.TLSConfig{InsecureSkipVerify: true}
+	require.NoError(t, b.InjectAuth(req, "testapp", profile))
+	assert.Equal(t, "Bearer tls-token", req.Header.Get("Authorization"))
+}
+
+func TestInjectAuth_OAuth2ClientCredentials_WithoutTokenRequest(t *testing.T) {
+	server, issued := newTokenServer(t, 3600)
+	b, cleanup := setupBuilderWithCredentials(t, "testapp", "default",
+		credential.NewOAuth2ClientCredentials("ob-client", "s3cret", server.URL, []string{"read", "write"}))
+	defer cleanup()
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
+	require.NoError(t, err)
+	err = b.InjectAuth(req, "testapp", clientCredentialsProfile(config.AuthConfig{Type: "oauth2_client_credentials"}), WithoutTokenRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+OAuth2TokenPlaceholder, req.Header.Get("Authorization"))
+	assert.Equal(t, int32(0), issued.Load())
+}
+
+func TestTokenCache_NoExpiry(t *testing.T) {
+	cache := newTokenCache()
+	cache.set("app/default", "client", "token", 0)
+
+	_, ok := cache.get("app/default", "client")
+	assert.False(t, ok, "tokens without expires_in are not cached")
+
+	cache.set("app/default", "client", "token", 3600)
+	token, ok := cache.get("app/default", "client")
+	assert.True(t, ok)
+	assert.Equal(t, "token", token)
+
+	_, ok = cache.get("app/default", "other-client")
+	assert.False(t, ok, "a token is not reused for another client")
+}
@@ -0,0 +1,115 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// PlannedRequest is a request built from an operation but not sent: what
+// BuildRequest would send, as plain values that are easy to display or assert
+// on. Authentication and interceptors are not applied.
+type PlannedRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Headers     http.Header `json:"headers,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	ContentType string      `json:"contentType,omitempty"`
+}
+
+// PlanRequest builds the request for an operation and parameters without
+// creating an http.Request. It validates and shapes the body the same way
+// BuildRequest does.
+func (b *Builder) PlanRequest(
+	method, path, baseURL string,
+	params map[string]any,
+	opParams openapi3.Parameters,
+	requestBody *openapi3.RequestBody,
+	opts ...BuildOption,
+) (*PlannedRequest, error) {
+	cfg := newBuildConfig(opts)
+
+	// Substitute path parameters
+	finalPath := b.substitutePathParams(path, params, opParams)
+
+	// Build query string
+	queryString := addRawQueryParams(b.buildQueryString(params, opParams), cfg.queryParams)
+
+	// Build request body for non-GET methods
+	body, contentType, err := b.createBody(method, params, opParams, requestBody, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request body: %w", err)
+	}
+
+	planned := &PlannedRequest{
+		Method:      method,
+		URL:         b.buildFullURL(baseURL, finalPath, queryString),
+		Headers:     make(http.Header),
+		Body:        body,
+		ContentType: contentType,
+	}
+
+	// Set content type if we have a body
+	if body != nil {
+		planned.Headers.Set("Content-Type", contentType)
+	}
+
+	// Add header parameters
+	b.addHeaderParams(&http.Request{Header: planned.Headers}, params, opParams)
+
+	// Identify the client and the request unless header parameters already did
+	if planned.Headers.Get("User-Agent") == "" && b.userAgent != "" {
+		planned.Headers.Set("User-Agent", b.userAgent)
+	}
+	if planned.Headers.Get(HeaderRequestID) == "" && !cfg.skipRequestID {
+		planned.Headers.Set(HeaderRequestID, newRequestID())
+	}
+
+	if cfg.prefer != "" && isWriteMethod(method) && planned.Headers.Get("Prefer") == "" {
+		planned.Headers.Set("Prefer", cfg.prefer)
+	}
+
+	return planned, nil
+}
+
+// PlanRequestForOperation is PlanRequest for an operation object, like
+// BuildRequestForOperation.
+func (b *Builder) PlanRequestForOperation(
+	method, pathTemplate, baseURL string,
+	op *openapi3.Operation,
+	params map[string]any,
+	opts ...BuildOption,
+) (*PlannedRequest, error) {
+	if op == nil {
+		return b.PlanRequest(method, pathTemplate, baseURL, params, nil, nil, opts...)
+	}
+
+	var requestBody *openapi3.RequestBody
+	if op.RequestBody != nil {
+		requestBody = op.RequestBody.Value
+	}
+
+	return b.PlanRequest(method, pathTemplate, baseURL, params, op.Parameters, requestBody, opts...)
+}
+
+// HTTPRequest creates the http.Request that sends the planned request. Each
+// call returns a new request with its own copy of the headers.
+func (p *PlannedRequest) HTTPRequest() (*http.Request, error) {
+	var req *http.Request
+	var err error
+	if p.Body != nil {
+		req, err = http.NewRequest(p.Method, p.URL, bytes.NewReader(p.Body))
+	} else {
+		req, err = http.NewRequest(p.Method, p.URL, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header = p.Headers.Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	return req, nil
+}
@@ -0,0 +1,86 @@
+package request
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRequest(t *testing.T) {
+	b := NewBuilder(nil, WithUserAgent("OpenBridge/test"))
+
+	op := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			paramRef("userId", "path", true, intSchema()),
+			paramRef("notify", "query", false, stringSchema()),
+			paramRef("X-Request-Source", "header", false, stringSchema()),
+		},
+		RequestBody: &openapi3.RequestBodyRef{
+			Value: &openapi3.RequestBody{
+				Content: openapi3.Content{
+					"application/json": &openapi3.MediaType{
+						Schema: openapi3.NewSchemaRef("", objectSchemaWithStringArray("title", "tags")),
+					},
+				},
+			},
+		},
+	}
+
+	planned, err := b.PlanRequestForOperation(http.MethodPost, "/users/{userId}/posts", "https://api.example.com", op, map[string]any{
+		"userId":           42,
+		"notify":           "email",
+		"X-Request-Source": "cli",
+		"title":            "Hello",
+		"tags":             "news,go",
+	}, WithPrefer("return=minimal"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, planned.Method)
+	assert.Equal(t, "https://api.example.com/users/42/posts?notify=email", planned.URL)
+	assert.Equal(t, "application/json", planned.ContentType)
+	assert.JSONEq(t, `{"title":"Hello","tags":["news","go"]}`, string(planned.Body))
+	assert.Equal(t, "application/json", planned.Headers.Get("Content-Type"))
+	assert.Equal(t, "cli", planned.Headers.Get("X-Request-Source"))
+	assert.Equal(t, "OpenBridge/test", planned.Headers.Get("User-Agent"))
+	assert.Equal(t, "return=minimal", planned.Headers.Get("Prefer"))
+	assert.NotEmpty(t, planned.Headers.Get(HeaderRequestID))
+
+	req, err := planned.HTTPRequest()
+	require.NoError(t, err)
+	assert.Equal(t, planned.URL, req.URL.String())
+	assert.Equal(t, planned.Headers, req.Header)
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, planned.Body, body)
+
+	req.Header.Set("Authorization", "Bearer token")
+	assert.Empty(t, planned.Headers.Get("Authorization"), "the request has its own headers")
+}
+
+func TestPlanRequest_NoBody(t *testing.T) {
+	b := NewBuilder(nil)
+
+	planned, err := b.PlanRequest(http.MethodGet, "/pets", "https://api.example.com", map[string]any{"limit": 10},
+		openapi3.Parameters{paramRef("limit", "query", false, intSchema())}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.example.com/pets?limit=10", planned.URL)
+	assert.Nil(t, planned.Body)
+	assert.Empty(t, planned.ContentType)
+	assert.Empty(t, planned.Headers.Get("Content-Type"))
+
+	req, err := planned.HTTPRequest()
+	require.NoError(t, err)
+	assert.Nil(t, req.Body)
+}
+
+func TestPlanRequest_InvalidBody(t *testing.T) {
+	b := NewBuilder(nil)
+
+	_, err := b.PlanRequest(http.MethodPost, "/pets", "https://api.example.com", map[string]any{"body": "{not json"}, nil, nil)
+	assert.ErrorContains(t, err, "failed to build request body")
+}
@@ -0,0 +1,138 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// queryParam is a raw query parameter added with WithQueryParam.
+type queryParam struct {
+	name  string
+	value string
+}
+
+// WithQueryParam adds name=value to the query string after the operation's
+// parameters are encoded, replacing any value they gave name. The value is not
+// checked against the spec, so it can carry parameters the spec does not
+// declare. Repeating a name adds it once per value.
+func WithQueryParam(name, value string) BuildOption {
+	return func(c *buildConfig) {
+		c.queryParams = append(c.queryParams, queryParam{name: name, value: value})
+	}
+}
+
+// addRawQueryParams adds the raw query parameters to an encoded query string.
+func addRawQueryParams(queryString string, params []queryParam) string {
+	if len(params) == 0 {
+		return queryString
+	}
+
+	values, err := url.ParseQuery(queryString)
+	if err != nil {
+		values = url.Values{}
+	}
+	for _, param := range params {
+		values.Del(param.name)
+	}
+	for _, param := range params {
+		values.Add(param.name, param.value)
+	}
+	return values.Encode()
+}
+
+// addQueryParam adds a query parameter value to values, serialized according
+// to the parameter's style.
+func addQueryParam(values url.Values, param *openapi3.Parameter, val any) {
+	switch param.Style {
+	case openapi3.SerializationDeepObject:
+		if obj, ok := queryObjectValue(val); ok {
+			addDeepObject(values, param.Name, obj)
+			return
+		}
+	case "", openapi3.SerializationForm:
+		if isObjectParam(param) {
+			if obj, ok := queryObjectValue(val); ok {
+				addFormObject(values, param, obj)
+				return
+			}
+		}
+	}
+	values.Add(param.Name, fmt.Sprintf("%v", val))
+}
+
+// isObjectParam reports whether a parameter's schema is an object.
+func isObjectParam(param *openapi3.Parameter) bool {
+	return param.Schema != nil && param.Schema.Value != nil &&
+		param.Schema.Value.Type != nil && param.Schema.Value.Type.Is("object")
+}
+
+// addFormObject serializes an object in form style. Exploded, the default for
+// form, each property becomes its own key (status=active&type=x); otherwise
+// the properties are joined as key,value pairs under the parameter name
+// (filter=status,active,type,x).
+func addFormObject(values url.Values, param *openapi3.Parameter, obj map[string]any) {
+	keys := slices.Sorted(maps.Keys(obj))
+	if param.Explode == nil || *param.Explode {
+		for _, key := range keys {
+			values.Add(key, fmt.Sprintf("%v", obj[key]))
+		}
+		return
+	}
+
+	parts := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		parts = append(parts, key, fmt.Sprintf("%v", obj[key]))
+	}
+	values.Add(param.Name, strings.Join(parts, ","))
+}
+
+// queryObjectValue returns val as an object: either a map, or a string
+// holding a JSON object such as a --filter '{"status":"active"}' flag value.
+func queryObjectValue(val any) (map[string]any, bool) {
+	switch v := val.(type) {
+	case map[string]any:
+		return v, true
+	case string:
+		if !strings.HasPrefix(strings.TrimSpace(v), "{") {
+			return nil, false
+		}
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(v), &obj); err != nil {
+			return nil, false
+		}
+		return obj, true
+	default:
+		return nil, false
+	}
+}
+
+// addDeepObject serializes an object in deepObject style: each property
+// becomes name[key]=value. Nested objects extend the key, as in
+// name[key][sub]=value, and arrays repeat the key for each item.
+func addDeepObject(values url.Values, name string, obj map[string]any) {
+	for key, val := range obj {
+		addDeepObjectValue(values, name+"["+key+"]", val)
+	}
+}
+
+// addDeepObjectValue adds a single deepObject property value.
+func addDeepObjectValue(values url.Values, key string, val any) {
+	switch v := val.(type) {
+	case map[string]any:
+		addDeepObject(values, key, v)
+	case []any:
+		for _, item := range v {
+			addDeepObjectValue(values, key, item)
+		}
+	case nil:
+		values.Add(key, "")
+	default:
+		values.Add(key, fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,113 @@
+package request
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deepObjectParam(name string) *openapi3.ParameterRef {
+	ref := paramRef(name, "query", false, &openapi3.Schema{Type: &openapi3.Types{"object"}})
+	ref.Value.Style = openapi3.SerializationDeepObject
+	ref.Value.Explode = openapi3.Ptr(true)
+	return ref
+}
+
+func TestBuildQueryString_DeepObject(t *testing.T) {
+	b := NewBuilder(nil)
+	opParams := openapi3.Parameters{deepObjectParam("filter"), paramRef("limit", "query", false, intSchema())}
+
+	tests := []struct {
+		name   string
+		filter any
+	}{
+		{"map value", map[string]any{"status": "active", "type": "x"}},
+		{"JSON flag value", `{"status": "active", "type": "x"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := b.buildQueryString(map[string]any{"filter": tt.filter, "limit": 10}, opParams)
+			assert.Equal(t, "filter%5Bstatus%5D=active&filter%5Btype%5D=x&limit=10", query)
+
+			values, err := url.ParseQuery(query)
+			require.NoError(t, err)
+			assert.Equal(t, "active", values.Get("filter[status]"))
+			assert.Equal(t, "x", values.Get("filter[type]"))
+		})
+	}
+}
+
+func TestBuildQueryString_DeepObjectNested(t *testing.T) {
+	b := NewBuilder(nil)
+	params := map[string]any{"filter": map[string]any{
+		"owner": map[string]any{"id": 7},
+		"tags":  []any{"a", "b"},
+	}}
+
+	values, err := url.ParseQuery(b.buildQueryString(params, openapi3.Parameters{deepObjectParam("filter")}))
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"filter[owner][id]": {"7"},
+		"filter[tags]":      {"a", "b"},
+	}, values)
+}
+
+func TestBuildQueryString_DeepObjectScalar(t *testing.T) {
+	b := NewBuilder(nil)
+	query := b.buildQueryString(map[string]any{"filter": "active"}, openapi3.Parameters{deepObjectParam("filter")})
+	assert.Equal(t, "filter=active", query)
+}
+
+func formObjectParam(name string, explode *bool) *openapi3.ParameterRef {
+	ref := paramRef(name, "query", false, &openapi3.Schema{Type: &openapi3.Types{"object"}})
+	ref.Value.Style = openapi3.SerializationForm
+	ref.Value.Explode = explode
+	return ref
+}
+
+func TestBuildQueryString_FormObject(t *testing.T) {
+	b := NewBuilder(nil)
+	filter := map[string]any{"status": "active", "type": "x"}
+
+	tests := []struct {
+		name    string
+		explode *bool
+		want    url.Values
+	}{
+		{"explode", openapi3.Ptr(true), url.Values{"status": {"active"}, "type": {"x"}}},
+		{"explode by default", nil, url.Values{"status": {"active"}, "type": {"x"}}},
+		{"no explode", openapi3.Ptr(false), url.Values{"filter": {"status,active,type,x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opParams := openapi3.Parameters{formObjectParam("filter", tt.explode)}
+			for _, value := range []any{filter, `{"type": "x", "status": "active"}`} {
+				values, err := url.ParseQuery(b.buildQueryString(map[string]any{"filter": value}, opParams))
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, values)
+			}
+		})
+	}
+}
+
+func TestBuildQueryString_FormObjectScalar(t *testing.T) {
+	b := NewBuilder(nil)
+	query := b.buildQueryString(map[string]any{"filter": "active"}, openapi3.Parameters{formObjectParam("filter", nil)})
+	assert.Equal(t, "filter=active", query)
+}
+
+func TestBuildRequest_WithQueryParam(t *testing.T) {
+	b := NewBuilder(nil)
+	opParams := openapi3.Parameters{paramRef("limit", "query", false, intSchema())}
+
+	req, err := b.BuildRequest(http.MethodGet, "/pets", "https://api.example.com", map[string]any{"limit": 10}, opParams, nil,
+		WithQueryParam("debug", "1"), WithQueryParam("limit", "all"), WithQueryParam("tag", "a&b"))
+	require.NoError(t, err)
+	assert.Equal(t, "debug=1&limit=all&tag=a%26b", req.URL.RawQuery)
+}
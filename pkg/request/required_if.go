@@ -0,0 +1,84 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RequiredIfExtension makes a parameter required only when other parameters
+// have certain values. Its value maps each trigger parameter to a value, or a
+// list of values, that it must have; the parameter is required when every
+// trigger matches. For example, on a reason parameter:
+//
+//	x-ob-required-if:
+//	  action: reject
+const RequiredIfExtension = "x-ob-required-if"
+
+// checkConditionalParams validates that parameters declaring x-ob-required-if
+// are present when their conditions hold.
+func (b *Builder) checkConditionalParams(params map[string]any, opParams openapi3.Parameters) error {
+	for _, paramRef := range opParams {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		param := paramRef.Value
+
+		conditions, ok := param.Extensions[RequiredIfExtension].(map[string]any)
+		if !ok || len(conditions) == 0 {
+			continue
+		}
+		if _, exists := params[param.Name]; exists {
+			continue
+		}
+
+		triggers := slices.Sorted(maps.Keys(conditions))
+		if conditionsHold(params, triggers, conditions) {
+			return fmt.Errorf("parameter '%s' is required when %s", param.Name, describeConditions(triggers, conditions))
+		}
+	}
+	return nil
+}
+
+// conditionsHold reports whether every trigger parameter is set to its
+// expected value, or to one of the expected values for a list.
+func conditionsHold(params map[string]any, triggers []string, conditions map[string]any) bool {
+	for _, trigger := range triggers {
+		value, exists := params[trigger]
+		if !exists {
+			return false
+		}
+
+		actual := fmt.Sprintf("%v", value)
+		expected, isList := conditions[trigger].([]any)
+		if !isList {
+			expected = []any{conditions[trigger]}
+		}
+		if !slices.ContainsFunc(expected, func(v any) bool { return fmt.Sprintf("%v", v) == actual }) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeConditions formats conditions as, e.g., 'action' is 'reject'.
+func describeConditions(triggers []string, conditions map[string]any) string {
+	parts := make([]string, 0, len(triggers))
+	for _, trigger := range triggers {
+		values, ok := conditions[trigger].([]any)
+		if !ok {
+			parts = append(parts, fmt.Sprintf("'%s' is '%v'", trigger, conditions[trigger]))
+			continue
+		}
+
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = fmt.Sprintf("'%v'", v)
+		}
+		parts = append(parts, fmt.Sprintf("'%s' is one of %s", trigger, strings.Join(quoted, ", ")))
+	}
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,55 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requiredIfParams(condition any) openapi3.Parameters {
+	reason := paramRef("reason", "query", false, stringSchema())
+	reason.Value.Extensions = map[string]any{RequiredIfExtension: map[string]any{"action": condition}}
+	return openapi3.Parameters{
+		paramRef("action", "query", true, stringSchema()),
+		reason,
+	}
+}
+
+func TestValidateParams_RequiredIf(t *testing.T) {
+	b := NewBuilder(nil)
+	opParams := requiredIfParams("reject")
+
+	err := b.ValidateParams(map[string]any{"action": "reject"}, opParams, nil)
+	require.Error(t, err)
+	assert.Equal(t, "parameter 'reason' is required when 'action' is 'reject'", err.Error())
+
+	assert.NoError(t, b.ValidateParams(map[string]any{"action": "reject", "reason": "duplicate"}, opParams, nil))
+	assert.NoError(t, b.ValidateParams(map[string]any{"action": "approve"}, opParams, nil))
+}
+
+func TestValidateParams_RequiredIfOneOf(t *testing.T) {
+	b := NewBuilder(nil)
+	opParams := requiredIfParams([]any{"reject", "hold"})
+
+	err := b.ValidateParams(map[string]any{"action": "hold"}, opParams, nil)
+	require.Error(t, err)
+	assert.Equal(t, "parameter 'reason' is required when 'action' is one of 'reject', 'hold'", err.Error())
+
+	assert.NoError(t, b.ValidateParams(map[string]any{"action": "approve"}, opParams, nil))
+}
+
+func TestValidateParams_RequiredIfTriggerTypes(t *testing.T) {
+	b := NewBuilder(nil)
+	reason := paramRef("reason", "query", false, stringSchema())
+	reason.Value.Extensions = map[string]any{RequiredIfExtension: map[string]any{"level": float64(3)}}
+	opParams := openapi3.Parameters{paramRef("level", "query", false, intSchema()), reason}
+
+	// The trigger value is converted to its schema type before the comparison.
+	err := b.ValidateParams(map[string]any{"level": "3"}, opParams, nil)
+	assert.EqualError(t, err, "parameter 'reason' is required when 'level' is '3'")
+
+	// A missing trigger never requires the parameter.
+	assert.NoError(t, b.ValidateParams(map[string]any{}, opParams, nil))
+}
@@ -0,0 +1,60 @@
+package request
+
+import (
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// nullType is the OpenAPI 3.1 type of a null value.
+const nullType = "null"
+
+// SchemaTypes returns the types a schema accepts other than null, e.g. string
+// for the OpenAPI 3.1 nullable string ["string", "null"], or string and
+// integer for the union ["string", "integer"].
+func SchemaTypes(schema *openapi3.Schema) []string {
+	if schema == nil || schema.Type == nil {
+		return nil
+	}
+	var types []string
+	for _, typ := range schema.Type.Slice() {
+		if typ != nullType {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// AllowsNull reports whether a schema accepts null, either as an OpenAPI 3.1
+// type or with the OpenAPI 3.0 nullable keyword.
+func AllowsNull(schema *openapi3.Schema) bool {
+	return schema != nil && (schema.Nullable || schema.Type.Includes(nullType))
+}
+
+// singleType returns the one type a schema accepts other than null, or "" if
+// it declares none or a union of several.
+func singleType(schema *openapi3.Schema) string {
+	if types := SchemaTypes(schema); len(types) == 1 {
+		return types[0]
+	}
+	return ""
+}
+
+// convertUnionValue converts a string value for a schema accepting several
+// types. A string is kept as is when the union includes string; otherwise the
+// value is converted to the first type it parses as, in the order declared.
+func convertUnionValue(strVal string, types []string, schema *openapi3.Schema, builder *Builder, visiting schemaSet) (any, error) {
+	if slices.Contains(types, "string") {
+		return strVal, nil
+	}
+	for _, typ := range types {
+		converted, err := convertStringToType(strVal, typ, schema, builder, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := converted.(string); !ok {
+			return converted, nil
+		}
+	}
+	return strVal, nil
+}
@@ -56,7 +56,7 @@ func (r *Router) Execute(args []string) error {
 	}
 
 	// Load app configuration
-	appConfig, err := r.configMgr.GetAppConfig(appName)
+	appConfig, err := r.configMgr.GetEffectiveAppConfig(appName)
 	if err != nil {
 		return fmt.Errorf("app not found: %s (use 'ob install' to add it)", appName)
 	}
@@ -201,6 +201,7 @@ func (r *Router) registerProgressiveHandler(
 		return nil, fmt.Errorf("failed to create progressive handler: %w", err)
 	}
 
+	progressiveHandler.SetMapper(r.mcpHandler.GetMapper())
 	progressiveHandler.SetAppConfig(appConfig, profileName)
 	if err := progressiveHandler.SetSpec(specDoc, &profile.SafetyConfig); err != nil {
 		_ = progressiveHandler.Close()
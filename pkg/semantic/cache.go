@@ -0,0 +1,139 @@
+// Package semantic provides semantic mapping from OpenAPI operations to CLI commands.
+// This file contains the persistent command tree cache.
+package semantic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// treeCacheVersion is bumped when the mapping logic or cached format changes,
+// so trees cached by older versions are rebuilt.
+const treeCacheVersion = "3"
+
+// cachedCommandTree is the on-disk form of a cached command tree.
+type cachedCommandTree struct {
+	Key  string       `json:"key"`
+	Tree *CommandTree `json:"tree"`
+}
+
+// Fingerprint returns a hash of the mapper configuration, so cached trees built
+// with different verb mappings or rules are not reused.
+func (m *Mapper) Fingerprint() string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "version=%s\n", treeCacheVersion)
+
+	verbMapper := m.verbMapper
+	for _, method := range slices.Sorted(maps.Keys(verbMapper.MethodVerbs)) {
+		_, _ = fmt.Fprintf(h, "method %s=%s\n", method, verbMapper.MethodVerbs[method])
+	}
+	for _, key := range slices.Sorted(maps.Keys(verbMapper.CustomMappings)) {
+		_, _ = fmt.Fprintf(h, "custom %s=%s\n", key, verbMapper.CustomMappings[key])
+	}
+	for _, rule := range verbMapper.PathPatternRules {
+		_, _ = fmt.Fprintf(h, "rule %s %s=%s\n", rule.Method, rule.Pattern, rule.Verb)
+	}
+	if verbMapper.PreferOperationID {
+		_, _ = fmt.Fprintf(h, "operation-id verbs %v\n", OperationIDVerbPrefixes)
+	}
+	for _, prefix := range m.extractor.IgnoredPrefixes {
+		_, _ = fmt.Fprintf(h, "ignore %s\n", prefix)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SpecFingerprint returns a hash of the content of a parsed spec.
+func SpecFingerprint(spec *openapi3.T) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// BuildCommandTreeCached returns the command tree for spec, reading it from
+// cachePath when it was built from the same spec content with the same mapper
+// configuration, and otherwise building it and writing it to cachePath.
+// Cache read and write failures fall back to building the tree.
+func (m *Mapper) BuildCommandTreeCached(spec *openapi3.T, cachePath string) *CommandTree {
+	specHash, err := SpecFingerprint(spec)
+	if err != nil || cachePath == "" {
+		return m.BuildCommandTree(spec)
+	}
+	key := m.Fingerprint() + ":" + specHash
+
+	if tree, ok := loadCachedCommandTree(cachePath, key); ok {
+		return tree
+	}
+
+	tree := m.BuildCommandTree(spec)
+	_ = saveCachedCommandTree(cachePath, key, tree)
+	return tree
+}
+
+// loadCachedCommandTree reads a cached tree, reporting false if it is missing,
+// corrupt, or was cached under a different key.
+func loadCachedCommandTree(path, key string) (*CommandTree, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var cached cachedCommandTree
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Key != key || cached.Tree == nil {
+		return nil, false
+	}
+
+	tree := cached.Tree
+	if tree.RootResources == nil {
+		tree.RootResources = make(map[string]*Resource)
+	}
+	for _, res := range tree.RootResources {
+		restoreResource(res, nil)
+	}
+	return tree, true
+}
+
+// restoreResource re-creates the fields of a cached resource that are not persisted.
+func restoreResource(res *Resource, parent *Resource) {
+	res.Parent = parent
+	if res.Operations == nil {
+		res.Operations = make(map[string]*Operation)
+	}
+	if res.SubResources == nil {
+		res.SubResources = make(map[string]*Resource)
+	}
+	res.verbSet = NewVerbSet(nil)
+	for _, sub := range res.SubResources {
+		restoreResource(sub, res)
+	}
+}
+
+// saveCachedCommandTree writes a tree to the cache atomically.
+func saveCachedCommandTree(path, key string, tree *CommandTree) error {
+	data, err := json.Marshal(cachedCommandTree{Key: key, Tree: tree})
+	if err != nil {
+		return fmt.Errorf("failed to marshal command tree: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write command tree: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to move command tree: %w", err)
+	}
+	return nil
+}
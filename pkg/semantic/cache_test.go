@@ -0,0 +1,105 @@
+package semantic
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// cacheTestSpec returns a spec with a single PATCH operation.
+func cacheTestSpec() *openapi3.T {
+	paths := openapi3.NewPaths()
+	paths.Set("/users/{id}", &openapi3.PathItem{
+		Patch: &openapi3.Operation{OperationID: "modifyUser", Responses: openapi3.NewResponses()},
+	})
+	return &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Users", Version: "1"}, Paths: paths}
+}
+
+// markCachedTree renames the cached resource so a tree read from disk can be told
+// apart from a freshly built one.
+func markCachedTree(t *testing.T, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected cache file: %v", err)
+	}
+	var cached cachedCommandTree
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatalf("failed to decode cache: %v", err)
+	}
+	cached.Tree.RootResources["from-disk"] = cached.Tree.RootResources["users"]
+	delete(cached.Tree.RootResources, "users")
+	data, _ = json.Marshal(cached)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+}
+
+func TestBuildCommandTreeCached(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache", "command-tree.json")
+
+	tree := NewMapper().BuildCommandTreeCached(cacheTestSpec(), cachePath)
+	if _, ok := tree.RootResources["users"]; !ok {
+		t.Fatalf("expected users resource, got %v", tree.RootResources)
+	}
+	markCachedTree(t, cachePath)
+
+	// A new mapper, as in a later process, reads the tree from disk.
+	tree = NewMapper().BuildCommandTreeCached(cacheTestSpec(), cachePath)
+	res, ok := tree.RootResources["from-disk"]
+	if !ok {
+		t.Fatalf("expected tree to be loaded from disk, got %v", tree.RootResources)
+	}
+	if op := res.Operations["update"]; op == nil || op.Method != "PATCH" || op.Path != "/users/{id}" {
+		t.Errorf("unexpected cached operation: %+v", res.Operations)
+	}
+}
+
+func TestBuildCommandTreeCachedInvalidation(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "command-tree.json")
+
+	NewMapper().BuildCommandTreeCached(cacheTestSpec(), cachePath)
+	markCachedTree(t, cachePath)
+
+	// A changed spec is rebuilt.
+	spec := cacheTestSpec()
+	spec.Paths.Set("/users", &openapi3.PathItem{Get: &openapi3.Operation{Responses: openapi3.NewResponses()}})
+	tree := NewMapper().BuildCommandTreeCached(spec, cachePath)
+	if _, ok := tree.RootResources["users"]; !ok {
+		t.Fatalf("expected a rebuilt tree for a changed spec, got %v", tree.RootResources)
+	}
+	markCachedTree(t, cachePath)
+
+	// Changed mapper options are rebuilt too.
+	tree = NewMapper(WithMethodVerbs(map[string]string{"LIST": "ls"})).BuildCommandTreeCached(spec, cachePath)
+	users, ok := tree.RootResources["users"]
+	if !ok {
+		t.Fatalf("expected a rebuilt tree for changed mapper options, got %v", tree.RootResources)
+	}
+	if _, ok := users.Operations["ls"]; !ok {
+		t.Errorf("expected custom list verb, got %v", users.Operations)
+	}
+	markCachedTree(t, cachePath)
+
+	// So is a switch to operationId verbs.
+	tree = NewMapper(WithMethodVerbs(map[string]string{"LIST": "ls"}), WithOperationIDVerbs()).BuildCommandTreeCached(spec, cachePath)
+	if _, ok := tree.RootResources["users"]; !ok {
+		t.Errorf("expected a rebuilt tree with operationId verbs, got %v", tree.RootResources)
+	}
+}
+
+func TestBuildCommandTreeCachedCorruptFile(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "command-tree.json")
+	if err := os.WriteFile(cachePath, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := NewMapper().BuildCommandTreeCached(cacheTestSpec(), cachePath)
+	if _, ok := tree.RootResources["users"]; !ok {
+		t.Fatalf("expected tree to be rebuilt, got %v", tree.RootResources)
+	}
+}
@@ -0,0 +1,31 @@
+package semantic
+
+import (
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ConfirmExtension marks a dangerous operation that the CLI only runs after
+// the user confirms it, e.g. x-ob-confirm: true, or a message explaining what
+// the operation does, such as x-ob-confirm: "This deletes every pet."
+const ConfirmExtension = "x-ob-confirm"
+
+// ConfirmMessage reports whether an operation is marked with x-ob-confirm,
+// and the message given with it, if any.
+func ConfirmMessage(op *openapi3.Operation) (string, bool) {
+	if op == nil {
+		return "", false
+	}
+	switch v := op.Extensions[ConfirmExtension].(type) {
+	case bool:
+		return "", v
+	case string:
+		if confirm, err := strconv.ParseBool(v); err == nil {
+			return "", confirm
+		}
+		return v, v != ""
+	default:
+		return "", false
+	}
+}
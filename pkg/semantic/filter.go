@@ -0,0 +1,53 @@
+package semantic
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OperationFilter reports whether an operation should be exposed as a CLI
+// command or MCP tool. It lets embedders restrict operations programmatically,
+// in addition to any allow and deny lists.
+type OperationFilter func(operationID, method, path string, op *openapi3.Operation) bool
+
+// FilterCommandTree removes the operations of tree that filter rejects, along
+// with resources left without operations or sub-resources. The tree is
+// modified in place. A nil filter keeps every operation.
+func FilterCommandTree(tree *CommandTree, spec *openapi3.T, filter OperationFilter) {
+	if tree == nil || filter == nil {
+		return
+	}
+	for name, res := range tree.RootResources {
+		if !filterResource(res, spec, filter) {
+			delete(tree.RootResources, name)
+		}
+	}
+}
+
+// filterResource filters a resource's operations and sub-resources, reporting
+// whether anything is left.
+func filterResource(res *Resource, spec *openapi3.T, filter OperationFilter) bool {
+	for verb, op := range res.Operations {
+		if !filter(op.OperationID, op.Method, op.Path, specOperation(spec, op.Method, op.Path)) {
+			delete(res.Operations, verb)
+		}
+	}
+	for name, sub := range res.SubResources {
+		if !filterResource(sub, spec, filter) {
+			delete(res.SubResources, name)
+		}
+	}
+	return len(res.Operations) > 0 || len(res.SubResources) > 0
+}
+
+// specOperation returns the spec operation for a method and path, or nil if
+// the spec does not declare it.
+func specOperation(spec *openapi3.T, method, path string) *openapi3.Operation {
+	if spec == nil || spec.Paths == nil {
+		return nil
+	}
+	pathItem := spec.Paths.Value(path)
+	if pathItem == nil {
+		return nil
+	}
+	return pathItem.GetOperation(method)
+}
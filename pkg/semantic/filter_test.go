@@ -0,0 +1,43 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFilterCommandTree(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/users", &openapi3.PathItem{
+		Get:  &openapi3.Operation{OperationID: "listUsers", Responses: openapi3.NewResponses()},
+		Post: &openapi3.Operation{OperationID: "createUser", Responses: openapi3.NewResponses()},
+	})
+	paths.Set("/jobs", &openapi3.PathItem{
+		Post: &openapi3.Operation{OperationID: "startJob", Responses: openapi3.NewResponses()},
+	})
+	spec := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Filter", Version: "1"}, Paths: paths}
+
+	tree := NewMapper().BuildCommandTree(spec)
+	var seen []string
+	FilterCommandTree(tree, spec, func(operationID, method, path string, op *openapi3.Operation) bool {
+		if op == nil || op.OperationID != operationID {
+			t.Errorf("expected spec operation for %s %s, got %v", method, path, op)
+		}
+		seen = append(seen, operationID)
+		return method == "GET"
+	})
+
+	if len(seen) != 3 {
+		t.Errorf("expected the filter to see 3 operations, got %v", seen)
+	}
+	if _, ok := tree.RootResources["jobs"]; ok {
+		t.Error("expected resource without remaining operations to be removed")
+	}
+	users, ok := tree.RootResources["users"]
+	if !ok {
+		t.Fatal("expected users resource to remain")
+	}
+	if len(users.Operations) != 1 || users.Operations["list"] == nil {
+		t.Errorf("expected only the list operation to remain, got %v", users.Operations)
+	}
+}
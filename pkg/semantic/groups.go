@@ -0,0 +1,108 @@
+package semantic
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// CommandGroup is a set of commands listed together in help: the operations of
+// one OpenAPI tag, or those of one resource when they are untagged.
+type CommandGroup struct {
+	Name        string
+	Description string
+	Commands    []GroupedCommand
+}
+
+// GroupedCommand is a command of a group: a resource and one of its operations.
+type GroupedCommand struct {
+	Resource  string
+	Operation *Operation
+}
+
+// GroupCommandsByTag groups the operations of tree by their first OpenAPI tag,
+// falling back to their resource when they have none. Tag groups come first,
+// in the order the spec declares its tags and then by name, followed by the
+// resource groups by name. Commands are sorted by resource and verb.
+func GroupCommandsByTag(tree *CommandTree, spec *openapi3.T) []CommandGroup {
+	if tree == nil {
+		return nil
+	}
+
+	tagGroups := make(map[string]*CommandGroup)
+	resourceGroups := make(map[string]*CommandGroup)
+	var addResource func(name string, res *Resource)
+	addResource = func(name string, res *Resource) {
+		for _, op := range res.Operations {
+			groups, groupName, description := resourceGroups, name, res.Description
+			if specOp := specOperation(spec, op.Method, op.Path); specOp != nil && len(specOp.Tags) > 0 {
+				groups, groupName, description = tagGroups, specOp.Tags[0], tagDescription(spec, specOp.Tags[0])
+			}
+			group, ok := groups[groupName]
+			if !ok {
+				group = &CommandGroup{Name: groupName, Description: description}
+				groups[groupName] = group
+			}
+			group.Commands = append(group.Commands, GroupedCommand{Resource: name, Operation: op})
+		}
+		for subName, sub := range res.SubResources {
+			addResource(subName, sub)
+		}
+	}
+	for name, res := range tree.RootResources {
+		addResource(name, res)
+	}
+
+	// An untagged resource named like a tag joins the tag's group
+	for name, group := range resourceGroups {
+		if tagGroup, ok := tagGroups[name]; ok {
+			tagGroup.Commands = append(tagGroup.Commands, group.Commands...)
+			delete(resourceGroups, name)
+		}
+	}
+
+	var result []CommandGroup
+	appendGroup := func(group *CommandGroup) {
+		sort.Slice(group.Commands, func(i, j int) bool {
+			a, b := group.Commands[i], group.Commands[j]
+			if a.Resource != b.Resource {
+				return a.Resource < b.Resource
+			}
+			return a.Operation.Name < b.Operation.Name
+		})
+		result = append(result, *group)
+	}
+	if spec != nil {
+		for _, tag := range spec.Tags {
+			if tag == nil {
+				continue
+			}
+			if group, ok := tagGroups[tag.Name]; ok {
+				appendGroup(group)
+				delete(tagGroups, tag.Name)
+			}
+		}
+	}
+	for _, groups := range []map[string]*CommandGroup{tagGroups, resourceGroups} {
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			appendGroup(groups[name])
+		}
+	}
+	return result
+}
+
+// tagDescription returns the description the spec declares for a tag.
+func tagDescription(spec *openapi3.T, name string) string {
+	if spec == nil {
+		return ""
+	}
+	if tag := spec.Tags.Get(name); tag != nil {
+		return tag.Description
+	}
+	return ""
+}
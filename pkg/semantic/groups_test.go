@@ -0,0 +1,64 @@
+package semantic
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGroupCommandsByTag(t *testing.T) {
+	paths := openapi3.NewPaths()
+	paths.Set("/users", &openapi3.PathItem{
+		Get:  &openapi3.Operation{OperationID: "listUsers", Tags: []string{"accounts"}, Responses: openapi3.NewResponses()},
+		Post: &openapi3.Operation{OperationID: "createUser", Tags: []string{"accounts", "admin"}, Responses: openapi3.NewResponses()},
+	})
+	paths.Set("/teams", &openapi3.PathItem{
+		Get: &openapi3.Operation{OperationID: "listTeams", Tags: []string{"accounts"}, Responses: openapi3.NewResponses()},
+	})
+	paths.Set("/billing", &openapi3.PathItem{
+		Get: &openapi3.Operation{OperationID: "getBilling", Tags: []string{"billing"}, Responses: openapi3.NewResponses()},
+	})
+	paths.Set("/jobs", &openapi3.PathItem{
+		Post: &openapi3.Operation{OperationID: "startJob", Responses: openapi3.NewResponses()},
+	})
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Groups", Version: "1"},
+		Paths:   paths,
+		Tags: openapi3.Tags{
+			{Name: "billing", Description: "Invoices and payments"},
+			{Name: "accounts", Description: "Users and teams"},
+		},
+	}
+
+	groups := GroupCommandsByTag(NewMapper().BuildCommandTree(spec), spec)
+
+	type command struct{ resource, verb string }
+	want := []struct {
+		name, description string
+		commands          []command
+	}{
+		{"billing", "Invoices and payments", []command{{"billing", "get"}}},
+		{"accounts", "Users and teams", []command{{"teams", "list"}, {"users", "create"}, {"users", "list"}}},
+		{"jobs", "", []command{{"jobs", "create"}}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d groups, got %+v", len(want), groups)
+	}
+	for i, w := range want {
+		group := groups[i]
+		if group.Name != w.name || group.Description != w.description {
+			t.Errorf("group %d: expected %s (%q), got %s (%q)", i, w.name, w.description, group.Name, group.Description)
+		}
+		if len(group.Commands) != len(w.commands) {
+			t.Errorf("group %s: expected %d commands, got %d", w.name, len(w.commands), len(group.Commands))
+			continue
+		}
+		for j, c := range w.commands {
+			got := group.Commands[j]
+			if got.Resource != c.resource || got.Operation.Name != c.verb {
+				t.Errorf("group %s command %d: expected %s %s, got %s %s", w.name, j, c.resource, c.verb, got.Resource, got.Operation.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package semantic
+
+import (
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// HiddenExtension marks an operation that is left out of the CLI commands,
+// completion, and MCP tools, e.g. x-ob-hidden: true for an internal or
+// experimental endpoint. Hidden operations stay in the spec and are still
+// counted by spec.SpecInfo.
+const HiddenExtension = "x-ob-hidden"
+
+// IsHidden reports whether an operation is marked with x-ob-hidden: true.
+func IsHidden(op *openapi3.Operation) bool {
+	if op == nil {
+		return false
+	}
+	switch v := op.Extensions[HiddenExtension].(type) {
+	case bool:
+		return v
+	case string:
+		hidden, _ := strconv.ParseBool(v)
+		return hidden
+	default:
+		return false
+	}
+}
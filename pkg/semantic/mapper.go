@@ -2,7 +2,10 @@
 package semantic
 
 import (
+	"maps"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -13,12 +16,84 @@ type Mapper struct {
 	verbMapper *VerbMapper
 }
 
+// MapperOption configures a Mapper.
+type MapperOption func(*Mapper)
+
+// WithMethodVerbs overrides entries of the HTTP method to verb table, e.g.
+// {"PATCH": "update"}. Methods not in table keep their DefaultMethodVerbs verb.
+func WithMethodVerbs(table map[string]string) MapperOption {
+	return func(m *Mapper) {
+		for method, verb := range table {
+			m.verbMapper.MethodVerbs[strings.ToUpper(method)] = verb
+		}
+	}
+}
+
+// WithOperationIDVerbs derives verbs from operationId prefixes such as "list"
+// in "listCustomers" before path patterns, falling back to the method-based
+// verbs for other operationIds. See VerbMapper.PreferOperationID.
+func WithOperationIDVerbs() MapperOption {
+	return func(m *Mapper) {
+		m.verbMapper.PreferOperationID = true
+	}
+}
+
+// AppOptions returns the mapper options selected by an app's verb_mappings
+// and operation_id_verbs settings, or none when both are unset.
+func AppOptions(methodVerbs map[string]string, operationIDVerbs bool) []MapperOption {
+	var opts []MapperOption
+	if len(methodVerbs) > 0 {
+		opts = append(opts, WithMethodVerbs(methodVerbs))
+	}
+	if operationIDVerbs {
+		opts = append(opts, WithOperationIDVerbs())
+	}
+	return opts
+}
+
+// ForApp returns the mapper with an app's verb_mappings and operation_id_verbs
+// settings applied on top of its configuration, or the mapper itself when both
+// are unset. A nil mapper stands for NewMapper().
+func (m *Mapper) ForApp(methodVerbs map[string]string, operationIDVerbs bool) *Mapper {
+	opts := AppOptions(methodVerbs, operationIDVerbs)
+	if len(opts) == 0 {
+		return m
+	}
+	if m == nil {
+		return NewMapper(opts...)
+	}
+	return m.With(opts...)
+}
+
 // NewMapper creates a new semantic mapper.
-func NewMapper() *Mapper {
-	return &Mapper{
+func NewMapper(opts ...MapperOption) *Mapper {
+	m := &Mapper{
 		extractor:  NewResourceExtractor(),
 		verbMapper: NewVerbMapper(),
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// With returns a copy of the mapper with opts applied on top of its current
+// configuration, such as an app's verb mappings on top of the mapper an
+// embedder configured. The mapper itself is left unchanged.
+func (m *Mapper) With(opts ...MapperOption) *Mapper {
+	verbMapper := *m.verbMapper
+	verbMapper.CustomMappings = maps.Clone(m.verbMapper.CustomMappings)
+	verbMapper.MethodVerbs = maps.Clone(m.verbMapper.MethodVerbs)
+	verbMapper.PathPatternRules = slices.Clone(m.verbMapper.PathPatternRules)
+
+	extractor := *m.extractor
+	extractor.IgnoredPrefixes = slices.Clone(m.extractor.IgnoredPrefixes)
+
+	c := &Mapper{extractor: &extractor, verbMapper: &verbMapper}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // CommandTree represents the hierarchical structure of CLI commands.
@@ -32,7 +107,7 @@ type Resource struct {
 	Description  string
 	Operations   map[string]*Operation // verb -> operation
 	SubResources map[string]*Resource  // name -> resource
-	Parent       *Resource             // pointer to parent
+	Parent       *Resource             `json:"-"` // pointer to parent
 
 	// Internal helper for conflict resolution
 	verbSet *VerbSet
@@ -63,11 +138,13 @@ func getSortedPaths(spec *openapi3.T) []string {
 // getPathOperations returns a map of method to operation for the given path item.
 func getPathOperations(pathItem *openapi3.PathItem) map[string]*openapi3.Operation {
 	return map[string]*openapi3.Operation{
-		"GET":    pathItem.Get,
-		"POST":   pathItem.Post,
-		"PUT":    pathItem.Put,
-		"PATCH":  pathItem.Patch,
-		"DELETE": pathItem.Delete,
+		"GET":     pathItem.Get,
+		"POST":    pathItem.Post,
+		"PUT":     pathItem.Put,
+		"PATCH":   pathItem.Patch,
+		"DELETE":  pathItem.Delete,
+		"HEAD":    pathItem.Head,
+		"OPTIONS": pathItem.Options,
 	}
 }
 
@@ -93,7 +170,7 @@ func (m *Mapper) processOperation(tree *CommandTree, allResources map[string]*Re
 func (m *Mapper) BuildCommandTree(spec *openapi3.T) *CommandTree {
 	tree := &CommandTree{RootResources: make(map[string]*Resource)}
 	allResources := make(map[string]*Resource)
-	methods := []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	methods := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 
 	for _, path := range getSortedPaths(spec) {
 		pathItem := spec.Paths.Find(path)
@@ -103,7 +180,7 @@ func (m *Mapper) BuildCommandTree(spec *openapi3.T) *CommandTree {
 
 		operations := getPathOperations(pathItem)
 		for _, method := range methods {
-			if op := operations[method]; op != nil {
+			if op := operations[method]; op != nil && !IsHidden(op) {
 				m.processOperation(tree, allResources, path, method, op)
 			}
 		}
@@ -75,6 +75,182 @@ func TestMapVerb(t *testing.T) {
 	}
 }
 
+func TestMapVerbWithMethodVerbs(t *testing.T) {
+	m := NewMapper(WithMethodVerbs(map[string]string{"patch": "update", "LIST": "ls"}))
+
+	tests := []struct {
+		method      string
+		path        string
+		operationID string
+		expected    string
+	}{
+		{"PATCH", "/users/{id}", "", "update"},
+		{"PATCH", "/users/{id}", "patchUser", "update"},
+		{"GET", "/users", "", "ls"},
+		{"GET", "/users/{id}", "", "get"},
+		{"POST", "/users", "", "create"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.operationID, func(t *testing.T) {
+			op := &openapi3.Operation{OperationID: tt.operationID}
+			result := m.MapVerb(tt.method, tt.path, op)
+			if result != tt.expected {
+				t.Errorf("MapVerb(%q, %q) = %q, expected %q", tt.method, tt.path, result, tt.expected)
+			}
+		})
+	}
+
+	if verb := NewMapper().MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}); verb != "apply" {
+		t.Errorf("default mapper should be unaffected, got %q", verb)
+	}
+}
+
+func TestMapperWith(t *testing.T) {
+	base := NewMapper(WithOperationIDVerbs())
+	m := base.With(WithMethodVerbs(map[string]string{"PATCH": "update"}))
+
+	if verb := m.MapVerb("POST", "/customers/search", &openapi3.Operation{OperationID: "listCustomers"}); verb != "list" {
+		t.Errorf("expected the base mapper's operationId verbs to be kept, got %q", verb)
+	}
+	if verb := m.MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}); verb != "update" {
+		t.Errorf("expected the added method verb, got %q", verb)
+	}
+	if verb := base.MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}); verb != "apply" {
+		t.Errorf("base mapper should be unaffected, got %q", verb)
+	}
+}
+
+func TestAppOptions(t *testing.T) {
+	if opts := AppOptions(nil, false); len(opts) != 0 {
+		t.Errorf("expected no options for unset settings, got %d", len(opts))
+	}
+
+	m := NewMapper(AppOptions(map[string]string{"PATCH": "update"}, true)...)
+	if verb := m.MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}); verb != "update" {
+		t.Errorf("expected the mapped method verb, got %q", verb)
+	}
+	if verb := m.MapVerb("POST", "/customers/search", &openapi3.Operation{OperationID: "listCustomers"}); verb != "list" {
+		t.Errorf("expected the operationId verb, got %q", verb)
+	}
+}
+
+func TestMapperForApp(t *testing.T) {
+	base := NewMapper(WithOperationIDVerbs())
+	if m := base.ForApp(nil, false); m != base {
+		t.Error("expected the mapper itself for unset settings")
+	}
+
+	m := base.ForApp(map[string]string{"PATCH": "update"}, false)
+	if verb := m.MapVerb("PATCH", "/users/{id}", &openapi3.Operation{}); verb != "update" {
+		t.Errorf("expected the mapped method verb, got %q", verb)
+	}
+	if verb := m.MapVerb("POST", "/customers/search", &openapi3.Operation{OperationID: "listCustomers"}); verb != "list" {
+		t.Errorf("expected the base mapper's operationId verbs to be kept, got %q", verb)
+	}
+
+	var unset *Mapper
+	if verb := unset.ForApp(nil, true).MapVerb("POST", "/customers/search", &openapi3.Operation{OperationID: "listCustomers"}); verb != "list" {
+		t.Errorf("expected a nil mapper to stand for the default mapper, got %q", verb)
+	}
+}
+
+func TestMapVerbWithOperationIDVerbs(t *testing.T) {
+	m := NewMapper(WithOperationIDVerbs())
+
+	tests := []struct {
+		method      string
+		path        string
+		operationID string
+		expected    string
+	}{
+		{"GET", "/customers", "listCustomers", "list"},
+		{"POST", "/customers", "createCustomer", "create"},
+		{"POST", "/customers/search", "listCustomers", "list"},
+		{"GET", "/pets/{petId}", "GetPetById", "get"},
+		{"PUT", "/pets/{petId}", "update_pet", "update"},
+		{"DELETE", "/orders/{id}", "delete-order", "delete"},
+		{"GET", "/pets/findByStatus", "findPetsByStatus", "find"},
+		{"POST", "/jobs/{id}/cancel", "cancelJob", "cancel"},
+		{"POST", "/customers", "registerCustomer", "create"},
+		{"GET", "/customers", "", "list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path+" "+tt.operationID, func(t *testing.T) {
+			op := &openapi3.Operation{OperationID: tt.operationID}
+			result := m.MapVerb(tt.method, tt.path, op)
+			if result != tt.expected {
+				t.Errorf("MapVerb(%q, %q) with operationId %q = %q, expected %q", tt.method, tt.path, tt.operationID, result, tt.expected)
+			}
+		})
+	}
+
+	if verb := NewMapper().MapVerb("POST", "/customers/search", &openapi3.Operation{OperationID: "listCustomers"}); verb != "search" {
+		t.Errorf("default mapper should prefer the path pattern, got %q", verb)
+	}
+}
+
+func TestOperationIDVerbPrefix(t *testing.T) {
+	runStringMappingTests(t, "operationIDVerbPrefix", []stringMappingCase{
+		{"listCustomers", "list"},
+		{"GetPetById", "get"},
+		{"delete_order", "delete"},
+		{"find", "find"},
+		{"listenEvents", ""},
+		{"getaway", ""},
+		{"cancelJob", ""},
+		{"", ""},
+	}, func(id string) string {
+		verb, _ := operationIDVerbPrefix(&openapi3.Operation{OperationID: id})
+		return verb
+	})
+}
+
+func TestBuildCommandTreeWithOperationIDVerbs(t *testing.T) {
+	spec := &openapi3.T{
+		Paths: openapi3.NewPaths(),
+	}
+
+	spec.Paths.Set("/customers", &openapi3.PathItem{
+		Get:  &openapi3.Operation{OperationID: "listCustomers"},
+		Post: &openapi3.Operation{OperationID: "createCustomer"},
+	})
+	spec.Paths.Set("/customers/{id}", &openapi3.PathItem{
+		Get:   &openapi3.Operation{OperationID: "getCustomer"},
+		Patch: &openapi3.Operation{OperationID: "updateCustomer"},
+	})
+
+	tree := NewMapper(WithOperationIDVerbs()).BuildCommandTree(spec)
+
+	customers, ok := tree.RootResources["customers"]
+	if !ok {
+		t.Fatal("expected 'customers' resource")
+	}
+	for verb, operationID := range map[string]string{"list": "listCustomers", "create": "createCustomer", "get": "getCustomer", "update": "updateCustomer"} {
+		op, ok := customers.Operations[verb]
+		if !ok {
+			t.Errorf("expected op '%s' on customers", verb)
+			continue
+		}
+		if op.OperationID != operationID {
+			t.Errorf("expected '%s' to be %s, got %s", verb, operationID, op.OperationID)
+		}
+	}
+}
+
+func TestValidateMethodVerbs(t *testing.T) {
+	if err := ValidateMethodVerbs(map[string]string{"PATCH": "update", "list": "ls"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateMethodVerbs(map[string]string{"TRACE": "trace"}); err == nil {
+		t.Error("expected error for unknown method")
+	}
+	if err := ValidateMethodVerbs(map[string]string{"PATCH": ""}); err == nil {
+		t.Error("expected error for empty verb")
+	}
+}
+
 func TestMapVerbWithOverride(t *testing.T) {
 	m := NewMapper()
 
@@ -160,6 +336,111 @@ func TestBuildCommandTree(t *testing.T) {
 	}
 }
 
+func TestBuildCommandTreeHeadAndOptions(t *testing.T) {
+	spec := &openapi3.T{
+		Paths: openapi3.NewPaths(),
+	}
+
+	spec.Paths.Set("/files/{id}", &openapi3.PathItem{
+		Get:     &openapi3.Operation{OperationID: "getFile"},
+		Head:    &openapi3.Operation{OperationID: "checkFile"},
+		Options: &openapi3.Operation{OperationID: "fileOptions"},
+	})
+
+	tree := NewMapper().BuildCommandTree(spec)
+
+	files, ok := tree.RootResources["files"]
+	if !ok {
+		t.Fatal("expected 'files' resource")
+	}
+	for verb, method := range map[string]string{"get": "GET", "check": "HEAD", "options": "OPTIONS"} {
+		op, ok := files.Operations[verb]
+		if !ok {
+			t.Errorf("expected op '%s' on files", verb)
+			continue
+		}
+		if op.Method != method {
+			t.Errorf("expected '%s' to use %s, got %s", verb, method, op.Method)
+		}
+	}
+}
+
+func TestBuildCommandTreeSkipsHiddenOperations(t *testing.T) {
+	spec := &openapi3.T{
+		Paths: openapi3.NewPaths(),
+	}
+
+	spec.Paths.Set("/users", &openapi3.PathItem{
+		Get:  &openapi3.Operation{OperationID: "listUsers"},
+		Post: &openapi3.Operation{OperationID: "createUser", Extensions: map[string]any{HiddenExtension: true}},
+	})
+	spec.Paths.Set("/internal/jobs", &openapi3.PathItem{
+		Get: &openapi3.Operation{OperationID: "listJobs", Extensions: map[string]any{HiddenExtension: "true"}},
+	})
+
+	tree := NewMapper().BuildCommandTree(spec)
+
+	users, ok := tree.RootResources["users"]
+	if !ok {
+		t.Fatal("expected 'users' resource")
+	}
+	if _, ok := users.Operations["list"]; !ok {
+		t.Error("expected 'list' on users")
+	}
+	if _, ok := users.Operations["create"]; ok {
+		t.Error("expected hidden 'create' to be left out")
+	}
+	if _, ok := tree.RootResources["jobs"]; ok {
+		t.Error("expected resource with only hidden operations to be left out")
+	}
+}
+
+func TestIsHidden(t *testing.T) {
+	tests := []struct {
+		value any
+		want  bool
+	}{
+		{true, true},
+		{false, false},
+		{"true", true},
+		{"no", false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		op := &openapi3.Operation{Extensions: map[string]any{HiddenExtension: tt.value}}
+		if got := IsHidden(op); got != tt.want {
+			t.Errorf("IsHidden(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	if IsHidden(nil) {
+		t.Error("expected nil operation not to be hidden")
+	}
+}
+
+func TestConfirmMessage(t *testing.T) {
+	tests := []struct {
+		value       any
+		wantMessage string
+		wantConfirm bool
+	}{
+		{true, "", true},
+		{false, "", false},
+		{"true", "", true},
+		{"This deletes every pet.", "This deletes every pet.", true},
+		{"", "", false},
+		{nil, "", false},
+	}
+
+	for _, tt := range tests {
+		op := &openapi3.Operation{Extensions: map[string]any{ConfirmExtension: tt.value}}
+		message, confirm := ConfirmMessage(op)
+		if message != tt.wantMessage || confirm != tt.wantConfirm {
+			t.Errorf("ConfirmMessage(%v) = %q, %v, want %q, %v", tt.value, message, confirm, tt.wantMessage, tt.wantConfirm)
+		}
+	}
+}
+
 func TestBuildCommandTreeConflictResolution(t *testing.T) {
 	spec := &openapi3.T{
 		Paths: openapi3.NewPaths(),
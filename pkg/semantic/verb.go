@@ -3,10 +3,13 @@
 package semantic
 
 import (
+	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -18,6 +21,54 @@ type VerbMapper struct {
 
 	// PathPatternRules define verb mappings based on path patterns.
 	PathPatternRules []PathPatternRule
+
+	// MethodVerbs maps HTTP methods to the verbs used when nothing more specific
+	// applies. See DefaultMethodVerbs for the keys.
+	MethodVerbs map[string]string
+
+	// PreferOperationID gives operationIds that start with one of
+	// OperationIDVerbPrefixes, as a whole word, precedence over path pattern
+	// rules and custom mappings. "listCustomers" then maps to "list" even on
+	// a path such as /customers/search.
+	PreferOperationID bool
+}
+
+// OperationIDVerbPrefixes are the operationId prefixes used as verbs by
+// VerbMapper.PreferOperationID.
+var OperationIDVerbPrefixes = []string{"list", "get", "create", "update", "delete", "find"}
+
+// ListMethodKey is the MethodVerbs key for a GET on a collection path, as opposed
+// to a GET on a single item (a path ending in a parameter), which uses "GET".
+const ListMethodKey = "LIST"
+
+// DefaultMethodVerbs returns the default HTTP method to verb table.
+func DefaultMethodVerbs() map[string]string {
+	return map[string]string{
+		ListMethodKey: "list",
+		"GET":         "get",
+		"POST":        "create",
+		"PUT":         "update",
+		"PATCH":       "apply",
+		"DELETE":      "delete",
+		"HEAD":        "check",
+		"OPTIONS":     "options",
+	}
+}
+
+// ValidateMethodVerbs checks a custom method to verb table. Keys must be HTTP
+// methods or ListMethodKey, and verbs must be non-empty command names.
+func ValidateMethodVerbs(table map[string]string) error {
+	defaults := DefaultMethodVerbs()
+	for _, method := range slices.Sorted(maps.Keys(table)) {
+		if _, ok := defaults[strings.ToUpper(method)]; !ok {
+			return fmt.Errorf("unknown method '%s' in verb mappings", method)
+		}
+		verb := table[method]
+		if verb == "" || strings.ContainsAny(verb, " \t/") {
+			return fmt.Errorf("invalid verb '%s' for method '%s'", verb, method)
+		}
+	}
+	return nil
 }
 
 // PathPatternRule defines a verb based on path pattern matching.
@@ -32,6 +83,7 @@ func NewVerbMapper() *VerbMapper {
 	return &VerbMapper{
 		CustomMappings:   make(map[string]string),
 		PathPatternRules: defaultPathPatternRules(),
+		MethodVerbs:      DefaultMethodVerbs(),
 	}
 }
 
@@ -189,6 +241,15 @@ func (m *VerbMapper) MapVerb(method, path string, operation *openapi3.Operation)
 		return result
 	}
 
+	// With PreferOperationID, a verb prefix of the operationId comes next
+	if m.PreferOperationID {
+		if verb, ok := operationIDVerbPrefix(operation); ok {
+			result.Verb = verb
+			result.Source = VerbSourceOperationID
+			return result
+		}
+	}
+
 	// Priority 2: Check path pattern rules
 	if verb, ok := m.checkPathPatternRules(method, path); ok {
 		result.Verb = verb
@@ -223,33 +284,32 @@ func (m *VerbMapper) defaultMethodMapping(method, path string) string {
 	// Check if path ends with a parameter (single item operation)
 	hasPathParam := regexp.MustCompile(`/\{[^}]+\}$`).MatchString(path)
 
-	switch strings.ToUpper(method) {
-	case "GET":
-		if hasPathParam {
-			return "get"
-		}
-		return "list"
-
-	case "POST":
-		return "create"
-
-	case "PUT":
-		return "update"
-
-	case "PATCH":
-		return "apply"
-
-	case "DELETE":
-		return "delete"
-
-	case "HEAD":
-		return "check"
+	key := strings.ToUpper(method)
+	if key == "GET" && !hasPathParam {
+		key = ListMethodKey
+	}
+	return m.methodVerb(key)
+}
 
-	case "OPTIONS":
-		return "options"
+// methodVerb looks up the verb for a MethodVerbs key, falling back to the
+// defaults and then to the lower-cased method.
+func (m *VerbMapper) methodVerb(key string) string {
+	if verb, ok := m.MethodVerbs[key]; ok && verb != "" {
+		return verb
+	}
+	if verb, ok := DefaultMethodVerbs()[key]; ok {
+		return verb
+	}
+	return strings.ToLower(key)
+}
 
+// isMethodPrefix reports whether an operationId prefix is an HTTP method name.
+func isMethodPrefix(prefix string) bool {
+	switch prefix {
+	case "post", "put", "patch":
+		return true
 	default:
-		return strings.ToLower(method)
+		return false
 	}
 }
 
@@ -290,6 +350,10 @@ func (m *VerbMapper) inferVerbFromOperationID(operationID string) string {
 	lower := strings.ToLower(operationID)
 	for prefix, verb := range verbMappings {
 		if strings.HasPrefix(lower, prefix) {
+			// Method-named prefixes such as "patchPet" follow the method table.
+			if isMethodPrefix(prefix) {
+				return m.methodVerb(strings.ToUpper(prefix))
+			}
 			return verb
 		}
 	}
@@ -297,6 +361,26 @@ func (m *VerbMapper) inferVerbFromOperationID(operationID string) string {
 	return ""
 }
 
+// operationIDVerbPrefix returns the OperationIDVerbPrefixes entry an
+// operationId starts with as a whole word, as in "listCustomers",
+// "GetPetById" or "delete_order", but not "listenEvents".
+func operationIDVerbPrefix(operation *openapi3.Operation) (string, bool) {
+	if operation == nil {
+		return "", false
+	}
+	id := operation.OperationID
+	for _, prefix := range OperationIDVerbPrefixes {
+		if len(id) < len(prefix) || !strings.EqualFold(id[:len(prefix)], prefix) {
+			continue
+		}
+		rest := id[len(prefix):]
+		if rest == "" || !unicode.IsLower(rune(rest[0])) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
 // VerbConflictResolver handles conflicts when multiple operations map to the same verb.
 type VerbConflictResolver struct {
 	// Strategy determines how conflicts are resolved.
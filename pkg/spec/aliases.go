@@ -0,0 +1,110 @@
+package spec
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// AliasExtension lists alternative CLI flag names for a parameter. Its value is a
+// single name or a list of names, e.g. x-ob-alias: issue.
+const AliasExtension = "x-ob-alias"
+
+// ParameterAliases returns the alias names declared for a parameter.
+func ParameterAliases(param *openapi3.Parameter) []string {
+	if param == nil {
+		return nil
+	}
+
+	var aliases []string
+	switch v := param.Extensions[AliasExtension].(type) {
+	case string:
+		aliases = append(aliases, v)
+	case []any:
+		for _, item := range v {
+			if name, ok := item.(string); ok {
+				aliases = append(aliases, name)
+			}
+		}
+	}
+	return slices.DeleteFunc(aliases, func(alias string) bool {
+		return alias == "" || alias == param.Name
+	})
+}
+
+// ResolveParameterAlias returns the canonical parameter name for name, which may
+// be an alias. Names that are not aliases are returned unchanged.
+func ResolveParameterAlias(params openapi3.Parameters, name string) string {
+	for _, paramRef := range params {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		if slices.Contains(ParameterAliases(paramRef.Value), name) {
+			return paramRef.Value.Name
+		}
+	}
+	return name
+}
+
+// OperationParameters returns the parameters of an operation together with
+// those declared on its path item, path item parameters first.
+func OperationParameters(pathItem *openapi3.PathItem, op *openapi3.Operation) openapi3.Parameters {
+	var params openapi3.Parameters
+	if pathItem != nil {
+		params = slices.Clone(pathItem.Parameters)
+	}
+	if op != nil {
+		params = append(params, op.Parameters...)
+	}
+	return params
+}
+
+// ValidateParameterAliases checks that, within every operation, no alias is used by
+// more than one parameter or shadows another parameter's name.
+func ValidateParameterAliases(doc *openapi3.T) error {
+	if doc == nil || doc.Paths == nil {
+		return nil
+	}
+
+	paths := doc.Paths.Map()
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		pathItem := paths[path]
+		operations := pathItem.Operations()
+		for _, method := range slices.Sorted(maps.Keys(operations)) {
+			if err := validateOperationAliases(OperationParameters(pathItem, operations[method])); err != nil {
+				return fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateOperationAliases checks the aliases of a single operation's parameters.
+func validateOperationAliases(params openapi3.Parameters) error {
+	names := make(map[string]bool)
+	for _, paramRef := range params {
+		if paramRef != nil && paramRef.Value != nil {
+			names[paramRef.Value.Name] = true
+		}
+	}
+
+	owners := make(map[string]string)
+	for _, paramRef := range params {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		name := paramRef.Value.Name
+		for _, alias := range ParameterAliases(paramRef.Value) {
+			if names[alias] {
+				return fmt.Errorf("alias '%s' of parameter '%s' conflicts with parameter '%s'", alias, name, alias)
+			}
+			if owner, ok := owners[alias]; ok && owner != name {
+				return fmt.Errorf("alias '%s' is used by both '%s' and '%s'", alias, owner, name)
+			}
+			owners[alias] = name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package spec
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParameterAliases(t *testing.T) {
+	doc, err := NewParser().LoadSpecFromReader(strings.NewReader(`
+openapi: "3.0.0"
+info:
+  title: Issues API
+  version: "1.0.0"
+paths:
+  /issues/{issue_number}:
+    get:
+      operationId: getIssue
+      parameters:
+        - name: issue_number
+          in: path
+          required: true
+          x-ob-alias: issue
+          schema:
+            type: integer
+        - name: per_page
+          in: query
+          x-ob-alias: [limit, per-page]
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	params := doc.Paths.Find("/issues/{issue_number}").Get.Parameters
+	if got := ParameterAliases(params[0].Value); !reflect.DeepEqual(got, []string{"issue"}) {
+		t.Errorf("expected [issue], got %v", got)
+	}
+	if got := ParameterAliases(params[1].Value); !reflect.DeepEqual(got, []string{"limit", "per-page"}) {
+		t.Errorf("expected [limit per-page], got %v", got)
+	}
+
+	if got := ResolveParameterAlias(params, "limit"); got != "per_page" {
+		t.Errorf("expected limit to resolve to per_page, got %s", got)
+	}
+	if got := ResolveParameterAlias(params, "issue_number"); got != "issue_number" {
+		t.Errorf("expected canonical name to be unchanged, got %s", got)
+	}
+
+	if err := ValidateParameterAliases(doc); err != nil {
+		t.Errorf("expected aliases to be valid, got: %v", err)
+	}
+}
+
+func TestValidateParameterAliasesConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases string
+		want    string
+	}{
+		{"shadows parameter", "x-ob-alias: state", "conflicts with parameter 'state'"},
+		{"shared alias", "x-ob-alias: s", "used by both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewParser().LoadSpecFromReader(strings.NewReader(`
+openapi: "3.0.0"
+info:
+  title: Issues API
+  version: "1.0.0"
+paths:
+  /issues:
+    get:
+      parameters:
+        - name: sort
+          in: query
+          ` + tt.aliases + `
+          schema:
+            type: string
+        - name: state
+          in: query
+          x-ob-alias: s
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`))
+			if err != nil {
+				t.Fatalf("failed to load spec: %v", err)
+			}
+
+			err = ValidateParameterAliases(doc)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
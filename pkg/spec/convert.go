@@ -0,0 +1,128 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ConversionResult holds a converted specification and notes about lossy conversions.
+type ConversionResult struct {
+	Spec     *openapi3.T
+	Warnings []string
+}
+
+// ConvertSwagger2 converts a Swagger 2.0 document (JSON or YAML) to OpenAPI 3.0.
+// Constructs that have no exact 3.0 equivalent are converted as closely as
+// possible and reported in Warnings. Validation problems in the converted
+// document are also reported as warnings rather than errors.
+func ConvertSwagger2(ctx context.Context, data []byte) (*ConversionResult, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if version := detectVersion(data); version != Version20 {
+		return nil, fmt.Errorf("not a Swagger 2.0 document (detected %s)", version)
+	}
+
+	swagger, err := unmarshalSwagger(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := openapi2conv.ToV3(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Swagger 2.0 to OpenAPI 3.0: %w", err)
+	}
+
+	result := &ConversionResult{
+		Spec:     doc,
+		Warnings: collectConversionWarnings(swagger),
+	}
+
+	if err := doc.Validate(ctx); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("converted document does not validate: %v", err))
+	}
+
+	return result, nil
+}
+
+// unmarshalSwagger decodes a Swagger 2.0 document from JSON or YAML.
+func unmarshalSwagger(data []byte) (*openapi2.T, error) {
+	// Convert to JSON first: some nested types only implement UnmarshalJSON.
+	jsonData, err := NewContentDetector().ToJSONWithFallback(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger 2.0 spec: %w", err)
+	}
+
+	var swagger openapi2.T
+	if err := json.Unmarshal(jsonData, &swagger); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Swagger 2.0 spec: %w", err)
+	}
+
+	return &swagger, nil
+}
+
+// collectConversionWarnings reports Swagger 2.0 constructs that do not convert losslessly.
+func collectConversionWarnings(swagger *openapi2.T) []string {
+	var warnings []string
+
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range sortedMethods(pathItem.Operations()) {
+			op := pathItem.Operations()[method]
+			location := fmt.Sprintf("%s %s", method, path)
+			warnings = append(warnings, parameterConversionWarnings(location, op.Parameters)...)
+			if len(op.Produces) > 1 {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s: produces %d media types; response schemas are duplicated for each", location, len(op.Produces)))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// parameterConversionWarnings reports parameter settings that OpenAPI 3.0 cannot express.
+func parameterConversionWarnings(location string, params openapi2.Parameters) []string {
+	var warnings []string
+	for _, param := range params {
+		if param == nil {
+			continue
+		}
+		if param.CollectionFormat == "tsv" {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: parameter '%s' uses collectionFormat tsv, which has no OpenAPI 3.0 equivalent", location, param.Name))
+		}
+		if param.In == "formData" && param.Type != nil && param.Type.Is("file") {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: file parameter '%s' converted to a binary string property", location, param.Name))
+		}
+	}
+	return warnings
+}
+
+// sortedMethods returns operation methods in sorted order.
+func sortedMethods(ops map[string]*openapi2.Operation) []string {
+	methods := make([]string, 0, len(ops))
+	for method := range ops {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
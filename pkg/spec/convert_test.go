@@ -0,0 +1,97 @@
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertSwagger2_Testdata(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "internal", "integration", "testdata", "swagger20.json"))
+	if err != nil {
+		t.Fatalf("failed to read testdata: %v", err)
+	}
+
+	result, err := ConvertSwagger2(context.Background(), data)
+	if err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
+
+	if !strings.HasPrefix(result.Spec.OpenAPI, "3.") {
+		t.Errorf("expected OpenAPI 3.x document, got version %q", result.Spec.OpenAPI)
+	}
+
+	out, err := json.Marshal(result.Spec)
+	if err != nil {
+		t.Fatalf("failed to marshal converted spec: %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("failed to decode converted spec: %v", err)
+	}
+
+	if _, ok := doc["swagger"]; ok {
+		t.Error("converted document should not contain a swagger field")
+	}
+
+	components, ok := doc["components"].(map[string]any)
+	if !ok {
+		t.Fatal("expected components in converted document")
+	}
+	schemas, ok := components["schemas"].(map[string]any)
+	if !ok {
+		t.Fatal("expected components/schemas in converted document")
+	}
+	for _, name := range []string{"Pet", "NewPet", "Category", "Error"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("expected schema %q in components/schemas", name)
+		}
+	}
+}
+
+func TestConvertSwagger2_RejectsOpenAPI3(t *testing.T) {
+	_, err := ConvertSwagger2(context.Background(), []byte(`{"openapi": "3.0.0", "info": {"title": "x", "version": "1"}, "paths": {}}`))
+	if err == nil {
+		t.Fatal("expected error for a non-Swagger 2.0 document")
+	}
+}
+
+func TestConvertSwagger2_LossyWarnings(t *testing.T) {
+	data := []byte(`
+swagger: "2.0"
+info:
+  title: Lossy
+  version: "1.0"
+paths:
+  /items:
+    get:
+      produces: [application/json, application/xml]
+      parameters:
+        - name: ids
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: tsv
+      responses:
+        "200":
+          description: ok
+`)
+
+	result, err := ConvertSwagger2(context.Background(), data)
+	if err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
+
+	joined := strings.Join(result.Warnings, "\n")
+	if !strings.Contains(joined, "collectionFormat tsv") {
+		t.Errorf("expected tsv warning, got: %v", result.Warnings)
+	}
+	if !strings.Contains(joined, "produces 2 media types") {
+		t.Errorf("expected produces warning, got: %v", result.Warnings)
+	}
+}
@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedEncoding is returned when spec data is not in an encoding that can be converted to UTF-8.
+var ErrUnsupportedEncoding = errors.New("unsupported spec encoding")
+
+// Byte order marks recognized at the start of spec data.
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF32BE = []byte{0x00, 0x00, 0xFE, 0xFF}
+	bomUTF32LE = []byte{0xFF, 0xFE, 0x00, 0x00}
+)
+
+// normalizeEncoding converts spec data to UTF-8 without a byte order mark.
+// UTF-8 and UTF-16 data are accepted with or without a BOM; UTF-16 without a
+// BOM is recognized by the zero byte next to the leading ASCII character.
+// Other encodings, such as UTF-32 or Latin-1, are rejected.
+func normalizeEncoding(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF32BE), bytes.HasPrefix(data, bomUTF32LE):
+		return nil, fmt.Errorf("%w: UTF-32 is not supported, save the spec as UTF-8", ErrUnsupportedEncoding)
+	case bytes.HasPrefix(data, bomUTF8):
+		data = data[len(bomUTF8):]
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return decodeUTF16(data[len(bomUTF16BE):], binary.BigEndian)
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return decodeUTF16(data[len(bomUTF16LE):], binary.LittleEndian)
+	case len(data) >= 2 && data[0] == 0 && data[1] != 0:
+		return decodeUTF16(data, binary.BigEndian)
+	case len(data) >= 2 && data[0] != 0 && data[1] == 0:
+		return decodeUTF16(data, binary.LittleEndian)
+	}
+
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: spec is not valid UTF-8 or UTF-16", ErrUnsupportedEncoding)
+	}
+	return data, nil
+}
+
+// decodeUTF16 converts UTF-16 data in the given byte order to UTF-8.
+func decodeUTF16(data []byte, order binary.ByteOrder) ([]byte, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("%w: truncated UTF-16 data", ErrUnsupportedEncoding)
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+
+	return []byte(string(utf16.Decode(units))), nil
+}
@@ -0,0 +1,79 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+const encodingTestSpec = `{"openapi":"3.0.0","info":{"title":"Caf` + "é" + ` API","version":"1.0.0"},"paths":{}}`
+
+// encodeUTF16 encodes s as UTF-16 in the given byte order, optionally with a BOM.
+func encodeUTF16(s string, order binary.ByteOrder, withBOM bool) []byte {
+	units := utf16.Encode([]rune(s))
+	if withBOM {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	data := make([]byte, 2*len(units))
+	for i, unit := range units {
+		order.PutUint16(data[2*i:], unit)
+	}
+	return data
+}
+
+func TestLoadSpecWithEncodings(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"UTF-8", []byte(encodingTestSpec)},
+		{"UTF-8 with BOM", append([]byte{0xEF, 0xBB, 0xBF}, encodingTestSpec...)},
+		{"UTF-16LE with BOM", encodeUTF16(encodingTestSpec, binary.LittleEndian, true)},
+		{"UTF-16BE with BOM", encodeUTF16(encodingTestSpec, binary.BigEndian, true)},
+		{"UTF-16LE without BOM", encodeUTF16(encodingTestSpec, binary.LittleEndian, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec.json")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatalf("failed to write spec: %v", err)
+			}
+
+			doc, err := NewParser().LoadSpec(path)
+			if err != nil {
+				t.Fatalf("LoadSpec failed: %v", err)
+			}
+			if doc.Info.Title != "Café API" {
+				t.Errorf("expected title to be decoded, got %q", doc.Info.Title)
+			}
+			if version := detectVersionFromSpec(doc); version != Version30 {
+				t.Errorf("expected version 3.0, got %s", version)
+			}
+		})
+	}
+}
+
+func TestLoadSpecWithUnsupportedEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"UTF-32LE", append([]byte{0xFF, 0xFE, 0x00, 0x00}, 0x7B, 0x00, 0x00, 0x00)},
+		{"Latin-1", []byte("{\"openapi\":\"3.0.0\",\"info\":{\"title\":\"Caf\xe9\"}}")},
+		{"truncated UTF-16", append([]byte{0xFF, 0xFE}, 0x7B, 0x00, 0x7D)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().LoadSpecFromReader(bytes.NewReader(tt.data))
+			if !errors.Is(err, ErrUnsupportedEncoding) {
+				t.Errorf("expected ErrUnsupportedEncoding, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package spec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// gitSourcePrefix marks a spec source that lives in a git repository, as in
+// git+https://github.com/org/repo.git//path/to/openapi.yaml@v1.2.0.
+const gitSourcePrefix = "git+"
+
+// GitSource is a spec file in a git repository.
+type GitSource struct {
+	// Repo is the URL the repository is cloned from.
+	Repo string
+
+	// Path is the spec file's path inside the repository.
+	Path string
+
+	// Ref is the branch or tag to check out; empty means the default branch.
+	Ref string
+}
+
+// IsGitSource reports whether source refers to a spec in a git repository.
+func IsGitSource(source string) bool {
+	return strings.HasPrefix(source, gitSourcePrefix) && strings.Contains(source, "://")
+}
+
+// ParseGitSource parses a source of the form git+<repo-url>//<path>[@<ref>].
+// The "//" after the repository URL separates it from the spec file's path,
+// and an optional "@" after the path pins a branch or tag.
+func ParseGitSource(source string) (*GitSource, error) {
+	if !IsGitSource(source) {
+		return nil, fmt.Errorf("invalid git spec source '%s': expected git+<repo-url>//<path>[@<ref>]", source)
+	}
+
+	repo := strings.TrimPrefix(source, gitSourcePrefix)
+	schemeEnd := strings.Index(repo, "://") + len("://")
+	sep := strings.Index(repo[schemeEnd:], "//")
+	if sep < 0 {
+		return nil, fmt.Errorf("invalid git spec source '%s': missing '//' before the spec path", source)
+	}
+	repo, specPath := repo[:schemeEnd+sep], repo[schemeEnd+sep+len("//"):]
+
+	var ref string
+	if at := strings.LastIndex(specPath, "@"); at >= 0 {
+		specPath, ref = specPath[:at], specPath[at+1:]
+		if ref == "" {
+			return nil, fmt.Errorf("invalid git spec source '%s': empty ref after '@'", source)
+		}
+	}
+
+	if specPath == "" || !filepath.IsLocal(filepath.FromSlash(specPath)) {
+		return nil, fmt.Errorf("invalid git spec source '%s': spec path must be a relative path inside the repository", source)
+	}
+	if _, err := url.Parse(repo); err != nil {
+		return nil, fmt.Errorf("invalid git spec source '%s': %w", source, err)
+	}
+
+	return &GitSource{Repo: repo, Path: specPath, Ref: ref}, nil
+}
+
+// CacheKey identifies the spec by repository, ref and path, for caching its content.
+func (s *GitSource) CacheKey() string {
+	return s.Repo + "@" + s.Ref + "//" + s.Path
+}
+
+// FetchGitSpec returns the raw content of the spec file a git source refers to.
+// The repository is cloned shallowly into a temporary directory, which is
+// removed once the file is read.
+func FetchGitSpec(ctx context.Context, source string, opts *SpecFetchOptions) ([]byte, error) {
+	src, err := ParseGitSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, cleanup, err := cloneGitSource(ctx, src, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(src.Path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file '%s' from '%s': %w", src.Path, src.Repo, err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("spec file '%s' in '%s' is empty", src.Path, src.Repo)
+	}
+	return data, nil
+}
+
+// loadFromGit loads a specification from a git repository. The spec is loaded
+// from the clone so that relative $refs to other files in the repository resolve.
+func (p *Parser) loadFromGit(ctx context.Context, source string, perSpecOpts *SpecFetchOptions) (*openapi3.T, error) {
+	src, err := ParseGitSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, cleanup, err := cloneGitSource(ctx, src, p.fetchOptions.Merge(perSpecOpts))
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	specPath := filepath.Join(dir, filepath.FromSlash(src.Path))
+	data, err := p.readSpecFile(src.Path, specPath)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseSpecWithBaseURL(ctx, data, &url.URL{Path: filepath.ToSlash(specPath)})
+}
+
+// cloneGitSource shallowly clones the single branch or tag of src into a
+// temporary directory. It returns the directory and a function removing it.
+func cloneGitSource(ctx context.Context, src *GitSource, opts *SpecFetchOptions) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "ob-git-spec-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create clone directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	args := []string{"clone", "--quiet", "--depth", "1", "--single-branch"}
+	if src.Ref != "" {
+		args = append(args, "--branch", src.Ref)
+	}
+	args = append(args, "--", src.Repo, dir)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), gitFetchEnv(src.Repo, opts)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", nil, fmt.Errorf("failed to clone '%s': %w: %s", src.Repo, err, msg)
+		}
+		return "", nil, fmt.Errorf("failed to clone '%s': %w", src.Repo, err)
+	}
+
+	return dir, cleanup, nil
+}
+
+// gitFetchEnv returns the environment making git send the fetch options'
+// headers and Authorization credential, for HTTPS repositories only. They
+// are passed as git config through the environment so that tokens do not
+// show up in the process list. Git is never allowed to prompt.
+func gitFetchEnv(repo string, opts *SpecFetchOptions) []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if opts == nil || !strings.HasPrefix(strings.ToLower(repo), "https://") {
+		return env
+	}
+
+	headers := make(map[string]string, len(opts.Headers)+1)
+	maps.Copy(headers, opts.Headers)
+	switch opts.AuthType {
+	case "bearer":
+		if opts.AuthToken != "" {
+			headers["Authorization"] = "Bearer " + opts.AuthToken
+		}
+	case "basic":
+		if token := opts.BasicAuthToken(); token != "" {
+			headers["Authorization"] = "Basic " + token
+		}
+	}
+	if len(headers) == 0 {
+		return env
+	}
+
+	keys := slices.Sorted(maps.Keys(headers))
+	env = append(env, "GIT_CONFIG_COUNT="+strconv.Itoa(len(keys)))
+	for i, key := range keys {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=http.extraHeader", i),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s: %s", i, key, headers[key]))
+	}
+	return env
+}
@@ -0,0 +1,192 @@
+package spec
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// gitSpecTemplate is a spec whose title is filled in per commit.
+const gitSpecTemplate = `openapi: "3.0.0"
+info:
+  title: %s
+  version: "1.0.0"
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          $ref: "./responses.yaml#/Users"
+`
+
+// createGitSpecRepo creates a repository with the spec at api/openapi.yaml.
+// The tag v1 holds a spec titled "Tagged API" and the default branch one
+// titled "Latest API". It returns the repository's file:// URL.
+func createGitSpecRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, "api", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "--quiet", "--initial-branch=main")
+	write("responses.yaml", "Users:\n  description: Success\n")
+	write("openapi.yaml", strings.Replace(gitSpecTemplate, "%s", "Tagged API", 1))
+	run("add", ".")
+	run("commit", "--quiet", "-m", "tagged")
+	run("tag", "v1")
+	write("openapi.yaml", strings.Replace(gitSpecTemplate, "%s", "Latest API", 1))
+	run("commit", "--quiet", "-am", "latest")
+
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    GitSource
+		wantErr bool
+	}{
+		{
+			source: "git+https://github.com/org/repo.git//path/to/openapi.yaml@v1.2.0",
+			want:   GitSource{Repo: "https://github.com/org/repo.git", Path: "path/to/openapi.yaml", Ref: "v1.2.0"},
+		},
+		{
+			source: "git+https://github.com/org/repo.git//openapi.yaml",
+			want:   GitSource{Repo: "https://github.com/org/repo.git", Path: "openapi.yaml"},
+		},
+		{
+			source: "git+ssh://git@github.com/org/repo.git//api/spec.json@main",
+			want:   GitSource{Repo: "ssh://git@github.com/org/repo.git", Path: "api/spec.json", Ref: "main"},
+		},
+		{source: "git+https://github.com/org/repo.git", wantErr: true},
+		{source: "git+https://github.com/org/repo.git//", wantErr: true},
+		{source: "git+https://github.com/org/repo.git//openapi.yaml@", wantErr: true},
+		{source: "git+https://github.com/org/repo.git//../secret.yaml", wantErr: true},
+		{source: "https://github.com/org/repo.git//openapi.yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := ParseGitSource(tt.source)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestGitSourceCacheKey(t *testing.T) {
+	a, _ := ParseGitSource("git+https://example.com/repo.git//openapi.yaml@v1")
+	b, _ := ParseGitSource("git+https://example.com/repo.git//openapi.yaml@v2")
+	c, _ := ParseGitSource("git+https://example.com/repo.git//other.yaml@v1")
+
+	if a.CacheKey() == b.CacheKey() || a.CacheKey() == c.CacheKey() {
+		t.Errorf("expected distinct cache keys, got %q, %q and %q", a.CacheKey(), b.CacheKey(), c.CacheKey())
+	}
+}
+
+func TestGitFetchEnv(t *testing.T) {
+	opts := &SpecFetchOptions{AuthType: "bearer", AuthToken: "secret"}
+
+	env := gitFetchEnv("https://example.com/repo.git", opts)
+	if !slices.Contains(env, "GIT_CONFIG_VALUE_0=Authorization: Bearer secret") {
+		t.Errorf("expected the bearer token as an extra header, got %v", env)
+	}
+	if !slices.Contains(env, "GIT_TERMINAL_PROMPT=0") {
+		t.Errorf("expected prompting to be disabled, got %v", env)
+	}
+
+	env = gitFetchEnv("ssh://git@example.com/repo.git", opts)
+	if slices.ContainsFunc(env, func(v string) bool { return strings.Contains(v, "secret") }) {
+		t.Errorf("expected no token for a non-HTTPS repository, got %v", env)
+	}
+}
+
+func TestLoadSpecFromGit(t *testing.T) {
+	repo := createGitSpecRepo(t)
+	p := NewParser()
+
+	t.Run("pinned ref", func(t *testing.T) {
+		doc, err := p.LoadSpec("git+" + repo + "//api/openapi.yaml@v1")
+		if err != nil {
+			t.Fatalf("failed to load spec: %v", err)
+		}
+		if doc.Info.Title != "Tagged API" {
+			t.Errorf("expected title 'Tagged API', got '%s'", doc.Info.Title)
+		}
+		op := doc.Paths.Find("/users").Get
+		if resp := op.Responses.Value("200"); resp == nil || resp.Value == nil || *resp.Value.Description != "Success" {
+			t.Error("expected the relative $ref in the repository to resolve")
+		}
+	})
+
+	t.Run("default branch", func(t *testing.T) {
+		doc, err := p.LoadSpec("git+" + repo + "//api/openapi.yaml")
+		if err != nil {
+			t.Fatalf("failed to load spec: %v", err)
+		}
+		if doc.Info.Title != "Latest API" {
+			t.Errorf("expected title 'Latest API', got '%s'", doc.Info.Title)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := p.LoadSpec("git+" + repo + "//api/missing.yaml"); err == nil {
+			t.Error("expected an error for a file missing from the repository")
+		}
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		if _, err := p.LoadSpec("git+" + repo + "//api/openapi.yaml@v9"); err == nil {
+			t.Error("expected an error for an unknown ref")
+		}
+	})
+}
+
+func TestFetchGitSpec(t *testing.T) {
+	repo := createGitSpecRepo(t)
+
+	data, err := FetchGitSpec(t.Context(), "git+"+repo+"//api/openapi.yaml@v1", nil)
+	if err != nil {
+		t.Fatalf("failed to fetch spec: %v", err)
+	}
+	if !strings.Contains(string(data), "Tagged API") {
+		t.Errorf("expected the tagged spec content, got %s", data)
+	}
+}
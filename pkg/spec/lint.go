@@ -0,0 +1,224 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// LintSeverity is the severity of a LintFinding.
+type LintSeverity string
+
+const (
+	// LintError marks a problem that makes the spec invalid.
+	LintError LintSeverity = "error"
+
+	// LintWarning marks a problem that makes the generated commands harder to use.
+	LintWarning LintSeverity = "warning"
+
+	// LintInfo marks a style suggestion.
+	LintInfo LintSeverity = "info"
+)
+
+// Lint finding categories.
+const (
+	LintCategoryValidity      = "validity"
+	LintCategoryOperationID   = "operation-id"
+	LintCategoryDocumentation = "documentation"
+	LintCategoryTags          = "tags"
+	LintCategoryNaming        = "naming"
+	LintCategoryResponses     = "responses"
+)
+
+// LintFinding is a validity or usability problem found in a spec.
+type LintFinding struct {
+	// Severity tells whether the finding is an error, a warning, or a suggestion.
+	Severity LintSeverity `json:"severity" yaml:"severity"`
+
+	// Category groups related findings, e.g. documentation.
+	Category string `json:"category" yaml:"category"`
+
+	// Location is the operation, e.g. GET /pets, or the spec path the finding is about.
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// Message describes the finding.
+	Message string `json:"message" yaml:"message"`
+}
+
+// String formats the finding, without its severity, for display.
+func (f LintFinding) String() string {
+	if f.Location == "" {
+		return fmt.Sprintf("[%s] %s", f.Category, f.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", f.Category, f.Location, f.Message)
+}
+
+// HasLintErrors reports whether any of the findings is an error.
+func HasLintErrors(findings []LintFinding) bool {
+	return slices.ContainsFunc(findings, func(f LintFinding) bool { return f.Severity == LintError })
+}
+
+// lintMethods lists the operation methods in the order they are linted.
+var lintMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// singleWordOperationID matches operation IDs, such as list, that fit every
+// lowercase naming style.
+var singleWordOperationID = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// operationIDStyles are the naming conventions operation IDs are compared by.
+var operationIDStyles = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"camelCase", regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)},
+	{"PascalCase", regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)},
+	{"snake_case", regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)+$`)},
+	{"kebab-case", regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)+$`)},
+}
+
+// LintSpec checks a spec for validity, as ValidateSpecWithOptions does, and for
+// usability problems of the commands generated from it: operations without an
+// operationId, summary, tags, or success response, and operation IDs that do
+// not follow the naming style of the rest of the spec. Validity findings come
+// first, followed by the operation findings ordered by path and method.
+func (p *Parser) LintSpec(doc *openapi3.T) []LintFinding {
+	var findings []LintFinding
+
+	result := p.ValidateSpecWithOptions(doc)
+	for _, e := range result.Errors {
+		findings = append(findings, LintFinding{Severity: LintError, Category: LintCategoryValidity, Location: e.Path, Message: e.Message})
+	}
+	for _, w := range result.Warnings {
+		findings = append(findings, LintFinding{Severity: LintWarning, Category: LintCategoryValidity, Location: w.Path, Message: w.Message})
+	}
+	if doc == nil || doc.Paths == nil {
+		return findings
+	}
+
+	var opFindings []LintFinding
+	operationIDs := make(map[string]string)
+	for path, pathItem := range doc.Paths.Map() {
+		for _, method := range lintMethods {
+			op := pathItem.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			location := method + " " + path
+			opFindings = append(opFindings, lintOperation(location, op)...)
+			if op.OperationID != "" {
+				operationIDs[location] = op.OperationID
+			}
+		}
+	}
+	opFindings = append(opFindings, lintOperationIDStyle(operationIDs)...)
+
+	slices.SortStableFunc(opFindings, func(a, b LintFinding) int {
+		return strings.Compare(sortableLocation(a.Location), sortableLocation(b.Location))
+	})
+	return append(findings, opFindings...)
+}
+
+// lintOperation returns the usability findings of a single operation.
+func lintOperation(location string, op *openapi3.Operation) []LintFinding {
+	var findings []LintFinding
+	add := func(severity LintSeverity, category, message string) {
+		findings = append(findings, LintFinding{Severity: severity, Category: category, Location: location, Message: message})
+	}
+
+	if op.OperationID == "" {
+		add(LintWarning, LintCategoryOperationID, "operation has no operationId")
+	}
+
+	switch {
+	case op.Summary == "" && op.Description == "":
+		add(LintWarning, LintCategoryDocumentation, "operation has no summary or description")
+	case op.Summary == "":
+		add(LintInfo, LintCategoryDocumentation, "operation has no summary")
+	}
+
+	if len(op.Tags) == 0 {
+		add(LintWarning, LintCategoryTags, "operation has no tags")
+	}
+
+	if !hasSuccessResponse(op) {
+		add(LintWarning, LintCategoryResponses, "operation declares no 2xx or 3xx response")
+	}
+	return findings
+}
+
+// hasSuccessResponse reports whether an operation declares a 2xx or 3xx
+// response, or a default response that may stand for one.
+func hasSuccessResponse(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+	for code := range op.Responses.Map() {
+		if code == "default" || strings.HasPrefix(code, "2") || strings.HasPrefix(code, "3") {
+			return true
+		}
+	}
+	return false
+}
+
+// lintOperationIDStyle reports operation IDs that do not follow the naming
+// style most operation IDs of the spec use.
+func lintOperationIDStyle(operationIDs map[string]string) []LintFinding {
+	counts := make(map[string]int)
+	for _, id := range operationIDs {
+		if singleWordOperationID.MatchString(id) {
+			continue
+		}
+		if style := operationIDStyle(id); style != "" {
+			counts[style]++
+		}
+	}
+
+	dominant := ""
+	for _, style := range operationIDStyles {
+		if counts[style.name] > counts[dominant] {
+			dominant = style.name
+		}
+	}
+	if dominant == "" {
+		return nil
+	}
+
+	var findings []LintFinding
+	for location, id := range operationIDs {
+		if singleWordOperationID.MatchString(id) && dominant != "PascalCase" {
+			continue
+		}
+		if operationIDStyle(id) != dominant {
+			findings = append(findings, LintFinding{
+				Severity: LintInfo,
+				Category: LintCategoryNaming,
+				Location: location,
+				Message:  fmt.Sprintf("operationId '%s' is not %s like most operation IDs", id, dominant),
+			})
+		}
+	}
+	return findings
+}
+
+// operationIDStyle returns the naming style of an operation ID, or "" if it
+// follows none.
+func operationIDStyle(id string) string {
+	for _, style := range operationIDStyles {
+		if style.pattern.MatchString(id) {
+			return style.name
+		}
+	}
+	return ""
+}
+
+// sortableLocation orders operation locations by path, then method.
+func sortableLocation(location string) string {
+	method, path, ok := strings.Cut(location, " ")
+	if !ok || !slices.Contains(lintMethods, method) {
+		return location
+	}
+	return fmt.Sprintf("%s %d", path, slices.Index(lintMethods, method))
+}
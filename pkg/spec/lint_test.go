@@ -0,0 +1,105 @@
+package spec
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintSpec(t *testing.T) {
+	p := NewParser()
+	doc, err := p.LoadSpecFromReader(strings.NewReader(`
+openapi: "3.0.0"
+info:
+  title: Pet API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      tags: [pets]
+      responses:
+        "200":
+          description: OK
+    post:
+      operationId: createPet
+      responses:
+        "201":
+          description: Created
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: get_pet
+      description: Returns a single pet.
+      tags: [pets]
+      responses:
+        "200":
+          description: OK
+    delete:
+      operationId: deletePet
+      summary: Delete a pet
+      tags: [pets]
+      responses:
+        "404":
+          description: Not found
+`))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	want := []LintFinding{
+		{Severity: LintWarning, Category: LintCategoryDocumentation, Location: "POST /pets", Message: "operation has no summary or description"},
+		{Severity: LintWarning, Category: LintCategoryTags, Location: "POST /pets", Message: "operation has no tags"},
+		{Severity: LintInfo, Category: LintCategoryDocumentation, Location: "GET /pets/{petId}", Message: "operation has no summary"},
+		{Severity: LintInfo, Category: LintCategoryNaming, Location: "GET /pets/{petId}", Message: "operationId 'get_pet' is not camelCase like most operation IDs"},
+		{Severity: LintWarning, Category: LintCategoryResponses, Location: "DELETE /pets/{petId}", Message: "operation declares no 2xx or 3xx response"},
+	}
+	got := p.LintSpec(doc)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected findings:\ngot:  %v\nwant: %v", got, want)
+	}
+	if HasLintErrors(got) {
+		t.Error("expected no lint errors")
+	}
+}
+
+func TestLintSpecMissingOperationID(t *testing.T) {
+	p := NewParser()
+	doc, err := p.LoadSpecFromReader(strings.NewReader(`
+openapi: "3.0.0"
+info:
+  title: Pet API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      summary: List pets
+      tags: [pets]
+      responses:
+        "200":
+          description: OK
+`))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	want := []LintFinding{
+		{Severity: LintWarning, Category: LintCategoryOperationID, Location: "GET /pets", Message: "operation has no operationId"},
+	}
+	if got := p.LintSpec(doc); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected findings:\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestLintFindingString(t *testing.T) {
+	finding := LintFinding{Severity: LintWarning, Category: LintCategoryTags, Location: "GET /pets", Message: "operation has no tags"}
+	if got := finding.String(); got != "[tags] GET /pets: operation has no tags" {
+		t.Errorf("unexpected string: %s", got)
+	}
+}
@@ -2,22 +2,29 @@
 package spec
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	ghodssyaml "github.com/ghodss/yaml"
 )
 
 // SpecVersion represents the OpenAPI specification version.
@@ -48,7 +55,7 @@ type SpecFetchOptions struct {
 	// AuthToken is the authentication token or credential value.
 	// For bearer: the token value
 	// For api_key: the API key value
-	// For basic: base64-encoded "username:password"
+	// For basic: base64-encoded "username:password" (or set AuthUsername and AuthPassword)
 	AuthToken string
 
 	// AuthKeyName is the header or query parameter name for api_key auth.
@@ -58,6 +65,21 @@ type SpecFetchOptions struct {
 	// AuthLocation is where to send api_key auth: "header" or "query".
 	// Defaults to "header".
 	AuthLocation string
+
+	// AuthUsername and AuthPassword are raw basic auth credentials. When either
+	// is set they are encoded for the request and take precedence over AuthToken.
+	AuthUsername string
+	AuthPassword string
+}
+
+// BasicAuthToken returns the base64-encoded "username:password" credential for
+// basic auth, built from AuthUsername and AuthPassword when set and falling
+// back to AuthToken otherwise.
+func (o *SpecFetchOptions) BasicAuthToken() string {
+	if o.AuthUsername != "" || o.AuthPassword != "" {
+		return base64.StdEncoding.EncodeToString([]byte(o.AuthUsername + ":" + o.AuthPassword))
+	}
+	return o.AuthToken
 }
 
 // Clone returns a deep copy of SpecFetchOptions.
@@ -70,6 +92,8 @@ func (o *SpecFetchOptions) Clone() *SpecFetchOptions {
 		AuthToken:    o.AuthToken,
 		AuthKeyName:  o.AuthKeyName,
 		AuthLocation: o.AuthLocation,
+		AuthUsername: o.AuthUsername,
+		AuthPassword: o.AuthPassword,
 	}
 	if o.Headers != nil {
 		clone.Headers = make(map[string]string, len(o.Headers))
@@ -99,6 +123,8 @@ func (o *SpecFetchOptions) Merge(override *SpecFetchOptions) *SpecFetchOptions {
 		merged.AuthToken = override.AuthToken
 		merged.AuthKeyName = override.AuthKeyName
 		merged.AuthLocation = override.AuthLocation
+		merged.AuthUsername = override.AuthUsername
+		merged.AuthPassword = override.AuthPassword
 	}
 
 	// Merge headers (override takes precedence)
@@ -119,6 +145,11 @@ type Parser struct {
 	client       *http.Client
 	loader       *openapi3.Loader
 	fetchOptions *SpecFetchOptions
+	rewriteURL   func(string) string
+	tlsConfig    *tls.Config
+	eagerRefs    bool
+	maxRefDepth  int
+	transformers []func(*openapi3.T) error
 }
 
 // CachedSpec represents a cached OpenAPI specification with metadata.
@@ -138,7 +169,41 @@ type SpecInfo struct {
 	SpecVersion SpecVersion
 	Source      string
 	PathCount   int
-	Operations  int
+
+	// Operations counts every operation of the spec, including those hidden
+	// from the CLI and MCP with x-ob-hidden.
+	Operations int
+
+	// Extensions holds the document's top-level x-* extensions.
+	// It is only populated when WithVendorExtensions is given.
+	Extensions map[string]any
+
+	// OperationExtensions lists the operations that declare x-* extensions,
+	// sorted by path and method. It is only populated when WithVendorExtensions is given.
+	OperationExtensions []OperationExtensions
+}
+
+// OperationExtensions holds the vendor extensions declared on one operation.
+type OperationExtensions struct {
+	Method      string
+	Path        string
+	OperationID string
+	Extensions  map[string]any
+}
+
+// SpecInfoOption configures what GetSpecInfo extracts.
+type SpecInfoOption func(*specInfoConfig)
+
+// specInfoConfig holds the settings applied by SpecInfoOptions.
+type specInfoConfig struct {
+	vendorExtensions bool
+}
+
+// WithVendorExtensions includes top-level and per-operation x-* extensions in SpecInfo.
+func WithVendorExtensions() SpecInfoOption {
+	return func(c *specInfoConfig) {
+		c.vendorExtensions = true
+	}
 }
 
 // ParserOption is a function that configures a Parser.
@@ -166,13 +231,65 @@ func WithFetchOptions(opts *SpecFetchOptions) ParserOption {
 	}
 }
 
+// WithServerURLRewriter sets a function applied to every server URL after parsing.
+// This is useful for routing all requests through an internal gateway or proxy.
+func WithServerURLRewriter(rewrite func(string) string) ParserOption {
+	return func(p *Parser) {
+		p.rewriteURL = rewrite
+	}
+}
+
+// WithExternalRefs controls whether $ref values pointing at other files or URLs are resolved.
+// External refs are allowed by default; disabling them makes specs that use them fail
+// to load instead of triggering fetches, which avoids SSRF from untrusted specs.
+func WithExternalRefs(allowed bool) ParserOption {
+	return func(p *Parser) {
+		p.loader.IsExternalRefsAllowed = allowed
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used when fetching specs and external
+// refs over HTTPS, e.g. to trust an internal CA through RootCAs. It applies on top
+// of any client set with WithHTTPClient and is independent of the TLS settings
+// used for API requests.
+func WithTLSConfig(cfg *tls.Config) ParserOption {
+	return func(p *Parser) {
+		p.tlsConfig = cfg
+	}
+}
+
+// WithDocumentTransformer adds a function that patches each document after it
+// is loaded and before it is validated, e.g. to add a missing servers entry or
+// fix a known bad schema. Transformers run in the order they were added, on
+// Swagger 2.0 documents after their conversion to OpenAPI 3.0. An error aborts
+// loading with ErrDocumentTransform.
+func WithDocumentTransformer(transform func(*openapi3.T) error) ParserOption {
+	return func(p *Parser) {
+		p.transformers = append(p.transformers, transform)
+	}
+}
+
+// ErrDocumentTransform is returned when a document transformer fails.
+var ErrDocumentTransform = errors.New("document transformer failed")
+
+// transformDocument applies the parser's document transformers to doc.
+func (p *Parser) transformDocument(doc *openapi3.T) error {
+	for _, transform := range p.transformers {
+		if err := transform(doc); err != nil {
+			return fmt.Errorf("%w: %w", ErrDocumentTransform, err)
+		}
+	}
+	return nil
+}
+
 // NewParser creates a new Parser with the given options. 🐾
 func NewParser(opts ...ParserOption) *Parser {
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
 
 	p := &Parser{
-		cacheTTL: 5 * time.Minute, // Default TTL for cache entries
+		cacheTTL:    5 * time.Minute, // Default TTL for cache entries
+		maxRefDepth: DefaultMaxRefDepth,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -181,10 +298,29 @@ func NewParser(opts ...ParserOption) *Parser {
 	for _, opt := range opts {
 		opt(p)
 	}
+	if p.tlsConfig != nil {
+		p.client = clientWithTLS(p.client, p.tlsConfig)
+		p.loader.ReadFromURIFunc = openapi3.URIMapCache(openapi3.ReadFromURIs(openapi3.ReadFromHTTP(p.client), openapi3.ReadFromFile))
+	}
 	return p
 }
 
-// LoadSpec loads an OpenAPI specification from a file path or URL.
+// clientWithTLS returns a copy of client whose transport uses cfg.
+func clientWithTLS(client *http.Client, cfg *tls.Config) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.TLSClientConfig = cfg
+
+	withTLS := *client
+	withTLS.Transport = transport
+	return &withTLS
+}
+
+// LoadSpec loads an OpenAPI specification from a file path, URL or git source
+// such as git+https://github.com/org/repo.git//openapi.yaml@v1.2.0.
 // It automatically detects the version (2.0, 3.0, or 3.1) and converts
 // OpenAPI 2.0 (Swagger) specs to 3.x format.
 func (p *Parser) LoadSpec(source string) (*openapi3.T, error) {
@@ -194,10 +330,13 @@ func (p *Parser) LoadSpec(source string) (*openapi3.T, error) {
 
 // LoadSpecWithContext loads an OpenAPI specification with context support.
 func (p *Parser) LoadSpecWithContext(ctx context.Context, source string) (*openapi3.T, error) {
-	// Detect if source is a URL or file path
+	// Detect if source is a URL, git repository or file path
 	if isURL(source) {
 		return p.loadFromURL(ctx, source, nil)
 	}
+	if IsGitSource(source) {
+		return p.loadFromGit(ctx, source, nil)
+	}
 	return p.loadFromFile(ctx, source)
 }
 
@@ -207,9 +346,24 @@ func (p *Parser) LoadSpecWithOptions(ctx context.Context, source string, opts *S
 	if isURL(source) {
 		return p.loadFromURL(ctx, source, opts)
 	}
+	if IsGitSource(source) {
+		return p.loadFromGit(ctx, source, opts)
+	}
 	return p.loadFromFile(ctx, source)
 }
 
+// LoadSpecFromReader loads an OpenAPI specification from r, such as stdin.
+func (p *Parser) LoadSpecFromReader(r io.Reader) (*openapi3.T, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("spec input is empty")
+	}
+	return p.parseSpec(context.Background(), data)
+}
+
 // GetHTTPClient returns the HTTP client used by the parser.
 // This allows sharing the same client with other components.
 func (p *Parser) GetHTTPClient() *http.Client {
@@ -267,6 +421,16 @@ func (p *Parser) loadFromURL(ctx context.Context, specURL string, perSpecOpts *S
 		return nil, err
 	}
 
+	data, err := p.fetchURL(ctx, specURL, perSpecOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseSpecWithBaseURL(ctx, data, parsedURL)
+}
+
+// fetchURL fetches the raw content of a remote specification.
+func (p *Parser) fetchURL(ctx context.Context, specURL string, perSpecOpts *SpecFetchOptions) ([]byte, error) {
 	req, err := p.createHTTPRequest(ctx, specURL)
 	if err != nil {
 		return nil, err
@@ -290,7 +454,46 @@ func (p *Parser) loadFromURL(ctx context.Context, specURL string, perSpecOpts *S
 		return nil, err
 	}
 
-	return p.parseSpecWithBaseURL(ctx, data, parsedURL)
+	if err := CheckHTMLResponse(specURL, resp.Header.Get("Content-Type"), data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SpecContentHash returns the SHA256 hash, hex encoded, of the raw content of
+// a specification file or URL, without parsing it. Callers can compare it with
+// a previously stored hash to cheaply decide whether the spec changed.
+func (p *Parser) SpecContentHash(source string) (string, error) {
+	return p.SpecContentHashWithContext(context.Background(), source)
+}
+
+// SpecContentHashWithContext is like SpecContentHash with context support.
+func (p *Parser) SpecContentHashWithContext(ctx context.Context, source string) (string, error) {
+	var data []byte
+	if isURL(source) {
+		fetched, err := p.fetchURL(ctx, source, nil)
+		if err != nil {
+			return "", err
+		}
+		data = fetched
+	} else if IsGitSource(source) {
+		fetched, err := FetchGitSpec(ctx, source, p.fetchOptions)
+		if err != nil {
+			return "", err
+		}
+		data = fetched
+	} else {
+		absPath, err := p.resolveAbsolutePath(source)
+		if err != nil {
+			return "", err
+		}
+		if data, err = p.readSpecFile(source, absPath); err != nil {
+			return "", err
+		}
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
 }
 
 // validateAndParseURL validates and parses the URL.
@@ -326,6 +529,33 @@ func (p *Parser) executeHTTPRequest(specURL string, req *http.Request) (*http.Re
 	return resp, nil
 }
 
+// ErrHTMLResponse is returned when a spec URL serves an HTML page instead of a spec.
+var ErrHTMLResponse = errors.New("spec URL returned HTML")
+
+// CheckHTMLResponse returns an ErrHTMLResponse error when a spec URL served an
+// HTML page, judged by its Content-Type or by the start of the body. This is
+// typically a login page in front of the spec, so the error suggests
+// authentication instead of surfacing a JSON or YAML parse error.
+func CheckHTMLResponse(specURL, contentType string, body []byte) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" && !looksLikeHTML(body) {
+		return nil
+	}
+	return fmt.Errorf("%w: '%s' returned an HTML page instead of an OpenAPI document; "+
+		"the server may require authentication (configure spec_fetch_auth on the profile) "+
+		"or the URL may point to a documentation page rather than the raw spec", ErrHTMLResponse, specURL)
+}
+
+// looksLikeHTML reports whether data starts like an HTML document.
+func looksLikeHTML(data []byte) bool {
+	start := bytes.TrimSpace(data)
+	if len(start) > 512 {
+		start = start[:512]
+	}
+	start = bytes.ToLower(start)
+	return bytes.HasPrefix(start, []byte("<!doctype html")) || bytes.HasPrefix(start, []byte("<html"))
+}
+
 // checkHTTPResponse checks if the HTTP response is valid.
 func (p *Parser) checkHTTPResponse(specURL string, resp *http.Response) error {
 	if resp.StatusCode != http.StatusOK {
@@ -367,6 +597,12 @@ func (p *Parser) applyCustomHeaders(req *http.Request, headers map[string]string
 
 // applyAuthentication applies authentication to the request based on auth type.
 func (p *Parser) applyAuthentication(req *http.Request, opts *SpecFetchOptions) {
+	if opts.AuthType == "basic" {
+		if token := opts.BasicAuthToken(); token != "" {
+			req.Header.Set("Authorization", "Basic "+token)
+		}
+		return
+	}
 	if opts.AuthToken == "" {
 		return
 	}
@@ -374,8 +610,6 @@ func (p *Parser) applyAuthentication(req *http.Request, opts *SpecFetchOptions)
 	switch opts.AuthType {
 	case "bearer":
 		req.Header.Set("Authorization", "Bearer "+opts.AuthToken)
-	case "basic":
-		req.Header.Set("Authorization", "Basic "+opts.AuthToken)
 	case "api_key":
 		p.applyAPIKeyAuth(req, opts)
 	default:
@@ -402,6 +636,25 @@ func (p *Parser) applyAPIKeyAuth(req *http.Request, opts *SpecFetchOptions) {
 
 // parseSpec parses specification data and handles version detection.
 func (p *Parser) parseSpec(ctx context.Context, data []byte) (*openapi3.T, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkRefs(data); err != nil {
+		return nil, err
+	}
+
+	doc, err := p.decodeSpec(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	p.rewriteServerURLs(doc)
+	return doc, nil
+}
+
+// decodeSpec decodes specification data according to its detected version.
+func (p *Parser) decodeSpec(ctx context.Context, data []byte) (*openapi3.T, error) {
 	// Detect version from content
 	version := detectVersion(data)
 
@@ -413,19 +666,63 @@ func (p *Parser) parseSpec(ctx context.Context, data []byte) (*openapi3.T, error
 	default:
 		// Try OpenAPI 3.x first, then fall back to 2.0
 		doc, err := p.parseOpenAPI3(ctx, data)
-		if err == nil {
-			return doc, nil
+		if err == nil || errors.Is(err, ErrDocumentTransform) {
+			return doc, err
 		}
-		return p.parseSwagger(ctx, data)
+		if doc, err = p.parseSwagger(ctx, data); err != nil {
+			return nil, unrecognizedSpecError(data, err)
+		}
+		return doc, nil
 	}
 }
 
+// ErrUnrecognizedSpec is returned when a document is neither an OpenAPI 3.x
+// nor a Swagger 2.0 specification.
+var ErrUnrecognizedSpec = errors.New("not a recognized OpenAPI/Swagger document")
+
+// unrecognizedSpecError explains why a document of unknown version failed to
+// parse. A document without a top-level openapi or swagger field yields an
+// ErrUnrecognizedSpec error, since the parsers' own errors are cryptic for
+// arbitrary JSON or YAML; otherwise, such as for a YAML syntax error or a spec
+// with an unsupported version, the parser's error is returned as is.
+func unrecognizedSpecError(data []byte, parseErr error) error {
+	var doc any
+	if err := ghodssyaml.Unmarshal(data, &doc); err != nil {
+		return parseErr
+	}
+
+	fields, ok := doc.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: expected an object with an 'openapi' or 'swagger' field, got a %T", ErrUnrecognizedSpec, doc)
+	}
+	if _, ok := fields["openapi"]; ok {
+		return parseErr
+	}
+	if _, ok := fields["swagger"]; ok {
+		return parseErr
+	}
+
+	keys := slices.Sorted(maps.Keys(fields))
+	if len(keys) > 5 {
+		keys = append(keys[:5], "...")
+	}
+	return fmt.Errorf("%w: no top-level 'openapi' (3.x) or 'swagger' (2.0) field found (fields: %s)",
+		ErrUnrecognizedSpec, strings.Join(keys, ", "))
+}
+
 // parseSpecWithBaseURL parses spec data with a base URL for reference resolution.
 func (p *Parser) parseSpecWithBaseURL(ctx context.Context, data []byte, baseURL *url.URL) (*openapi3.T, error) {
+	data, err := normalizeEncoding(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkRefs(data); err != nil {
+		return nil, err
+	}
+
 	version := detectVersion(data)
 
 	var spec *openapi3.T
-	var err error
 
 	switch version {
 	case Version20:
@@ -434,28 +731,70 @@ func (p *Parser) parseSpecWithBaseURL(ctx context.Context, data []byte, baseURL
 		spec, err = p.parseOpenAPI3WithBaseURL(ctx, data, baseURL)
 	default:
 		spec, err = p.parseOpenAPI3WithBaseURL(ctx, data, baseURL)
-		if err == nil {
+		if err == nil || errors.Is(err, ErrDocumentTransform) {
 			break
 		}
-		spec, err = p.parseSwaggerWithBaseURL(ctx, data)
+		if spec, err = p.parseSwaggerWithBaseURL(ctx, data); err != nil {
+			err = unrecognizedSpecError(data, err)
+		}
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	p.rewriteServerURLs(spec)
 	return spec, nil
 }
 
+// rewriteServerURLs applies the configured rewriter to document, path and operation servers.
+func (p *Parser) rewriteServerURLs(doc *openapi3.T) {
+	if p.rewriteURL == nil || doc == nil {
+		return
+	}
+
+	p.rewriteServers(doc.Servers)
+	if doc.Paths == nil {
+		return
+	}
+	for _, pathItem := range doc.Paths.Map() {
+		p.rewriteServers(pathItem.Servers)
+		for _, op := range pathItem.Operations() {
+			if op.Servers != nil {
+				p.rewriteServers(*op.Servers)
+			}
+		}
+	}
+}
+
+// rewriteServers rewrites the URL of each server in place.
+func (p *Parser) rewriteServers(servers openapi3.Servers) {
+	for _, server := range servers {
+		if server != nil {
+			server.URL = p.rewriteURL(server.URL)
+		}
+	}
+}
+
+// openAPI31Fields lets validation accept the root fields OpenAPI 3.1 added,
+// which the loader keeps as extensions.
+var openAPI31Fields = openapi3.AllowExtraSiblingFields("webhooks", "jsonSchemaDialect")
+
 // parseOpenAPI3 parses an OpenAPI 3.x specification.
 func (p *Parser) parseOpenAPI3(ctx context.Context, data []byte) (*openapi3.T, error) {
+	defer p.trackRefDepth(data, nil)()
+
 	doc, err := p.loader.LoadFromData(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OpenAPI 3.x spec: %w", err)
 	}
 
+	if err := p.transformDocument(doc); err != nil {
+		return nil, err
+	}
+
 	// Validate the specification
-	if err := doc.Validate(ctx); err != nil {
+	if err := doc.Validate(ctx, openAPI31Fields); err != nil {
 		// Workaround for kin-openapi validation issue with OpenAPI 3.1 type: "null"
 		// See: https://github.com/getkin/kin-openapi/issues/???
 		if strings.Contains(err.Error(), `unsupported 'type' value "null"`) {
@@ -469,12 +808,18 @@ func (p *Parser) parseOpenAPI3(ctx context.Context, data []byte) (*openapi3.T, e
 
 // parseOpenAPI3WithBaseURL parses OpenAPI 3.x with a base URL.
 func (p *Parser) parseOpenAPI3WithBaseURL(ctx context.Context, data []byte, baseURL *url.URL) (*openapi3.T, error) {
+	defer p.trackRefDepth(data, baseURL)()
+
 	doc, err := p.loader.LoadFromDataWithPath(data, baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OpenAPI 3.x spec: %w", err)
 	}
 
-	if err := doc.Validate(ctx); err != nil {
+	if err := p.transformDocument(doc); err != nil {
+		return nil, err
+	}
+
+	if err := doc.Validate(ctx, openAPI31Fields); err != nil {
 		// Workaround for kin-openapi validation issue with OpenAPI 3.1 type: "null"
 		if strings.Contains(err.Error(), `unsupported 'type' value "null"`) {
 			return doc, nil
@@ -487,31 +832,21 @@ func (p *Parser) parseOpenAPI3WithBaseURL(ctx context.Context, data []byte, base
 
 // parseSwagger parses an OpenAPI 2.0 (Swagger) specification.
 func (p *Parser) parseSwagger(ctx context.Context, data []byte) (*openapi3.T, error) {
-	var swagger openapi2.T
-
-	// Use ContentDetector to handle format detection and conversion
-	detector := NewContentDetector()
-
-	// Convert to JSON (handles both JSON and YAML input)
-	// This is necessary because some nested types (e.g., openapi3.Types) only implement
-	// UnmarshalJSON but not UnmarshalYAML, causing YAML parsing to fail with type errors
-	// like "cannot unmarshal !!str `string` into openapi3.Types"
-	jsonData, err := detector.ToJSONWithFallback(data)
+	swagger, err := unmarshalSwagger(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Swagger 2.0 spec: %w", err)
-	}
-
-	// Unmarshal the JSON data
-	if err := json.Unmarshal(jsonData, &swagger); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal Swagger 2.0 spec: %w", err)
+		return nil, err
 	}
 
 	// Convert Swagger 2.0 to OpenAPI 3.0
-	doc, err := openapi2conv.ToV3(&swagger)
+	doc, err := openapi2conv.ToV3(swagger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert Swagger 2.0 to OpenAPI 3.0: %w", err)
 	}
 
+	if err := p.transformDocument(doc); err != nil {
+		return nil, err
+	}
+
 	// Validate the converted specification
 	if err := doc.Validate(ctx); err != nil {
 		return nil, fmt.Errorf("converted OpenAPI 3.0 validation failed: %w", err)
@@ -532,7 +867,7 @@ func (p *Parser) ValidateSpec(spec *openapi3.T) error {
 		return fmt.Errorf("spec is nil")
 	}
 	ctx := context.Background()
-	return spec.Validate(ctx)
+	return spec.Validate(ctx, openAPI31Fields)
 }
 
 // validateSpecInfo validates the info section and adds errors/warnings.
@@ -588,7 +923,7 @@ func (p *Parser) ValidateSpecWithOptions(spec *openapi3.T, opts ...ValidationOpt
 	}
 
 	ctx := context.Background()
-	if err := spec.Validate(ctx); err != nil {
+	if err := spec.Validate(ctx, openAPI31Fields); err != nil {
 		result.Valid = false
 		result.Errors = append(result.Errors, ValidationError{
 			Path: "", Message: err.Error(), Type: "schema",
@@ -749,11 +1084,16 @@ func countPathOperations(pathItem *openapi3.PathItem) int {
 }
 
 // GetSpecInfo extracts metadata from a parsed specification.
-func GetSpecInfo(spec *openapi3.T, source string) *SpecInfo {
+func GetSpecInfo(spec *openapi3.T, source string, opts ...SpecInfoOption) *SpecInfo {
 	if spec == nil {
 		return nil
 	}
 
+	var cfg specInfoConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	info := &SpecInfo{
 		Source:      source,
 		SpecVersion: detectVersionFromSpec(spec),
@@ -771,9 +1111,39 @@ func GetSpecInfo(spec *openapi3.T, source string) *SpecInfo {
 		}
 	}
 
+	if cfg.vendorExtensions {
+		info.Extensions = maps.Clone(spec.Extensions)
+		info.OperationExtensions = collectOperationExtensions(spec)
+	}
+
 	return info
 }
 
+// collectOperationExtensions returns the x-* extensions of every operation that declares any.
+func collectOperationExtensions(spec *openapi3.T) []OperationExtensions {
+	if spec.Paths == nil {
+		return nil
+	}
+
+	var result []OperationExtensions
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths.Map())) {
+		operations := spec.Paths.Value(path).Operations()
+		for _, method := range slices.Sorted(maps.Keys(operations)) {
+			op := operations[method]
+			if len(op.Extensions) == 0 {
+				continue
+			}
+			result = append(result, OperationExtensions{
+				Method:      method,
+				Path:        path,
+				OperationID: op.OperationID,
+				Extensions:  maps.Clone(op.Extensions),
+			})
+		}
+	}
+	return result
+}
+
 // detectVersion detects the OpenAPI version from raw data.
 func detectVersion(data []byte) SpecVersion {
 	// Quick detection using string matching
@@ -2,7 +2,11 @@ package spec
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -10,6 +14,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 func TestNewParser(t *testing.T) {
@@ -259,6 +265,96 @@ paths:
 	}
 }
 
+func TestLoadSpecFromReader(t *testing.T) {
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Piped API
+  version: "1.0.0"
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: Success
+`
+
+	p := NewParser()
+	doc, err := p.LoadSpecFromReader(strings.NewReader(specContent))
+	if err != nil {
+		t.Fatalf("LoadSpecFromReader failed: %v", err)
+	}
+	if doc.Info.Title != "Piped API" {
+		t.Errorf("expected title 'Piped API', got '%s'", doc.Info.Title)
+	}
+
+	if _, err := p.LoadSpecFromReader(strings.NewReader("")); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestLoadSpecWithServerURLRewriter(t *testing.T) {
+	tmpDir := t.TempDir()
+	specPath := filepath.Join(tmpDir, "spec.yaml")
+
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+servers:
+  - url: https://api.example.com/v1
+  - url: https://api.example.com/v2
+  - url: https://staging.example.com
+paths:
+  /users:
+    servers:
+      - url: https://api.example.com/users-service
+    get:
+      operationId: listUsers
+      servers:
+        - url: https://api.example.com/read
+      responses:
+        "200":
+          description: Success
+`
+
+	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
+		t.Fatalf("failed to write test spec: %v", err)
+	}
+
+	p := NewParser(WithServerURLRewriter(func(url string) string {
+		return strings.Replace(url, "api.example.com", "gateway.internal:8443", 1)
+	}))
+	spec, err := p.LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	expected := []string{
+		"https://gateway.internal:8443/v1",
+		"https://gateway.internal:8443/v2",
+		"https://staging.example.com",
+	}
+	if len(spec.Servers) != len(expected) {
+		t.Fatalf("expected %d servers, got %d", len(expected), len(spec.Servers))
+	}
+	for i, want := range expected {
+		if spec.Servers[i].URL != want {
+			t.Errorf("server %d: expected %q, got %q", i, want, spec.Servers[i].URL)
+		}
+	}
+
+	pathItem := spec.Paths.Find("/users")
+	if got := pathItem.Servers[0].URL; got != "https://gateway.internal:8443/users-service" {
+		t.Errorf("expected rewritten path server, got %q", got)
+	}
+	if got := (*pathItem.Get.Servers)[0].URL; got != "https://gateway.internal:8443/read" {
+		t.Errorf("expected rewritten operation server, got %q", got)
+	}
+}
+
 func TestLoadSpecFromFileJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	specPath := filepath.Join(tmpDir, "spec.json")
@@ -322,6 +418,27 @@ func TestLoadSpecEmptyFile(t *testing.T) {
 	}
 }
 
+func TestLoadSpecUnrecognizedDocument(t *testing.T) {
+	p := NewParser()
+
+	tests := map[string]string{
+		"json object":  `{"name":"x","items":[1,2]}`,
+		"json array":   `[1, 2, 3]`,
+		"yaml mapping": "name: x\nitems:\n  - 1\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := p.LoadSpecFromReader(strings.NewReader(content))
+			if !errors.Is(err, ErrUnrecognizedSpec) {
+				t.Fatalf("expected ErrUnrecognizedSpec, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "not a recognized OpenAPI/Swagger document") {
+				t.Errorf("unexpected error message %q", err)
+			}
+		})
+	}
+}
+
 func TestLoadSpecFromURL(t *testing.T) {
 	// Create a test server
 	spec := map[string]any{
@@ -361,6 +478,204 @@ func TestLoadSpecFromURL(t *testing.T) {
 	}
 }
 
+func TestLoadSpecWithTLSConfig(t *testing.T) {
+	mainSpec := `
+openapi: "3.0.0"
+info:
+  title: Internal API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: "./schemas.yaml#/Pet"
+`
+	schemas := `
+Pet:
+  type: object
+  properties:
+    name:
+      type: string
+`
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			_, _ = w.Write([]byte(mainSpec))
+		case "/schemas.yaml":
+			_, _ = w.Write([]byte(schemas))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := NewParser().LoadSpec(server.URL + "/openapi.yaml"); err == nil {
+		t.Fatal("expected default client to reject the untrusted certificate")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	p := NewParser(WithTLSConfig(&tls.Config{RootCAs: roots}))
+	spec, err := p.LoadSpec(server.URL + "/openapi.yaml")
+	if err != nil {
+		t.Fatalf("expected spec to load with custom CA, got: %v", err)
+	}
+	schema := spec.Paths.Find("/pets").Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+	if schema.Value == nil || schema.Value.Properties["name"] == nil {
+		t.Error("expected external schema to be fetched with custom CA")
+	}
+}
+
+func TestLoadSpecExternalRefs(t *testing.T) {
+	mainSpec := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: "./schemas.yaml#/Pet"
+`
+	schemas := `
+Pet:
+  type: object
+  properties:
+    name:
+      type: string
+`
+
+	var schemaFetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			_, _ = w.Write([]byte(mainSpec))
+		case "/schemas.yaml":
+			schemaFetches++
+			_, _ = w.Write([]byte(schemas))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("allowed by default", func(t *testing.T) {
+		p := NewParser()
+		spec, err := p.LoadSpec(server.URL + "/openapi.yaml")
+		if err != nil {
+			t.Fatalf("expected spec with external ref to load, got: %v", err)
+		}
+		schema := spec.Paths.Find("/pets").Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+		if schema.Value == nil || schema.Value.Properties["name"] == nil {
+			t.Error("expected external schema to be resolved")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		schemaFetches = 0
+		p := NewParser(WithExternalRefs(false))
+		_, err := p.LoadSpec(server.URL + "/openapi.yaml")
+		if err == nil {
+			t.Fatal("expected error for external ref when disabled")
+		}
+		if !strings.Contains(err.Error(), "external reference") {
+			t.Errorf("expected external reference error, got: %v", err)
+		}
+		if schemaFetches != 0 {
+			t.Errorf("expected external schema not to be fetched, got %d fetches", schemaFetches)
+		}
+	})
+}
+
+func TestLoadSpecMaxRefDepth(t *testing.T) {
+	// openapi.yaml refers to s1.yaml, which refers to s2.yaml, and so on to s5.yaml.
+	mainSpec := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: Success
+          content:
+            application/json:
+              schema:
+                $ref: "./s1.yaml#/Node"
+`
+	fetches := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/openapi.yaml" {
+			_, _ = w.Write([]byte(mainSpec))
+			return
+		}
+		var depth int
+		if _, err := fmt.Sscanf(r.URL.Path, "/s%d.yaml", &depth); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		fetches[r.URL.Path]++
+		if depth == 5 {
+			_, _ = w.Write([]byte("Node:\n  type: string\n"))
+			return
+		}
+		_, _ = fmt.Fprintf(w, "Node:\n  type: object\n  properties:\n    next:\n      $ref: \"./s%d.yaml#/Node\"\n", depth+1)
+	}))
+	defer server.Close()
+
+	t.Run("exceeded", func(t *testing.T) {
+		p := NewParser(WithMaxRefDepth(3))
+		_, err := p.LoadSpec(server.URL + "/openapi.yaml")
+		if !errors.Is(err, ErrRefDepthExceeded) {
+			t.Fatalf("expected ErrRefDepthExceeded, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), "/s4.yaml is 4 references deep (limit 3)") {
+			t.Errorf("expected the error to name the document past the limit, got: %v", err)
+		}
+		if fetches["/s3.yaml"] == 0 || fetches["/s4.yaml"] != 0 {
+			t.Errorf("expected documents to be fetched up to the limit only, got %v", fetches)
+		}
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		p := NewParser(WithMaxRefDepth(5))
+		spec, err := p.LoadSpec(server.URL + "/openapi.yaml")
+		if err != nil {
+			t.Fatalf("expected spec within the depth limit to load, got: %v", err)
+		}
+		schema := spec.Paths.Find("/pets").Get.Responses.Status(200).Value.Content.Get("application/json").Schema.Value
+		for range 4 {
+			schema = schema.Properties["next"].Value
+		}
+		if !schema.Type.Is("string") {
+			t.Errorf("expected the last document of the chain to be resolved, got %v", schema.Type)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		if _, err := NewParser().LoadSpec(server.URL + "/openapi.yaml"); err != nil {
+			t.Fatalf("expected spec within the default depth limit to load, got: %v", err)
+		}
+	})
+}
+
 func TestLoadSpecFromURLError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -521,6 +836,73 @@ paths:
 	}
 }
 
+func TestGetSpecInfoWithVendorExtensions(t *testing.T) {
+	specContent := `
+openapi: "3.0.0"
+info:
+  title: Extensions API
+  version: "1.0.0"
+x-internal: true
+x-rate-limit:
+  requests: 100
+  window: 1m
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      x-rate-limit:
+        requests: 10
+      responses:
+        "200":
+          description: OK
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: Created
+  /admin:
+    delete:
+      operationId: purge
+      x-internal: true
+      responses:
+        "204":
+          description: Deleted
+`
+
+	p := NewParser()
+	spec, err := p.LoadSpecFromReader(strings.NewReader(specContent))
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	if info := GetSpecInfo(spec, ""); info.Extensions != nil || info.OperationExtensions != nil {
+		t.Errorf("expected no extensions without WithVendorExtensions, got %v and %v", info.Extensions, info.OperationExtensions)
+	}
+
+	info := GetSpecInfo(spec, "", WithVendorExtensions())
+	if info.Extensions["x-internal"] != true {
+		t.Errorf("expected top-level x-internal, got %v", info.Extensions)
+	}
+	rateLimit, ok := info.Extensions["x-rate-limit"].(map[string]any)
+	if !ok || rateLimit["window"] != "1m" {
+		t.Errorf("expected structured top-level x-rate-limit, got %v", info.Extensions["x-rate-limit"])
+	}
+
+	if len(info.OperationExtensions) != 2 {
+		t.Fatalf("expected 2 operations with extensions, got %+v", info.OperationExtensions)
+	}
+	admin, users := info.OperationExtensions[0], info.OperationExtensions[1]
+	if admin.Path != "/admin" || admin.Method != "DELETE" || admin.OperationID != "purge" || admin.Extensions["x-internal"] != true {
+		t.Errorf("unexpected extensions for /admin: %+v", admin)
+	}
+	if users.Path != "/users" || users.Method != "GET" || users.OperationID != "listUsers" {
+		t.Errorf("unexpected extensions for /users: %+v", users)
+	}
+	if limit, _ := users.Extensions["x-rate-limit"].(map[string]any); limit["requests"] != float64(10) {
+		t.Errorf("expected per-operation x-rate-limit, got %v", users.Extensions)
+	}
+}
+
 func TestGetSpecInfoNil(t *testing.T) {
 	info := GetSpecInfo(nil, "")
 	if info != nil {
@@ -1849,6 +2231,120 @@ func TestLoadSpecWithBasicAuth(t *testing.T) {
 	}
 }
 
+func TestLoadSpecWithBasicAuthUsernamePassword(t *testing.T) {
+	var receivedHeaders http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Test","version":"1.0.0"},"paths":{}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opts *SpecFetchOptions
+	}{
+		{"raw credentials", &SpecFetchOptions{AuthType: "basic", AuthUsername: "user", AuthPassword: "pass"}},
+		{"raw credentials take precedence", &SpecFetchOptions{AuthType: "basic", AuthToken: "c3RhbGU6dG9rZW4=", AuthUsername: "user", AuthPassword: "pass"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser(WithFetchOptions(tt.opts))
+			if _, err := p.LoadSpec(server.URL); err != nil {
+				t.Fatalf("failed to load spec: %v", err)
+			}
+
+			username, password, ok := (&http.Request{Header: receivedHeaders}).BasicAuth()
+			if !ok || username != "user" || password != "pass" {
+				t.Errorf("expected basic auth user:pass, got %q %q (ok=%v)", username, password, ok)
+			}
+		})
+	}
+}
+
+func TestLoadSpecFromURLReturningHTML(t *testing.T) {
+	const loginPage = "\n<!DOCTYPE html>\n<html><head><title>Sign in</title></head><body>Please sign in</body></html>"
+
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{"html content type", "text/html; charset=utf-8"},
+		{"mislabeled html body", "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write([]byte(loginPage))
+			}))
+			defer server.Close()
+
+			_, err := NewParser().LoadSpec(server.URL)
+			if !errors.Is(err, ErrHTMLResponse) {
+				t.Fatalf("expected ErrHTMLResponse, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "authentication") || !strings.Contains(err.Error(), server.URL) {
+				t.Errorf("expected error to mention the URL and authentication, got %q", err)
+			}
+		})
+	}
+}
+
+func TestSpecContentHash(t *testing.T) {
+	const specV1 = "openapi: \"3.0.0\"\ninfo:\n  title: Hash API\n  version: \"1.0.0\"\npaths: {}\n"
+	const specV2 = "openapi: \"3.0.0\"\ninfo:\n  title: Hash API\n  version: \"2.0.0\"\npaths: {}\n"
+
+	dir := t.TempDir()
+	writeSpec := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+		return path
+	}
+
+	served := specV1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write([]byte(served))
+	}))
+	defer server.Close()
+
+	parser := NewParser()
+	hash := func(source string) string {
+		t.Helper()
+		h, err := parser.SpecContentHash(source)
+		if err != nil {
+			t.Fatalf("SpecContentHash(%s) failed: %v", source, err)
+		}
+		return h
+	}
+
+	fileV1 := hash(writeSpec("a.yaml", specV1))
+	if other := hash(writeSpec("b.yaml", specV1)); other != fileV1 {
+		t.Errorf("expected identical content to hash the same, got %s and %s", fileV1, other)
+	}
+	if changed := hash(writeSpec("c.yaml", specV2)); changed == fileV1 {
+		t.Error("expected changed content to hash differently")
+	}
+
+	if urlV1 := hash(server.URL); urlV1 != fileV1 {
+		t.Errorf("expected URL hash to match file hash for the same content, got %s and %s", urlV1, fileV1)
+	}
+	served = specV2
+	if urlV2 := hash(server.URL); urlV2 == fileV1 {
+		t.Error("expected changed remote content to hash differently")
+	}
+
+	if _, err := parser.SpecContentHash(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
 func TestGetHTTPClient(t *testing.T) {
 	customClient := &http.Client{Timeout: 60 * time.Second}
 	p := NewParser(WithHTTPClient(customClient))
@@ -1866,3 +2362,70 @@ func TestGetFetchOptions(t *testing.T) {
 		t.Error("GetFetchOptions should return the configured options")
 	}
 }
+
+func TestLoadSpecWithDocumentTransformer(t *testing.T) {
+	serverless := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0.0"
+paths:
+  /admin/users:
+    servers:
+      - url: /admin
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: Success
+`
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(serverless), 0644); err != nil {
+		t.Fatalf("failed to write test spec: %v", err)
+	}
+
+	t.Run("injects a server", func(t *testing.T) {
+		p := NewParser(WithDocumentTransformer(func(doc *openapi3.T) error {
+			if len(doc.Servers) == 0 {
+				doc.AddServer(&openapi3.Server{URL: "https://api.example.com"})
+			}
+			return nil
+		}))
+
+		doc, err := p.LoadSpec(specPath)
+		if err != nil {
+			t.Fatalf("failed to load spec: %v", err)
+		}
+		if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com" {
+			t.Fatalf("expected the injected server, got %+v", doc.Servers)
+		}
+
+		op := doc.Paths.Find("/admin/users").Get
+		if got := OperationBaseURL(doc, "/admin/users", op, doc.Servers[0].URL); got != "https://api.example.com/admin" {
+			t.Errorf("expected base URL 'https://api.example.com/admin', got '%s'", got)
+		}
+	})
+
+	t.Run("runs before validation", func(t *testing.T) {
+		p := NewParser(WithDocumentTransformer(func(doc *openapi3.T) error {
+			doc.Info = nil
+			return nil
+		}))
+
+		if _, err := p.LoadSpec(specPath); err == nil || !strings.Contains(err.Error(), "validation failed") {
+			t.Errorf("expected the transformed document to fail validation, got: %v", err)
+		}
+	})
+
+	t.Run("error aborts loading", func(t *testing.T) {
+		transformErr := errors.New("no servers to patch")
+		p := NewParser(WithDocumentTransformer(func(*openapi3.T) error {
+			return transformErr
+		}))
+
+		_, err := p.LoadSpec(specPath)
+		if !errors.Is(err, ErrDocumentTransform) || !errors.Is(err, transformErr) {
+			t.Errorf("expected the transformer error, got: %v", err)
+		}
+	})
+}
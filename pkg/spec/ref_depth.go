@@ -0,0 +1,137 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	ghodssyaml "github.com/ghodss/yaml"
+)
+
+// DefaultMaxRefDepth is how many documents deep a chain of external $refs is
+// followed by default.
+const DefaultMaxRefDepth = 32
+
+// ErrRefDepthExceeded is returned when a chain of external $refs is deeper
+// than the parser allows.
+var ErrRefDepthExceeded = errors.New("external $ref depth limit exceeded")
+
+// WithMaxRefDepth limits how deep chains of external $refs are followed while
+// loading: a spec referring to a document that refers to another is two deep.
+// Loading fails with ErrRefDepthExceeded before fetching a document past the
+// limit, so a spec cannot make a long-running server, such as an MCP server
+// reloading specs, fetch documents without end. A depth of 0 or less removes
+// the limit.
+func WithMaxRefDepth(depth int) ParserOption {
+	return func(p *Parser) {
+		p.maxRefDepth = depth
+	}
+}
+
+// refDepthTracker records how many external $refs away from the root document
+// each document is, and refuses to read those past the limit.
+type refDepthTracker struct {
+	max     int
+	depths  map[string]int
+	deepest int
+}
+
+// trackRefDepth makes the loader enforce the parser's ref depth limit while
+// loading data from location, which is nil for a document without one. It
+// returns a function restoring the loader's reader.
+func (p *Parser) trackRefDepth(data []byte, location *url.URL) func() {
+	if p.maxRefDepth <= 0 || !p.loader.IsExternalRefsAllowed {
+		return func() {}
+	}
+
+	original := p.loader.ReadFromURIFunc
+	next := original
+	if next == nil {
+		next = openapi3.DefaultReadFromURI
+	}
+
+	tracker := &refDepthTracker{max: p.maxRefDepth, depths: make(map[string]int)}
+	if location != nil {
+		tracker.depths[refLocationKey(location)] = 0
+	}
+	tracker.record(data, location, 1)
+
+	p.loader.ReadFromURIFunc = tracker.reader(next)
+	return func() { p.loader.ReadFromURIFunc = original }
+}
+
+// reader wraps next to check the depth of each document before reading it and
+// record the depth of the documents it refers to. A document the tracker did
+// not see referred to counts as deep as the deepest seen.
+func (t *refDepthTracker) reader(next openapi3.ReadFromURIFunc) openapi3.ReadFromURIFunc {
+	return func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		key := refLocationKey(location)
+		depth, ok := t.depths[key]
+		if !ok {
+			depth = t.deepest
+		}
+		if depth > t.max {
+			return nil, fmt.Errorf("%w: %s is %d references deep (limit %d)", ErrRefDepthExceeded, key, depth, t.max)
+		}
+
+		data, err := next(loader, location)
+		if err != nil {
+			return nil, err
+		}
+		t.record(data, location, depth+1)
+		return data, nil
+	}
+}
+
+// record gives the documents referred to by data, read from base, the given
+// depth unless they are already known to be shallower.
+func (t *refDepthTracker) record(data []byte, base *url.URL, depth int) {
+	var doc any
+	if err := ghodssyaml.Unmarshal(data, &doc); err != nil {
+		return
+	}
+
+	walkRefs(doc, "", func(_, ref string) {
+		if strings.HasPrefix(ref, "#") {
+			return
+		}
+		target, err := url.Parse(ref)
+		if err != nil {
+			return
+		}
+		target.Fragment = ""
+		if target.String() == "" {
+			return
+		}
+
+		key := refLocationKey(resolveRefLocation(base, target))
+		if known, ok := t.depths[key]; !ok || depth < known {
+			t.depths[key] = depth
+		}
+		t.deepest = max(t.deepest, depth)
+	})
+}
+
+// resolveRefLocation resolves a reference to another document against the
+// location of the referring one, as the loader does: relative file paths are
+// joined to the referring document's directory, anything else is used as is.
+func resolveRefLocation(base, target *url.URL) *url.URL {
+	isFile := target.Path != "" && target.Host == "" && (target.Scheme == "" || target.Scheme == "file")
+	if !isFile || filepath.IsAbs(target.Path) || base == nil {
+		return target
+	}
+	resolved := *base
+	resolved.Path = path.Join(path.Dir(base.Path), target.Path)
+	return &resolved
+}
+
+// refLocationKey identifies a document by its location without a fragment.
+func refLocationKey(location *url.URL) string {
+	withoutFragment := *location
+	withoutFragment.Fragment = ""
+	return withoutFragment.String()
+}
@@ -0,0 +1,146 @@
+package spec
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	ghodssyaml "github.com/ghodss/yaml"
+)
+
+// UnresolvedRef is a $ref whose target does not exist in the document.
+type UnresolvedRef struct {
+	// Pointer is the JSON pointer of the object holding the $ref,
+	// e.g. /paths/~1pets/get/parameters/0.
+	Pointer string
+
+	// Ref is the reference itself, e.g. #/components/parameters/Missing.
+	Ref string
+}
+
+// UnresolvedRefsError lists every unresolvable $ref of a document.
+type UnresolvedRefsError struct {
+	Refs []UnresolvedRef
+}
+
+func (e *UnresolvedRefsError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "spec has %d unresolved $ref(s):", len(e.Refs))
+	for _, ref := range e.Refs {
+		fmt.Fprintf(&sb, "\n  %s: %s", ref.Pointer, ref.Ref)
+	}
+	return sb.String()
+}
+
+// WithEagerRefResolution checks every local $ref of a document before it is
+// parsed and fails with an *UnresolvedRefsError listing all that do not
+// resolve, instead of surfacing only the first one, or none if it is never
+// used. References to other documents are left to the loader.
+func WithEagerRefResolution() ParserOption {
+	return func(p *Parser) {
+		p.eagerRefs = true
+	}
+}
+
+// checkRefs reports the unresolved local references of data when eager
+// resolution is enabled. Data that cannot be decoded is left to the parser.
+func (p *Parser) checkRefs(data []byte) error {
+	if !p.eagerRefs {
+		return nil
+	}
+
+	var doc any
+	if err := ghodssyaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	if refs := FindUnresolvedRefs(doc); len(refs) > 0 {
+		return &UnresolvedRefsError{Refs: refs}
+	}
+	return nil
+}
+
+// FindUnresolvedRefs walks a decoded document and returns every local $ref,
+// such as #/components/schemas/Pet, whose target does not exist, in document
+// order with object keys sorted.
+func FindUnresolvedRefs(doc any) []UnresolvedRef {
+	var refs []UnresolvedRef
+	walkRefs(doc, "", func(pointer, ref string) {
+		fragment, ok := strings.CutPrefix(ref, "#")
+		if !ok {
+			return
+		}
+		if _, found := resolvePointer(doc, fragment); !found {
+			refs = append(refs, UnresolvedRef{Pointer: pointerOrRoot(pointer), Ref: ref})
+		}
+	})
+	return refs
+}
+
+// walkRefs calls visit with the location and value of every $ref in node.
+func walkRefs(node any, pointer string, visit func(pointer, ref string)) {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			visit(pointer, ref)
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			walkRefs(v[key], pointer+"/"+escapePointerToken(key), visit)
+		}
+	case []any:
+		for i, item := range v {
+			walkRefs(item, pointer+"/"+strconv.Itoa(i), visit)
+		}
+	}
+}
+
+// resolvePointer returns the value at a JSON pointer fragment such as
+// /components/schemas/Pet, which may be percent-encoded as in a URI.
+func resolvePointer(doc any, fragment string) (any, bool) {
+	if decoded, err := url.PathUnescape(fragment); err == nil {
+		fragment = decoded
+	}
+	if fragment == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, false
+	}
+
+	node := doc
+	for token := range strings.SplitSeq(fragment[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := node.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			node = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			node = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// escapePointerToken escapes a key for use in a JSON pointer.
+func escapePointerToken(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// pointerOrRoot returns pointer, or "/" for the document root.
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+	return pointer
+}
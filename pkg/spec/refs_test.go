@@ -0,0 +1,86 @@
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const danglingRefsSpec = `
+openapi: "3.0.0"
+info:
+  title: Dangling refs
+  version: "1.0.0"
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Missing'
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner:
+          $ref: '#/components/schemas/Owner'
+`
+
+func TestLoadSpecEagerRefResolution(t *testing.T) {
+	p := NewParser(WithEagerRefResolution())
+
+	_, err := p.LoadSpecFromReader(strings.NewReader(danglingRefsSpec))
+	var refsErr *UnresolvedRefsError
+	if !errors.As(err, &refsErr) {
+		t.Fatalf("expected UnresolvedRefsError, got %v", err)
+	}
+
+	want := []UnresolvedRef{
+		{Pointer: "/components/schemas/Pet/properties/owner", Ref: "#/components/schemas/Owner"},
+		{Pointer: "/paths/~1pets/get/parameters/0", Ref: "#/components/parameters/Missing"},
+	}
+	if !reflect.DeepEqual(refsErr.Refs, want) {
+		t.Errorf("unexpected unresolved refs %+v", refsErr.Refs)
+	}
+	if !strings.Contains(err.Error(), "/paths/~1pets/get/parameters/0: #/components/parameters/Missing") {
+		t.Errorf("expected the error to name the pointer, got %q", err)
+	}
+}
+
+func TestLoadSpecEagerRefResolutionValid(t *testing.T) {
+	p := NewParser(WithEagerRefResolution())
+
+	spec := strings.Replace(danglingRefsSpec, "        - $ref: '#/components/parameters/Missing'\n", "", 1)
+	spec = strings.Replace(spec, "#/components/schemas/Owner", "#/components/schemas/Pet", 1)
+	if _, err := p.LoadSpecFromReader(strings.NewReader(spec)); err != nil {
+		t.Fatalf("expected the spec to load, got %v", err)
+	}
+}
+
+func TestResolvePointer(t *testing.T) {
+	doc := map[string]any{
+		"paths": map[string]any{"/pets/{id}": map[string]any{"get": "op"}},
+		"a~b":   []any{"zero", "one"},
+	}
+
+	tests := map[string]bool{
+		"":                            true,
+		"/paths/~1pets~1%7Bid%7D/get": true,
+		"/a~0b/1":                     true,
+		"/a~0b/2":                     false,
+		"/paths/pets":                 false,
+		"paths":                       false,
+	}
+	for fragment, want := range tests {
+		if _, found := resolvePointer(doc, fragment); found != want {
+			t.Errorf("resolvePointer(%q) found = %v, want %v", fragment, found, want)
+		}
+	}
+}
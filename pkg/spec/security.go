@@ -0,0 +1,112 @@
+package spec
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OAuthFlowInfo contains normalized metadata for a single OAuth2 flow.
+type OAuthFlowInfo struct {
+	Type             string   // implicit, password, clientCredentials, authorizationCode
+	AuthorizationURL string   // authorization endpoint (implicit and authorizationCode only)
+	TokenURL         string   // token endpoint
+	RefreshURL       string   // optional refresh endpoint
+	Scopes           []string // sorted scope names
+}
+
+// SecuritySchemeInfo contains normalized metadata for a security scheme.
+type SecuritySchemeInfo struct {
+	Name             string          // key in components.securitySchemes
+	Type             string          // http, apiKey, oauth2, openIdConnect, mutualTLS
+	Description      string          // optional description
+	Scheme           string          // HTTP auth scheme, lowercased since it is case-insensitive
+	BearerFormat     string          // bearer token format hint (e.g. JWT)
+	In               string          // apiKey location: header, query or cookie
+	KeyName          string          // apiKey header, query or cookie name
+	OpenIDConnectURL string          // OpenID Connect discovery URL
+	Flows            []OAuthFlowInfo // OAuth2 flows, in a stable order
+}
+
+// GetSecuritySchemes returns normalized information for every security scheme
+// declared in the spec, sorted by scheme name. References are resolved; schemes
+// whose reference could not be resolved are skipped.
+func GetSecuritySchemes(spec *openapi3.T) []SecuritySchemeInfo {
+	if spec == nil || spec.Components == nil || len(spec.Components.SecuritySchemes) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(spec.Components.SecuritySchemes))
+	for name := range spec.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemes := make([]SecuritySchemeInfo, 0, len(names))
+	for _, name := range names {
+		ref := spec.Components.SecuritySchemes[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		schemes = append(schemes, newSecuritySchemeInfo(name, ref.Value))
+	}
+
+	return schemes
+}
+
+// newSecuritySchemeInfo converts a security scheme into its normalized form.
+func newSecuritySchemeInfo(name string, scheme *openapi3.SecurityScheme) SecuritySchemeInfo {
+	info := SecuritySchemeInfo{
+		Name:             name,
+		Type:             scheme.Type,
+		Description:      scheme.Description,
+		Scheme:           strings.ToLower(scheme.Scheme),
+		BearerFormat:     scheme.BearerFormat,
+		In:               scheme.In,
+		KeyName:          scheme.Name,
+		OpenIDConnectURL: scheme.OpenIdConnectUrl,
+	}
+
+	if scheme.Flows != nil {
+		info.Flows = collectOAuthFlows(scheme.Flows)
+	}
+
+	return info
+}
+
+// collectOAuthFlows returns the defined OAuth2 flows in a stable order.
+func collectOAuthFlows(flows *openapi3.OAuthFlows) []OAuthFlowInfo {
+	candidates := []struct {
+		name string
+		flow *openapi3.OAuthFlow
+	}{
+		{"authorizationCode", flows.AuthorizationCode},
+		{"clientCredentials", flows.ClientCredentials},
+		{"implicit", flows.Implicit},
+		{"password", flows.Password},
+	}
+
+	var result []OAuthFlowInfo
+	for _, c := range candidates {
+		if c.flow == nil {
+			continue
+		}
+
+		scopes := make([]string, 0, len(c.flow.Scopes))
+		for scope := range c.flow.Scopes {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+
+		result = append(result, OAuthFlowInfo{
+			Type:             c.name,
+			AuthorizationURL: c.flow.AuthorizationURL,
+			TokenURL:         c.flow.TokenURL,
+			RefreshURL:       c.flow.RefreshURL,
+			Scopes:           scopes,
+		})
+	}
+
+	return result
+}
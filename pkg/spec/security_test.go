@@ -0,0 +1,101 @@
+package spec
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGetSecuritySchemes_ComplexSpec(t *testing.T) {
+	specPath := filepath.Join("..", "..", "internal", "integration", "testdata", "complex_openapi3.json")
+	p := NewParser()
+	spec, err := p.LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	schemes := GetSecuritySchemes(spec)
+	if len(schemes) != 6 {
+		t.Fatalf("expected 6 security schemes, got %d", len(schemes))
+	}
+
+	byName := make(map[string]SecuritySchemeInfo, len(schemes))
+	for _, s := range schemes {
+		byName[s.Name] = s
+	}
+
+	expected := map[string]SecuritySchemeInfo{
+		"bearerAuth":   {Name: "bearerAuth", Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		"basicAuth":    {Name: "basicAuth", Type: "http", Scheme: "basic"},
+		"apiKeyHeader": {Name: "apiKeyHeader", Type: "apiKey", In: "header", KeyName: "X-API-Key"},
+		"apiKeyQuery":  {Name: "apiKeyQuery", Type: "apiKey", In: "query", KeyName: "api_key"},
+		"apiKeyCookie": {Name: "apiKeyCookie", Type: "apiKey", In: "cookie", KeyName: "api_key"},
+		"oauth2": {
+			Name: "oauth2",
+			Type: "oauth2",
+			Flows: []OAuthFlowInfo{
+				{
+					Type:             "authorizationCode",
+					AuthorizationURL: "https://auth.example.com/oauth/authorize",
+					TokenURL:         "https://auth.example.com/oauth/token",
+					Scopes:           []string{"admin", "pets:read", "pets:write"},
+				},
+				{
+					Type:     "clientCredentials",
+					TokenURL: "https://auth.example.com/oauth/token",
+					Scopes:   []string{"pets:read", "pets:write"},
+				},
+			},
+		},
+	}
+
+	for name, want := range expected {
+		got, ok := byName[name]
+		if !ok {
+			t.Errorf("expected security scheme %q to be enumerated", name)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("scheme %q:\nexpected %+v\ngot      %+v", name, want, got)
+		}
+	}
+
+	// Results are sorted by name for stable output.
+	for i := 1; i < len(schemes); i++ {
+		if schemes[i-1].Name > schemes[i].Name {
+			t.Errorf("expected schemes sorted by name, got %q before %q", schemes[i-1].Name, schemes[i].Name)
+		}
+	}
+}
+
+func TestGetSecuritySchemes_NoComponents(t *testing.T) {
+	if got := GetSecuritySchemes(nil); got != nil {
+		t.Errorf("expected nil for nil spec, got %v", got)
+	}
+	if got := GetSecuritySchemes(&openapi3.T{}); got != nil {
+		t.Errorf("expected nil for spec without components, got %v", got)
+	}
+}
+
+func TestGetSecuritySchemes_NormalizesScheme(t *testing.T) {
+	spec := &openapi3.T{
+		Components: &openapi3.Components{
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"token": &openapi3.SecuritySchemeRef{
+					Value: &openapi3.SecurityScheme{Type: "http", Scheme: "Bearer"},
+				},
+				"unresolved": &openapi3.SecuritySchemeRef{Ref: "#/components/securitySchemes/missing"},
+			},
+		},
+	}
+
+	schemes := GetSecuritySchemes(spec)
+	if len(schemes) != 1 {
+		t.Fatalf("expected unresolved scheme to be skipped, got %d schemes", len(schemes))
+	}
+	if schemes[0].Scheme != "bearer" {
+		t.Errorf("expected scheme to be lowercased, got %q", schemes[0].Scheme)
+	}
+}
@@ -0,0 +1,98 @@
+package spec
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OperationBaseURL returns the base URL an operation is sent to. A spec may
+// route single paths or operations to another gateway by declaring servers on
+// them, so the first operation-level server wins, then the first path-level
+// server, then baseURL, the profile's base URL.
+//
+// A profile whose base URL is not one of the root servers has been pointed at
+// another host on purpose, so every operation goes to it and path and
+// operation servers are ignored. Server variables are replaced with their
+// defaults, and relative server URLs are resolved against baseURL.
+func OperationBaseURL(doc *openapi3.T, path string, op *openapi3.Operation, baseURL string) string {
+	if doc == nil || !isRootServerURL(doc.Servers, baseURL) {
+		return baseURL
+	}
+
+	var servers openapi3.Servers
+	if op != nil && op.Servers != nil && len(*op.Servers) > 0 {
+		servers = *op.Servers
+	} else if doc.Paths != nil {
+		if pathItem := doc.Paths.Find(path); pathItem != nil {
+			servers = pathItem.Servers
+		}
+	}
+	if len(servers) == 0 || servers[0] == nil || servers[0].URL == "" {
+		return baseURL
+	}
+
+	return resolveServerURL(expandServerURL(servers[0]), baseURL)
+}
+
+// RootServerURLs returns the URLs of the spec's root servers, in order, with
+// variables replaced by their defaults and relative URLs resolved against
+// baseURL.
+func RootServerURLs(doc *openapi3.T, baseURL string) []string {
+	if doc == nil {
+		return nil
+	}
+	urls := make([]string, 0, len(doc.Servers))
+	for _, server := range doc.Servers {
+		if server == nil || server.URL == "" {
+			continue
+		}
+		urls = append(urls, resolveServerURL(expandServerURL(server), baseURL))
+	}
+	return urls
+}
+
+// isRootServerURL reports whether baseURL is one of the root servers, or
+// whether the spec declares none, so that the profile's base URL only stands
+// in for them.
+func isRootServerURL(servers openapi3.Servers, baseURL string) bool {
+	if len(servers) == 0 {
+		return true
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		if strings.TrimSuffix(server.URL, "/") == baseURL || strings.TrimSuffix(expandServerURL(server), "/") == baseURL {
+			return true
+		}
+	}
+	return false
+}
+
+// expandServerURL replaces the {variables} of a server URL with their defaults.
+func expandServerURL(server *openapi3.Server) string {
+	serverURL := server.URL
+	for name, variable := range server.Variables {
+		if variable != nil {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+		}
+	}
+	return serverURL
+}
+
+// resolveServerURL resolves a relative server URL, such as /v2, against the
+// profile's base URL.
+func resolveServerURL(serverURL, baseURL string) string {
+	ref, err := url.Parse(serverURL)
+	if err != nil || ref.IsAbs() {
+		return serverURL
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		return serverURL
+	}
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,76 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestOperationBaseURL(t *testing.T) {
+	reports := &openapi3.Operation{Servers: &openapi3.Servers{{URL: "https://reports.example.com"}}}
+	regional := &openapi3.Operation{Servers: &openapi3.Servers{{
+		URL:       "https://{region}.example.com",
+		Variables: map[string]*openapi3.ServerVariable{"region": {Default: "eu"}},
+	}}}
+	plain := &openapi3.Operation{}
+
+	paths := openapi3.NewPaths()
+	paths.Set("/reports", &openapi3.PathItem{Get: reports})
+	paths.Set("/regional", &openapi3.PathItem{Get: regional})
+	paths.Set("/pets", &openapi3.PathItem{Get: plain})
+	paths.Set("/legacy", &openapi3.PathItem{Get: plain, Servers: openapi3.Servers{{URL: "/legacy-api"}}})
+
+	doc := &openapi3.T{
+		Servers: openapi3.Servers{{URL: "https://api.example.com/v1"}, {URL: "https://staging.example.com/v1"}},
+		Paths:   paths,
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		op      *openapi3.Operation
+		baseURL string
+		want    string
+	}{
+		{"operation server", "/reports", reports, "https://api.example.com/v1", "https://reports.example.com"},
+		{"server variables", "/regional", regional, "https://api.example.com/v1", "https://eu.example.com"},
+		{"root server", "/pets", plain, "https://api.example.com/v1", "https://api.example.com/v1"},
+		{"relative path server", "/legacy", plain, "https://staging.example.com/v1/", "https://staging.example.com/legacy-api"},
+		{"profile override", "/reports", reports, "http://localhost:8080", "http://localhost:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OperationBaseURL(doc, tt.path, tt.op, tt.baseURL); got != tt.want {
+				t.Errorf("OperationBaseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := OperationBaseURL(nil, "/reports", reports, "http://localhost"); got != "http://localhost" {
+		t.Errorf("OperationBaseURL(nil) = %q, want the profile base URL", got)
+	}
+}
+
+func TestRootServerURLs(t *testing.T) {
+	doc := &openapi3.T{Servers: openapi3.Servers{
+		{URL: "https://{region}.example.com", Variables: map[string]*openapi3.ServerVariable{"region": {Default: "eu"}}},
+		{URL: "/fallback"},
+		{URL: ""},
+	}}
+
+	got := RootServerURLs(doc, "https://api.example.com/v1")
+	want := []string{"https://eu.example.com", "https://api.example.com/fallback"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+
+	if urls := RootServerURLs(nil, ""); urls != nil {
+		t.Errorf("expected no URLs for a nil document, got %v", urls)
+	}
+}
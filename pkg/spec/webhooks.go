@@ -0,0 +1,180 @@
+package spec
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kinds of inbound events.
+const (
+	EventWebhook  = "webhook"
+	EventCallback = "callback"
+)
+
+// schemaRefPrefix is the prefix of a $ref to a component schema.
+const schemaRefPrefix = "#/components/schemas/"
+
+// EventInfo describes a request the API sends to the client: an OpenAPI 3.1
+// webhook or an operation callback.
+type EventInfo struct {
+	Kind        string              `json:"kind"`                  // webhook or callback
+	Name        string              `json:"name"`                  // webhook name, or operationId/callback name
+	Method      string              `json:"method"`                // HTTP method of the inbound request
+	Expression  string              `json:"expression,omitempty"`  // callback URL expression, e.g. {$request.body#/callbackUrl}
+	OperationID string              `json:"operationId,omitempty"` // operationId of the inbound request
+	Summary     string              `json:"summary,omitempty"`     // summary of the inbound request
+	ContentType string              `json:"contentType,omitempty"` // media type of the payload
+	Schema      *openapi3.SchemaRef `json:"schema,omitempty"`      // schema of the payload
+}
+
+// GetEvents returns the webhooks and operation callbacks declared in the
+// spec, webhooks first, each sorted by name.
+func GetEvents(doc *openapi3.T) []EventInfo {
+	if doc == nil {
+		return nil
+	}
+
+	events := getWebhooks(doc)
+	return append(events, getCallbacks(doc)...)
+}
+
+// getWebhooks returns the top-level webhooks of an OpenAPI 3.1 document. The
+// loader does not know the webhooks field and keeps it as an extension, so it
+// is decoded here and its references to component schemas resolved.
+func getWebhooks(doc *openapi3.T) []EventInfo {
+	raw, ok := doc.Extensions["webhooks"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var webhooks map[string]*openapi3.PathItem
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil
+	}
+
+	var events []EventInfo
+	for _, name := range slices.Sorted(maps.Keys(webhooks)) {
+		for _, event := range pathItemEvents(EventWebhook, name, "", webhooks[name]) {
+			resolveSchemaRefs(doc, event.Schema, make(map[*openapi3.Schema]bool))
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// getCallbacks returns the callbacks declared on the operations of the spec.
+func getCallbacks(doc *openapi3.T) []EventInfo {
+	if doc.Paths == nil {
+		return nil
+	}
+
+	var events []EventInfo
+	for _, path := range doc.Paths.InMatchingOrder() {
+		for method, op := range doc.Paths.Value(path).Operations() {
+			for callbackName, callbackRef := range op.Callbacks {
+				if callbackRef == nil || callbackRef.Value == nil {
+					continue
+				}
+				owner := op.OperationID
+				if owner == "" {
+					owner = method + " " + path
+				}
+				for expression, pathItem := range callbackRef.Value.Map() {
+					events = append(events, pathItemEvents(EventCallback, owner+"/"+callbackName, expression, pathItem)...)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Name != events[j].Name {
+			return events[i].Name < events[j].Name
+		}
+		if events[i].Expression != events[j].Expression {
+			return events[i].Expression < events[j].Expression
+		}
+		return events[i].Method < events[j].Method
+	})
+	return events
+}
+
+// pathItemEvents returns an event for each operation of an inbound path item.
+func pathItemEvents(kind, name, expression string, pathItem *openapi3.PathItem) []EventInfo {
+	if pathItem == nil {
+		return nil
+	}
+
+	ops := pathItem.Operations()
+	methods := make([]string, 0, len(ops))
+	for method := range ops {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	events := make([]EventInfo, 0, len(methods))
+	for _, method := range methods {
+		op := ops[method]
+		event := EventInfo{
+			Kind:        kind,
+			Name:        name,
+			Method:      method,
+			Expression:  expression,
+			OperationID: op.OperationID,
+			Summary:     op.Summary,
+		}
+		if op.RequestBody != nil && op.RequestBody.Value != nil {
+			event.ContentType, event.Schema = payloadSchema(op.RequestBody.Value.Content)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// payloadSchema returns the JSON media type of a payload, or else the first in
+// name order, and its schema.
+func payloadSchema(content openapi3.Content) (string, *openapi3.SchemaRef) {
+	if mediaType := content.Get("application/json"); mediaType != nil {
+		return "application/json", mediaType.Schema
+	}
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		return contentType, content[contentType].Schema
+	}
+	return "", nil
+}
+
+// resolveSchemaRefs fills in the values of the references to component schemas
+// in a schema decoded outside the loader.
+func resolveSchemaRefs(doc *openapi3.T, ref *openapi3.SchemaRef, seen map[*openapi3.Schema]bool) {
+	if ref == nil {
+		return
+	}
+	if ref.Value == nil && strings.HasPrefix(ref.Ref, schemaRefPrefix) && doc.Components != nil {
+		if target := doc.Components.Schemas[strings.TrimPrefix(ref.Ref, schemaRefPrefix)]; target != nil {
+			ref.Value = target.Value
+		}
+	}
+
+	schema := ref.Value
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+
+	for _, prop := range schema.Properties {
+		resolveSchemaRefs(doc, prop, seen)
+	}
+	resolveSchemaRefs(doc, schema.Items, seen)
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, sub := range refs {
+			resolveSchemaRefs(doc, sub, seen)
+		}
+	}
+}
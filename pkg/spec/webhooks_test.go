@@ -0,0 +1,100 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const webhookSpec = `openapi: 3.1.0
+info:
+  title: Pet Events
+  version: 1.0.0
+paths:
+  /subscriptions:
+    post:
+      operationId: subscribe
+      responses:
+        "201":
+          description: Created
+      callbacks:
+        onAdoption:
+          "{$request.body#/callbackUrl}":
+            post:
+              summary: A pet was adopted
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      type: object
+                      properties:
+                        petId:
+                          type: integer
+              responses:
+                "200":
+                  description: OK
+webhooks:
+  newPet:
+    post:
+      operationId: newPetWebhook
+      summary: A pet was added to the store
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          example: doggie
+`
+
+func TestGetEvents(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "events.yaml")
+	if err := os.WriteFile(specPath, []byte(webhookSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := NewParser().LoadSpec(specPath)
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	events := GetEvents(doc)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %+v", len(events), events)
+	}
+
+	webhook := events[0]
+	if webhook.Kind != EventWebhook || webhook.Name != "newPet" || webhook.Method != "POST" || webhook.OperationID != "newPetWebhook" {
+		t.Errorf("unexpected webhook: %+v", webhook)
+	}
+	if webhook.ContentType != "application/json" || webhook.Schema == nil || webhook.Schema.Value == nil {
+		t.Fatalf("expected the webhook payload schema to be resolved, got %+v", webhook.Schema)
+	}
+	if _, ok := webhook.Schema.Value.Properties["name"]; !ok {
+		t.Errorf("expected the Pet schema, got %+v", webhook.Schema.Value)
+	}
+
+	callback := events[1]
+	if callback.Kind != EventCallback || callback.Name != "subscribe/onAdoption" || callback.Expression != "{$request.body#/callbackUrl}" {
+		t.Errorf("unexpected callback: %+v", callback)
+	}
+	if callback.Summary != "A pet was adopted" {
+		t.Errorf("expected the callback summary, got %q", callback.Summary)
+	}
+}
+
+func TestGetEventsNone(t *testing.T) {
+	if events := GetEvents(nil); events != nil {
+		t.Errorf("expected no events for a nil spec, got %+v", events)
+	}
+}
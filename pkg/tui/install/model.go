@@ -226,7 +226,7 @@ func NewModel(appName string, opts config.InstallOptions, appExists bool) Model
 		options:                     opts,
 		appExists:                   appExists,
 		collectedHeaders:            make(map[string]string),
-		authOptions:                 []string{"none", "bearer", "api_key", "basic"},
+		authOptions:                 []string{"none", "bearer", "api_key", "basic", "oauth2_client_credentials"},
 		shimOptions:                 []string{"Yes", "No"},
 		confirmOptions:              []string{"No", "Yes"},
 		addHeadersOptions:           []string{"No", "Yes"},
@@ -909,6 +909,29 @@ func (m *Model) prepareAuthDetails() {
 		m.authInputs = append(m.authInputs, tiValue)
 		m.authInputLabels = append(m.authInputLabels, "Key Value")
 
+	case "oauth2_client_credentials":
+		tiID := textinput.New()
+		tiID.Placeholder = "Client ID"
+		tiID.Focus()
+		m.authInputs = append(m.authInputs, tiID)
+		m.authInputLabels = append(m.authInputLabels, "Client ID")
+
+		tiSecret := textinput.New()
+		tiSecret.Placeholder = "Client Secret"
+		tiSecret.EchoMode = textinput.EchoPassword
+		m.authInputs = append(m.authInputs, tiSecret)
+		m.authInputLabels = append(m.authInputLabels, "Client Secret")
+
+		tiTokenURL := textinput.New()
+		tiTokenURL.Placeholder = "https://auth.example.com/oauth/token"
+		m.authInputs = append(m.authInputs, tiTokenURL)
+		m.authInputLabels = append(m.authInputLabels, "Token URL")
+
+		tiScopes := textinput.New()
+		tiScopes.Placeholder = "read write (optional)"
+		m.authInputs = append(m.authInputs, tiScopes)
+		m.authInputLabels = append(m.authInputLabels, "Scopes")
+
 	default:
 		// "none" or unknown auth type - no inputs needed
 	}
@@ -926,6 +949,11 @@ func (m *Model) collectAuthParams() {
 	case "api_key":
 		m.options.AuthParams["key_name"] = m.authInputs[0].Value()
 		m.options.AuthParams["token"] = m.authInputs[1].Value()
+	case "oauth2_client_credentials":
+		m.options.AuthParams["client_id"] = m.authInputs[0].Value()
+		m.options.AuthParams["client_secret"] = m.authInputs[1].Value()
+		m.options.AuthParams["token_url"] = m.authInputs[2].Value()
+		m.options.AuthParams["scopes"] = m.authInputs[3].Value()
 	default:
 		// "none" or unknown auth type - no params to collect
 	}